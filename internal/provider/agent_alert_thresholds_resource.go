@@ -0,0 +1,326 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AgentAlertThresholdsResource{}
+var _ resource.ResourceWithImportState = &AgentAlertThresholdsResource{}
+var _ resource.ResourceWithIdentity = &AgentAlertThresholdsResource{}
+var _ resource.ResourceWithValidateConfig = &AgentAlertThresholdsResource{}
+
+// Ranges accepted for the agent alert thresholds, in minutes. The overdue
+// threshold is capped at a week.
+const (
+    minAgentOfflineTime = 2
+    maxAgentOfflineTime = 1440
+    minAgentOverdueTime = 3
+    maxAgentOverdueTime = 10080
+)
+
+func NewAgentAlertThresholdsResource() resource.Resource {
+    return &AgentAlertThresholdsResource{}
+}
+
+// AgentAlertThresholdsResource manages when one agent is considered offline
+// and overdue.
+type AgentAlertThresholdsResource struct {
+    client *ClientConfig
+}
+
+// AgentAlertThresholdsResourceModel describes the resource data model.
+type AgentAlertThresholdsResourceModel struct {
+    Id          types.String `tfsdk:"id"`
+    AgentId     types.String `tfsdk:"agent_id"`
+    OfflineTime types.Int64  `tfsdk:"offline_time"`
+    OverdueTime types.Int64  `tfsdk:"overdue_time"`
+}
+
+func (r *AgentAlertThresholdsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_alert_thresholds"
+}
+
+func (r *AgentAlertThresholdsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Manages when an agent is considered offline and overdue. Thresholds that are not set keep their current value; " +
+            "destroying the resource restores the Tactical RMM defaults.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of the resource, same as `agent_id`",
+                Computed:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.UseStateForUnknown(),
+                },
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent whose thresholds are managed",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "offline_time": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Minutes without a check-in after which the agent is offline, %d to %d. Defaults to the current value of the agent.", minAgentOfflineTime, maxAgentOfflineTime),
+                Optional:            true,
+                Computed:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.UseStateForUnknown(),
+                },
+            },
+            "overdue_time": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Minutes without a check-in after which the agent is overdue and alerts are raised, %d to %d. Must be greater than `offline_time`. Defaults to the current value of the agent.", minAgentOverdueTime, maxAgentOverdueTime),
+                Optional:            true,
+                Computed:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.UseStateForUnknown(),
+                },
+            },
+        },
+    }
+}
+
+func (r *AgentAlertThresholdsResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = agentIdentitySchema()
+}
+
+func (r *AgentAlertThresholdsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *AgentAlertThresholdsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data AgentAlertThresholdsResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    invalid := func(attribute string, format string, args ...interface{}) {
+        resp.Diagnostics.AddAttributeError(path.Root(attribute), "Invalid Alert Threshold", fmt.Sprintf(format, args...))
+    }
+
+    offline, overdue := data.OfflineTime, data.OverdueTime
+    if !offline.IsNull() && !offline.IsUnknown() && (offline.ValueInt64() < minAgentOfflineTime || offline.ValueInt64() > maxAgentOfflineTime) {
+        invalid("offline_time", "offline_time must be between %d and %d minutes, got %d.", minAgentOfflineTime, maxAgentOfflineTime, offline.ValueInt64())
+    }
+    if !overdue.IsNull() && !overdue.IsUnknown() && (overdue.ValueInt64() < minAgentOverdueTime || overdue.ValueInt64() > maxAgentOverdueTime) {
+        invalid("overdue_time", "overdue_time must be between %d and %d minutes, got %d.", minAgentOverdueTime, maxAgentOverdueTime, overdue.ValueInt64())
+    }
+
+    // An agent is offline before it is overdue. With only one threshold set,
+    // the other is not known until apply.
+    if offline.IsNull() || offline.IsUnknown() || overdue.IsNull() || overdue.IsUnknown() {
+        return
+    }
+    if overdue.ValueInt64() <= offline.ValueInt64() {
+        invalid("overdue_time", "overdue_time must be greater than offline_time (%d), got %d.", offline.ValueInt64(), overdue.ValueInt64())
+    }
+}
+
+func (r *AgentAlertThresholdsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentAlertThresholdsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.apply(ctx, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = data.AgentId
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+}
+
+func (r *AgentAlertThresholdsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentAlertThresholdsResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+
+    thresholds, found, err := r.storedThresholds(ctx, data.AgentId.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent alert thresholds, got error: %s", err))
+        return
+    }
+    if !found {
+        resp.State.RemoveResource(ctx)
+        return
+    }
+
+    data.OfflineTime = int64Value(thresholds["offline_time"])
+    data.OverdueTime = int64Value(thresholds["overdue_time"])
+    data.Id = data.AgentId
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AgentAlertThresholdsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentAlertThresholdsResourceModel
+    var state AgentAlertThresholdsResourceModel
+
+    // Get the planned values
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.apply(ctx, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = state.Id
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+}
+
+func (r *AgentAlertThresholdsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentAlertThresholdsResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Restore the defaults; an agent that is already gone has nothing left
+    // to reset
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, data.AgentId.ValueString()), map[string]interface{}{
+        "offline_time": defaultAgentOfflineTime,
+        "overdue_time": defaultAgentOverdueTime,
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reset agent alert thresholds, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK && statusCode != http.StatusNotFound {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reset agent alert thresholds, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+    }
+}
+
+func (r *AgentAlertThresholdsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks may use the identity instead of an agent ID
+    resource.ImportStatePassthroughWithIdentity(ctx, path.Root("agent_id"), path.Root("agent_id"), req, resp)
+    resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("agent_id"), req, resp)
+}
+
+// apply writes the configured thresholds of data to its agent and reads both
+// back, so thresholds that are not set take the current value of the agent.
+func (r *AgentAlertThresholdsResource) apply(ctx context.Context, data *AgentAlertThresholdsResourceModel, diags *diag.Diagnostics) {
+    body := map[string]interface{}{}
+    if !data.OfflineTime.IsUnknown() && !data.OfflineTime.IsNull() {
+        body["offline_time"] = data.OfflineTime.ValueInt64()
+    }
+    if !data.OverdueTime.IsUnknown() && !data.OverdueTime.IsNull() {
+        body["overdue_time"] = data.OverdueTime.ValueInt64()
+    }
+
+    if len(body) > 0 {
+        statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, data.AgentId.ValueString()), body)
+        if err != nil {
+            diags.AddError("Client Error", fmt.Sprintf("Unable to update agent alert thresholds, got error: %s", err))
+            return
+        }
+        if statusCode != http.StatusOK {
+            diags.AddError("Client Error", fmt.Sprintf("Unable to update agent alert thresholds, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+            return
+        }
+    }
+
+    thresholds, found, err := r.storedThresholds(ctx, data.AgentId.ValueString())
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read agent alert thresholds, got error: %s", err))
+        return
+    }
+    if !found {
+        diags.AddError("Client Error", fmt.Sprintf("Agent %s not found", data.AgentId.ValueString()))
+        return
+    }
+
+    data.OfflineTime = int64Value(thresholds["offline_time"])
+    data.OverdueTime = int64Value(thresholds["overdue_time"])
+}
+
+// storedThresholds returns the agent with its offline_time and overdue_time.
+// found is false when the agent does not exist.
+func (r *AgentAlertThresholdsResource) storedThresholds(ctx context.Context, agentId string) (map[string]interface{}, bool, error) {
+    statusCode, respBody, err := r.client.sendJSON(ctx, "GET", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId), nil)
+    if err != nil {
+        return nil, false, err
+    }
+    if statusCode == http.StatusNotFound {
+        return nil, false, nil
+    }
+    if statusCode != http.StatusOK {
+        return nil, false, fmt.Errorf("unexpected status code: %d", statusCode)
+    }
+
+    var agent map[string]interface{}
+    if err := json.Unmarshal(respBody, &agent); err != nil {
+        return nil, false, fmt.Errorf("unable to parse response: %w", err)
+    }
+    return agent, true, nil
+}
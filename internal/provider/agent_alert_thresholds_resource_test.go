@@ -0,0 +1,196 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// alertThresholdsServer serves agent agent-1 with the default thresholds,
+// applying updates to it. Update bodies are recorded.
+func alertThresholdsServer(t *testing.T) (*httptest.Server, *[]map[string]interface{}) {
+    t.Helper()
+    var updates []map[string]interface{}
+    agent := map[string]interface{}{"agent_id": "agent-1", "hostname": "web01", "offline_time": 4, "overdue_time": 30}
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/agents/agent-1/" {
+            http.NotFound(w, r)
+            return
+        }
+        switch r.Method {
+        case http.MethodGet:
+            writeJSON(t, w, agent)
+        case http.MethodPut:
+            var body map[string]interface{}
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            updates = append(updates, body)
+            for field, value := range body {
+                agent[field] = value
+            }
+            writeJSON(t, w, "The agent was updated successfully")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &updates
+}
+
+func alertThresholdsModel() *AgentAlertThresholdsResourceModel {
+    return &AgentAlertThresholdsResourceModel{
+        Id:          types.StringNull(),
+        AgentId:     types.StringValue("agent-1"),
+        OfflineTime: types.Int64Unknown(),
+        OverdueTime: types.Int64Unknown(),
+    }
+}
+
+func TestAgentAlertThresholdsResourceCreate_RoundTrip(t *testing.T) {
+    server, updates := alertThresholdsServer(t)
+
+    model := alertThresholdsModel()
+    model.OfflineTime = types.Int64Value(10)
+    model.OverdueTime = types.Int64Value(120)
+
+    r := &AgentAlertThresholdsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := map[string]interface{}{"offline_time": float64(10), "overdue_time": float64(120)}
+    if len(*updates) != 1 || !reflect.DeepEqual((*updates)[0], expected) {
+        t.Fatalf("unexpected updates:\n got: %v\nwant: %v", *updates, expected)
+    }
+
+    // Reading the thresholds back shows no difference
+    var state AgentAlertThresholdsResourceModel
+    resp.State.Get(context.Background(), &state)
+    readResp := readResource(t, r, &state)
+    var read AgentAlertThresholdsResourceModel
+    readResp.State.Get(context.Background(), &read)
+    if !reflect.DeepEqual(read, state) {
+        t.Errorf("expected the read state to match the applied state:\n got: %+v\nwant: %+v", read, state)
+    }
+    if state.Id.ValueString() != "agent-1" || state.OfflineTime.ValueInt64() != 10 || state.OverdueTime.ValueInt64() != 120 {
+        t.Errorf("unexpected state: %+v", state)
+    }
+}
+
+func TestAgentAlertThresholdsResourceCreate_KeepsUnsetThreshold(t *testing.T) {
+    server, updates := alertThresholdsServer(t)
+
+    model := alertThresholdsModel()
+    model.OverdueTime = types.Int64Value(60)
+
+    r := &AgentAlertThresholdsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(*updates) != 1 || !reflect.DeepEqual((*updates)[0], map[string]interface{}{"overdue_time": float64(60)}) {
+        t.Errorf("expected only overdue_time to be sent, got %v", *updates)
+    }
+
+    var state AgentAlertThresholdsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.OfflineTime.ValueInt64() != 4 || state.OverdueTime.ValueInt64() != 60 {
+        t.Errorf("expected the current offline_time to be kept, got %s and %s", state.OfflineTime, state.OverdueTime)
+    }
+}
+
+func TestAgentAlertThresholdsResourceRead_AgentGone(t *testing.T) {
+    server, _ := alertThresholdsServer(t)
+
+    model := alertThresholdsModel()
+    model.AgentId = types.StringValue("agent-2")
+    model.Id = types.StringValue("agent-2")
+    model.OfflineTime = types.Int64Value(4)
+    model.OverdueTime = types.Int64Value(30)
+
+    r := &AgentAlertThresholdsResource{client: newTestClient(server)}
+    resp := readResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if !resp.State.Raw.IsNull() {
+        t.Error("expected the resource to be removed from state")
+    }
+}
+
+func TestAgentAlertThresholdsResourceDelete_RestoresDefaults(t *testing.T) {
+    server, updates := alertThresholdsServer(t)
+
+    model := alertThresholdsModel()
+    model.Id = types.StringValue("agent-1")
+    model.OfflineTime = types.Int64Value(10)
+    model.OverdueTime = types.Int64Value(120)
+
+    r := &AgentAlertThresholdsResource{client: newTestClient(server)}
+    resp := deleteResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := map[string]interface{}{"offline_time": float64(defaultAgentOfflineTime), "overdue_time": float64(defaultAgentOverdueTime)}
+    if len(*updates) != 1 || !reflect.DeepEqual((*updates)[0], expected) {
+        t.Errorf("expected the default thresholds, got %v", *updates)
+    }
+}
+
+func TestAgentAlertThresholdsResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &AgentAlertThresholdsResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    cases := map[string]struct {
+        offline types.Int64
+        overdue types.Int64
+        err     bool
+    }{
+        "both set":               {offline: types.Int64Value(2), overdue: types.Int64Value(10080)},
+        "neither set":            {offline: types.Int64Null(), overdue: types.Int64Null()},
+        "only overdue":           {offline: types.Int64Null(), overdue: types.Int64Value(3)},
+        "unknown offline":        {offline: types.Int64Unknown(), overdue: types.Int64Value(3)},
+        "offline too short":      {offline: types.Int64Value(1), overdue: types.Int64Null(), err: true},
+        "offline too long":       {offline: types.Int64Value(1441), overdue: types.Int64Null(), err: true},
+        "overdue too short":      {offline: types.Int64Null(), overdue: types.Int64Value(2), err: true},
+        "overdue too long":       {offline: types.Int64Null(), overdue: types.Int64Value(10081), err: true},
+        "overdue before offline": {offline: types.Int64Value(30), overdue: types.Int64Value(30), err: true},
+    }
+
+    for name, tc := range cases {
+        model := alertThresholdsModel()
+        model.OfflineTime = tc.offline
+        model.OverdueTime = tc.overdue
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if !tc.err {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Invalid Alert Threshold" {
+            t.Errorf("%s: expected an invalid threshold error, got: %v", name, resp.Diagnostics)
+        }
+    }
+}
@@ -0,0 +1,251 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AgentCollectedFieldsDataSource{}
+
+var collectedFieldAttrTypes = map[string]attr.Type{
+    "name":      types.StringType,
+    "type":      types.StringType,
+    "task_id":   types.Int64Type,
+    "task_name": types.StringType,
+    "collected": types.BoolType,
+    "value":     types.StringType,
+    "last_run":  types.StringType,
+}
+
+func NewAgentCollectedFieldsDataSource() datasource.DataSource {
+    return &AgentCollectedFieldsDataSource{}
+}
+
+// AgentCollectedFieldsDataSource reads the custom field values an agent's
+// collector tasks have stored.
+type AgentCollectedFieldsDataSource struct {
+    client *ClientConfig
+}
+
+// AgentCollectedFieldsDataSourceModel describes the data source data model.
+type AgentCollectedFieldsDataSourceModel struct {
+    AgentId types.String `tfsdk:"agent_id"`
+    Values  types.Map    `tfsdk:"values"`
+    Fields  types.List   `tfsdk:"fields"`
+}
+
+func (d *AgentCollectedFieldsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_collected_fields"
+}
+
+func (d *AgentCollectedFieldsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Agent collected fields data source for Tactical RMM. Returns the custom field values stored by the collector tasks of an agent, i.e. tasks that save their script output to a custom field. " +
+            "Fields whose collector task has not run yet are listed with `collected` false and a null value.",
+
+        Attributes: map[string]schema.Attribute{
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent to read",
+                Required:            true,
+            },
+            "values": schema.MapAttribute{
+                MarkdownDescription: "Collected values keyed by custom field name. Fields not collected yet are omitted. " +
+                    "Checkbox values are \"true\" or \"false\" and multiple choice values JSON encoded lists.",
+                Computed:    true,
+                ElementType: types.StringType,
+            },
+            "fields": schema.ListNestedAttribute{
+                MarkdownDescription: "Custom fields populated by a collector task of the agent, ordered by field and task name",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Custom field name",
+                            Computed:            true,
+                        },
+                        "type": schema.StringAttribute{
+                            MarkdownDescription: "Custom field type, e.g. text, number, checkbox",
+                            Computed:            true,
+                        },
+                        "task_id": schema.Int64Attribute{
+                            MarkdownDescription: "Collector task identifier",
+                            Computed:            true,
+                        },
+                        "task_name": schema.StringAttribute{
+                            MarkdownDescription: "Collector task name",
+                            Computed:            true,
+                        },
+                        "collected": schema.BoolAttribute{
+                            MarkdownDescription: "Whether the task has run and the agent has a value for the field",
+                            Computed:            true,
+                        },
+                        "value": schema.StringAttribute{
+                            MarkdownDescription: "Collected value in the same form as `values`, null if not collected yet",
+                            Computed:            true,
+                        },
+                        "last_run": schema.StringAttribute{
+                            MarkdownDescription: "Time the task last ran on the agent (RFC 3339, UTC), null if it never ran",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *AgentCollectedFieldsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *AgentCollectedFieldsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentCollectedFieldsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    agentId := data.AgentId.ValueString()
+
+    // Agent tasks include the tasks applied by automation policies
+    tasks, err := d.client.listObjects(ctx, fmt.Sprintf("%s/tasks/%s/", d.client.BaseURL, agentId))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agent tasks, got error: %s", err))
+        return
+    }
+
+    definitions, err := d.client.customFieldDefinitions(ctx, "agent")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom field definitions, got error: %s", err))
+        return
+    }
+    definitionsById := make(map[int64]map[string]interface{}, len(definitions))
+    for _, definition := range definitions {
+        definitionsById[customFieldId(definition)] = definition
+    }
+
+    agent, err := d.client.getObject(ctx, fmt.Sprintf("%s/agents/%s/", d.client.BaseURL, agentId))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent, got error: %s", err))
+        return
+    }
+    stored := storedCustomFields(agent)
+
+    type collectedField struct {
+        name      string
+        fieldType string
+        taskId    int64
+        taskName  string
+        value     *string
+        lastRun   *string
+    }
+
+    var fields []collectedField
+    for _, task := range tasks {
+        // Only collector tasks reference a custom field
+        fieldId, ok := task["custom_field"].(float64)
+        if !ok {
+            continue
+        }
+        taskName, _ := task["name"].(string)
+
+        definition, ok := definitionsById[int64(fieldId)]
+        if !ok {
+            resp.Diagnostics.AddWarning(
+                "Unknown Collector Field",
+                fmt.Sprintf("Task '%s' collects into custom field %d, which is not an agent custom field. The task was skipped.", taskName, int64(fieldId)),
+            )
+            continue
+        }
+
+        field := collectedField{taskName: taskName, fieldType: customFieldType(definition)}
+        field.name, _ = definition["name"].(string)
+        if taskId, ok := task["id"].(float64); ok {
+            field.taskId = int64(taskId)
+        }
+
+        result, _ := task["task_result"].(map[string]interface{})
+        if lastRun, ok := result["last_run"].(string); ok && lastRun != "" {
+            if parsed, err := parseAPITime(lastRun); err == nil {
+                formatted := parsed.UTC().Format(time.RFC3339)
+                field.lastRun = &formatted
+            }
+        }
+
+        // A value stored before the task ever ran was not collected by it
+        if value, ok := stored[int64(fieldId)]; ok && field.lastRun != nil {
+            if str, ok := customFieldValueString(definition, value); ok {
+                field.value = &str
+            }
+        }
+
+        fields = append(fields, field)
+    }
+
+    sort.Slice(fields, func(i, j int) bool {
+        if fields[i].name != fields[j].name {
+            return fields[i].name < fields[j].name
+        }
+        return fields[i].taskName < fields[j].taskName
+    })
+
+    values := make(map[string]attr.Value)
+    fieldValues := make([]attr.Value, 0, len(fields))
+    for _, field := range fields {
+        value := types.StringNull()
+        if field.value != nil {
+            value = types.StringValue(*field.value)
+            values[field.name] = value
+        }
+        lastRun := types.StringNull()
+        if field.lastRun != nil {
+            lastRun = types.StringValue(*field.lastRun)
+        }
+
+        fieldValue, diags := types.ObjectValue(collectedFieldAttrTypes, map[string]attr.Value{
+            "name":      types.StringValue(field.name),
+            "type":      types.StringValue(field.fieldType),
+            "task_id":   types.Int64Value(field.taskId),
+            "task_name": types.StringValue(field.taskName),
+            "collected": types.BoolValue(field.value != nil),
+            "value":     value,
+            "last_run":  lastRun,
+        })
+        resp.Diagnostics.Append(diags...)
+        fieldValues = append(fieldValues, fieldValue)
+    }
+
+    valuesMap, diags := types.MapValue(types.StringType, values)
+    resp.Diagnostics.Append(diags...)
+    data.Values = valuesMap
+
+    fieldsValue, diags := types.ListValue(types.ObjectType{AttrTypes: collectedFieldAttrTypes}, fieldValues)
+    resp.Diagnostics.Append(diags...)
+    data.Fields = fieldsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
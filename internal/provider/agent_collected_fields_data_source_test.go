@@ -0,0 +1,123 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAgentCollectedFieldsDataSourceRead(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/tasks/abc/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 10, "name": "Collect BIOS", "custom_field": 1, "task_result": map[string]interface{}{"last_run": "2026-10-15T08:30:00Z"}},
+                {"id": 11, "name": "Collect Roles", "custom_field": 2, "task_result": map[string]interface{}{"last_run": "2026-10-15T09:00:00Z"}},
+                {"id": 12, "name": "Collect GPU", "custom_field": 3, "task_result": map[string]interface{}{}},
+                {"id": 13, "name": "Reboot", "custom_field": nil},
+            })
+        case "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "model": "agent", "name": "BIOS Version", "type": "text"},
+                {"id": 2, "model": "agent", "name": "Roles", "type": "multiple"},
+                {"id": 3, "model": "agent", "name": "GPU", "type": "text"},
+                {"id": 4, "model": "client", "name": "Contract", "type": "text"},
+            })
+        case "/agents/abc/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "abc", "custom_fields": []map[string]interface{}{
+                {"field": 1, "string_value": "1.14.2"},
+                {"field": 2, "multiple_value": []string{"web", "db"}},
+                {"field": 3, "string_value": "set by hand"},
+            }})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &AgentCollectedFieldsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &AgentCollectedFieldsDataSourceModel{
+        AgentId: types.StringValue("abc"),
+        Values:  types.MapNull(types.StringType),
+        Fields:  types.ListNull(types.ObjectType{AttrTypes: collectedFieldAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentCollectedFieldsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    values := map[string]string{}
+    state.Values.ElementsAs(context.Background(), &values, false)
+    if len(values) != 2 || values["BIOS Version"] != "1.14.2" || values["Roles"] != `["web","db"]` {
+        t.Errorf("unexpected values: %v", values)
+    }
+
+    type field struct {
+        Name      types.String `tfsdk:"name"`
+        Type      types.String `tfsdk:"type"`
+        TaskId    types.Int64  `tfsdk:"task_id"`
+        TaskName  types.String `tfsdk:"task_name"`
+        Collected types.Bool   `tfsdk:"collected"`
+        Value     types.String `tfsdk:"value"`
+        LastRun   types.String `tfsdk:"last_run"`
+    }
+    var fields []field
+    state.Fields.ElementsAs(context.Background(), &fields, false)
+    if len(fields) != 3 {
+        t.Fatalf("expected 3 collector fields, got %d", len(fields))
+    }
+
+    bios := fields[0]
+    if bios.Name.ValueString() != "BIOS Version" || bios.TaskId.ValueInt64() != 10 || !bios.Collected.ValueBool() || bios.LastRun.ValueString() != "2026-10-15T08:30:00Z" {
+        t.Errorf("unexpected BIOS field: %+v", bios)
+    }
+
+    // The GPU task never ran, so the stored value was not collected by it
+    gpu := fields[1]
+    if gpu.Name.ValueString() != "GPU" || gpu.Collected.ValueBool() || !gpu.Value.IsNull() || !gpu.LastRun.IsNull() {
+        t.Errorf("expected GPU to be not collected yet, got %+v", gpu)
+    }
+}
+
+func TestAgentCollectedFieldsDataSourceRead_UnknownField(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/tasks/abc/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 10, "name": "Collect Contract", "custom_field": 4},
+            })
+        case "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{})
+        case "/agents/abc/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "abc"})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &AgentCollectedFieldsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &AgentCollectedFieldsDataSourceModel{
+        AgentId: types.StringValue("abc"),
+        Values:  types.MapNull(types.StringType),
+        Fields:  types.ListNull(types.ObjectType{AttrTypes: collectedFieldAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 1 || warnings[0].Summary() != "Unknown Collector Field" {
+        t.Errorf("expected an unknown field warning, got: %v", resp.Diagnostics)
+    }
+
+    var state AgentCollectedFieldsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if len(state.Values.Elements()) != 0 || len(state.Fields.Elements()) != 0 {
+        t.Errorf("expected no collected fields, got %v and %v", state.Values, state.Fields)
+    }
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AgentCustomFieldsDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &AgentCustomFieldsDataSource{}
+
+func NewAgentCustomFieldsDataSource() datasource.DataSource {
+    return &AgentCustomFieldsDataSource{}
+}
+
+// AgentCustomFieldsDataSource reads the custom field values of an agent, or
+// of every agent of a site or client.
+type AgentCustomFieldsDataSource struct {
+    client *ClientConfig
+}
+
+// AgentCustomFieldsDataSourceModel describes the data source data model.
+type AgentCustomFieldsDataSourceModel struct {
+    AgentId  types.String  `tfsdk:"agent_id"`
+    SiteId   types.Int64   `tfsdk:"site_id"`
+    ClientId types.Int64   `tfsdk:"client_id"`
+    Values   types.Dynamic `tfsdk:"values"`
+    Agents   types.Dynamic `tfsdk:"agents"`
+}
+
+func (d *AgentCustomFieldsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_custom_fields"
+}
+
+func (d *AgentCustomFieldsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Agent custom fields data source for Tactical RMM. Returns the custom field values of one agent, or of every agent of a site or client. " +
+            "Values are typed by field definition: checkbox fields are bools, number fields numbers, multiple choice fields lists of strings and all other fields strings. " +
+            "Fields without a stored value return the default of their definition.",
+
+        Attributes: map[string]schema.Attribute{
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent to read. Exactly one of `agent_id`, `site_id` or `client_id` must be specified.",
+                Optional:            true,
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Read every agent of this site. Exactly one of `agent_id`, `site_id` or `client_id` must be specified.",
+                Optional:            true,
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Read every agent of this client. Exactly one of `agent_id`, `site_id` or `client_id` must be specified.",
+                Optional:            true,
+            },
+            "values": schema.DynamicAttribute{
+                MarkdownDescription: "Object of custom field name to value for `agent_id`. Null when reading a site or client.",
+                Computed:            true,
+            },
+            "agents": schema.DynamicAttribute{
+                MarkdownDescription: "Object keyed by agent ID with `hostname` and `values` of every agent of `site_id` or `client_id`. Null when reading a single agent.",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *AgentCustomFieldsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+    var data AgentCustomFieldsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.AgentId.IsUnknown() || data.SiteId.IsUnknown() || data.ClientId.IsUnknown() {
+        return
+    }
+
+    specified := 0
+    for _, isNull := range []bool{data.AgentId.IsNull(), data.SiteId.IsNull(), data.ClientId.IsNull()} {
+        if !isNull {
+            specified++
+        }
+    }
+
+    if specified != 1 {
+        resp.Diagnostics.AddError(
+            "Invalid Custom Field Scope",
+            "Exactly one of 'agent_id', 'site_id' or 'client_id' must be specified.",
+        )
+    }
+}
+
+func (d *AgentCustomFieldsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *AgentCustomFieldsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentCustomFieldsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    definitions, err := d.client.customFieldDefinitions(ctx, "agent")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom field definitions, got error: %s", err))
+        return
+    }
+
+    data.Values = types.DynamicNull()
+    data.Agents = types.DynamicNull()
+
+    if !data.AgentId.IsNull() {
+        agent, err := d.client.getObject(ctx, fmt.Sprintf("%s/agents/%s/", d.client.BaseURL, data.AgentId.ValueString()))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent, got error: %s", err))
+            return
+        }
+
+        values, diags := customFieldValues(definitions, storedCustomFields(agent))
+        resp.Diagnostics.Append(diags...)
+        data.Values = types.DynamicValue(values)
+
+        resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+        return
+    }
+
+    // The agents list can be filtered by site or client
+    listURL := fmt.Sprintf("%s/agents/?site=%d", d.client.BaseURL, data.SiteId.ValueInt64())
+    if !data.ClientId.IsNull() {
+        listURL = fmt.Sprintf("%s/agents/?client=%d", d.client.BaseURL, data.ClientId.ValueInt64())
+    }
+
+    agents, err := d.client.listObjects(ctx, listURL)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+        return
+    }
+
+    // The list does not include custom fields, so every agent is fetched
+    var agentIds []string
+    for _, agent := range agents {
+        if agentId, ok := agent["agent_id"].(string); ok {
+            agentIds = append(agentIds, agentId)
+        }
+    }
+
+    details, failures := d.client.fetchAgentDetails(ctx, agentIds)
+
+    agentTypes := make(map[string]attr.Type, len(agentIds))
+    agentValues := make(map[string]attr.Value, len(agentIds))
+    for _, agentId := range agentIds {
+        // Agents that cannot be read are left out of agents
+        if err, failed := failures[agentId]; failed {
+            addItemFetchWarning(&resp.Diagnostics, fmt.Sprintf("agent %s", agentId), err)
+            continue
+        }
+
+        agent := details[agentId]
+        values, diags := customFieldValues(definitions, storedCustomFields(agent))
+        resp.Diagnostics.Append(diags...)
+
+        hostname, _ := agent["hostname"].(string)
+        entryTypes := map[string]attr.Type{
+            "hostname": types.StringType,
+            "values":   values.Type(ctx),
+        }
+        entry, diags := types.ObjectValue(entryTypes, map[string]attr.Value{
+            "hostname": types.StringValue(hostname),
+            "values":   values,
+        })
+        resp.Diagnostics.Append(diags...)
+
+        agentTypes[agentId] = types.ObjectType{AttrTypes: entryTypes}
+        agentValues[agentId] = entry
+    }
+
+    agentsValue, diags := types.ObjectValue(agentTypes, agentValues)
+    resp.Diagnostics.Append(diags...)
+    data.Agents = types.DynamicValue(agentsValue)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
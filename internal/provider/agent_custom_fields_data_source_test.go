@@ -0,0 +1,155 @@
+package provider
+
+import (
+    "context"
+    "math/big"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func agentCustomFieldsDataServer(t *testing.T) *httptest.Server {
+    t.Helper()
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.URL.Path == "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "model": "agent", "name": "Rack", "type": "number"},
+                {"id": 2, "model": "agent", "name": "Roles", "type": "multiple", "default_values_multiple": []string{"base"}},
+                {"id": 3, "model": "agent", "name": "Managed", "type": "checkbox", "default_value_bool": true},
+                {"id": 4, "model": "agent", "name": "Warranty", "type": "datetime"},
+            })
+        case r.URL.Path == "/agents/" && r.URL.Query().Get("site") == "5":
+            writeJSON(t, w, []map[string]interface{}{
+                {"agent_id": "abc", "hostname": "web01"},
+                {"agent_id": "def", "hostname": "db01"},
+            })
+        case r.URL.Path == "/agents/" && r.URL.Query().Get("client") == "9":
+            writeJSON(t, w, []map[string]interface{}{
+                {"agent_id": "abc", "hostname": "web01"},
+                {"agent_id": "gone", "hostname": "old01"},
+            })
+        case r.URL.Path == "/agents/abc/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "abc", "hostname": "web01", "custom_fields": []map[string]interface{}{
+                {"field": 1, "string_value": "12"},
+                {"field": 2, "multiple_value": []string{"web", "db"}},
+                {"field": 4, "string_value": "2027-01-31"},
+            }})
+        case r.URL.Path == "/agents/def/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "def", "hostname": "db01", "custom_fields": []map[string]interface{}{}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server
+}
+
+func readAgentCustomFields(t *testing.T, model *AgentCustomFieldsDataSourceModel) AgentCustomFieldsDataSourceModel {
+    t.Helper()
+    model.Values = types.DynamicNull()
+    model.Agents = types.DynamicNull()
+
+    d := &AgentCustomFieldsDataSource{client: newTestClient(agentCustomFieldsDataServer(t))}
+    resp := readDataSource(t, d, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentCustomFieldsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    return state
+}
+
+func TestAgentCustomFieldsDataSourceRead_TypedAgentValues(t *testing.T) {
+    state := readAgentCustomFields(t, &AgentCustomFieldsDataSourceModel{
+        AgentId:  types.StringValue("abc"),
+        SiteId:   types.Int64Null(),
+        ClientId: types.Int64Null(),
+    })
+
+    if !state.Agents.IsNull() {
+        t.Errorf("expected agents to be null for a single agent, got %v", state.Agents)
+    }
+    values, ok := state.Values.UnderlyingValue().(types.Object)
+    if !ok {
+        t.Fatalf("expected values to be an object, got %T", state.Values.UnderlyingValue())
+    }
+    attrs := values.Attributes()
+
+    if got := attrs["Rack"].(types.Number).ValueBigFloat(); got.Cmp(big.NewFloat(12)) != 0 {
+        t.Errorf("expected Rack 12, got %v", got)
+    }
+    var roles []string
+    attrs["Roles"].(types.List).ElementsAs(context.Background(), &roles, false)
+    if len(roles) != 2 || roles[0] != "web" || roles[1] != "db" {
+        t.Errorf("expected Roles as a list [web db], got %v", roles)
+    }
+    if got := attrs["Managed"].(types.Bool).ValueBool(); !got {
+        t.Errorf("expected Managed to fall back to its default true, got %v", got)
+    }
+    if got := attrs["Warranty"].(types.String).ValueString(); got != "2027-01-31" {
+        t.Errorf("expected Warranty '2027-01-31', got %q", got)
+    }
+}
+
+func TestAgentCustomFieldsDataSourceRead_SiteAgents(t *testing.T) {
+    state := readAgentCustomFields(t, &AgentCustomFieldsDataSourceModel{
+        AgentId:  types.StringNull(),
+        SiteId:   types.Int64Value(5),
+        ClientId: types.Int64Null(),
+    })
+
+    if !state.Values.IsNull() {
+        t.Errorf("expected values to be null for a site, got %v", state.Values)
+    }
+    agents, ok := state.Agents.UnderlyingValue().(types.Object)
+    if !ok {
+        t.Fatalf("expected agents to be an object, got %T", state.Agents.UnderlyingValue())
+    }
+    if len(agents.Attributes()) != 2 {
+        t.Fatalf("expected 2 agents, got %d", len(agents.Attributes()))
+    }
+
+    db := agents.Attributes()["def"].(types.Object).Attributes()
+    if got := db["hostname"].(types.String).ValueString(); got != "db01" {
+        t.Errorf("expected hostname db01, got %q", got)
+    }
+    var roles []string
+    db["values"].(types.Object).Attributes()["Roles"].(types.List).ElementsAs(context.Background(), &roles, false)
+    if len(roles) != 1 || roles[0] != "base" {
+        t.Errorf("expected default Roles [base], got %v", roles)
+    }
+}
+
+func TestAgentCustomFieldsDataSourceRead_SkipsUnreadableAgents(t *testing.T) {
+    d := &AgentCustomFieldsDataSource{client: newTestClient(agentCustomFieldsDataServer(t))}
+    resp := readDataSource(t, d, &AgentCustomFieldsDataSourceModel{
+        AgentId:  types.StringNull(),
+        SiteId:   types.Int64Null(),
+        ClientId: types.Int64Value(9),
+        Values:   types.DynamicNull(),
+        Agents:   types.DynamicNull(),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 1 || warnings[0].Summary() != "Incomplete Results" {
+        t.Errorf("expected a warning for the unreadable agent, got: %v", resp.Diagnostics)
+    }
+
+    var state AgentCustomFieldsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    agents, ok := state.Agents.UnderlyingValue().(types.Object)
+    if !ok {
+        t.Fatalf("expected agents to be an object, got %T", state.Agents.UnderlyingValue())
+    }
+    if _, ok := agents.Attributes()["abc"]; !ok || len(agents.Attributes()) != 1 {
+        t.Errorf("expected only the readable agent, got %v", agents)
+    }
+}
@@ -0,0 +1,371 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sort"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AgentCustomFieldsResource{}
+var _ resource.ResourceWithImportState = &AgentCustomFieldsResource{}
+var _ resource.ResourceWithIdentity = &AgentCustomFieldsResource{}
+
+func NewAgentCustomFieldsResource() resource.Resource {
+    return &AgentCustomFieldsResource{}
+}
+
+// AgentCustomFieldsResource manages a set of custom field values on one agent.
+type AgentCustomFieldsResource struct {
+    client *ClientConfig
+}
+
+// AgentCustomFieldsResourceModel describes the resource data model.
+type AgentCustomFieldsResourceModel struct {
+    Id      types.String `tfsdk:"id"`
+    AgentId types.String `tfsdk:"agent_id"`
+    Values  types.Map    `tfsdk:"values"`
+}
+
+func (r *AgentCustomFieldsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_custom_fields"
+}
+
+func (r *AgentCustomFieldsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Manages several agent custom field values in one resource. All values are written in a single agent update. " +
+            "Fields removed from `values` are reset to the default of their definition, and all managed fields are reset when the resource is destroyed.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of the resource, same as `agent_id`",
+                Computed:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.UseStateForUnknown(),
+                },
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent whose custom fields are managed",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "values": schema.MapAttribute{
+                MarkdownDescription: "Custom field name to value. Checkbox fields take `\"true\"` or `\"false\"`, number fields a numeric string, " +
+                    "and multiple choice fields a JSON encoded list such as `jsonencode([\"a\", \"b\"])`.",
+                Required:    true,
+                ElementType: types.StringType,
+            },
+        },
+    }
+}
+
+func (r *AgentCustomFieldsResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = agentIdentitySchema()
+}
+
+func (r *AgentCustomFieldsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *AgentCustomFieldsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentCustomFieldsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var values map[string]string
+    resp.Diagnostics.Append(data.Values.ElementsAs(ctx, &values, false)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.reconcile(ctx, data.AgentId.ValueString(), values, nil, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = data.AgentId
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+}
+
+func (r *AgentCustomFieldsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentCustomFieldsResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+
+    definitions, err := r.client.customFieldDefinitions(ctx, "agent")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom field definitions, got error: %s", err))
+        return
+    }
+
+    stored, found, err := r.storedValues(ctx, data.AgentId.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent custom fields, got error: %s", err))
+        return
+    }
+    if !found {
+        resp.State.RemoveResource(ctx)
+        return
+    }
+
+    // Only refresh the fields this resource manages; after an import every
+    // field with a stored value is adopted
+    var managed []string
+    if data.Values.IsNull() || data.Values.IsUnknown() {
+        for name := range definitions {
+            managed = append(managed, name)
+        }
+    } else {
+        var values map[string]string
+        resp.Diagnostics.Append(data.Values.ElementsAs(ctx, &values, false)...)
+        for name := range values {
+            managed = append(managed, name)
+        }
+    }
+
+    current := make(map[string]string)
+    for _, name := range managed {
+        definition, ok := definitions[name]
+        if !ok {
+            continue
+        }
+        value, ok := stored[customFieldId(definition)]
+        if !ok {
+            continue
+        }
+        if str, ok := customFieldValueString(definition, value); ok {
+            current[name] = str
+        }
+    }
+
+    valuesMap, diags := types.MapValueFrom(ctx, types.StringType, current)
+    resp.Diagnostics.Append(diags...)
+    data.Values = valuesMap
+    data.Id = data.AgentId
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AgentCustomFieldsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentCustomFieldsResourceModel
+    var state AgentCustomFieldsResourceModel
+
+    // Get the planned values
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Get the current state to find fields that are no longer managed
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var values map[string]string
+    var previous map[string]string
+    resp.Diagnostics.Append(data.Values.ElementsAs(ctx, &values, false)...)
+    resp.Diagnostics.Append(state.Values.ElementsAs(ctx, &previous, false)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var removed []string
+    for name := range previous {
+        if _, ok := values[name]; !ok {
+            removed = append(removed, name)
+        }
+    }
+
+    r.reconcile(ctx, data.AgentId.ValueString(), values, removed, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = state.Id
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+}
+
+func (r *AgentCustomFieldsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentCustomFieldsResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var previous map[string]string
+    resp.Diagnostics.Append(data.Values.ElementsAs(ctx, &previous, false)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var removed []string
+    for name := range previous {
+        removed = append(removed, name)
+    }
+
+    r.reconcile(ctx, data.AgentId.ValueString(), nil, removed, &resp.Diagnostics)
+}
+
+func (r *AgentCustomFieldsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks may use the identity instead of an agent ID
+    resource.ImportStatePassthroughWithIdentity(ctx, path.Root("agent_id"), path.Root("agent_id"), req, resp)
+    resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("agent_id"), req, resp)
+}
+
+// reconcile writes values and resets the removed fields to their defaults in a
+// single agent update.
+func (r *AgentCustomFieldsResource) reconcile(ctx context.Context, agentId string, values map[string]string, removed []string, diags *diag.Diagnostics) {
+    definitions, err := r.client.customFieldDefinitions(ctx, "agent")
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read custom field definitions, got error: %s", err))
+        return
+    }
+
+    // Sort names so the request body is stable
+    names := make([]string, 0, len(values))
+    for name := range values {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    sort.Strings(removed)
+
+    payloads := make([]map[string]interface{}, 0, len(names)+len(removed))
+    for _, name := range names {
+        definition, ok := definitions[name]
+        if !ok {
+            diags.AddAttributeError(
+                path.Root("values").AtMapKey(name),
+                "Unknown Custom Field",
+                fmt.Sprintf("Custom field '%s' is not defined for agents.", name),
+            )
+            continue
+        }
+        payload, err := customFieldPayload(definition, values[name])
+        if err != nil {
+            diags.AddAttributeError(
+                path.Root("values").AtMapKey(name),
+                "Invalid Custom Field Value",
+                fmt.Sprintf("Invalid value for custom field '%s' of type %s: %s", name, customFieldType(definition), err),
+            )
+            continue
+        }
+        payloads = append(payloads, payload)
+    }
+
+    for _, name := range removed {
+        // Fields whose definition was deleted have nothing left to reset
+        if definition, ok := definitions[name]; ok {
+            payloads = append(payloads, customFieldDefaultPayload(definition))
+        }
+    }
+
+    if diags.HasError() || len(payloads) == 0 {
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId), map[string]interface{}{
+        "custom_fields": payloads,
+    })
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update agent custom fields, got error: %s", err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update agent custom fields, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+    }
+}
+
+// storedValues returns the custom field value objects stored on an agent keyed
+// by field ID. found is false when the agent does not exist.
+func (r *AgentCustomFieldsResource) storedValues(ctx context.Context, agentId string) (map[int64]map[string]interface{}, bool, error) {
+    statusCode, respBody, err := r.client.sendJSON(ctx, "GET", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId), nil)
+    if err != nil {
+        return nil, false, err
+    }
+
+    if statusCode == http.StatusNotFound {
+        return nil, false, nil
+    }
+
+    if statusCode != http.StatusOK {
+        return nil, false, fmt.Errorf("unexpected status code: %d", statusCode)
+    }
+
+    var agent struct {
+        CustomFields []map[string]interface{} `json:"custom_fields"`
+    }
+    if err := json.Unmarshal(respBody, &agent); err != nil {
+        return nil, false, fmt.Errorf("unable to parse response: %w", err)
+    }
+
+    stored := make(map[int64]map[string]interface{})
+    for _, value := range agent.CustomFields {
+        if fieldId, ok := value["field"].(float64); ok {
+            stored[int64(fieldId)] = value
+        }
+    }
+
+    return stored, true, nil
+}
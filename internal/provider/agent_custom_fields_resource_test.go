@@ -0,0 +1,202 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// customFieldsServer serves agent custom field definitions and the custom
+// field values of agent "abc", recording the body of every agent update.
+func customFieldsServer(t *testing.T, stored []map[string]interface{}) (*httptest.Server, *[]map[string]interface{}) {
+    t.Helper()
+    var updates []map[string]interface{}
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "model": "agent", "name": "Owner", "type": "text", "default_value_string": "unassigned"},
+                {"id": 2, "model": "agent", "name": "Managed", "type": "checkbox", "default_value_bool": true},
+                {"id": 3, "model": "agent", "name": "Roles", "type": "multiple", "default_values_multiple": []string{"base"}},
+                {"id": 4, "model": "agent", "name": "Rack", "type": "number"},
+                {"id": 5, "model": "client", "name": "Owner", "type": "text"},
+            })
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/abc/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "abc", "custom_fields": stored})
+        case r.Method == http.MethodPut && r.URL.Path == "/agents/abc/":
+            var body map[string]interface{}
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Fatalf("unable to decode update: %s", err)
+            }
+            updates = append(updates, body)
+            writeJSON(t, w, "The agent was updated successfully")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &updates
+}
+
+func customFieldsModel(t *testing.T, values map[string]string) *AgentCustomFieldsResourceModel {
+    t.Helper()
+    valuesMap, diags := types.MapValueFrom(context.Background(), types.StringType, values)
+    if diags.HasError() {
+        t.Fatalf("unable to build values: %v", diags)
+    }
+    return &AgentCustomFieldsResourceModel{
+        Id:      types.StringValue("abc"),
+        AgentId: types.StringValue("abc"),
+        Values:  valuesMap,
+    }
+}
+
+// updatedFields decodes the custom_fields of a recorded update keyed by field ID.
+func updatedFields(t *testing.T, update map[string]interface{}) map[float64]map[string]interface{} {
+    t.Helper()
+    items, ok := update["custom_fields"].([]interface{})
+    if !ok {
+        t.Fatalf("update has no custom_fields: %v", update)
+    }
+    fields := make(map[float64]map[string]interface{})
+    for _, item := range items {
+        field := item.(map[string]interface{})
+        fields[field["field"].(float64)] = field
+    }
+    return fields
+}
+
+func TestAgentCustomFieldsResourceCreate_WritesTypedValuesInOneUpdate(t *testing.T) {
+    server, updates := customFieldsServer(t, nil)
+    r := &AgentCustomFieldsResource{client: newTestClient(server)}
+
+    resp := createResource(t, r, customFieldsModel(t, map[string]string{
+        "Owner":   "ops",
+        "Managed": "false",
+        "Roles":   `["web","db"]`,
+        "Rack":    "12",
+    }))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(*updates) != 1 {
+        t.Fatalf("expected a single agent update, got %d", len(*updates))
+    }
+    fields := updatedFields(t, (*updates)[0])
+    if got := fields[1]["string_value"]; got != "ops" {
+        t.Errorf("expected Owner 'ops', got %v", got)
+    }
+    if got := fields[2]["bool_value"]; got != false {
+        t.Errorf("expected Managed false, got %v", got)
+    }
+    if got := fields[3]["multiple_value"]; !reflect.DeepEqual(got, []interface{}{"web", "db"}) {
+        t.Errorf("expected Roles [web db], got %v", got)
+    }
+    if got := fields[4]["string_value"]; got != "12" {
+        t.Errorf("expected Rack '12', got %v", got)
+    }
+}
+
+func TestAgentCustomFieldsResourceCreate_RejectsInvalidValues(t *testing.T) {
+    server, updates := customFieldsServer(t, nil)
+    r := &AgentCustomFieldsResource{client: newTestClient(server)}
+
+    for name, value := range map[string]string{
+        "Managed": "maybe",
+        "Roles":   "web",
+        "Rack":    "twelve",
+        "Missing": "x",
+    } {
+        resp := createResource(t, r, customFieldsModel(t, map[string]string{name: value}))
+        if !resp.Diagnostics.HasError() {
+            t.Errorf("expected an error for %s = %q", name, value)
+        }
+    }
+
+    if len(*updates) != 0 {
+        t.Errorf("expected no agent updates, got %d", len(*updates))
+    }
+}
+
+func TestAgentCustomFieldsResourceUpdate_ResetsRemovedKeysToDefault(t *testing.T) {
+    server, updates := customFieldsServer(t, nil)
+    r := &AgentCustomFieldsResource{client: newTestClient(server)}
+
+    resp := updateResource(t, r,
+        customFieldsModel(t, map[string]string{"Owner": "ops", "Managed": "false", "Roles": `["web"]`}),
+        customFieldsModel(t, map[string]string{"Owner": "platform"}),
+    )
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(*updates) != 1 {
+        t.Fatalf("expected a single agent update, got %d", len(*updates))
+    }
+    fields := updatedFields(t, (*updates)[0])
+    if len(fields) != 3 {
+        t.Fatalf("expected 3 fields in the update, got %d", len(fields))
+    }
+    if got := fields[1]["string_value"]; got != "platform" {
+        t.Errorf("expected Owner 'platform', got %v", got)
+    }
+    if got := fields[2]["bool_value"]; got != true {
+        t.Errorf("expected Managed reset to true, got %v", got)
+    }
+    if got := fields[3]["multiple_value"]; !reflect.DeepEqual(got, []interface{}{"base"}) {
+        t.Errorf("expected Roles reset to [base], got %v", got)
+    }
+}
+
+func TestAgentCustomFieldsResourceDelete_ResetsAllManagedKeys(t *testing.T) {
+    server, updates := customFieldsServer(t, nil)
+    r := &AgentCustomFieldsResource{client: newTestClient(server)}
+
+    resp := deleteResource(t, r, customFieldsModel(t, map[string]string{"Owner": "ops", "Rack": "12"}))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(*updates) != 1 {
+        t.Fatalf("expected a single agent update, got %d", len(*updates))
+    }
+    fields := updatedFields(t, (*updates)[0])
+    if got := fields[1]["string_value"]; got != "unassigned" {
+        t.Errorf("expected Owner reset to 'unassigned', got %v", got)
+    }
+    if got := fields[4]["string_value"]; got != "" {
+        t.Errorf("expected Rack reset to empty, got %v", got)
+    }
+}
+
+func TestAgentCustomFieldsResourceRead_RefreshesManagedKeysOnly(t *testing.T) {
+    server, _ := customFieldsServer(t, []map[string]interface{}{
+        {"field": 1, "string_value": "changed"},
+        {"field": 2, "bool_value": true},
+        {"field": 3, "multiple_value": []string{"web", "db"}},
+    })
+    r := &AgentCustomFieldsResource{client: newTestClient(server)}
+
+    resp := readResource(t, r, customFieldsModel(t, map[string]string{"Owner": "ops", "Roles": `["web"]`, "Rack": "12"}))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentCustomFieldsResourceModel
+    resp.State.Get(context.Background(), &state)
+    var values map[string]string
+    state.Values.ElementsAs(context.Background(), &values, false)
+
+    expected := map[string]string{"Owner": "changed", "Roles": `["web","db"]`}
+    if !reflect.DeepEqual(values, expected) {
+        t.Errorf("expected %v, got %v", expected, values)
+    }
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AgentEffectivePolicyDataSource{}
+
+func NewAgentEffectivePolicyDataSource() datasource.DataSource {
+    return &AgentEffectivePolicyDataSource{}
+}
+
+// AgentEffectivePolicyDataSource reports which automation policy applies to
+// an agent once client, site and agent assignments and blocked inheritance
+// are taken into account.
+type AgentEffectivePolicyDataSource struct {
+    client *ClientConfig
+}
+
+// AgentEffectivePolicyDataSourceModel describes the data source data model.
+type AgentEffectivePolicyDataSourceModel struct {
+    AgentId             types.String `tfsdk:"agent_id"`
+    EffectivePolicyId   types.Int64  `tfsdk:"effective_policy_id"`
+    EffectivePolicyName types.String `tfsdk:"effective_policy_name"`
+    InheritedFrom       types.String `tfsdk:"inherited_from"`
+    AgentPolicyId       types.Int64  `tfsdk:"agent_policy_id"`
+    SitePolicyId        types.Int64  `tfsdk:"site_policy_id"`
+    ClientPolicyId      types.Int64  `tfsdk:"client_policy_id"`
+    DefaultPolicyId     types.Int64  `tfsdk:"default_policy_id"`
+}
+
+// policyLevels are the keys of the applied_policies TRMM computes for an
+// agent, from the most to the least specific. The most specific applied
+// policy is the effective one.
+var policyLevels = []struct {
+    key   string
+    level string
+}{
+    {"agent_policy", "agent"},
+    {"site_policy", "site"},
+    {"client_policy", "client"},
+    {"default_policy", "default"},
+}
+
+func (d *AgentEffectivePolicyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_effective_policy"
+}
+
+func (d *AgentEffectivePolicyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Effective automation policy data source for Tactical RMM. Use this to see which policy applies to an agent after inheritance " +
+            "from its client and site, policy exclusions and blocked inheritance have been resolved by Tactical RMM.",
+
+        Attributes: map[string]schema.Attribute{
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent identifier",
+                Required:            true,
+            },
+            "effective_policy_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the policy that applies to the agent, null if no policy applies",
+                Computed:            true,
+            },
+            "effective_policy_name": schema.StringAttribute{
+                MarkdownDescription: "Name of the policy that applies to the agent, null if no policy applies",
+                Computed:            true,
+            },
+            "inherited_from": schema.StringAttribute{
+                MarkdownDescription: "Level the effective policy is assigned at: agent, site, client or default. Null if no policy applies",
+                Computed:            true,
+            },
+            "agent_policy_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the policy assigned to the agent itself, null if none applies",
+                Computed:            true,
+            },
+            "site_policy_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the policy inherited from the site, null if none applies or inheritance is blocked",
+                Computed:            true,
+            },
+            "client_policy_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the policy inherited from the client, null if none applies or inheritance is blocked",
+                Computed:            true,
+            },
+            "default_policy_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the global default policy, null if none applies or inheritance is blocked",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *AgentEffectivePolicyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *AgentEffectivePolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentEffectivePolicyDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    agent, err := d.client.getObject(ctx, fmt.Sprintf("%s/agents/%s/", d.client.BaseURL, data.AgentId.ValueString()))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent %s, got error: %s", data.AgentId.ValueString(), err))
+        return
+    }
+
+    applied, ok := agent["applied_policies"].(map[string]interface{})
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Effective Policy Unavailable",
+            fmt.Sprintf("The agent %s was returned without applied_policies. This Tactical RMM version does not report the policies applied to an agent.", data.AgentId.ValueString()),
+        )
+        return
+    }
+
+    ids := make(map[string]types.Int64, len(policyLevels))
+    data.EffectivePolicyId = types.Int64Null()
+    data.EffectivePolicyName = types.StringNull()
+    data.InheritedFrom = types.StringNull()
+    for _, level := range policyLevels {
+        id, name := appliedPolicy(applied[level.key])
+        ids[level.key] = id
+        if !id.IsNull() && data.InheritedFrom.IsNull() {
+            data.EffectivePolicyId = id
+            data.EffectivePolicyName = name
+            data.InheritedFrom = types.StringValue(level.level)
+        }
+    }
+
+    data.AgentPolicyId = ids["agent_policy"]
+    data.SitePolicyId = ids["site_policy"]
+    data.ClientPolicyId = ids["client_policy"]
+    data.DefaultPolicyId = ids["default_policy"]
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// appliedPolicy returns the ID and name of one applied_policies entry, which
+// is a serialized policy, a bare policy ID or null when no policy applies.
+func appliedPolicy(value interface{}) (types.Int64, types.String) {
+    switch policy := value.(type) {
+    case map[string]interface{}:
+        return int64Value(policy["id"]), stringValue(policy["name"])
+    case float64:
+        return types.Int64Value(int64(policy)), types.StringNull()
+    }
+    return types.Int64Null(), types.StringNull()
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func effectivePolicyServer(t *testing.T, applied interface{}) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/agents/abc123/" {
+            http.NotFound(w, r)
+            return
+        }
+        agent := map[string]interface{}{"agent_id": "abc123", "hostname": "WS-01"}
+        if applied != nil {
+            agent["applied_policies"] = applied
+        }
+        writeJSON(t, w, agent)
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func TestAgentEffectivePolicyDataSourceRead_InheritedFromClient(t *testing.T) {
+    // Neither the agent nor its site has a policy, so the client policy wins
+    // over the default one.
+    server := effectivePolicyServer(t, map[string]interface{}{
+        "agent_policy":   nil,
+        "site_policy":    nil,
+        "client_policy":  map[string]interface{}{"id": 4, "name": "Acme Workstations"},
+        "default_policy": map[string]interface{}{"id": 1, "name": "Default Workstations"},
+    })
+
+    d := &AgentEffectivePolicyDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &AgentEffectivePolicyDataSourceModel{AgentId: types.StringValue("abc123")})
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentEffectivePolicyDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.EffectivePolicyId.ValueInt64() != 4 || state.EffectivePolicyName.ValueString() != "Acme Workstations" {
+        t.Errorf("expected the client policy to be effective, got %d %q", state.EffectivePolicyId.ValueInt64(), state.EffectivePolicyName.ValueString())
+    }
+    if state.InheritedFrom.ValueString() != "client" {
+        t.Errorf("expected inherited_from client, got %q", state.InheritedFrom.ValueString())
+    }
+    if !state.AgentPolicyId.IsNull() || !state.SitePolicyId.IsNull() {
+        t.Errorf("expected no agent or site policy, got %v and %v", state.AgentPolicyId, state.SitePolicyId)
+    }
+    if state.DefaultPolicyId.ValueInt64() != 1 {
+        t.Errorf("expected default_policy_id 1, got %v", state.DefaultPolicyId)
+    }
+}
+
+func TestAgentEffectivePolicyDataSourceRead_NoPolicy(t *testing.T) {
+    server := effectivePolicyServer(t, map[string]interface{}{
+        "agent_policy":   nil,
+        "site_policy":    nil,
+        "client_policy":  nil,
+        "default_policy": nil,
+    })
+
+    d := &AgentEffectivePolicyDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &AgentEffectivePolicyDataSourceModel{AgentId: types.StringValue("abc123")})
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentEffectivePolicyDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.EffectivePolicyId.IsNull() || !state.InheritedFrom.IsNull() {
+        t.Errorf("expected no effective policy, got %v from %v", state.EffectivePolicyId, state.InheritedFrom)
+    }
+}
+
+func TestAgentEffectivePolicyDataSourceRead_MissingAppliedPolicies(t *testing.T) {
+    d := &AgentEffectivePolicyDataSource{client: newTestClient(effectivePolicyServer(t, nil))}
+    resp := readDataSource(t, d, &AgentEffectivePolicyDataSourceModel{AgentId: types.StringValue("abc123")})
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when the agent has no applied_policies")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Effective Policy Unavailable" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+}
@@ -0,0 +1,565 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AgentPatchPolicyResource{}
+var _ resource.ResourceWithImportState = &AgentPatchPolicyResource{}
+var _ resource.ResourceWithIdentity = &AgentPatchPolicyResource{}
+var _ resource.ResourceWithValidateConfig = &AgentPatchPolicyResource{}
+
+// patchPolicyInherit is the value of a patch policy setting taken from the
+// automation policy of the agent.
+const patchPolicyInherit = "inherit"
+
+// Values of the patch policy settings an agent can override.
+var (
+    patchApprovalModes = map[string]bool{"manual": true, "approve": true, "ignore": true}
+    patchRebootModes   = map[string]bool{"never": true, "required": true, "always": true}
+    patchRunFrequency  = map[string]bool{"daily": true, "monthly": true}
+)
+
+func NewAgentPatchPolicyResource() resource.Resource {
+    return &AgentPatchPolicyResource{}
+}
+
+// AgentPatchPolicyResource manages the Windows update policy overrides of one
+// agent.
+type AgentPatchPolicyResource struct {
+    client *ClientConfig
+}
+
+// AgentPatchPolicyResourceModel describes the resource data model.
+type AgentPatchPolicyResourceModel struct {
+    Id                   types.String `tfsdk:"id"`
+    AgentId              types.String `tfsdk:"agent_id"`
+    Inherit              types.Bool   `tfsdk:"inherit"`
+    Critical             types.String `tfsdk:"critical"`
+    Important            types.String `tfsdk:"important"`
+    Moderate             types.String `tfsdk:"moderate"`
+    Low                  types.String `tfsdk:"low"`
+    Other                types.String `tfsdk:"other"`
+    RebootAfterInstall   types.String `tfsdk:"reboot_after_install"`
+    RunTimeFrequency     types.String `tfsdk:"run_time_frequency"`
+    RunTimeHour          types.Int64  `tfsdk:"run_time_hour"`
+    RunTimeDays          types.Set    `tfsdk:"run_time_days"`
+    RunTimeDay           types.Int64  `tfsdk:"run_time_day"`
+    ReprocessFailed      types.Bool   `tfsdk:"reprocess_failed"`
+    ReprocessFailedTimes types.Int64  `tfsdk:"reprocess_failed_times"`
+}
+
+// patchSetting is a patch policy setting whose null value inherits from the
+// automation policy: the model attribute, which is also the API field, and
+// the values it accepts.
+type patchSetting struct {
+    attribute string
+    value     *types.String
+    allowed   map[string]bool
+}
+
+// settings returns the string settings of m in schema order.
+func (m *AgentPatchPolicyResourceModel) settings() []patchSetting {
+    return []patchSetting{
+        {attribute: "critical", value: &m.Critical, allowed: patchApprovalModes},
+        {attribute: "important", value: &m.Important, allowed: patchApprovalModes},
+        {attribute: "moderate", value: &m.Moderate, allowed: patchApprovalModes},
+        {attribute: "low", value: &m.Low, allowed: patchApprovalModes},
+        {attribute: "other", value: &m.Other, allowed: patchApprovalModes},
+        {attribute: "reboot_after_install", value: &m.RebootAfterInstall, allowed: patchRebootModes},
+        {attribute: "run_time_frequency", value: &m.RunTimeFrequency, allowed: patchRunFrequency},
+    }
+}
+
+func (r *AgentPatchPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_patch_policy"
+}
+
+func (r *AgentPatchPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    approval := func(severity string) schema.StringAttribute {
+        return schema.StringAttribute{
+            MarkdownDescription: fmt.Sprintf("Approval of %s updates: `manual`, `approve` or `ignore`. Inherited from the automation policy when not set.", severity),
+            Optional:            true,
+        }
+    }
+
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Manages the patch policy overrides of an agent. Settings that are not set inherit from the automation policy of the agent; " +
+            "`inherit = true` makes the agent inherit all of them. Destroying the resource makes the agent inherit every setting again.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of the resource, same as `agent_id`",
+                Computed:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.UseStateForUnknown(),
+                },
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent whose patch policy is managed",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "inherit": schema.BoolAttribute{
+                MarkdownDescription: "When true, the agent inherits every patch policy setting and no other setting may be set",
+                Optional:            true,
+            },
+            "critical":  approval("critical"),
+            "important": approval("important"),
+            "moderate":  approval("moderate"),
+            "low":       approval("low"),
+            "other":     approval("other"),
+            "reboot_after_install": schema.StringAttribute{
+                MarkdownDescription: "Reboot after installing updates: `never`, `required` or `always`. Inherited when not set.",
+                Optional:            true,
+            },
+            "run_time_frequency": schema.StringAttribute{
+                MarkdownDescription: "Installation schedule: `daily` on `run_time_days` or `monthly` on `run_time_day`, both at `run_time_hour`. Inherited when not set.",
+                Optional:            true,
+            },
+            "run_time_hour": schema.Int64Attribute{
+                MarkdownDescription: "Hour (0-23) updates are installed at. Required with `run_time_frequency`.",
+                Optional:            true,
+            },
+            "run_time_days": schema.SetAttribute{
+                MarkdownDescription: "Weekdays updates are installed on, 0 (Monday) to 6 (Sunday). Required with `run_time_frequency = \"daily\"`.",
+                Optional:            true,
+                ElementType:         types.Int64Type,
+            },
+            "run_time_day": schema.Int64Attribute{
+                MarkdownDescription: "Day of the month (1-31) updates are installed on. Required with `run_time_frequency = \"monthly\"`.",
+                Optional:            true,
+            },
+            "reprocess_failed": schema.BoolAttribute{
+                MarkdownDescription: "Whether failed updates are retried. Inherited when not set.",
+                Optional:            true,
+            },
+            "reprocess_failed_times": schema.Int64Attribute{
+                MarkdownDescription: "How often a failed update is retried. Required with `reprocess_failed = true`.",
+                Optional:            true,
+            },
+        },
+    }
+}
+
+func (r *AgentPatchPolicyResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = agentIdentitySchema()
+}
+
+func (r *AgentPatchPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *AgentPatchPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data AgentPatchPolicyResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    invalid := func(attribute string, format string, args ...interface{}) {
+        resp.Diagnostics.AddAttributeError(path.Root(attribute), "Invalid Patch Policy", fmt.Sprintf(format, args...))
+    }
+
+    if data.Inherit.ValueBool() {
+        for _, attribute := range data.overrides() {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(attribute),
+                "Conflicting Patch Policy Settings",
+                fmt.Sprintf("%s cannot be set together with inherit = true.", attribute),
+            )
+        }
+        return
+    }
+
+    for _, setting := range data.settings() {
+        value := *setting.value
+        if value.IsNull() || value.IsUnknown() || setting.allowed[value.ValueString()] {
+            continue
+        }
+        invalid(setting.attribute, "%s must be one of %s, got: %q. Leave it unset to inherit from the automation policy.",
+            setting.attribute, allowedList(setting.allowed), value.ValueString())
+    }
+
+    if !data.RunTimeHour.IsNull() && !data.RunTimeHour.IsUnknown() && (data.RunTimeHour.ValueInt64() < 0 || data.RunTimeHour.ValueInt64() > 23) {
+        invalid("run_time_hour", "run_time_hour must be between 0 and 23, got %d.", data.RunTimeHour.ValueInt64())
+    }
+    if !data.RunTimeDay.IsNull() && !data.RunTimeDay.IsUnknown() && (data.RunTimeDay.ValueInt64() < 1 || data.RunTimeDay.ValueInt64() > 31) {
+        invalid("run_time_day", "run_time_day must be between 1 and 31, got %d.", data.RunTimeDay.ValueInt64())
+    }
+    if !data.RunTimeDays.IsNull() && !data.RunTimeDays.IsUnknown() {
+        var days []types.Int64
+        resp.Diagnostics.Append(data.RunTimeDays.ElementsAs(ctx, &days, false)...)
+        for _, day := range days {
+            if !day.IsUnknown() && (day.ValueInt64() < 0 || day.ValueInt64() > 6) {
+                invalid("run_time_days", "run_time_days must be weekdays from 0 (Monday) to 6 (Sunday), got %d.", day.ValueInt64())
+            }
+        }
+    }
+
+    // The schedule attributes only apply to their frequency
+    if !data.RunTimeFrequency.IsUnknown() {
+        frequency := data.RunTimeFrequency.ValueString()
+        if frequency != "" && data.RunTimeHour.IsNull() {
+            invalid("run_time_hour", "run_time_hour is required with run_time_frequency.")
+        }
+        if frequency == "" && !data.RunTimeHour.IsNull() {
+            invalid("run_time_hour", "run_time_hour requires run_time_frequency to be set.")
+        }
+        if frequency == "daily" && data.RunTimeDays.IsNull() {
+            invalid("run_time_days", "run_time_days is required with run_time_frequency = \"daily\".")
+        }
+        if frequency != "daily" && !data.RunTimeDays.IsNull() {
+            invalid("run_time_days", "run_time_days requires run_time_frequency = \"daily\".")
+        }
+        if frequency == "monthly" && data.RunTimeDay.IsNull() {
+            invalid("run_time_day", "run_time_day is required with run_time_frequency = \"monthly\".")
+        }
+        if frequency != "monthly" && !data.RunTimeDay.IsNull() {
+            invalid("run_time_day", "run_time_day requires run_time_frequency = \"monthly\".")
+        }
+    }
+
+    if data.ReprocessFailed.ValueBool() && data.ReprocessFailedTimes.IsNull() {
+        invalid("reprocess_failed_times", "reprocess_failed_times is required with reprocess_failed = true.")
+    }
+    if !data.ReprocessFailedTimes.IsNull() && !data.ReprocessFailed.IsUnknown() && !data.ReprocessFailed.ValueBool() {
+        invalid("reprocess_failed_times", "reprocess_failed_times requires reprocess_failed = true.")
+    }
+    if !data.ReprocessFailedTimes.IsNull() && !data.ReprocessFailedTimes.IsUnknown() && data.ReprocessFailedTimes.ValueInt64() < 1 {
+        invalid("reprocess_failed_times", "reprocess_failed_times must be at least 1, got %d.", data.ReprocessFailedTimes.ValueInt64())
+    }
+}
+
+func (r *AgentPatchPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentPatchPolicyResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.apply(ctx, data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = data.AgentId
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+}
+
+func (r *AgentPatchPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentPatchPolicyResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+
+    policy, found, err := r.storedPolicy(ctx, data.AgentId.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent patch policy, got error: %s", err))
+        return
+    }
+    if !found {
+        resp.State.RemoveResource(ctx)
+        return
+    }
+
+    resp.Diagnostics.Append(data.setFromAPI(ctx, policy)...)
+    data.Id = data.AgentId
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AgentPatchPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentPatchPolicyResourceModel
+    var state AgentPatchPolicyResourceModel
+
+    // Get the planned values
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.apply(ctx, data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = state.Id
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "agent_id", data.AgentId, &resp.Diagnostics)
+}
+
+func (r *AgentPatchPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentPatchPolicyResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Inherit everything again; an agent that is already gone has no policy
+    // left to reset
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, data.AgentId.ValueString()), map[string]interface{}{
+        "winupdatepolicy": []map[string]interface{}{inheritedPatchPolicy()},
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reset agent patch policy, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK && statusCode != http.StatusNotFound {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reset agent patch policy, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+    }
+}
+
+func (r *AgentPatchPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks may use the identity instead of an agent ID
+    resource.ImportStatePassthroughWithIdentity(ctx, path.Root("agent_id"), path.Root("agent_id"), req, resp)
+    resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("agent_id"), req, resp)
+}
+
+// apply writes the patch policy of data to its agent.
+func (r *AgentPatchPolicyResource) apply(ctx context.Context, data AgentPatchPolicyResourceModel, diags *diag.Diagnostics) {
+    body, bodyDiags := data.requestBody(ctx)
+    diags.Append(bodyDiags...)
+    if diags.HasError() {
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, data.AgentId.ValueString()), map[string]interface{}{
+        "winupdatepolicy": []map[string]interface{}{body},
+    })
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update agent patch policy, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update agent patch policy, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+    }
+}
+
+// storedPolicy returns the patch policy of an agent. found is false when the
+// agent does not exist.
+func (r *AgentPatchPolicyResource) storedPolicy(ctx context.Context, agentId string) (map[string]interface{}, bool, error) {
+    statusCode, respBody, err := r.client.sendJSON(ctx, "GET", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId), nil)
+    if err != nil {
+        return nil, false, err
+    }
+    if statusCode == http.StatusNotFound {
+        return nil, false, nil
+    }
+    if statusCode != http.StatusOK {
+        return nil, false, fmt.Errorf("unexpected status code: %d", statusCode)
+    }
+
+    var agent struct {
+        WinUpdatePolicy []map[string]interface{} `json:"winupdatepolicy"`
+    }
+    if err := json.Unmarshal(respBody, &agent); err != nil {
+        return nil, false, fmt.Errorf("unable to parse response: %w", err)
+    }
+    if len(agent.WinUpdatePolicy) == 0 {
+        return nil, false, fmt.Errorf("agent %s has no patch policy", agentId)
+    }
+    return agent.WinUpdatePolicy[0], true, nil
+}
+
+// inheritedPatchPolicy returns the policy body inheriting every setting.
+func inheritedPatchPolicy() map[string]interface{} {
+    body := map[string]interface{}{"reprocess_failed_inherit": true}
+    for _, setting := range (&AgentPatchPolicyResourceModel{}).settings() {
+        body[setting.attribute] = patchPolicyInherit
+    }
+    return body
+}
+
+// requestBody returns the policy body for m. Settings that are not set are
+// sent as inherited; schedule values are only sent for their frequency.
+func (m AgentPatchPolicyResourceModel) requestBody(ctx context.Context) (map[string]interface{}, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    body := inheritedPatchPolicy()
+    if m.Inherit.ValueBool() {
+        return body, diags
+    }
+
+    for _, setting := range m.settings() {
+        if !setting.value.IsNull() {
+            body[setting.attribute] = setting.value.ValueString()
+        }
+    }
+
+    if !m.RunTimeHour.IsNull() {
+        body["run_time_hour"] = m.RunTimeHour.ValueInt64()
+    }
+    if !m.RunTimeDays.IsNull() {
+        var days []int64
+        diags.Append(m.RunTimeDays.ElementsAs(ctx, &days, false)...)
+        sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+        body["run_time_days"] = days
+    }
+    if !m.RunTimeDay.IsNull() {
+        body["run_time_day"] = m.RunTimeDay.ValueInt64()
+    }
+
+    if !m.ReprocessFailed.IsNull() {
+        body["reprocess_failed_inherit"] = false
+        body["reprocess_failed"] = m.ReprocessFailed.ValueBool()
+    }
+    if !m.ReprocessFailedTimes.IsNull() {
+        body["reprocess_failed_times"] = m.ReprocessFailedTimes.ValueInt64()
+    }
+
+    return body, diags
+}
+
+// setFromAPI updates m from a stored patch policy. Inherited settings become
+// null, and inherit stays true only while every setting is inherited.
+func (m *AgentPatchPolicyResourceModel) setFromAPI(ctx context.Context, policy map[string]interface{}) diag.Diagnostics {
+    var diags diag.Diagnostics
+
+    for _, setting := range m.settings() {
+        value, _ := policy[setting.attribute].(string)
+        if value == "" || value == patchPolicyInherit {
+            *setting.value = types.StringNull()
+        } else {
+            *setting.value = types.StringValue(value)
+        }
+    }
+
+    m.RunTimeHour, m.RunTimeDay = types.Int64Null(), types.Int64Null()
+    m.RunTimeDays = types.SetNull(types.Int64Type)
+    if !m.RunTimeFrequency.IsNull() {
+        m.RunTimeHour = int64Value(policy["run_time_hour"])
+    }
+    switch m.RunTimeFrequency.ValueString() {
+    case "daily":
+        days := []attr.Value{}
+        items, _ := policy["run_time_days"].([]interface{})
+        for _, item := range items {
+            if day, ok := item.(float64); ok {
+                days = append(days, types.Int64Value(int64(day)))
+            }
+        }
+        var setDiags diag.Diagnostics
+        m.RunTimeDays, setDiags = types.SetValue(types.Int64Type, days)
+        diags.Append(setDiags...)
+    case "monthly":
+        m.RunTimeDay = int64Value(policy["run_time_day"])
+    }
+
+    m.ReprocessFailed, m.ReprocessFailedTimes = types.BoolNull(), types.Int64Null()
+    if inherit, _ := policy["reprocess_failed_inherit"].(bool); !inherit {
+        reprocess, _ := policy["reprocess_failed"].(bool)
+        m.ReprocessFailed = types.BoolValue(reprocess)
+        if reprocess {
+            m.ReprocessFailedTimes = int64Value(policy["reprocess_failed_times"])
+        }
+    }
+
+    if m.Inherit.ValueBool() && len(m.overrides()) > 0 {
+        m.Inherit = types.BoolValue(false)
+    }
+
+    return diags
+}
+
+// overrides returns the attributes of m that override the automation policy.
+func (m *AgentPatchPolicyResourceModel) overrides() []string {
+    var attributes []string
+    for _, setting := range m.settings() {
+        if !setting.value.IsNull() {
+            attributes = append(attributes, setting.attribute)
+        }
+    }
+    if !m.RunTimeHour.IsNull() {
+        attributes = append(attributes, "run_time_hour")
+    }
+    if !m.RunTimeDays.IsNull() {
+        attributes = append(attributes, "run_time_days")
+    }
+    if !m.RunTimeDay.IsNull() {
+        attributes = append(attributes, "run_time_day")
+    }
+    if !m.ReprocessFailed.IsNull() {
+        attributes = append(attributes, "reprocess_failed")
+    }
+    if !m.ReprocessFailedTimes.IsNull() {
+        attributes = append(attributes, "reprocess_failed_times")
+    }
+    return attributes
+}
+
+// allowedList formats the keys of allowed as a sorted, comma separated list.
+func allowedList(allowed map[string]bool) string {
+    values := make([]string, 0, len(allowed))
+    for value := range allowed {
+        values = append(values, value)
+    }
+    sort.Strings(values)
+    return strings.Join(values, ", ")
+}
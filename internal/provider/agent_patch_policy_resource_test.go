@@ -0,0 +1,288 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// patchPolicyServer serves agent agent-1 with policy as its patch policy,
+// applying updates to it. Policy bodies of updates are recorded.
+func patchPolicyServer(t *testing.T, policy map[string]interface{}) (*httptest.Server, *[]map[string]interface{}) {
+    t.Helper()
+    var updates []map[string]interface{}
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/agents/agent-1/" {
+            http.NotFound(w, r)
+            return
+        }
+        switch r.Method {
+        case http.MethodGet:
+            writeJSON(t, w, map[string]interface{}{"agent_id": "agent-1", "winupdatepolicy": []interface{}{policy}})
+        case http.MethodPut:
+            var body struct {
+                WinUpdatePolicy []map[string]interface{} `json:"winupdatepolicy"`
+            }
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            updates = append(updates, body.WinUpdatePolicy[0])
+            for field, value := range body.WinUpdatePolicy[0] {
+                policy[field] = value
+            }
+            writeJSON(t, w, "The agent was updated successfully")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &updates
+}
+
+// inheritedPolicy is a stored patch policy inheriting every setting.
+func inheritedPolicy() map[string]interface{} {
+    return map[string]interface{}{
+        "critical": "inherit", "important": "inherit", "moderate": "inherit", "low": "inherit", "other": "inherit",
+        "reboot_after_install": "inherit", "run_time_frequency": "inherit", "run_time_hour": 3, "run_time_days": []interface{}{},
+        "run_time_day": 1, "reprocess_failed_inherit": true, "reprocess_failed": false, "reprocess_failed_times": 5,
+    }
+}
+
+func patchPolicyModel() *AgentPatchPolicyResourceModel {
+    return &AgentPatchPolicyResourceModel{
+        Id:                   types.StringNull(),
+        AgentId:              types.StringValue("agent-1"),
+        Inherit:              types.BoolNull(),
+        Critical:             types.StringNull(),
+        Important:            types.StringNull(),
+        Moderate:             types.StringNull(),
+        Low:                  types.StringNull(),
+        Other:                types.StringNull(),
+        RebootAfterInstall:   types.StringNull(),
+        RunTimeFrequency:     types.StringNull(),
+        RunTimeHour:          types.Int64Null(),
+        RunTimeDays:          types.SetNull(types.Int64Type),
+        RunTimeDay:           types.Int64Null(),
+        ReprocessFailed:      types.BoolNull(),
+        ReprocessFailedTimes: types.Int64Null(),
+    }
+}
+
+func TestAgentPatchPolicyResourceCreate_Overrides(t *testing.T) {
+    server, updates := patchPolicyServer(t, inheritedPolicy())
+
+    model := patchPolicyModel()
+    model.Critical = types.StringValue("approve")
+    model.Other = types.StringValue("ignore")
+    model.RebootAfterInstall = types.StringValue("required")
+    model.RunTimeFrequency = types.StringValue("daily")
+    model.RunTimeHour = types.Int64Value(22)
+    model.RunTimeDays = types.SetValueMust(types.Int64Type, []attr.Value{types.Int64Value(5), types.Int64Value(1)})
+
+    r := &AgentPatchPolicyResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := map[string]interface{}{
+        "critical": "approve", "important": "inherit", "moderate": "inherit", "low": "inherit", "other": "ignore",
+        "reboot_after_install": "required", "run_time_frequency": "daily", "run_time_hour": float64(22),
+        "run_time_days": []interface{}{float64(1), float64(5)}, "reprocess_failed_inherit": true,
+    }
+    if len(*updates) != 1 || !reflect.DeepEqual((*updates)[0], expected) {
+        t.Fatalf("unexpected updates:\n got: %v\nwant: %v", *updates, expected)
+    }
+
+    // Reading the policy back shows no difference
+    var state AgentPatchPolicyResourceModel
+    resp.State.Get(context.Background(), &state)
+    readResp := readResource(t, r, &state)
+    var read AgentPatchPolicyResourceModel
+    readResp.State.Get(context.Background(), &read)
+    if !read.RunTimeDays.Equal(state.RunTimeDays) {
+        t.Errorf("expected run_time_days %s, got %s", state.RunTimeDays, read.RunTimeDays)
+    }
+    // Sets compare equal regardless of element order, which DeepEqual does not
+    read.RunTimeDays = state.RunTimeDays
+    if !reflect.DeepEqual(read, state) {
+        t.Errorf("expected the read state to match the applied state:\n got: %+v\nwant: %+v", read, state)
+    }
+}
+
+func TestAgentPatchPolicyResourceCreate_Inherit(t *testing.T) {
+    policy := inheritedPolicy()
+    policy["critical"] = "manual"
+    policy["reprocess_failed_inherit"] = false
+    server, updates := patchPolicyServer(t, policy)
+
+    model := patchPolicyModel()
+    model.Inherit = types.BoolValue(true)
+
+    r := &AgentPatchPolicyResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(*updates) != 1 || !reflect.DeepEqual((*updates)[0], jsonRoundTrip(t, inheritedPatchPolicy())) {
+        t.Errorf("expected every setting to be inherited, got %v", *updates)
+    }
+}
+
+func TestAgentPatchPolicyResourceRead_OverrideDrift(t *testing.T) {
+    policy := inheritedPolicy()
+    policy["low"] = "ignore"
+    policy["run_time_frequency"] = "monthly"
+    policy["run_time_day"] = 15
+    policy["reprocess_failed_inherit"] = false
+    policy["reprocess_failed"] = true
+    server, _ := patchPolicyServer(t, policy)
+
+    // State inheriting everything while an override was made in the UI
+    model := patchPolicyModel()
+    model.Id = types.StringValue("agent-1")
+    model.Inherit = types.BoolValue(true)
+
+    r := &AgentPatchPolicyResource{client: newTestClient(server)}
+    resp := readResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentPatchPolicyResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Inherit.ValueBool() {
+        t.Error("expected inherit to be false while settings are overridden")
+    }
+    if state.Low.ValueString() != "ignore" || !state.Critical.IsNull() {
+        t.Errorf("expected only low to be overridden, got low %s and critical %s", state.Low, state.Critical)
+    }
+    if state.RunTimeDay.ValueInt64() != 15 || state.RunTimeHour.ValueInt64() != 3 || !state.RunTimeDays.IsNull() {
+        t.Errorf("unexpected monthly schedule: %s, %s, %s", state.RunTimeDay, state.RunTimeHour, state.RunTimeDays)
+    }
+    if !state.ReprocessFailed.ValueBool() || state.ReprocessFailedTimes.ValueInt64() != 5 {
+        t.Errorf("unexpected reprocess settings: %s, %s", state.ReprocessFailed, state.ReprocessFailedTimes)
+    }
+}
+
+func TestAgentPatchPolicyResourceDelete_InheritsEverything(t *testing.T) {
+    policy := inheritedPolicy()
+    policy["critical"] = "approve"
+    server, updates := patchPolicyServer(t, policy)
+
+    model := patchPolicyModel()
+    model.Id = types.StringValue("agent-1")
+    model.Critical = types.StringValue("approve")
+
+    r := &AgentPatchPolicyResource{client: newTestClient(server)}
+    resp := deleteResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(*updates) != 1 || (*updates)[0]["critical"] != patchPolicyInherit || (*updates)[0]["reprocess_failed_inherit"] != true {
+        t.Errorf("expected every setting to be inherited, got %v", *updates)
+    }
+}
+
+func TestAgentPatchPolicyResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &AgentPatchPolicyResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    cases := map[string]struct {
+        modify  func(m *AgentPatchPolicyResourceModel)
+        summary string
+    }{
+        "inherit": {
+            modify: func(m *AgentPatchPolicyResourceModel) { m.Inherit = types.BoolValue(true) },
+        },
+        "monthly schedule": {
+            modify: func(m *AgentPatchPolicyResourceModel) {
+                m.RunTimeFrequency = types.StringValue("monthly")
+                m.RunTimeHour = types.Int64Value(0)
+                m.RunTimeDay = types.Int64Value(31)
+            },
+        },
+        "inherit with override": {
+            modify: func(m *AgentPatchPolicyResourceModel) {
+                m.Inherit = types.BoolValue(true)
+                m.Critical = types.StringValue("approve")
+            },
+            summary: "Conflicting Patch Policy Settings",
+        },
+        "explicit inherit value": {
+            modify:  func(m *AgentPatchPolicyResourceModel) { m.Important = types.StringValue("inherit") },
+            summary: "Invalid Patch Policy",
+        },
+        "unknown reboot mode": {
+            modify:  func(m *AgentPatchPolicyResourceModel) { m.RebootAfterInstall = types.StringValue("sometimes") },
+            summary: "Invalid Patch Policy",
+        },
+        "daily without days": {
+            modify: func(m *AgentPatchPolicyResourceModel) {
+                m.RunTimeFrequency = types.StringValue("daily")
+                m.RunTimeHour = types.Int64Value(3)
+            },
+            summary: "Invalid Patch Policy",
+        },
+        "hour out of range": {
+            modify: func(m *AgentPatchPolicyResourceModel) {
+                m.RunTimeFrequency = types.StringValue("monthly")
+                m.RunTimeHour = types.Int64Value(24)
+                m.RunTimeDay = types.Int64Value(1)
+            },
+            summary: "Invalid Patch Policy",
+        },
+        "reprocess without times": {
+            modify:  func(m *AgentPatchPolicyResourceModel) { m.ReprocessFailed = types.BoolValue(true) },
+            summary: "Invalid Patch Policy",
+        },
+    }
+
+    for name, tc := range cases {
+        model := patchPolicyModel()
+        tc.modify(model)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if tc.summary == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != tc.summary {
+            t.Errorf("%s: expected a %q error, got: %v", name, tc.summary, resp.Diagnostics)
+        }
+    }
+}
+
+// jsonRoundTrip returns value as decoded from its JSON encoding.
+func jsonRoundTrip(t *testing.T, value interface{}) map[string]interface{} {
+    t.Helper()
+    encoded, err := json.Marshal(value)
+    if err != nil {
+        t.Fatalf("unable to encode: %s", err)
+    }
+    var decoded map[string]interface{}
+    if err := json.Unmarshal(encoded, &decoded); err != nil {
+        t.Fatalf("unable to decode: %s", err)
+    }
+    return decoded
+}
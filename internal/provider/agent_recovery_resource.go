@@ -0,0 +1,237 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AgentRecoveryResource{}
+var _ resource.ResourceWithValidateConfig = &AgentRecoveryResource{}
+
+// Recovery modes accepted by the agent recover endpoint.
+const (
+    // agentRecoveryModeMesh restarts the MeshCentral agent. The command is
+    // sent through the Tactical agent, so that agent must be online.
+    agentRecoveryModeMesh = "mesh"
+
+    // agentRecoveryModeTacAgent restarts the Tactical agent service. The
+    // command is sent through MeshCentral, so it also reaches agents whose
+    // Tactical agent is offline.
+    agentRecoveryModeTacAgent = "tacagent"
+)
+
+func NewAgentRecoveryResource() resource.Resource {
+    return &AgentRecoveryResource{}
+}
+
+// AgentRecoveryResource submits an agent recovery when it is created.
+type AgentRecoveryResource struct {
+    client *ClientConfig
+}
+
+// AgentRecoveryResourceModel describes the resource data model.
+type AgentRecoveryResourceModel struct {
+    Id          types.String `tfsdk:"id"`
+    AgentId     types.String `tfsdk:"agent_id"`
+    Mode        types.String `tfsdk:"mode"`
+    Triggers    types.Map    `tfsdk:"triggers"`
+    AgentStatus types.String `tfsdk:"agent_status"`
+    Message     types.String `tfsdk:"message"`
+}
+
+func (r *AgentRecoveryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agent_recovery"
+}
+
+func (r *AgentRecoveryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Submits a recovery for an agent in a bad state when created. " +
+            "`tacagent` restarts the Tactical agent service through MeshCentral and also works while the Tactical agent is offline; " +
+            "`mesh` restarts the MeshCentral agent through the Tactical agent and requires the agent to be online. " +
+            "Destroying this resource does nothing on the server.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this recovery submission",
+                Computed:            true,
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent to recover",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "mode": schema.StringAttribute{
+                MarkdownDescription: "Recovery mode: `tacagent` (Tactical agent service) or `mesh` (MeshCentral agent)",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the recovery to be submitted again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "agent_status": schema.StringAttribute{
+                MarkdownDescription: "Status of the agent (online, offline or overdue) when the recovery was submitted",
+                Computed:            true,
+            },
+            "message": schema.StringAttribute{
+                MarkdownDescription: "Response message returned by Tactical RMM",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *AgentRecoveryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data AgentRecoveryResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.Mode.IsNull() || data.Mode.IsUnknown() {
+        return
+    }
+
+    switch data.Mode.ValueString() {
+    case agentRecoveryModeMesh, agentRecoveryModeTacAgent:
+    default:
+        resp.Diagnostics.AddAttributeError(
+            path.Root("mode"),
+            "Invalid Recovery Mode",
+            fmt.Sprintf("mode must be %q or %q, got %q.", agentRecoveryModeTacAgent, agentRecoveryModeMesh, data.Mode.ValueString()),
+        )
+    }
+}
+
+func (r *AgentRecoveryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *AgentRecoveryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentRecoveryResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    agentId := data.AgentId.ValueString()
+    agent, err := r.client.getObject(ctx, fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent %s, got error: %s", agentId, err))
+        return
+    }
+
+    status, ok := agent["status"].(string)
+    if !ok {
+        lastSeen, seen := agentLastSeen(agent)
+        status = agentStatus(agent, lastSeen, seen, time.Now())
+    }
+
+    // Mesh recovery is delivered by the Tactical agent, which cannot receive
+    // it while offline
+    if data.Mode.ValueString() == agentRecoveryModeMesh && status != agentStatusOnline {
+        resp.Diagnostics.AddError(
+            "Agent Offline",
+            fmt.Sprintf("Agent %s is %s. Mesh recovery is sent through the Tactical agent and needs it online; "+
+                "use mode = %q to restart the Tactical agent through MeshCentral instead.", agentId, status, agentRecoveryModeTacAgent),
+        )
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/agents/%s/recover/", r.client.BaseURL, agentId), map[string]interface{}{
+        "mode": data.Mode.ValueString(),
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to submit agent recovery, got error: %s", err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to submit agent recovery, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.AgentStatus = types.StringValue(status)
+    data.Message = types.StringValue(errorMessage(respBody))
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AgentRecoveryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Recovery is a one-off operation, there is no remote object to refresh
+}
+
+func (r *AgentRecoveryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AgentRecoveryResourceModel
+    var state AgentRecoveryResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.AgentStatus = state.AgentStatus
+    data.Message = state.Message
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AgentRecoveryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // A submitted recovery cannot be undone, removing the resource only drops it from state
+}
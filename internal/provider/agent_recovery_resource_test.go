@@ -0,0 +1,101 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// recoveryServer serves agent agent-1 with the given status and records the
+// mode of every recovery submission.
+func recoveryServer(t *testing.T, status string, recoverStatus int, recoverBody interface{}) (*httptest.Server, *[]string) {
+    t.Helper()
+    var modes []string
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/agent-1/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "agent-1", "hostname": "web01", "status": status})
+        case r.Method == http.MethodPost && r.URL.Path == "/agents/agent-1/recover/":
+            var body struct {
+                Mode string `json:"mode"`
+            }
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode recovery request: %s", err)
+            }
+            modes = append(modes, body.Mode)
+            w.WriteHeader(recoverStatus)
+            writeJSON(t, w, recoverBody)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &modes
+}
+
+func recoveryModel(mode string) *AgentRecoveryResourceModel {
+    return &AgentRecoveryResourceModel{
+        AgentId:  types.StringValue("agent-1"),
+        Mode:     types.StringValue(mode),
+        Triggers: types.MapNull(types.StringType),
+    }
+}
+
+func TestAgentRecoveryResourceCreate_SubmitsRecovery(t *testing.T) {
+    server, modes := recoveryServer(t, agentStatusOverdue, http.StatusOK, "Recovery will be attempted shortly")
+
+    r := &AgentRecoveryResource{client: newTestClient(server)}
+    resp := createResource(t, r, recoveryModel(agentRecoveryModeTacAgent))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(*modes) != 1 || (*modes)[0] != agentRecoveryModeTacAgent {
+        t.Errorf("expected one tacagent recovery, got %v", *modes)
+    }
+
+    var state AgentRecoveryResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Message.ValueString() != "Recovery will be attempted shortly" {
+        t.Errorf("unexpected message: %s", state.Message.ValueString())
+    }
+    if state.AgentStatus.ValueString() != agentStatusOverdue {
+        t.Errorf("expected agent_status overdue, got %s", state.AgentStatus.ValueString())
+    }
+}
+
+func TestAgentRecoveryResourceCreate_MeshRequiresOnlineAgent(t *testing.T) {
+    server, modes := recoveryServer(t, agentStatusOffline, http.StatusOK, "Successfully completed recovery")
+
+    r := &AgentRecoveryResource{client: newTestClient(server)}
+    resp := createResource(t, r, recoveryModel(agentRecoveryModeMesh))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for mesh recovery of an offline agent")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Agent Offline" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+    if len(*modes) != 0 {
+        t.Errorf("expected no recovery to be submitted, got %v", *modes)
+    }
+}
+
+func TestAgentRecoveryResourceCreate_ReportsRejection(t *testing.T) {
+    server, _ := recoveryServer(t, agentStatusOnline, http.StatusBadRequest, "Unable to complete recovery: timeout")
+
+    r := &AgentRecoveryResource{client: newTestClient(server)}
+    resp := createResource(t, r, recoveryModel(agentRecoveryModeMesh))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when the server rejects the recovery")
+    }
+    if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "Unable to complete recovery: timeout") {
+        t.Errorf("expected the server message in the error, got: %s", detail)
+    }
+}
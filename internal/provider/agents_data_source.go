@@ -0,0 +1,660 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AgentsDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &AgentsDataSource{}
+
+// Agent statuses as reported by Tactical RMM.
+const (
+    agentStatusOnline  = "online"
+    agentStatusOffline = "offline"
+    agentStatusOverdue = "overdue"
+)
+
+// Tactical RMM defaults for the per agent offline and overdue thresholds, in
+// minutes, used when the agent does not report its own.
+const (
+    defaultAgentOfflineTime = 4
+    defaultAgentOverdueTime = 30
+)
+
+// agentCustomFieldFilterAttrTypes describes the custom_field filter.
+var agentCustomFieldFilterAttrTypes = map[string]attr.Type{
+    "name":  types.StringType,
+    "value": types.StringType,
+}
+
+// agentAttrTypes describes one entry of the agents list.
+var agentAttrTypes = map[string]attr.Type{
+    "agent_id":        types.StringType,
+    "hostname":        types.StringType,
+    "client_name":     types.StringType,
+    "site_name":       types.StringType,
+    "plat":            types.StringType,
+    "status":          types.StringType,
+    "last_seen":       types.StringType,
+    "offline_minutes": types.Int64Type,
+}
+
+func NewAgentsDataSource() datasource.DataSource {
+    return &AgentsDataSource{}
+}
+
+// AgentsDataSource lists agents, optionally filtered by status or by how long
+// they have been offline.
+type AgentsDataSource struct {
+    client *ClientConfig
+}
+
+// AgentsDataSourceModel describes the data source data model.
+type AgentsDataSourceModel struct {
+    Status            types.String `tfsdk:"status"`
+    OfflineForMinutes types.Int64  `tfsdk:"offline_for_minutes"`
+    LastSeenWithin    types.String `tfsdk:"last_seen_within"`
+    CustomField       types.Object `tfsdk:"custom_field"`
+    Limit             types.Int64  `tfsdk:"limit"`
+    Offset            types.Int64  `tfsdk:"offset"`
+    Agents            types.List   `tfsdk:"agents"`
+}
+
+// AgentCustomFieldFilterModel describes the custom_field filter.
+type AgentCustomFieldFilterModel struct {
+    Name  types.String `tfsdk:"name"`
+    Value types.String `tfsdk:"value"`
+}
+
+func (d *AgentsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_agents"
+}
+
+func (d *AgentsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Agents data source for Tactical RMM. Lists all agents, optionally only those with a given status or offline for at least a number of minutes. " +
+            "Status and offline time are computed from `last_seen` and each agent's offline and overdue thresholds, against the clock of the Tactical RMM server.",
+
+        Attributes: map[string]schema.Attribute{
+            "status": schema.StringAttribute{
+                MarkdownDescription: "Optional: Only list agents with this status: `online`, `offline` or `overdue`.",
+                Optional:            true,
+            },
+            "offline_for_minutes": schema.Int64Attribute{
+                MarkdownDescription: "Optional: Only list agents last seen at least this many minutes ago. Agents that never checked in always match.",
+                Optional:            true,
+            },
+            "last_seen_within": schema.StringAttribute{
+                MarkdownDescription: "Optional: Only list agents last seen within this duration, such as `\"30m\"` or `\"24h\"`. " +
+                    "Accepts Go duration units up to hours (`h`, `m`, `s`). Agents that never checked in never match.",
+                Optional: true,
+            },
+            "custom_field": schema.SingleNestedAttribute{
+                MarkdownDescription: "Optional: Only list agents whose agent custom field `name` matches `value`. Multiple choice fields match when they contain `value`, " +
+                    "checkbox fields match `\"true\"` or `\"false\"` and all other fields match exactly. Agents without a stored value match the field default. " +
+                    "The agents list carries no custom fields, so the details of every agent left by the other filters are fetched, a few at a time.",
+                Optional: true,
+                Attributes: map[string]schema.Attribute{
+                    "name": schema.StringAttribute{
+                        MarkdownDescription: "Name of the agent custom field",
+                        Required:            true,
+                    },
+                    "value": schema.StringAttribute{
+                        MarkdownDescription: "Expected value",
+                        Required:            true,
+                    },
+                },
+            },
+            "limit": schema.Int64Attribute{
+                MarkdownDescription: "Optional: Maximum number of agents to list. Sent to servers that paginate the agents list; on other servers the first agents after `offset` are kept.",
+                Optional:            true,
+            },
+            "offset": schema.Int64Attribute{
+                MarkdownDescription: "Optional: Number of agents to skip. Sent to servers that paginate the agents list; on other servers the agents are skipped after filtering and sorting.",
+                Optional:            true,
+            },
+            "agents": schema.ListNestedAttribute{
+                MarkdownDescription: "Agents matching the filters, sorted by hostname",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "agent_id": schema.StringAttribute{
+                            MarkdownDescription: "Agent identifier",
+                            Computed:            true,
+                        },
+                        "hostname": schema.StringAttribute{
+                            MarkdownDescription: "Agent hostname",
+                            Computed:            true,
+                        },
+                        "client_name": schema.StringAttribute{
+                            MarkdownDescription: "Name of the client the agent belongs to",
+                            Computed:            true,
+                        },
+                        "site_name": schema.StringAttribute{
+                            MarkdownDescription: "Name of the site the agent belongs to",
+                            Computed:            true,
+                        },
+                        "plat": schema.StringAttribute{
+                            MarkdownDescription: "Agent platform (windows, linux, darwin)",
+                            Computed:            true,
+                        },
+                        "status": schema.StringAttribute{
+                            MarkdownDescription: "Agent status: `online`, `offline` or `overdue`",
+                            Computed:            true,
+                        },
+                        "last_seen": schema.StringAttribute{
+                            MarkdownDescription: "Time the agent last checked in (RFC3339), null if it never did",
+                            Computed:            true,
+                        },
+                        "offline_minutes": schema.Int64Attribute{
+                            MarkdownDescription: "Whole minutes since the agent last checked in, null if it never did",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *AgentsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+    var data AgentsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.Status.IsNull() && !data.Status.IsUnknown() {
+        switch data.Status.ValueString() {
+        case agentStatusOnline, agentStatusOffline, agentStatusOverdue:
+        default:
+            resp.Diagnostics.AddAttributeError(
+                path.Root("status"),
+                "Invalid Agent Status",
+                fmt.Sprintf("status must be one of %q, %q or %q, got %q.", agentStatusOnline, agentStatusOffline, agentStatusOverdue, data.Status.ValueString()),
+            )
+        }
+    }
+
+    if !data.OfflineForMinutes.IsNull() && !data.OfflineForMinutes.IsUnknown() && data.OfflineForMinutes.ValueInt64() < 0 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("offline_for_minutes"),
+            "Invalid Offline Duration",
+            "offline_for_minutes must not be negative.",
+        )
+    }
+
+    if !data.LastSeenWithin.IsNull() && !data.LastSeenWithin.IsUnknown() {
+        if _, err := parseLastSeenWithin(data.LastSeenWithin.ValueString()); err != nil {
+            resp.Diagnostics.AddAttributeError(path.Root("last_seen_within"), "Invalid Duration", err.Error())
+        }
+    }
+
+    if !data.Limit.IsNull() && !data.Limit.IsUnknown() && data.Limit.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("limit"),
+            "Invalid Page Size",
+            fmt.Sprintf("limit must be at least 1, got %d.", data.Limit.ValueInt64()),
+        )
+    }
+    if !data.Offset.IsNull() && !data.Offset.IsUnknown() && data.Offset.ValueInt64() < 0 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("offset"),
+            "Invalid Page Offset",
+            "offset must not be negative.",
+        )
+    }
+}
+
+func (d *AgentsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *AgentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AgentsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Validation is skipped for unknown values, so the duration is parsed
+    // again once it is known
+    var window time.Duration
+    if !data.LastSeenWithin.IsNull() {
+        var err error
+        window, err = parseLastSeenWithin(data.LastSeenWithin.ValueString())
+        if err != nil {
+            resp.Diagnostics.AddAttributeError(path.Root("last_seen_within"), "Invalid Duration", err.Error())
+            return
+        }
+    }
+
+    listURL := fmt.Sprintf("%s/agents/", d.client.BaseURL)
+    query := url.Values{}
+    if !data.Limit.IsNull() {
+        query.Set("limit", strconv.FormatInt(data.Limit.ValueInt64(), 10))
+    }
+    if !data.Offset.IsNull() {
+        query.Set("offset", strconv.FormatInt(data.Offset.ValueInt64(), 10))
+    }
+    if len(query) > 0 {
+        listURL += "?" + query.Encode()
+    }
+
+    httpReq, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+        return
+    }
+
+    httpResp, err := d.client.Do(httpReq)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+        return
+    }
+    defer httpResp.Body.Close()
+
+    if httpResp.StatusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agents, status code: %d", httpResp.StatusCode))
+        return
+    }
+
+    // last_seen is stamped by the server, so elapsed time is measured against
+    // the server clock from the Date header rather than the local clock
+    now := time.Now().UTC()
+    if serverTime, err := http.ParseTime(httpResp.Header.Get("Date")); err == nil {
+        now = serverTime
+    }
+
+    // Large fleets return a list of many megabytes, so it is decoded one agent
+    // at a time and only the matching agents are kept
+    candidates, paginated, err := decodeAgentList(httpResp.Body, func(agent map[string]interface{}) bool {
+        lastSeen, seen := agentLastSeen(agent)
+        if !data.Status.IsNull() && agentStatus(agent, lastSeen, seen, now) != data.Status.ValueString() {
+            return false
+        }
+        if !data.OfflineForMinutes.IsNull() && seen && int64(now.Sub(lastSeen)/time.Minute) < data.OfflineForMinutes.ValueInt64() {
+            return false
+        }
+        if !data.LastSeenWithin.IsNull() && (!seen || now.Sub(lastSeen) > window) {
+            return false
+        }
+        return true
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return
+    }
+
+    sort.SliceStable(candidates, func(i, j int) bool {
+        a, _ := candidates[i]["hostname"].(string)
+        b, _ := candidates[j]["hostname"].(string)
+        return a < b
+    })
+
+    if !data.CustomField.IsNull() {
+        var filter AgentCustomFieldFilterModel
+        resp.Diagnostics.Append(data.CustomField.As(ctx, &filter, basetypes.ObjectAsOptions{})...)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+
+        candidates = d.filterByCustomField(ctx, candidates, filter, &resp.Diagnostics)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+    }
+
+    // Servers without pagination ignore limit and offset, so the page is
+    // selected from the filtered and sorted agents instead
+    if !paginated {
+        candidates = agentsPage(candidates, data.Offset, data.Limit)
+    }
+
+    agentValues := make([]attr.Value, 0, len(candidates))
+    for _, agent := range candidates {
+        lastSeen, seen := agentLastSeen(agent)
+
+        lastSeenValue := types.StringNull()
+        offlineMinutes := types.Int64Null()
+        if seen {
+            lastSeenValue = types.StringValue(lastSeen.UTC().Format(time.RFC3339))
+            offlineMinutes = types.Int64Value(int64(now.Sub(lastSeen) / time.Minute))
+        }
+
+        agentValue, diags := types.ObjectValue(agentAttrTypes, map[string]attr.Value{
+            "agent_id":        stringValue(agent["agent_id"]),
+            "hostname":        stringValue(agent["hostname"]),
+            "client_name":     stringValue(agent["client_name"]),
+            "site_name":       stringValue(agent["site_name"]),
+            "plat":            stringValue(agent["plat"]),
+            "status":          types.StringValue(agentStatus(agent, lastSeen, seen, now)),
+            "last_seen":       lastSeenValue,
+            "offline_minutes": offlineMinutes,
+        })
+        resp.Diagnostics.Append(diags...)
+        agentValues = append(agentValues, agentValue)
+    }
+
+    agentsValue, diags := types.ListValue(types.ObjectType{AttrTypes: agentAttrTypes}, agentValues)
+    resp.Diagnostics.Append(diags...)
+    data.Agents = agentsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// filterByCustomField keeps the agents whose custom field matches filter. The
+// agents list does not include custom fields, so the detail of every agent is
+// fetched with bounded concurrency.
+func (d *AgentsDataSource) filterByCustomField(ctx context.Context, agents []map[string]interface{}, filter AgentCustomFieldFilterModel, diags *diag.Diagnostics) []map[string]interface{} {
+    definitions, err := d.client.customFieldDefinitions(ctx, "agent")
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read custom field definitions, got error: %s", err))
+        return nil
+    }
+
+    definition, ok := definitions[filter.Name.ValueString()]
+    if !ok {
+        diags.AddAttributeError(
+            path.Root("custom_field").AtName("name"),
+            "Unknown Custom Field",
+            fmt.Sprintf("No agent custom field named %q exists.", filter.Name.ValueString()),
+        )
+        return nil
+    }
+
+    agentIds := make([]string, 0, len(agents))
+    for _, agent := range agents {
+        if agentId, ok := agent["agent_id"].(string); ok {
+            agentIds = append(agentIds, agentId)
+        }
+    }
+
+    details, failures := d.client.fetchAgentDetails(ctx, agentIds)
+
+    var matched []map[string]interface{}
+    for _, agent := range agents {
+        agentId, _ := agent["agent_id"].(string)
+        // An agent whose custom fields cannot be read cannot be matched
+        if err, failed := failures[agentId]; failed {
+            addItemFetchWarning(diags, fmt.Sprintf("the custom fields of agent %s", agentId), err)
+            continue
+        }
+        value, ok := storedCustomFields(details[agentId])[customFieldId(definition)]
+        if !ok {
+            value = customFieldDefaultPayload(definition)
+        }
+        if customFieldMatches(definition, value, filter.Value.ValueString()) {
+            matched = append(matched, agent)
+        }
+    }
+
+    return matched
+}
+
+// customFieldMatches reports whether a stored custom field value matches
+// expected. Multiple choice fields match when one of their values equals
+// expected, all other fields compare their Terraform string form.
+func customFieldMatches(definition map[string]interface{}, value map[string]interface{}, expected string) bool {
+    if customFieldType(definition) == customFieldTypeMultiple {
+        for _, item := range stringSlice(value["multiple_value"]) {
+            if item == expected {
+                return true
+            }
+        }
+        return false
+    }
+
+    actual, ok := customFieldValueString(definition, value)
+    if !ok {
+        return false
+    }
+    if customFieldType(definition) == customFieldTypeCheckbox {
+        return strings.EqualFold(actual, strings.TrimSpace(expected))
+    }
+    return actual == expected
+}
+
+// agentListEntry holds the agents list fields used by the data source. Other
+// fields, such as the disks or services some servers include, are skipped
+// while decoding and never held in memory.
+type agentListEntry struct {
+    AgentId     *string  `json:"agent_id"`
+    Hostname    *string  `json:"hostname"`
+    ClientName  *string  `json:"client_name"`
+    SiteName    *string  `json:"site_name"`
+    Plat        *string  `json:"plat"`
+    LastSeen    *string  `json:"last_seen"`
+    OfflineTime *float64 `json:"offline_time"`
+    OverdueTime *float64 `json:"overdue_time"`
+}
+
+// object converts the entry into the form the agent helpers take. Missing and
+// null fields are left out.
+func (e agentListEntry) object() map[string]interface{} {
+    agent := make(map[string]interface{}, 8)
+    for key, value := range map[string]*string{
+        "agent_id":    e.AgentId,
+        "hostname":    e.Hostname,
+        "client_name": e.ClientName,
+        "site_name":   e.SiteName,
+        "plat":        e.Plat,
+        "last_seen":   e.LastSeen,
+    } {
+        if value != nil {
+            agent[key] = *value
+        }
+    }
+    if e.OfflineTime != nil {
+        agent["offline_time"] = *e.OfflineTime
+    }
+    if e.OverdueTime != nil {
+        agent["overdue_time"] = *e.OverdueTime
+    }
+    return agent
+}
+
+// decodeAgentList decodes an agents list response one agent at a time and
+// returns the agents keep accepts. The response is either a JSON array or,
+// on servers that paginate, an object with the page in results; paginated
+// reports which one it was.
+func decodeAgentList(body io.Reader, keep func(map[string]interface{}) bool) (agents []map[string]interface{}, paginated bool, err error) {
+    decoder := json.NewDecoder(body)
+
+    token, err := decoder.Token()
+    if err != nil {
+        return nil, false, err
+    }
+
+    switch token {
+    case json.Delim('['):
+        agents, err = decodeAgentArray(decoder, keep)
+        return agents, false, err
+    case json.Delim('{'):
+        for decoder.More() {
+            key, err := decoder.Token()
+            if err != nil {
+                return nil, true, err
+            }
+            if key != "results" {
+                var skipped json.RawMessage
+                if err := decoder.Decode(&skipped); err != nil {
+                    return nil, true, err
+                }
+                continue
+            }
+
+            token, err := decoder.Token()
+            if err != nil {
+                return nil, true, err
+            }
+            if token != json.Delim('[') {
+                return nil, true, fmt.Errorf("expected results to be a list, got %v", token)
+            }
+            if agents, err = decodeAgentArray(decoder, keep); err != nil {
+                return nil, true, err
+            }
+        }
+        return agents, true, nil
+    default:
+        return nil, false, fmt.Errorf("expected a list of agents, got %v", token)
+    }
+}
+
+// decodeAgentArray decodes the elements of a JSON array whose opening bracket
+// has been read, including the closing bracket.
+func decodeAgentArray(decoder *json.Decoder, keep func(map[string]interface{}) bool) ([]map[string]interface{}, error) {
+    var agents []map[string]interface{}
+    for decoder.More() {
+        var entry agentListEntry
+        if err := decoder.Decode(&entry); err != nil {
+            return nil, err
+        }
+        if agent := entry.object(); keep(agent) {
+            agents = append(agents, agent)
+        }
+    }
+    if _, err := decoder.Token(); err != nil {
+        return nil, err
+    }
+    return agents, nil
+}
+
+// agentsPage returns the agents selected by offset and limit, which may be null.
+func agentsPage(agents []map[string]interface{}, offset types.Int64, limit types.Int64) []map[string]interface{} {
+    if start := offset.ValueInt64(); start > 0 {
+        if start >= int64(len(agents)) {
+            return nil
+        }
+        agents = agents[start:]
+    }
+    if !limit.IsNull() && limit.ValueInt64() < int64(len(agents)) {
+        agents = agents[:limit.ValueInt64()]
+    }
+    return agents
+}
+
+// agentLastSeen returns when the agent last checked in, and false when it
+// never did or the timestamp cannot be parsed.
+func agentLastSeen(agent map[string]interface{}) (time.Time, bool) {
+    value, ok := agent["last_seen"].(string)
+    if !ok || value == "" {
+        return time.Time{}, false
+    }
+    lastSeen, err := parseAPITime(value)
+    if err != nil {
+        return time.Time{}, false
+    }
+    return lastSeen, true
+}
+
+// parseLastSeenWithin parses the last_seen_within filter, a positive Go
+// duration.
+func parseLastSeenWithin(value string) (time.Duration, error) {
+    window, err := time.ParseDuration(value)
+    if err != nil {
+        return 0, fmt.Errorf("last_seen_within must be a duration such as \"30m\" or \"24h\": %s.", err)
+    }
+    if window <= 0 {
+        return 0, fmt.Errorf("last_seen_within must be positive, got %q.", value)
+    }
+    return window, nil
+}
+
+// agentStatus derives the status of an agent the way Tactical RMM does: online
+// while last seen within its offline_time, offline until its overdue_time has
+// passed and overdue after that. Agents that never checked in are overdue.
+func agentStatus(agent map[string]interface{}, lastSeen time.Time, seen bool, now time.Time) string {
+    if !seen {
+        return agentStatusOverdue
+    }
+
+    offlineTime := int64(defaultAgentOfflineTime)
+    if minutes, ok := agent["offline_time"].(float64); ok {
+        offlineTime = int64(minutes)
+    }
+    overdueTime := int64(defaultAgentOverdueTime)
+    if minutes, ok := agent["overdue_time"].(float64); ok {
+        overdueTime = int64(minutes)
+    }
+
+    elapsed := now.Sub(lastSeen)
+    switch {
+    case elapsed < time.Duration(offlineTime)*time.Minute:
+        return agentStatusOnline
+    case elapsed < time.Duration(overdueTime)*time.Minute:
+        return agentStatusOffline
+    default:
+        return agentStatusOverdue
+    }
+}
+
+// fetchAgentDetails retrieves agent details with at most detailFetchConcurrency
+// parallel requests. Agents that could not be fetched are reported in the
+// returned error map.
+func (c *ClientConfig) fetchAgentDetails(ctx context.Context, agentIds []string) (map[string]map[string]interface{}, map[string]error) {
+    details := make(map[string]map[string]interface{}, len(agentIds))
+    failures := make(map[string]error)
+
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+    sem := make(chan struct{}, detailFetchConcurrency)
+
+    for _, agentId := range agentIds {
+        wg.Add(1)
+        go func(agentId string) {
+            defer wg.Done()
+            sem <- struct{}{}
+            defer func() { <-sem }()
+
+            detail, err := c.getObject(ctx, fmt.Sprintf("%s/agents/%s/", c.BaseURL, agentId))
+
+            mu.Lock()
+            defer mu.Unlock()
+            if err != nil {
+                failures[agentId] = err
+                return
+            }
+            details[agentId] = detail
+        }(agentId)
+    }
+    wg.Wait()
+
+    return details, failures
+}
@@ -0,0 +1,435 @@
+package provider
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "runtime"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type agentEntry struct {
+    AgentId        types.String `tfsdk:"agent_id"`
+    Hostname       types.String `tfsdk:"hostname"`
+    ClientName     types.String `tfsdk:"client_name"`
+    SiteName       types.String `tfsdk:"site_name"`
+    Plat           types.String `tfsdk:"plat"`
+    Status         types.String `tfsdk:"status"`
+    LastSeen       types.String `tfsdk:"last_seen"`
+    OfflineMinutes types.Int64  `tfsdk:"offline_minutes"`
+}
+
+// agentsServer serves agents last seen relative to a server clock that is far
+// from the local clock, so only the server Date header gives correct results.
+func agentsServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/agents/" {
+            http.NotFound(w, r)
+            return
+        }
+        w.Header().Set("Date", "Mon, 02 Mar 2020 12:00:00 GMT")
+        writeJSON(t, w, []map[string]interface{}{
+            {"agent_id": "a1", "hostname": "web01", "last_seen": "2020-03-02T11:58:00Z", "offline_time": 4, "overdue_time": 30},
+            {"agent_id": "a2", "hostname": "db01", "last_seen": "2020-03-02T11:50:00Z", "offline_time": 4, "overdue_time": 30},
+            {"agent_id": "a3", "hostname": "old01", "last_seen": "2020-03-01T12:00:00Z", "offline_time": 4, "overdue_time": 30},
+            {"agent_id": "a4", "hostname": "lab01", "last_seen": "2020-03-02T11:30:00Z", "offline_time": 10, "overdue_time": 120},
+            {"agent_id": "a5", "hostname": "new01", "last_seen": nil},
+        })
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func readAgents(t *testing.T, model *AgentsDataSourceModel) []agentEntry {
+    t.Helper()
+    model.Agents = types.ListNull(types.ObjectType{AttrTypes: agentAttrTypes})
+    if len(model.CustomField.AttributeTypes(context.Background())) == 0 {
+        model.CustomField = types.ObjectNull(agentCustomFieldFilterAttrTypes)
+    }
+
+    d := &AgentsDataSource{client: newTestClient(agentsServer(t))}
+    resp := readDataSource(t, d, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var agents []agentEntry
+    state.Agents.ElementsAs(context.Background(), &agents, false)
+    return agents
+}
+
+func agentHostnames(agents []agentEntry) []string {
+    hostnames := []string{}
+    for _, agent := range agents {
+        hostnames = append(hostnames, agent.Hostname.ValueString())
+    }
+    return hostnames
+}
+
+func TestAgentsDataSourceRead_StatusUsesServerClock(t *testing.T) {
+    agents := readAgents(t, &AgentsDataSourceModel{
+        Status:            types.StringNull(),
+        OfflineForMinutes: types.Int64Null(),
+    })
+
+    expected := map[string]string{
+        "web01": agentStatusOnline,
+        "db01":  agentStatusOffline,
+        "old01": agentStatusOverdue,
+        "lab01": agentStatusOffline,
+        "new01": agentStatusOverdue,
+    }
+    if len(agents) != len(expected) {
+        t.Fatalf("expected %d agents, got %v", len(expected), agentHostnames(agents))
+    }
+    for _, agent := range agents {
+        if want := expected[agent.Hostname.ValueString()]; agent.Status.ValueString() != want {
+            t.Errorf("%s: expected status %s, got %s", agent.Hostname.ValueString(), want, agent.Status.ValueString())
+        }
+    }
+    if agents[0].Hostname.ValueString() != "db01" || agents[0].OfflineMinutes.ValueInt64() != 10 {
+        t.Errorf("expected db01 offline for 10 minutes first, got %s offline for %d", agents[0].Hostname.ValueString(), agents[0].OfflineMinutes.ValueInt64())
+    }
+}
+
+func TestAgentsDataSourceRead_OfflineForMinutes(t *testing.T) {
+    agents := readAgents(t, &AgentsDataSourceModel{
+        Status:            types.StringNull(),
+        OfflineForMinutes: types.Int64Value(60),
+    })
+
+    hostnames := agentHostnames(agents)
+    if len(hostnames) != 2 || hostnames[0] != "new01" || hostnames[1] != "old01" {
+        t.Errorf("expected new01 and old01, got %v", hostnames)
+    }
+}
+
+func TestAgentsDataSourceRead_LastSeenWithin(t *testing.T) {
+    cases := map[string][]string{
+        // db01 was last seen exactly 10 minutes before the server clock
+        "10m":    {"db01", "web01"},
+        "9m59s":  {"web01"},
+        "24h":    {"db01", "lab01", "old01", "web01"},
+        "23h59m": {"db01", "lab01", "web01"},
+    }
+
+    for window, expected := range cases {
+        agents := readAgents(t, &AgentsDataSourceModel{
+            Status:            types.StringNull(),
+            OfflineForMinutes: types.Int64Null(),
+            LastSeenWithin:    types.StringValue(window),
+        })
+
+        if got := agentHostnames(agents); !reflect.DeepEqual(got, expected) {
+            t.Errorf("%s: expected %v, got %v", window, expected, got)
+        }
+    }
+}
+
+func TestParseLastSeenWithin(t *testing.T) {
+    valid := map[string]time.Duration{
+        "24h":    24 * time.Hour,
+        "90m":    90 * time.Minute,
+        "1h30m":  90 * time.Minute,
+        "45s":    45 * time.Second,
+        "168h":   7 * 24 * time.Hour,
+        "0.5h":   30 * time.Minute,
+        "1h0m0s": time.Hour,
+    }
+    for value, expected := range valid {
+        window, err := parseLastSeenWithin(value)
+        if err != nil {
+            t.Errorf("%s: unexpected error: %s", value, err)
+            continue
+        }
+        if window != expected {
+            t.Errorf("%s: expected %s, got %s", value, expected, window)
+        }
+    }
+
+    for _, value := range []string{"", "24", "1d", "one hour", "0s", "-1h"} {
+        if _, err := parseLastSeenWithin(value); err == nil {
+            t.Errorf("%q: expected an error", value)
+        }
+    }
+}
+
+func TestAgentsDataSourceValidateConfig_LastSeenWithin(t *testing.T) {
+    ctx := context.Background()
+    d := &AgentsDataSource{}
+    var schemaResp datasource.SchemaResponse
+    d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+    // Config has no Set method, so the raw value is built through a state
+    config := tfsdk.State{Schema: schemaResp.Schema}
+    config.Set(ctx, &AgentsDataSourceModel{
+        Status:            types.StringNull(),
+        OfflineForMinutes: types.Int64Null(),
+        LastSeenWithin:    types.StringValue("1d"),
+        CustomField:       types.ObjectNull(agentCustomFieldFilterAttrTypes),
+        Agents:            types.ListNull(types.ObjectType{AttrTypes: agentAttrTypes}),
+    })
+
+    var resp datasource.ValidateConfigResponse
+    d.ValidateConfig(ctx, datasource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}, &resp)
+    if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Invalid Duration" {
+        t.Fatalf("expected an invalid duration error, got: %v", resp.Diagnostics)
+    }
+    if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, `"1d"`) {
+        t.Errorf("expected the detail to quote the value, got: %s", detail)
+    }
+}
+
+func TestAgentsDataSourceRead_StatusFilter(t *testing.T) {
+    agents := readAgents(t, &AgentsDataSourceModel{
+        Status:            types.StringValue(agentStatusOffline),
+        OfflineForMinutes: types.Int64Null(),
+    })
+
+    hostnames := agentHostnames(agents)
+    if len(hostnames) != 2 || hostnames[0] != "db01" || hostnames[1] != "lab01" {
+        t.Errorf("expected db01 and lab01, got %v", hostnames)
+    }
+}
+
+func TestAgentsDataSourceRead_CustomFieldFilter(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/agents/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"agent_id": "a1", "hostname": "web01"},
+                {"agent_id": "a2", "hostname": "web02"},
+                {"agent_id": "a3", "hostname": "dev01"},
+            })
+        case "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "model": "agent", "name": "environment", "type": "single", "default_value_string": "dev"},
+                {"id": 2, "model": "agent", "name": "roles", "type": "multiple"},
+            })
+        case "/agents/a1/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "a1", "custom_fields": []map[string]interface{}{
+                {"field": 1, "string_value": "prod"},
+                {"field": 2, "multiple_value": []string{"web", "cache"}},
+            }})
+        case "/agents/a2/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "a2", "custom_fields": []map[string]interface{}{
+                {"field": 1, "string_value": "prod"},
+                {"field": 2, "multiple_value": []string{"db"}},
+            }})
+        case "/agents/a3/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "a3", "custom_fields": []map[string]interface{}{}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    cases := []struct {
+        name      string
+        field     string
+        value     string
+        hostnames []string
+    }{
+        {"exact match", "environment", "prod", []string{"web01", "web02"}},
+        {"default value", "environment", "dev", []string{"dev01"}},
+        {"multiple contains", "roles", "web", []string{"web01"}},
+    }
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            d := &AgentsDataSource{client: newTestClient(server)}
+            resp := readDataSource(t, d, &AgentsDataSourceModel{
+                Status:            types.StringNull(),
+                OfflineForMinutes: types.Int64Null(),
+                CustomField: types.ObjectValueMust(agentCustomFieldFilterAttrTypes, map[string]attr.Value{
+                    "name":  types.StringValue(tc.field),
+                    "value": types.StringValue(tc.value),
+                }),
+                Agents: types.ListNull(types.ObjectType{AttrTypes: agentAttrTypes}),
+            })
+            if resp.Diagnostics.HasError() {
+                t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+            }
+
+            var state AgentsDataSourceModel
+            resp.State.Get(context.Background(), &state)
+            var agents []agentEntry
+            state.Agents.ElementsAs(context.Background(), &agents, false)
+            if got := agentHostnames(agents); !reflect.DeepEqual(got, tc.hostnames) {
+                t.Errorf("expected %v, got %v", tc.hostnames, got)
+            }
+        })
+    }
+}
+
+func TestAgentsDataSourceRead_PageWithoutServerPagination(t *testing.T) {
+    agents := readAgents(t, &AgentsDataSourceModel{
+        Status:            types.StringNull(),
+        OfflineForMinutes: types.Int64Null(),
+        Limit:             types.Int64Value(2),
+        Offset:            types.Int64Value(1),
+    })
+
+    if got := agentHostnames(agents); !reflect.DeepEqual(got, []string{"lab01", "new01"}) {
+        t.Errorf("expected the second and third agent by hostname, got %v", got)
+    }
+}
+
+func TestAgentsDataSourceRead_ServerPagination(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/agents/" {
+            http.NotFound(w, r)
+            return
+        }
+        if r.URL.Query().Get("limit") != "2" || r.URL.Query().Get("offset") != "4" {
+            t.Errorf("expected limit and offset to be passed through, got %s", r.URL.RawQuery)
+        }
+        writeJSON(t, w, map[string]interface{}{
+            "count":    6,
+            "next":     nil,
+            "previous": "/agents/?limit=2&offset=2",
+            "results": []map[string]interface{}{
+                {"agent_id": "a6", "hostname": "web06", "last_seen": nil},
+                {"agent_id": "a5", "hostname": "web05", "last_seen": nil},
+            },
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    d := &AgentsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &AgentsDataSourceModel{
+        Status:            types.StringNull(),
+        OfflineForMinutes: types.Int64Null(),
+        CustomField:       types.ObjectNull(agentCustomFieldFilterAttrTypes),
+        Limit:             types.Int64Value(2),
+        Offset:            types.Int64Value(4),
+        Agents:            types.ListNull(types.ObjectType{AttrTypes: agentAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AgentsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var agents []agentEntry
+    state.Agents.ElementsAs(context.Background(), &agents, false)
+    if got := agentHostnames(agents); !reflect.DeepEqual(got, []string{"web05", "web06"}) {
+        t.Errorf("expected the server page sorted by hostname, got %v", got)
+    }
+}
+
+func TestDecodeAgentList_SkipsUnusedFields(t *testing.T) {
+    body := `[{"agent_id": "a1", "hostname": "web01", "services": [{"name": "spooler"}], "disks": {"C:": 1}}]`
+
+    agents, paginated, err := decodeAgentList(strings.NewReader(body), func(map[string]interface{}) bool { return true })
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+    if paginated {
+        t.Error("expected a plain list not to be reported as paginated")
+    }
+    if !reflect.DeepEqual(agents, []map[string]interface{}{{"agent_id": "a1", "hostname": "web01"}}) {
+        t.Errorf("expected only the used fields to be kept, got %v", agents)
+    }
+}
+
+// syntheticAgentsPayload is an agents list of about size bytes. Every agent
+// carries the bulky fields real servers include, like services and software.
+func syntheticAgentsPayload(size int) []byte {
+    padding := strings.Repeat("x", 4000)
+    var buf bytes.Buffer
+    buf.WriteString("[")
+    for i := 0; buf.Len() < size; i++ {
+        if i > 0 {
+            buf.WriteString(",")
+        }
+        online := "2020-03-02T11:59:00Z"
+        if i%10 == 0 {
+            online = "2020-02-01T00:00:00Z"
+        }
+        fmt.Fprintf(&buf, `{"agent_id":"agent-%d","hostname":"host-%06d","client_name":"Client %d","site_name":"Site %d","plat":"windows",`+
+            `"last_seen":%q,"offline_time":4,"overdue_time":30,"services":[{"name":"svc","description":%q}],"wmi_detail":{"os":%q}}`,
+            i, i, i%50, i%200, online, padding, padding)
+    }
+    buf.WriteString("]")
+    return buf.Bytes()
+}
+
+// BenchmarkAgentsDataSourceRead_LargeFleet reads a synthetic 50 MB agents list
+// filtered to the overdue tenth of the fleet and reports the peak heap growth
+// during the read, including garbage not yet collected. The peak stays a
+// fraction of the payload, since neither the response nor the unused agent
+// fields are held in memory.
+func BenchmarkAgentsDataSourceRead_LargeFleet(b *testing.B) {
+    payload := syntheticAgentsPayload(50 << 20)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Date", "Mon, 02 Mar 2020 12:00:00 GMT")
+        w.Header().Set("Content-Type", "application/json")
+        w.Write(payload)
+    }))
+    b.Cleanup(server.Close)
+
+    d := &AgentsDataSource{client: &ClientConfig{BaseURL: server.URL, HTTPClient: server.Client()}}
+    ctx := context.Background()
+    var schemaResp datasource.SchemaResponse
+    d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+    config := tfsdk.State{Schema: schemaResp.Schema}
+    config.Set(ctx, &AgentsDataSourceModel{
+        Status:            types.StringValue(agentStatusOverdue),
+        OfflineForMinutes: types.Int64Null(),
+        CustomField:       types.ObjectNull(agentCustomFieldFilterAttrTypes),
+        Agents:            types.ListNull(types.ObjectType{AttrTypes: agentAttrTypes}),
+    })
+
+    runtime.GC()
+    var baseline runtime.MemStats
+    runtime.ReadMemStats(&baseline)
+
+    var peak uint64
+    done := make(chan struct{})
+    sampled := make(chan struct{})
+    go func() {
+        defer close(sampled)
+        var stats runtime.MemStats
+        for {
+            select {
+            case <-done:
+                return
+            case <-time.After(time.Millisecond):
+                runtime.ReadMemStats(&stats)
+                if stats.HeapAlloc > peak {
+                    peak = stats.HeapAlloc
+                }
+            }
+        }
+    }()
+
+    b.SetBytes(int64(len(payload)))
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+        d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}, &resp)
+        if resp.Diagnostics.HasError() {
+            b.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+        }
+    }
+    b.StopTimer()
+
+    close(done)
+    <-sampled
+    if peak > baseline.HeapAlloc {
+        b.ReportMetric(float64(peak-baseline.HeapAlloc)/(1<<20), "peak-heap-MB")
+    }
+}
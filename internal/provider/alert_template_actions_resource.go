@@ -0,0 +1,468 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strconv"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AlertTemplateActionsResource{}
+var _ resource.ResourceWithImportState = &AlertTemplateActionsResource{}
+var _ resource.ResourceWithIdentity = &AlertTemplateActionsResource{}
+var _ resource.ResourceWithValidateConfig = &AlertTemplateActionsResource{}
+
+// alertActionTypeScript is the action type of alert actions that run a
+// script on the agent. Servers predating action types ignore the field.
+const alertActionTypeScript = "script"
+
+func NewAlertTemplateActionsResource() resource.Resource {
+    return &AlertTemplateActionsResource{}
+}
+
+// AlertTemplateActionsResource manages the scripts an existing alert
+// template runs when an alert fails or resolves.
+type AlertTemplateActionsResource struct {
+    client *ClientConfig
+}
+
+// AlertTemplateActionsResourceModel describes the resource data model.
+type AlertTemplateActionsResourceModel struct {
+    Id                    types.String `tfsdk:"id"`
+    AlertTemplateId       types.Int64  `tfsdk:"alert_template_id"`
+    FailureAction         types.Int64  `tfsdk:"failure_action"`
+    FailureActionArgs     types.List   `tfsdk:"failure_action_args"`
+    FailureActionTimeout  types.Int64  `tfsdk:"failure_action_timeout"`
+    ResolvedAction        types.Int64  `tfsdk:"resolved_action"`
+    ResolvedActionArgs    types.List   `tfsdk:"resolved_action_args"`
+    ResolvedActionTimeout types.Int64  `tfsdk:"resolved_action_timeout"`
+}
+
+// alertAction describes one of the two actions of an alert template: the
+// model attributes and the API fields it is stored in.
+type alertAction struct {
+    attribute string
+    field     string
+    script    types.Int64
+    args      types.List
+    timeout   types.Int64
+}
+
+func (r *AlertTemplateActionsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_alert_template_actions"
+}
+
+func (r *AlertTemplateActionsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Manages the scripts an existing alert template runs on the agent when an alert is raised (`failure_action`) and when it resolves (`resolved_action`). " +
+            "The referenced scripts must exist. Destroying the resource removes both actions from the template.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of the alert template",
+                Computed:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.UseStateForUnknown(),
+                },
+            },
+            "alert_template_id": schema.Int64Attribute{
+                MarkdownDescription: "Alert template whose actions are managed",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "failure_action": schema.Int64Attribute{
+                MarkdownDescription: "ID of the script run when an alert is raised, null for none",
+                Optional:            true,
+            },
+            "failure_action_args": schema.ListAttribute{
+                MarkdownDescription: "Arguments passed to the failure action script",
+                Optional:            true,
+                ElementType:         types.StringType,
+            },
+            "failure_action_timeout": schema.Int64Attribute{
+                MarkdownDescription: "Timeout in seconds of the failure action script, the server default when not set",
+                Optional:            true,
+                Computed:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.UseStateForUnknown(),
+                },
+            },
+            "resolved_action": schema.Int64Attribute{
+                MarkdownDescription: "ID of the script run when an alert resolves, null for none",
+                Optional:            true,
+            },
+            "resolved_action_args": schema.ListAttribute{
+                MarkdownDescription: "Arguments passed to the resolved action script",
+                Optional:            true,
+                ElementType:         types.StringType,
+            },
+            "resolved_action_timeout": schema.Int64Attribute{
+                MarkdownDescription: "Timeout in seconds of the resolved action script, the server default when not set",
+                Optional:            true,
+                Computed:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.UseStateForUnknown(),
+                },
+            },
+        },
+    }
+}
+
+func (r *AlertTemplateActionsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data AlertTemplateActionsResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    for _, action := range data.actions() {
+        if !action.script.IsUnknown() && !action.script.IsNull() && action.script.ValueInt64() < 1 {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(action.attribute),
+                "Invalid Alert Action",
+                fmt.Sprintf("%s must be a script ID, got %d.", action.attribute, action.script.ValueInt64()),
+            )
+        }
+
+        // Arguments or a timeout without a script would be silently dropped
+        if action.script.IsNull() && !action.args.IsNull() {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(action.attribute+"_args"),
+                "Invalid Alert Action",
+                fmt.Sprintf("%s_args requires %s to be set.", action.attribute, action.attribute),
+            )
+        }
+        if action.script.IsNull() && !action.timeout.IsNull() && !action.timeout.IsUnknown() {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(action.attribute+"_timeout"),
+                "Invalid Alert Action",
+                fmt.Sprintf("%s_timeout requires %s to be set.", action.attribute, action.attribute),
+            )
+        }
+
+        if !action.timeout.IsNull() && !action.timeout.IsUnknown() && action.timeout.ValueInt64() < 1 {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(action.attribute+"_timeout"),
+                "Invalid Alert Action",
+                fmt.Sprintf("%s_timeout must be at least 1 second.", action.attribute),
+            )
+        }
+    }
+}
+
+func (r *AlertTemplateActionsResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = identityschema.Schema{
+        Attributes: map[string]identityschema.Attribute{
+            "alert_template_id": identityschema.Int64Attribute{
+                Description:       "Alert template identifier",
+                RequiredForImport: true,
+            },
+        },
+    }
+}
+
+func (r *AlertTemplateActionsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *AlertTemplateActionsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AlertTemplateActionsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.apply(ctx, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "alert_template_id", data.AlertTemplateId, &resp.Diagnostics)
+}
+
+func (r *AlertTemplateActionsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AlertTemplateActionsResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentityAttribute(ctx, resp.Identity, "alert_template_id", data.AlertTemplateId, &resp.Diagnostics)
+
+    template, found := r.readTemplate(ctx, data.AlertTemplateId.ValueInt64(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+    if !found {
+        resp.State.RemoveResource(ctx)
+        return
+    }
+
+    data.setFromAPI(template, &resp.Diagnostics)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AlertTemplateActionsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AlertTemplateActionsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    r.apply(ctx, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentityAttribute(ctx, resp.Identity, "alert_template_id", data.AlertTemplateId, &resp.Diagnostics)
+}
+
+func (r *AlertTemplateActionsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AlertTemplateActionsResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    templateId := data.AlertTemplateId.ValueInt64()
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/alerts/templates/%d/", r.client.BaseURL, templateId), map[string]interface{}{
+        "action":               nil,
+        "action_args":          []string{},
+        "resolved_action":      nil,
+        "resolved_action_args": []string{},
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to remove alert template actions, got error: %s", err))
+        return
+    }
+
+    // A deleted template has no actions left to remove
+    if statusCode != http.StatusOK && statusCode != http.StatusNotFound {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to remove alert template actions, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+    }
+}
+
+func (r *AlertTemplateActionsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks may use the identity instead of an alert template ID
+    id, ok := identityImportInt64(ctx, req, "alert_template_id", &resp.Diagnostics)
+    if !ok {
+        var err error
+        id, err = strconv.ParseInt(req.ID, 10, 64)
+        if err != nil {
+            resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Expected an alert template ID, got %q", req.ID))
+            return
+        }
+    }
+
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alert_template_id"), id)...)
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), strconv.FormatInt(id, 10))...)
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("failure_action_args"), types.ListNull(types.StringType))...)
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resolved_action_args"), types.ListNull(types.StringType))...)
+}
+
+// apply checks that the referenced scripts exist, stores the actions on the
+// alert template and reads them back into data.
+func (r *AlertTemplateActionsResource) apply(ctx context.Context, data *AlertTemplateActionsResourceModel, diags *diag.Diagnostics) {
+    for _, action := range data.actions() {
+        if action.script.IsNull() {
+            continue
+        }
+        statusCode, _, err := r.client.sendJSON(ctx, "GET", fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, action.script.ValueInt64()), nil)
+        if err != nil {
+            diags.AddError("Client Error", fmt.Sprintf("Unable to read script %d, got error: %s", action.script.ValueInt64(), err))
+            return
+        }
+        if statusCode == http.StatusNotFound {
+            diags.AddAttributeError(
+                path.Root(action.attribute),
+                "Script Not Found",
+                fmt.Sprintf("Script with ID %d not found", action.script.ValueInt64()),
+            )
+        } else if statusCode != http.StatusOK {
+            diags.AddError("Client Error", fmt.Sprintf("Unable to read script %d, status code: %d", action.script.ValueInt64(), statusCode))
+        }
+    }
+    if diags.HasError() {
+        return
+    }
+
+    body, bodyDiags := data.requestBody(ctx)
+    diags.Append(bodyDiags...)
+    if diags.HasError() {
+        return
+    }
+
+    templateId := data.AlertTemplateId.ValueInt64()
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/alerts/templates/%d/", r.client.BaseURL, templateId), body)
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update alert template actions, got error: %s", err))
+        return
+    }
+    if statusCode == http.StatusNotFound {
+        diags.AddAttributeError(path.Root("alert_template_id"), "Alert Template Not Found", fmt.Sprintf("Alert template with ID %d not found", templateId))
+        return
+    }
+    if statusCode != http.StatusOK {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update alert template actions, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    template, found := r.readTemplate(ctx, templateId, diags)
+    if diags.HasError() {
+        return
+    }
+    if !found {
+        diags.AddError("Alert Template Not Found", fmt.Sprintf("Alert template %d disappeared after its actions were updated", templateId))
+        return
+    }
+
+    data.Id = types.StringValue(strconv.FormatInt(templateId, 10))
+    data.setFromAPI(template, diags)
+}
+
+// readTemplate fetches an alert template. found is false when it does not
+// exist.
+func (r *AlertTemplateActionsResource) readTemplate(ctx context.Context, templateId int64, diags *diag.Diagnostics) (map[string]interface{}, bool) {
+    statusCode, respBody, err := r.client.sendJSON(ctx, "GET", fmt.Sprintf("%s/alerts/templates/%d/", r.client.BaseURL, templateId), nil)
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read alert template, got error: %s", err))
+        return nil, false
+    }
+    if statusCode == http.StatusNotFound {
+        return nil, false
+    }
+    if statusCode != http.StatusOK {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read alert template, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return nil, false
+    }
+
+    var template map[string]interface{}
+    if err := json.Unmarshal(respBody, &template); err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return nil, false
+    }
+    return template, true
+}
+
+// actions returns the failure and resolved actions of the model.
+func (m AlertTemplateActionsResourceModel) actions() []alertAction {
+    return []alertAction{
+        {attribute: "failure_action", field: "action", script: m.FailureAction, args: m.FailureActionArgs, timeout: m.FailureActionTimeout},
+        {attribute: "resolved_action", field: "resolved_action", script: m.ResolvedAction, args: m.ResolvedActionArgs, timeout: m.ResolvedActionTimeout},
+    }
+}
+
+// requestBody returns the partial alert template update for both actions.
+// The API rejects null argument lists, so missing arguments are sent as an
+// empty list. A timeout is only sent when configured.
+func (m AlertTemplateActionsResourceModel) requestBody(ctx context.Context) (map[string]interface{}, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    body := map[string]interface{}{}
+
+    for _, action := range m.actions() {
+        args := []string{}
+        if !action.args.IsNull() && !action.args.IsUnknown() {
+            diags.Append(action.args.ElementsAs(ctx, &args, false)...)
+        }
+
+        if action.script.IsNull() {
+            body[action.field] = nil
+        } else {
+            body[action.field] = action.script.ValueInt64()
+            body[action.field+"_type"] = alertActionTypeScript
+        }
+        body[action.field+"_args"] = args
+        if !action.timeout.IsNull() && !action.timeout.IsUnknown() {
+            body[action.field+"_timeout"] = action.timeout.ValueInt64()
+        }
+    }
+
+    return body, diags
+}
+
+// setFromAPI updates the model from an alert template. Empty argument lists
+// keep a null configuration null.
+func (m *AlertTemplateActionsResourceModel) setFromAPI(template map[string]interface{}, diags *diag.Diagnostics) {
+    m.FailureAction = alertActionScript(template["action"])
+    m.FailureActionTimeout = int64Value(template["action_timeout"])
+    m.ResolvedAction = alertActionScript(template["resolved_action"])
+    m.ResolvedActionTimeout = int64Value(template["resolved_action_timeout"])
+
+    if args, ok := template["action_args"].([]interface{}); ok && len(args) > 0 {
+        m.FailureActionArgs = stringListFrom(args, path.Root("failure_action_args"), diags)
+    } else if !m.FailureActionArgs.IsNull() {
+        m.FailureActionArgs = types.ListValueMust(types.StringType, []attr.Value{})
+    }
+    if args, ok := template["resolved_action_args"].([]interface{}); ok && len(args) > 0 {
+        m.ResolvedActionArgs = stringListFrom(args, path.Root("resolved_action_args"), diags)
+    } else if !m.ResolvedActionArgs.IsNull() {
+        m.ResolvedActionArgs = types.ListValueMust(types.StringType, []attr.Value{})
+    }
+}
+
+// alertActionScript returns the script of an alert action, which is returned
+// as an ID or a nested script object. No script yields null.
+func alertActionScript(value interface{}) types.Int64 {
+    if id := referencedId(value); id > 0 {
+        return types.Int64Value(id)
+    }
+    return types.Int64Null()
+}
@@ -0,0 +1,250 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// alertActionsServer serves alert template 4, applying updates to template,
+// and script 12. Update bodies are recorded.
+func alertActionsServer(t *testing.T, template map[string]interface{}) (*httptest.Server, *[]map[string]interface{}) {
+    t.Helper()
+    var updates []map[string]interface{}
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/12/":
+            writeJSON(t, w, map[string]interface{}{"id": 12, "name": "Restart Service"})
+        case r.Method == http.MethodGet && r.URL.Path == "/alerts/templates/4/":
+            writeJSON(t, w, template)
+        case r.Method == http.MethodPut && r.URL.Path == "/alerts/templates/4/":
+            var body map[string]interface{}
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            updates = append(updates, body)
+            for field, value := range body {
+                template[field] = value
+            }
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &updates
+}
+
+func alertActionsModel() *AlertTemplateActionsResourceModel {
+    return &AlertTemplateActionsResourceModel{
+        Id:                    types.StringNull(),
+        AlertTemplateId:       types.Int64Value(4),
+        FailureAction:         types.Int64Null(),
+        FailureActionArgs:     types.ListNull(types.StringType),
+        FailureActionTimeout:  types.Int64Null(),
+        ResolvedAction:        types.Int64Null(),
+        ResolvedActionArgs:    types.ListNull(types.StringType),
+        ResolvedActionTimeout: types.Int64Null(),
+    }
+}
+
+func TestAlertTemplateActionsRequestBody(t *testing.T) {
+    model := alertActionsModel()
+    model.FailureAction = types.Int64Value(12)
+    model.FailureActionArgs = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("-Name"), types.StringValue("Spooler")})
+    model.FailureActionTimeout = types.Int64Value(30)
+
+    body, diags := model.requestBody(context.Background())
+    if diags.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", diags)
+    }
+
+    expected := map[string]interface{}{
+        "action":               int64(12),
+        "action_type":          alertActionTypeScript,
+        "action_args":          []string{"-Name", "Spooler"},
+        "action_timeout":       int64(30),
+        "resolved_action":      nil,
+        "resolved_action_args": []string{},
+    }
+    if !reflect.DeepEqual(body, expected) {
+        t.Errorf("unexpected request body:\n got: %#v\nwant: %#v", body, expected)
+    }
+}
+
+func TestAlertTemplateActionsResourceCreate(t *testing.T) {
+    server, updates := alertActionsServer(t, map[string]interface{}{
+        "id": 4, "name": "Servers", "action": nil, "action_args": []interface{}{}, "action_timeout": 15,
+        "resolved_action": nil, "resolved_action_args": []interface{}{}, "resolved_action_timeout": 15,
+    })
+
+    model := alertActionsModel()
+    model.FailureAction = types.Int64Value(12)
+    model.FailureActionArgs = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Spooler")})
+    model.ResolvedAction = types.Int64Value(12)
+    model.ResolvedActionArgs = types.ListValueMust(types.StringType, []attr.Value{})
+    model.FailureActionTimeout = types.Int64Unknown()
+    model.ResolvedActionTimeout = types.Int64Unknown()
+
+    r := &AlertTemplateActionsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(*updates) != 1 {
+        t.Fatalf("expected 1 update, got %d", len(*updates))
+    }
+    if _, ok := (*updates)[0]["action_timeout"]; ok {
+        t.Errorf("expected no timeout to be sent, got %v", (*updates)[0])
+    }
+
+    var state AlertTemplateActionsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueString() != "4" || state.FailureAction.ValueInt64() != 12 || state.ResolvedAction.ValueInt64() != 12 {
+        t.Errorf("unexpected state: %+v", state)
+    }
+    if state.FailureActionTimeout.ValueInt64() != 15 {
+        t.Errorf("expected the server default timeout, got %s", state.FailureActionTimeout)
+    }
+    if len(state.FailureActionArgs.Elements()) != 1 {
+        t.Errorf("unexpected failure_action_args: %s", state.FailureActionArgs)
+    }
+    // An empty list stays empty instead of turning into null
+    if state.ResolvedActionArgs.IsNull() || len(state.ResolvedActionArgs.Elements()) != 0 {
+        t.Errorf("expected empty resolved_action_args, got %s", state.ResolvedActionArgs)
+    }
+}
+
+func TestAlertTemplateActionsResourceCreate_ScriptNotFound(t *testing.T) {
+    server, updates := alertActionsServer(t, map[string]interface{}{"id": 4})
+
+    model := alertActionsModel()
+    model.FailureAction = types.Int64Value(99)
+
+    r := &AlertTemplateActionsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a missing script")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Script Not Found" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+    if len(*updates) != 0 {
+        t.Errorf("expected no updates, got %v", *updates)
+    }
+}
+
+func TestAlertTemplateActionsResourceRead_NullArgsStayNull(t *testing.T) {
+    server, _ := alertActionsServer(t, map[string]interface{}{
+        "id": 4, "action": map[string]interface{}{"id": 12, "name": "Restart Service"}, "action_args": []interface{}{}, "action_timeout": 20,
+        "resolved_action": nil, "resolved_action_args": []interface{}{}, "resolved_action_timeout": 15,
+    })
+
+    model := alertActionsModel()
+    model.Id = types.StringValue("4")
+    model.FailureAction = types.Int64Value(12)
+
+    r := &AlertTemplateActionsResource{client: newTestClient(server)}
+    resp := readResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AlertTemplateActionsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.FailureAction.ValueInt64() != 12 || !state.ResolvedAction.IsNull() {
+        t.Errorf("unexpected actions: %s, %s", state.FailureAction, state.ResolvedAction)
+    }
+    if !state.FailureActionArgs.IsNull() || !state.ResolvedActionArgs.IsNull() {
+        t.Errorf("expected null args, got %s and %s", state.FailureActionArgs, state.ResolvedActionArgs)
+    }
+}
+
+func TestAlertTemplateActionsResourceDelete_ClearsActions(t *testing.T) {
+    server, updates := alertActionsServer(t, map[string]interface{}{"id": 4, "action": 12})
+
+    model := alertActionsModel()
+    model.Id = types.StringValue("4")
+    model.FailureAction = types.Int64Value(12)
+
+    r := &AlertTemplateActionsResource{client: newTestClient(server)}
+    resp := deleteResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := []map[string]interface{}{{
+        "action": nil, "action_args": []interface{}{}, "resolved_action": nil, "resolved_action_args": []interface{}{},
+    }}
+    if !reflect.DeepEqual(*updates, expected) {
+        t.Errorf("unexpected updates: %v", *updates)
+    }
+}
+
+func TestAlertTemplateActionsResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &AlertTemplateActionsResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    cases := map[string]struct {
+        modify  func(m *AlertTemplateActionsResourceModel)
+        summary string
+    }{
+        "no actions": {
+            modify: func(m *AlertTemplateActionsResourceModel) {},
+        },
+        "script with args": {
+            modify: func(m *AlertTemplateActionsResourceModel) {
+                m.ResolvedAction = types.Int64Value(12)
+                m.ResolvedActionArgs = types.ListValueMust(types.StringType, []attr.Value{})
+                m.ResolvedActionTimeout = types.Int64Value(60)
+            },
+        },
+        "args without script": {
+            modify: func(m *AlertTemplateActionsResourceModel) {
+                m.FailureActionArgs = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("-Force")})
+            },
+            summary: "Invalid Alert Action",
+        },
+        "zero timeout": {
+            modify: func(m *AlertTemplateActionsResourceModel) {
+                m.FailureAction = types.Int64Value(12)
+                m.FailureActionTimeout = types.Int64Value(0)
+            },
+            summary: "Invalid Alert Action",
+        },
+    }
+
+    for name, tc := range cases {
+        model := alertActionsModel()
+        tc.modify(model)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if tc.summary == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != tc.summary {
+            t.Errorf("%s: expected a %q error, got: %v", name, tc.summary, resp.Diagnostics)
+        }
+    }
+}
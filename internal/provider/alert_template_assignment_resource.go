@@ -0,0 +1,438 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AlertTemplateAssignmentResource{}
+var _ resource.ResourceWithImportState = &AlertTemplateAssignmentResource{}
+var _ resource.ResourceWithIdentity = &AlertTemplateAssignmentResource{}
+var _ resource.ResourceWithValidateConfig = &AlertTemplateAssignmentResource{}
+
+func NewAlertTemplateAssignmentResource() resource.Resource {
+    return &AlertTemplateAssignmentResource{}
+}
+
+// AlertTemplateAssignmentResource attaches an alert template to a client, a
+// site or an automation policy.
+type AlertTemplateAssignmentResource struct {
+    client *ClientConfig
+}
+
+// AlertTemplateAssignmentResourceModel describes the resource data model.
+type AlertTemplateAssignmentResourceModel struct {
+    Id              types.String `tfsdk:"id"`
+    AlertTemplateId types.Int64  `tfsdk:"alert_template_id"`
+    ClientId        types.Int64  `tfsdk:"client_id"`
+    SiteId          types.Int64  `tfsdk:"site_id"`
+    PolicyId        types.Int64  `tfsdk:"policy_id"`
+}
+
+// Kinds of objects an alert template can be assigned to.
+const (
+    assignmentTargetClient = "client"
+    assignmentTargetSite   = "site"
+    assignmentTargetPolicy = "policy"
+)
+
+// assignmentTarget is the client, site or policy an alert template is
+// assigned to.
+type assignmentTarget struct {
+    kind string
+    id   int64
+}
+
+func (r *AlertTemplateAssignmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_alert_template_assignment"
+}
+
+func (r *AlertTemplateAssignmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Assigns an alert template to a client, a site or an automation policy. Exactly one of `client_id`, `site_id` or `policy_id` must be set. " +
+            "A template switched or removed outside Terraform is reported as drift, and destroying the resource detaches the template.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of the assignment in the form `<client|site|policy>/<id>`",
+                Computed:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.UseStateForUnknown(),
+                },
+            },
+            "alert_template_id": schema.Int64Attribute{
+                MarkdownDescription: "Alert template to assign",
+                Required:            true,
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Client the template is assigned to",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Site the template is assigned to",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "policy_id": schema.Int64Attribute{
+                MarkdownDescription: "Automation policy the template is assigned to",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+        },
+    }
+}
+
+func (r *AlertTemplateAssignmentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data AlertTemplateAssignmentResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.ClientId.IsUnknown() || data.SiteId.IsUnknown() || data.PolicyId.IsUnknown() {
+        return
+    }
+
+    set := 0
+    for _, id := range []types.Int64{data.ClientId, data.SiteId, data.PolicyId} {
+        if !id.IsNull() {
+            set++
+        }
+    }
+    if set != 1 {
+        resp.Diagnostics.AddError(
+            "Invalid Assignment Target",
+            "Exactly one of 'client_id', 'site_id' or 'policy_id' must be specified.",
+        )
+    }
+}
+
+func (r *AlertTemplateAssignmentResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = identityschema.Schema{
+        Attributes: map[string]identityschema.Attribute{
+            "client_id": identityschema.Int64Attribute{
+                Description:       "Client the template is assigned to",
+                OptionalForImport: true,
+            },
+            "site_id": identityschema.Int64Attribute{
+                Description:       "Site the template is assigned to",
+                OptionalForImport: true,
+            },
+            "policy_id": identityschema.Int64Attribute{
+                Description:       "Automation policy the template is assigned to",
+                OptionalForImport: true,
+            },
+        },
+    }
+}
+
+func (r *AlertTemplateAssignmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *AlertTemplateAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AlertTemplateAssignmentResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    target := data.target()
+
+    // Refuse to take over an assignment made outside Terraform; it has to be
+    // imported so replacing it shows up in a plan
+    current, found, err := r.assignedTemplate(ctx, target)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s, got error: %s", target, err))
+        return
+    }
+    if !found {
+        resp.Diagnostics.AddError("Assignment Target Not Found", fmt.Sprintf("The %s does not exist.", target))
+        return
+    }
+    if !current.IsNull() && !current.Equal(data.AlertTemplateId) {
+        resp.Diagnostics.AddError(
+            "Alert Template Already Assigned",
+            fmt.Sprintf("The %s already has alert template %d assigned. Import it with the ID %q to manage the existing assignment.", target, current.ValueInt64(), target.importID()),
+        )
+        return
+    }
+
+    r.assign(ctx, target, data.AlertTemplateId.ValueInt64(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = types.StringValue(target.importID())
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    data.setIdentity(ctx, resp.Identity, &resp.Diagnostics)
+}
+
+func (r *AlertTemplateAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AlertTemplateAssignmentResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    data.setIdentity(ctx, resp.Identity, &resp.Diagnostics)
+
+    target := data.target()
+    current, found, err := r.assignedTemplate(ctx, target)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s, got error: %s", target, err))
+        return
+    }
+
+    // A deleted target or a detached template means the assignment is gone
+    if !found || current.IsNull() {
+        resp.State.RemoveResource(ctx)
+        return
+    }
+
+    // A template switched in the UI is stored as is so the plan shows it
+    data.AlertTemplateId = current
+    data.Id = types.StringValue(target.importID())
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AlertTemplateAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AlertTemplateAssignmentResourceModel
+    var state AlertTemplateAssignmentResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Only alert_template_id can change in place; the target forces replacement
+    r.assign(ctx, data.target(), data.AlertTemplateId.ValueInt64(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = state.Id
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    data.setIdentity(ctx, resp.Identity, &resp.Diagnostics)
+}
+
+func (r *AlertTemplateAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data AlertTemplateAssignmentResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    target := data.target()
+    current, found, err := r.assignedTemplate(ctx, target)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s, got error: %s", target, err))
+        return
+    }
+
+    // Leave a template assigned by someone else in the meantime alone
+    if !found || !current.Equal(data.AlertTemplateId) {
+        return
+    }
+
+    r.assign(ctx, target, nil, &resp.Diagnostics)
+}
+
+func (r *AlertTemplateAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    var target assignmentTarget
+    if importsByIdentity(req) {
+        // The identity has the target attribute of the configuration
+        var data AlertTemplateAssignmentResourceModel
+        resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root("client_id"), &data.ClientId)...)
+        resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root("site_id"), &data.SiteId)...)
+        resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root("policy_id"), &data.PolicyId)...)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+
+        targets := 0
+        for _, id := range []types.Int64{data.ClientId, data.SiteId, data.PolicyId} {
+            if !id.IsNull() {
+                targets++
+            }
+        }
+        if targets != 1 {
+            resp.Diagnostics.AddError("Invalid Identity", "Exactly one of client_id, site_id or policy_id must be set in the identity.")
+            return
+        }
+        target = data.target()
+    } else {
+        kind, rawId, ok := strings.Cut(req.ID, "/")
+        id, err := strconv.ParseInt(rawId, 10, 64)
+        if !ok || err != nil {
+            resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Expected an ID of the form <client|site|policy>/<id>, got %q", req.ID))
+            return
+        }
+
+        switch kind {
+        case assignmentTargetClient, assignmentTargetSite, assignmentTargetPolicy:
+        default:
+            resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unknown assignment target %q, expected client, site or policy", kind))
+            return
+        }
+        target = assignmentTarget{kind: kind, id: id}
+    }
+
+    // The target attributes are named after the kinds of targets
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(target.kind+"_id"), target.id)...)
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), target.importID())...)
+}
+
+// target returns the object the template is assigned to.
+func (m AlertTemplateAssignmentResourceModel) target() assignmentTarget {
+    switch {
+    case !m.ClientId.IsNull():
+        return assignmentTarget{kind: assignmentTargetClient, id: m.ClientId.ValueInt64()}
+    case !m.SiteId.IsNull():
+        return assignmentTarget{kind: assignmentTargetSite, id: m.SiteId.ValueInt64()}
+    default:
+        return assignmentTarget{kind: assignmentTargetPolicy, id: m.PolicyId.ValueInt64()}
+    }
+}
+
+// setIdentity stores the target attribute as the identity.
+func (m AlertTemplateAssignmentResourceModel) setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, diags *diag.Diagnostics) {
+    setIdentityAttribute(ctx, identity, "client_id", m.ClientId, diags)
+    setIdentityAttribute(ctx, identity, "site_id", m.SiteId, diags)
+    setIdentityAttribute(ctx, identity, "policy_id", m.PolicyId, diags)
+}
+
+func (t assignmentTarget) String() string {
+    return fmt.Sprintf("%s %d", t.kind, t.id)
+}
+
+// importID returns the resource ID, which is also the import ID.
+func (t assignmentTarget) importID() string {
+    return fmt.Sprintf("%s/%d", t.kind, t.id)
+}
+
+// url returns the detail endpoint of the target.
+func (t assignmentTarget) url(baseURL string) string {
+    switch t.kind {
+    case assignmentTargetClient:
+        return fmt.Sprintf("%s/clients/%d/", baseURL, t.id)
+    case assignmentTargetSite:
+        return fmt.Sprintf("%s/clients/sites/%d/", baseURL, t.id)
+    default:
+        return fmt.Sprintf("%s/automation/policies/%d/", baseURL, t.id)
+    }
+}
+
+// updateBody returns the partial update setting the alert template of the
+// target. Clients and sites expect the fields wrapped in an object named
+// after the target, policies take them at the top level.
+func (t assignmentTarget) updateBody(alertTemplate interface{}) map[string]interface{} {
+    fields := map[string]interface{}{"alert_template": alertTemplate}
+    if t.kind == assignmentTargetPolicy {
+        return fields
+    }
+    return map[string]interface{}{t.kind: fields}
+}
+
+// assignedTemplate returns the alert template currently assigned to the
+// target, null if there is none. found is false when the target does not exist.
+func (r *AlertTemplateAssignmentResource) assignedTemplate(ctx context.Context, target assignmentTarget) (types.Int64, bool, error) {
+    statusCode, respBody, err := r.client.sendJSON(ctx, "GET", target.url(r.client.BaseURL), nil)
+    if err != nil {
+        return types.Int64Null(), false, err
+    }
+
+    if statusCode == http.StatusNotFound {
+        return types.Int64Null(), false, nil
+    }
+
+    if statusCode != http.StatusOK {
+        return types.Int64Null(), false, fmt.Errorf("unexpected status code: %d", statusCode)
+    }
+
+    var object map[string]interface{}
+    if err := json.Unmarshal(respBody, &object); err != nil {
+        return types.Int64Null(), false, fmt.Errorf("unable to parse response: %w", err)
+    }
+
+    return int64Value(object["alert_template"]), true, nil
+}
+
+// assign sets the alert template of the target; nil detaches it.
+func (r *AlertTemplateAssignmentResource) assign(ctx context.Context, target assignmentTarget, alertTemplate interface{}, diags *diag.Diagnostics) {
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", target.url(r.client.BaseURL), target.updateBody(alertTemplate))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update alert template of %s, got error: %s", target, err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to update alert template of %s, status code: %d, response: %s", target, statusCode, errorMessage(respBody)))
+    }
+}
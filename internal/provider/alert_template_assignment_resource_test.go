@@ -0,0 +1,151 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// assignmentServer serves one client, one site and one policy whose alert
+// templates are kept in templates by path, and records the update bodies.
+func assignmentServer(t *testing.T, templates map[string]interface{}) (*httptest.Server, *[]map[string]interface{}) {
+    t.Helper()
+    var updates []map[string]interface{}
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        template, ok := templates[r.URL.Path]
+        if !ok {
+            http.NotFound(w, r)
+            return
+        }
+        switch r.Method {
+        case http.MethodGet:
+            writeJSON(t, w, map[string]interface{}{"id": 3, "alert_template": template})
+        case http.MethodPut:
+            var body map[string]interface{}
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            updates = append(updates, body)
+            writeJSON(t, w, "updated")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server, &updates
+}
+
+func assignmentModel(alertTemplateId int64, clientId, siteId, policyId types.Int64) *AlertTemplateAssignmentResourceModel {
+    return &AlertTemplateAssignmentResourceModel{
+        Id:              types.StringNull(),
+        AlertTemplateId: types.Int64Value(alertTemplateId),
+        ClientId:        clientId,
+        SiteId:          siteId,
+        PolicyId:        policyId,
+    }
+}
+
+func TestAlertTemplateAssignmentResourceCreate_Client(t *testing.T) {
+    server, updates := assignmentServer(t, map[string]interface{}{"/clients/3/": nil})
+
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    resp := createResource(t, r, assignmentModel(8, types.Int64Value(3), types.Int64Null(), types.Int64Null()))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := []map[string]interface{}{{"client": map[string]interface{}{"alert_template": float64(8)}}}
+    if !reflect.DeepEqual(*updates, expected) {
+        t.Errorf("expected update %v, got %v", expected, *updates)
+    }
+
+    var state AlertTemplateAssignmentResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueString() != "client/3" {
+        t.Errorf("expected id client/3, got %q", state.Id.ValueString())
+    }
+}
+
+func TestAlertTemplateAssignmentResourceCreate_PolicyBodyIsNotWrapped(t *testing.T) {
+    server, updates := assignmentServer(t, map[string]interface{}{"/automation/policies/3/": nil})
+
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    resp := createResource(t, r, assignmentModel(8, types.Int64Null(), types.Int64Null(), types.Int64Value(3)))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := []map[string]interface{}{{"alert_template": float64(8)}}
+    if !reflect.DeepEqual(*updates, expected) {
+        t.Errorf("expected update %v, got %v", expected, *updates)
+    }
+}
+
+func TestAlertTemplateAssignmentResourceCreate_RefusesExistingAssignment(t *testing.T) {
+    server, updates := assignmentServer(t, map[string]interface{}{"/clients/sites/3/": 5})
+
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    resp := createResource(t, r, assignmentModel(8, types.Int64Null(), types.Int64Value(3), types.Int64Null()))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when another template is assigned")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Alert Template Already Assigned" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+    if len(*updates) != 0 {
+        t.Errorf("expected no update, got %v", *updates)
+    }
+}
+
+func TestAlertTemplateAssignmentResourceRead_ReportsSwitchedTemplate(t *testing.T) {
+    server, _ := assignmentServer(t, map[string]interface{}{"/clients/3/": 5})
+
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    model := assignmentModel(8, types.Int64Value(3), types.Int64Null(), types.Int64Null())
+    model.Id = types.StringValue("client/3")
+    resp := readResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state AlertTemplateAssignmentResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.AlertTemplateId.ValueInt64() != 5 {
+        t.Errorf("expected the switched template 5 in state, got %d", state.AlertTemplateId.ValueInt64())
+    }
+}
+
+func TestAlertTemplateAssignmentResourceRead_RemovesDetachedAssignment(t *testing.T) {
+    server, _ := assignmentServer(t, map[string]interface{}{"/clients/3/": nil})
+
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    model := assignmentModel(8, types.Int64Value(3), types.Int64Null(), types.Int64Null())
+    model.Id = types.StringValue("client/3")
+    resp := readResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if !resp.State.Raw.IsNull() {
+        t.Error("expected the detached assignment to be removed from state")
+    }
+}
+
+func TestAlertTemplateAssignmentResourceDelete_LeavesSwitchedTemplate(t *testing.T) {
+    server, updates := assignmentServer(t, map[string]interface{}{"/clients/3/": 5})
+
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    model := assignmentModel(8, types.Int64Value(3), types.Int64Null(), types.Int64Null())
+    model.Id = types.StringValue("client/3")
+    resp := deleteResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(*updates) != 0 {
+        t.Errorf("expected a template assigned by someone else to be left alone, got %v", *updates)
+    }
+}
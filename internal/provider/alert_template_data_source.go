@@ -0,0 +1,343 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AlertTemplateDataSource{}
+
+func NewAlertTemplateDataSource() datasource.DataSource {
+    return &AlertTemplateDataSource{}
+}
+
+// AlertTemplateDataSource defines the data source implementation.
+type AlertTemplateDataSource struct {
+    client *ClientConfig
+}
+
+// AlertTemplateDataSourceModel describes the data source data model.
+type AlertTemplateDataSourceModel struct {
+    Id                          types.Int64  `tfsdk:"id"`
+    Name                        types.String `tfsdk:"name"`
+    IsActive                    types.Bool   `tfsdk:"is_active"`
+    EmailRecipients             types.List   `tfsdk:"email_recipients"`
+    TextRecipients              types.List   `tfsdk:"text_recipients"`
+    EmailFrom                   types.String `tfsdk:"email_from"`
+    Action                      types.Int64  `tfsdk:"action"`
+    ActionTimeout               types.Int64  `tfsdk:"action_timeout"`
+    ResolvedAction              types.Int64  `tfsdk:"resolved_action"`
+    ResolvedActionTimeout       types.Int64  `tfsdk:"resolved_action_timeout"`
+    AgentEmailOnResolved        types.Bool   `tfsdk:"agent_email_on_resolved"`
+    AgentTextOnResolved         types.Bool   `tfsdk:"agent_text_on_resolved"`
+    AgentAlwaysEmail            types.Bool   `tfsdk:"agent_always_email"`
+    AgentAlwaysText             types.Bool   `tfsdk:"agent_always_text"`
+    AgentAlwaysAlert            types.Bool   `tfsdk:"agent_always_alert"`
+    AgentPeriodicAlertDays      types.Int64  `tfsdk:"agent_periodic_alert_days"`
+    CheckEmailAlertSeverity     types.List   `tfsdk:"check_email_alert_severity"`
+    CheckTextAlertSeverity      types.List   `tfsdk:"check_text_alert_severity"`
+    CheckDashboardAlertSeverity types.List   `tfsdk:"check_dashboard_alert_severity"`
+    CheckEmailOnResolved        types.Bool   `tfsdk:"check_email_on_resolved"`
+    CheckTextOnResolved         types.Bool   `tfsdk:"check_text_on_resolved"`
+    CheckAlwaysEmail            types.Bool   `tfsdk:"check_always_email"`
+    CheckAlwaysText             types.Bool   `tfsdk:"check_always_text"`
+    CheckAlwaysAlert            types.Bool   `tfsdk:"check_always_alert"`
+    CheckPeriodicAlertDays      types.Int64  `tfsdk:"check_periodic_alert_days"`
+    TaskEmailAlertSeverity      types.List   `tfsdk:"task_email_alert_severity"`
+    TaskTextAlertSeverity       types.List   `tfsdk:"task_text_alert_severity"`
+    TaskDashboardAlertSeverity  types.List   `tfsdk:"task_dashboard_alert_severity"`
+    TaskEmailOnResolved         types.Bool   `tfsdk:"task_email_on_resolved"`
+    TaskTextOnResolved          types.Bool   `tfsdk:"task_text_on_resolved"`
+    TaskAlwaysEmail             types.Bool   `tfsdk:"task_always_email"`
+    TaskAlwaysText              types.Bool   `tfsdk:"task_always_text"`
+    TaskAlwaysAlert             types.Bool   `tfsdk:"task_always_alert"`
+    TaskPeriodicAlertDays       types.Int64  `tfsdk:"task_periodic_alert_days"`
+    ExcludeWorkstations         types.Bool   `tfsdk:"exclude_workstations"`
+    ExcludeServers              types.Bool   `tfsdk:"exclude_servers"`
+}
+
+func (d *AlertTemplateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_alert_template"
+}
+
+func (d *AlertTemplateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Alert Template data source for Tactical RMM. Use this to look up an existing alert template by ID or exact name and read its notification settings.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: "Alert template identifier. Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Alert template name (exact match). Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "is_active": schema.BoolAttribute{
+                MarkdownDescription: "Whether the template is active",
+                Computed:            true,
+            },
+            "email_recipients": schema.ListAttribute{
+                MarkdownDescription: "Email addresses notified by this template",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "text_recipients": schema.ListAttribute{
+                MarkdownDescription: "Phone numbers notified by SMS",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "email_from": schema.StringAttribute{
+                MarkdownDescription: "Sender address of alert emails",
+                Computed:            true,
+            },
+            "action": schema.Int64Attribute{
+                MarkdownDescription: "Script run when an alert is triggered",
+                Computed:            true,
+            },
+            "action_timeout": schema.Int64Attribute{
+                MarkdownDescription: "Timeout in seconds of the failure action",
+                Computed:            true,
+            },
+            "resolved_action": schema.Int64Attribute{
+                MarkdownDescription: "Script run when an alert is resolved",
+                Computed:            true,
+            },
+            "resolved_action_timeout": schema.Int64Attribute{
+                MarkdownDescription: "Timeout in seconds of the resolved action",
+                Computed:            true,
+            },
+            "agent_email_on_resolved": schema.BoolAttribute{
+                MarkdownDescription: "Email when an agent comes back online",
+                Computed:            true,
+            },
+            "agent_text_on_resolved": schema.BoolAttribute{
+                MarkdownDescription: "Text when an agent comes back online",
+                Computed:            true,
+            },
+            "agent_always_email": schema.BoolAttribute{
+                MarkdownDescription: "Always email on agent outages, overriding agent settings",
+                Computed:            true,
+            },
+            "agent_always_text": schema.BoolAttribute{
+                MarkdownDescription: "Always text on agent outages, overriding agent settings",
+                Computed:            true,
+            },
+            "agent_always_alert": schema.BoolAttribute{
+                MarkdownDescription: "Always raise dashboard alerts on agent outages",
+                Computed:            true,
+            },
+            "agent_periodic_alert_days": schema.Int64Attribute{
+                MarkdownDescription: "Days between repeated agent outage notifications",
+                Computed:            true,
+            },
+            "check_email_alert_severity": schema.ListAttribute{
+                MarkdownDescription: "Check severities that send email",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "check_text_alert_severity": schema.ListAttribute{
+                MarkdownDescription: "Check severities that send texts",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "check_dashboard_alert_severity": schema.ListAttribute{
+                MarkdownDescription: "Check severities that raise dashboard alerts",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "check_email_on_resolved": schema.BoolAttribute{
+                MarkdownDescription: "Email when a check recovers",
+                Computed:            true,
+            },
+            "check_text_on_resolved": schema.BoolAttribute{
+                MarkdownDescription: "Text when a check recovers",
+                Computed:            true,
+            },
+            "check_always_email": schema.BoolAttribute{
+                MarkdownDescription: "Always email on check failures, overriding check settings",
+                Computed:            true,
+            },
+            "check_always_text": schema.BoolAttribute{
+                MarkdownDescription: "Always text on check failures, overriding check settings",
+                Computed:            true,
+            },
+            "check_always_alert": schema.BoolAttribute{
+                MarkdownDescription: "Always raise dashboard alerts on check failures",
+                Computed:            true,
+            },
+            "check_periodic_alert_days": schema.Int64Attribute{
+                MarkdownDescription: "Days between repeated check failure notifications",
+                Computed:            true,
+            },
+            "task_email_alert_severity": schema.ListAttribute{
+                MarkdownDescription: "Task severities that send email",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "task_text_alert_severity": schema.ListAttribute{
+                MarkdownDescription: "Task severities that send texts",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "task_dashboard_alert_severity": schema.ListAttribute{
+                MarkdownDescription: "Task severities that raise dashboard alerts",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "task_email_on_resolved": schema.BoolAttribute{
+                MarkdownDescription: "Email when a task recovers",
+                Computed:            true,
+            },
+            "task_text_on_resolved": schema.BoolAttribute{
+                MarkdownDescription: "Text when a task recovers",
+                Computed:            true,
+            },
+            "task_always_email": schema.BoolAttribute{
+                MarkdownDescription: "Always email on task failures, overriding task settings",
+                Computed:            true,
+            },
+            "task_always_text": schema.BoolAttribute{
+                MarkdownDescription: "Always text on task failures, overriding task settings",
+                Computed:            true,
+            },
+            "task_always_alert": schema.BoolAttribute{
+                MarkdownDescription: "Always raise dashboard alerts on task failures",
+                Computed:            true,
+            },
+            "task_periodic_alert_days": schema.Int64Attribute{
+                MarkdownDescription: "Days between repeated task failure notifications",
+                Computed:            true,
+            },
+            "exclude_workstations": schema.BoolAttribute{
+                MarkdownDescription: "Whether workstations are excluded",
+                Computed:            true,
+            },
+            "exclude_servers": schema.BoolAttribute{
+                MarkdownDescription: "Whether servers are excluded",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *AlertTemplateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *AlertTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data AlertTemplateDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Validate that either ID or name is provided
+    if data.Id.IsNull() && data.Name.IsNull() {
+        resp.Diagnostics.AddError(
+            "Missing Alert Template Identifier",
+            "Either 'id' or 'name' must be specified to look up an alert template.",
+        )
+        return
+    }
+
+    templates, err := d.client.listObjects(ctx, fmt.Sprintf("%s/alerts/templates/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list alert templates, got error: %s", err))
+        return
+    }
+
+    // Find the template by ID, or by name which must be unique
+    var matches []map[string]interface{}
+    for _, t := range templates {
+        if !data.Id.IsNull() {
+            if id, ok := t["id"].(float64); ok && int64(id) == data.Id.ValueInt64() {
+                matches = append(matches, t)
+            }
+        } else if name, ok := t["name"].(string); ok && name == data.Name.ValueString() {
+            matches = append(matches, t)
+        }
+    }
+
+    if len(matches) == 0 {
+        if !data.Id.IsNull() {
+            resp.Diagnostics.AddError("Alert Template Not Found", fmt.Sprintf("Alert template with ID %d not found", data.Id.ValueInt64()))
+        } else {
+            resp.Diagnostics.AddError("Alert Template Not Found", fmt.Sprintf("Alert template with name '%s' not found", data.Name.ValueString()))
+        }
+        return
+    }
+
+    if len(matches) > 1 {
+        resp.Diagnostics.AddError(
+            "Ambiguous Alert Template Name",
+            fmt.Sprintf("Found %d alert templates named '%s'; use 'id' to select one.", len(matches), data.Name.ValueString()),
+        )
+        return
+    }
+
+    template := matches[0]
+
+    // Update model with response data
+    data.Id = int64Value(template["id"])
+    data.Name = stringValue(template["name"])
+    data.IsActive = boolValue(template["is_active"])
+    data.EmailRecipients = stringListValue(template["email_recipients"])
+    data.TextRecipients = stringListValue(template["text_recipients"])
+    data.EmailFrom = stringValue(template["email_from"])
+    data.Action = int64Value(template["action"])
+    data.ActionTimeout = int64Value(template["action_timeout"])
+    data.ResolvedAction = int64Value(template["resolved_action"])
+    data.ResolvedActionTimeout = int64Value(template["resolved_action_timeout"])
+    data.AgentEmailOnResolved = boolValue(template["agent_email_on_resolved"])
+    data.AgentTextOnResolved = boolValue(template["agent_text_on_resolved"])
+    data.AgentAlwaysEmail = boolValue(template["agent_always_email"])
+    data.AgentAlwaysText = boolValue(template["agent_always_text"])
+    data.AgentAlwaysAlert = boolValue(template["agent_always_alert"])
+    data.AgentPeriodicAlertDays = int64Value(template["agent_periodic_alert_days"])
+    data.CheckEmailAlertSeverity = stringListValue(template["check_email_alert_severity"])
+    data.CheckTextAlertSeverity = stringListValue(template["check_text_alert_severity"])
+    data.CheckDashboardAlertSeverity = stringListValue(template["check_dashboard_alert_severity"])
+    data.CheckEmailOnResolved = boolValue(template["check_email_on_resolved"])
+    data.CheckTextOnResolved = boolValue(template["check_text_on_resolved"])
+    data.CheckAlwaysEmail = boolValue(template["check_always_email"])
+    data.CheckAlwaysText = boolValue(template["check_always_text"])
+    data.CheckAlwaysAlert = boolValue(template["check_always_alert"])
+    data.CheckPeriodicAlertDays = int64Value(template["check_periodic_alert_days"])
+    data.TaskEmailAlertSeverity = stringListValue(template["task_email_alert_severity"])
+    data.TaskTextAlertSeverity = stringListValue(template["task_text_alert_severity"])
+    data.TaskDashboardAlertSeverity = stringListValue(template["task_dashboard_alert_severity"])
+    data.TaskEmailOnResolved = boolValue(template["task_email_on_resolved"])
+    data.TaskTextOnResolved = boolValue(template["task_text_on_resolved"])
+    data.TaskAlwaysEmail = boolValue(template["task_always_email"])
+    data.TaskAlwaysText = boolValue(template["task_always_text"])
+    data.TaskAlwaysAlert = boolValue(template["task_always_alert"])
+    data.TaskPeriodicAlertDays = int64Value(template["task_periodic_alert_days"])
+    data.ExcludeWorkstations = boolValue(template["exclude_workstations"])
+    data.ExcludeServers = boolValue(template["exclude_servers"])
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func readAlertTemplate(t *testing.T, id types.Int64, name types.String) (AlertTemplateDataSourceModel, bool) {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/alerts/templates/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": 1, "name": "Servers", "is_active": true, "email_recipients": []string{"ops@example.com"}, "check_email_alert_severity": []string{"error"}, "agent_always_email": nil},
+            {"id": 2, "name": "Shared", "is_active": true},
+            {"id": 3, "name": "Shared", "is_active": false},
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    d := &AlertTemplateDataSource{client: newTestClient(server)}
+    nullList := types.ListNull(types.StringType)
+    resp := readDataSource(t, d, &AlertTemplateDataSourceModel{
+        Id:                          id,
+        Name:                        name,
+        EmailRecipients:             nullList,
+        TextRecipients:              nullList,
+        CheckEmailAlertSeverity:     nullList,
+        CheckTextAlertSeverity:      nullList,
+        CheckDashboardAlertSeverity: nullList,
+        TaskEmailAlertSeverity:      nullList,
+        TaskTextAlertSeverity:       nullList,
+        TaskDashboardAlertSeverity:  nullList,
+    })
+    var state AlertTemplateDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    return state, resp.Diagnostics.HasError()
+}
+
+func TestAlertTemplateDataSourceRead_ByName(t *testing.T) {
+    state, failed := readAlertTemplate(t, types.Int64Null(), types.StringValue("Servers"))
+    if failed {
+        t.Fatal("unexpected error looking up a unique name")
+    }
+    if state.Id.ValueInt64() != 1 {
+        t.Errorf("expected id 1, got %d", state.Id.ValueInt64())
+    }
+    var recipients []string
+    state.EmailRecipients.ElementsAs(context.Background(), &recipients, false)
+    if len(recipients) != 1 || recipients[0] != "ops@example.com" {
+        t.Errorf("unexpected email recipients: %v", recipients)
+    }
+    if !state.AgentAlwaysEmail.IsNull() {
+        t.Errorf("expected agent_always_email to stay null, got %v", state.AgentAlwaysEmail)
+    }
+}
+
+func TestAlertTemplateDataSourceRead_AmbiguousAndMissingNames(t *testing.T) {
+    if _, failed := readAlertTemplate(t, types.Int64Null(), types.StringValue("Shared")); !failed {
+        t.Error("expected an error for an ambiguous name")
+    }
+    if _, failed := readAlertTemplate(t, types.Int64Null(), types.StringValue("Missing")); !failed {
+        t.Error("expected an error for a missing name")
+    }
+    if _, failed := readAlertTemplate(t, types.Int64Value(3), types.StringNull()); failed {
+        t.Error("expected a lookup by id to select one of the duplicates")
+    }
+}
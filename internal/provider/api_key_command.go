@@ -0,0 +1,49 @@
+package provider
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "os/exec"
+    "strings"
+    "time"
+)
+
+// apiKeyCommandTimeout bounds how long api_key_command may run. It is a
+// variable so tests can shorten it.
+var apiKeyCommandTimeout = 30 * time.Second
+
+// resolveAPIKeyCommand runs the api_key_command helper and returns the API key
+// it prints on stdout, following the credential_process pattern. The command
+// is executed directly, not through a shell.
+func resolveAPIKeyCommand(ctx context.Context, command []string) (string, error) {
+    if len(command) == 0 || command[0] == "" {
+        return "", errors.New("command must not be empty")
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+    defer cancel()
+
+    var stdout, stderr bytes.Buffer
+    cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+    cmd.Stdout = &stdout
+    cmd.Stderr = &stderr
+
+    if err := cmd.Run(); err != nil {
+        if ctx.Err() == context.DeadlineExceeded {
+            return "", fmt.Errorf("%s did not finish within %s", command[0], apiKeyCommandTimeout)
+        }
+        if message := strings.TrimSpace(stderr.String()); message != "" {
+            return "", fmt.Errorf("%s failed: %w: %s", command[0], err, message)
+        }
+        return "", fmt.Errorf("%s failed: %w", command[0], err)
+    }
+
+    apiKey := strings.TrimSpace(stdout.String())
+    if apiKey == "" {
+        return "", fmt.Errorf("%s printed no API key on stdout", command[0])
+    }
+
+    return apiKey, nil
+}
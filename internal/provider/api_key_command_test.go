@@ -0,0 +1,86 @@
+package provider
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestAPIKeyCommandHelperProcess is not a real test. It is the helper command
+// run by the api_key_command tests, which re-execute the test binary with
+// TRMM_TEST_API_KEY_HELPER set to select its behavior.
+func TestAPIKeyCommandHelperProcess(t *testing.T) {
+    switch os.Getenv("TRMM_TEST_API_KEY_HELPER") {
+    case "":
+        return
+    case "print":
+        fmt.Fprintln(os.Stdout, "  key-from-helper  ")
+    case "fail":
+        fmt.Fprintln(os.Stderr, "vault: permission denied")
+        os.Exit(2)
+    case "empty":
+    }
+    os.Exit(0)
+}
+
+// apiKeyHelperCommand returns an api_key_command running the helper process
+// with the given behavior.
+func apiKeyHelperCommand(t *testing.T, behavior string) types.List {
+    t.Helper()
+    t.Setenv("TRMM_TEST_API_KEY_HELPER", behavior)
+    return types.ListValueMust(types.StringType, []attr.Value{
+        types.StringValue(os.Args[0]),
+        types.StringValue("-test.run=^TestAPIKeyCommandHelperProcess$"),
+    })
+}
+
+func TestProviderConfigure_APIKeyCommand(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKeyCommand: apiKeyHelperCommand(t, "print"),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if got := resp.ResourceData.(*ClientConfig).APIKey; got != "key-from-helper" {
+        t.Errorf("expected the key printed by the command, got %q", got)
+    }
+}
+
+func TestProviderConfigure_APIKeyCommandFails(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKeyCommand: apiKeyHelperCommand(t, "fail"),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when the command fails")
+    }
+    if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "vault: permission denied") {
+        t.Errorf("expected the command stderr in the error, got: %s", detail)
+    }
+}
+
+func TestProviderConfigure_APIKeyCommandPrintsNothing(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKeyCommand: apiKeyHelperCommand(t, "empty"),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when the command prints no key")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Unable to Resolve API Key" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+}
+
+func TestProviderConfigure_APIKeyCommandConflictsWithAPIKey(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKey:        types.StringValue("test-key"),
+        APIKeyCommand: apiKeyHelperCommand(t, "print"),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when both api_key and api_key_command are set")
+    }
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "regexp"
+    "strconv"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &BulkMaintenanceResource{}
+var _ resource.ResourceWithValidateConfig = &BulkMaintenanceResource{}
+
+// maintenanceCountPattern extracts the agent count from the bulk maintenance
+// response, e.g. "Maintenance mode has been enabled on 12 agents".
+var maintenanceCountPattern = regexp.MustCompile(`on (\d+) agents`)
+
+func NewBulkMaintenanceResource() resource.Resource {
+    return &BulkMaintenanceResource{}
+}
+
+// BulkMaintenanceResource toggles maintenance mode for every agent of a client
+// or site when it is created.
+type BulkMaintenanceResource struct {
+    client *ClientConfig
+}
+
+// BulkMaintenanceResourceModel describes the resource data model.
+type BulkMaintenanceResourceModel struct {
+    Id         types.String `tfsdk:"id"`
+    ClientId   types.Int64  `tfsdk:"client_id"`
+    SiteId     types.Int64  `tfsdk:"site_id"`
+    Enabled    types.Bool   `tfsdk:"enabled"`
+    Triggers   types.Map    `tfsdk:"triggers"`
+    AgentCount types.Int64  `tfsdk:"agent_count"`
+    Message    types.String `tfsdk:"message"`
+}
+
+func (r *BulkMaintenanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_bulk_maintenance"
+}
+
+func (r *BulkMaintenanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Enables or disables maintenance mode for every agent of a client or site when created. " +
+            "Destroying this resource does not revert maintenance mode; declare a second resource with `enabled = false` to leave maintenance.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this maintenance toggle",
+                Computed:            true,
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Client whose agents are toggled. Conflicts with `site_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Site whose agents are toggled. Conflicts with `client_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "enabled": schema.BoolAttribute{
+                MarkdownDescription: "Whether maintenance mode is enabled (true) or disabled (false)",
+                Required:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause maintenance mode to be applied again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "agent_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of agents that were toggled",
+                Computed:            true,
+            },
+            "message": schema.StringAttribute{
+                MarkdownDescription: "Response message returned by Tactical RMM",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *BulkMaintenanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data BulkMaintenanceResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.ClientId.IsUnknown() || data.SiteId.IsUnknown() {
+        return
+    }
+
+    if data.ClientId.IsNull() == data.SiteId.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Maintenance Scope",
+            "Exactly one of 'client_id' or 'site_id' must be specified.",
+        )
+    }
+}
+
+func (r *BulkMaintenanceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *BulkMaintenanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data BulkMaintenanceResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Create API request body
+    body := map[string]interface{}{
+        "action": data.Enabled.ValueBool(),
+    }
+    if !data.ClientId.IsNull() {
+        body["type"] = "Client"
+        body["id"] = data.ClientId.ValueInt64()
+    } else {
+        body["type"] = "Site"
+        body["id"] = data.SiteId.ValueInt64()
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/agents/maintenance/bulk/", r.client.BaseURL), body)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to toggle maintenance mode, got error: %s", err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to toggle maintenance mode, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    // The response is only a message, the agent count is embedded in it
+    message := errorMessage(respBody)
+    var count int64
+    if match := maintenanceCountPattern.FindStringSubmatch(message); match != nil {
+        count, _ = strconv.ParseInt(match[1], 10, 64)
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.AgentCount = types.Int64Value(count)
+    data.Message = types.StringValue(message)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BulkMaintenanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Toggling maintenance mode is a one-off operation, there is no remote object to refresh
+}
+
+func (r *BulkMaintenanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data BulkMaintenanceResourceModel
+    var state BulkMaintenanceResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.AgentCount = state.AgentCount
+    data.Message = state.Message
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BulkMaintenanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Maintenance mode is left as is, removing the resource only drops it from state
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// bulkMaintenanceServer answers bulk maintenance requests with status and
+// message, recording the request bodies.
+func bulkMaintenanceServer(t *testing.T, status int, message string) (*httptest.Server, *[]map[string]interface{}) {
+    t.Helper()
+    var requests []map[string]interface{}
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost || r.URL.Path != "/agents/maintenance/bulk/" {
+            http.NotFound(w, r)
+            return
+        }
+        var body map[string]interface{}
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+            t.Errorf("unable to decode request: %s", err)
+        }
+        requests = append(requests, body)
+        w.WriteHeader(status)
+        writeJSON(t, w, message)
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &requests
+}
+
+func bulkMaintenanceModel() *BulkMaintenanceResourceModel {
+    return &BulkMaintenanceResourceModel{
+        ClientId:   types.Int64Null(),
+        SiteId:     types.Int64Null(),
+        Enabled:    types.BoolValue(true),
+        Triggers:   types.MapNull(types.StringType),
+        AgentCount: types.Int64Unknown(),
+        Message:    types.StringUnknown(),
+    }
+}
+
+func TestBulkMaintenanceResourceCreate_Client(t *testing.T) {
+    server, requests := bulkMaintenanceServer(t, http.StatusOK, "Maintenance mode has been enabled on 12 agents")
+
+    model := bulkMaintenanceModel()
+    model.ClientId = types.Int64Value(3)
+
+    r := &BulkMaintenanceResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := map[string]interface{}{"action": true, "type": "Client", "id": float64(3)}
+    if len(*requests) != 1 || !reflect.DeepEqual((*requests)[0], expected) {
+        t.Fatalf("unexpected requests:\n got: %v\nwant: %v", *requests, expected)
+    }
+
+    var state BulkMaintenanceResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.AgentCount.ValueInt64() != 12 {
+        t.Errorf("expected 12 agents, got %s", state.AgentCount)
+    }
+    if state.Message.ValueString() != "Maintenance mode has been enabled on 12 agents" || state.Id.ValueString() == "" {
+        t.Errorf("unexpected state: %+v", state)
+    }
+}
+
+func TestBulkMaintenanceResourceCreate_SiteDisable(t *testing.T) {
+    server, requests := bulkMaintenanceServer(t, http.StatusOK, "Maintenance mode has been disabled on 1 agents")
+
+    model := bulkMaintenanceModel()
+    model.SiteId = types.Int64Value(8)
+    model.Enabled = types.BoolValue(false)
+
+    r := &BulkMaintenanceResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    expected := map[string]interface{}{"action": false, "type": "Site", "id": float64(8)}
+    if len(*requests) != 1 || !reflect.DeepEqual((*requests)[0], expected) {
+        t.Fatalf("unexpected requests:\n got: %v\nwant: %v", *requests, expected)
+    }
+
+    var state BulkMaintenanceResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.AgentCount.ValueInt64() != 1 {
+        t.Errorf("expected 1 agent, got %s", state.AgentCount)
+    }
+}
+
+func TestBulkMaintenanceResourceCreate_UnparsedMessage(t *testing.T) {
+    server, _ := bulkMaintenanceServer(t, http.StatusOK, "No agents found")
+
+    model := bulkMaintenanceModel()
+    model.SiteId = types.Int64Value(8)
+
+    r := &BulkMaintenanceResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    // The message is kept even though no count could be read from it
+    var state BulkMaintenanceResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.AgentCount.ValueInt64() != 0 || state.Message.ValueString() != "No agents found" {
+        t.Errorf("expected no count and the message, got %s and %s", state.AgentCount, state.Message)
+    }
+}
+
+func TestBulkMaintenanceResourceCreate_Rejected(t *testing.T) {
+    server, _ := bulkMaintenanceServer(t, http.StatusBadRequest, "Site does not exist")
+
+    model := bulkMaintenanceModel()
+    model.SiteId = types.Int64Value(99)
+
+    r := &BulkMaintenanceResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "status code: 400, response: Site does not exist") {
+        t.Fatalf("expected the rejection to be reported, got %v", resp.Diagnostics)
+    }
+}
@@ -0,0 +1,521 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &BulkRunScriptResource{}
+var _ resource.ResourceWithValidateConfig = &BulkRunScriptResource{}
+var _ resource.ResourceWithModifyPlan = &BulkRunScriptResource{}
+
+// bulkRunCountPattern extracts the agent count from the bulk script response,
+// e.g. "Cleanup will now be run on 12 agents".
+var bulkRunCountPattern = regexp.MustCompile(`run on (\d+) agents`)
+
+// bulkMonitoringTypes maps the monitoring_type attribute to the monType of
+// the bulk action endpoint.
+var bulkMonitoringTypes = map[string]string{
+    "all":         "all",
+    "server":      "servers",
+    "workstation": "workstations",
+}
+
+func NewBulkRunScriptResource() resource.Resource {
+    return &BulkRunScriptResource{}
+}
+
+// BulkRunScriptResource runs a script on every agent of a client, a site or
+// an explicit agent list when it is created.
+type BulkRunScriptResource struct {
+    client *ClientConfig
+}
+
+// BulkRunScriptResourceModel describes the resource data model.
+type BulkRunScriptResourceModel struct {
+    Id             types.String `tfsdk:"id"`
+    ScriptId       types.Int64  `tfsdk:"script_id"`
+    Args           types.List   `tfsdk:"args"`
+    Timeout        types.Int64  `tfsdk:"timeout"`
+    ClientId       types.Int64  `tfsdk:"client_id"`
+    SiteId         types.Int64  `tfsdk:"site_id"`
+    AgentIds       types.List   `tfsdk:"agent_ids"`
+    MonitoringType types.String `tfsdk:"monitoring_type"`
+    OnlineOnly     types.Bool   `tfsdk:"online_only"`
+    DryRun         types.Bool   `tfsdk:"dry_run"`
+    Triggers       types.Map    `tfsdk:"triggers"`
+    AgentCount     types.Int64  `tfsdk:"agent_count"`
+    TargetAgentIds types.List   `tfsdk:"target_agent_ids"`
+    Message        types.String `tfsdk:"message"`
+}
+
+func (r *BulkRunScriptResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_bulk_run_script"
+}
+
+func (r *BulkRunScriptResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Runs a script on every agent of a client, a site or an explicit agent list when created, like \"Bulk Script\" in the Tools menu. " +
+            "Exactly one of `client_id`, `site_id` or `agent_ids` must be set. The script is not awaited; destroying this resource does nothing on the server.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this run",
+                Computed:            true,
+            },
+            "script_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the script to run",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "args": schema.ListAttribute{
+                MarkdownDescription: "Script arguments. A warning is shown at plan time when they do not match the syntax the script declares",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.List{
+                    listplanmodifier.RequiresReplace(),
+                },
+            },
+            "timeout": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Script timeout in seconds on each agent, defaults to %d", defaultScriptOutputTimeout),
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Run on the agents of this client",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Run on the agents of this site",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "agent_ids": schema.ListAttribute{
+                MarkdownDescription: "Run on these agents",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.List{
+                    listplanmodifier.RequiresReplace(),
+                },
+            },
+            "monitoring_type": schema.StringAttribute{
+                MarkdownDescription: "Only run on agents of this monitoring type: `all` (default), `server` or `workstation`",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "online_only": schema.BoolAttribute{
+                MarkdownDescription: "When true, only run on agents that are online when the resource is created",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "dry_run": schema.BoolAttribute{
+                MarkdownDescription: "When true, only resolve the targeted agents into `target_agent_ids` and `agent_count` without running the script",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the script to run again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "agent_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of agents the script was run on, or would be run on for a dry run",
+                Computed:            true,
+            },
+            "target_agent_ids": schema.ListAttribute{
+                MarkdownDescription: "IDs of the agents in scope when the resource was created, sorted",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "message": schema.StringAttribute{
+                MarkdownDescription: "Response message returned by Tactical RMM, null for a dry run",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *BulkRunScriptResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data BulkRunScriptResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.MonitoringType.IsNull() && !data.MonitoringType.IsUnknown() {
+        if _, ok := bulkMonitoringTypes[data.MonitoringType.ValueString()]; !ok {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("monitoring_type"),
+                "Invalid Monitoring Type",
+                fmt.Sprintf("monitoring_type must be one of \"all\", \"server\" or \"workstation\", got %q.", data.MonitoringType.ValueString()),
+            )
+        }
+    }
+
+    if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() && data.Timeout.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("timeout"),
+            "Invalid Script Timeout",
+            "timeout must be at least 1 second.",
+        )
+    }
+
+    if data.ClientId.IsUnknown() || data.SiteId.IsUnknown() || data.AgentIds.IsUnknown() {
+        return
+    }
+
+    set := 0
+    for _, isNull := range []bool{data.ClientId.IsNull(), data.SiteId.IsNull(), data.AgentIds.IsNull()} {
+        if !isNull {
+            set++
+        }
+    }
+    if set != 1 {
+        resp.Diagnostics.AddError(
+            "Invalid Bulk Run Scope",
+            "Exactly one of 'client_id', 'site_id' or 'agent_ids' must be specified.",
+        )
+    }
+}
+
+func (r *BulkRunScriptResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    // Nothing to check on destroy or before the provider is configured
+    if req.Plan.Raw.IsNull() || r.client == nil {
+        return
+    }
+
+    var data BulkRunScriptResourceModel
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.Args.IsNull() || data.Args.IsUnknown() || data.ScriptId.IsUnknown() {
+        return
+    }
+
+    // The script only runs on create, so the args are not checked again
+    // while they and the script are unchanged
+    if !req.State.Raw.IsNull() {
+        var state BulkRunScriptResourceModel
+        resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+        if resp.Diagnostics.HasError() || (state.ScriptId.Equal(data.ScriptId) && state.Args.Equal(data.Args)) {
+            return
+        }
+    }
+
+    var argValues []types.String
+    resp.Diagnostics.Append(data.Args.ElementsAs(ctx, &argValues, false)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+    args := make([]string, 0, len(argValues))
+    for _, arg := range argValues {
+        if arg.IsUnknown() {
+            return
+        }
+        args = append(args, arg.ValueString())
+    }
+
+    script, err := r.client.getObject(ctx, fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, data.ScriptId.ValueInt64()))
+    if err != nil {
+        resp.Diagnostics.AddWarning(
+            "Unable to Check Script Arguments",
+            fmt.Sprintf("Unable to read script ID %d, got error: %s", data.ScriptId.ValueInt64(), err),
+        )
+        return
+    }
+
+    syntax := stringValue(script["syntax"]).ValueString()
+    problems := parseScriptArgSpec(syntax, stringSlice(script["args"])).problems(args)
+    if len(problems) == 0 {
+        return
+    }
+
+    detail := fmt.Sprintf("The args do not match the usage script %q declares:\n- %s", stringValue(script["name"]).ValueString(), strings.Join(problems, "\n- "))
+    if syntax != "" {
+        detail += fmt.Sprintf("\n\nSyntax: %s", syntax)
+    }
+    resp.Diagnostics.AddAttributeWarning(path.Root("args"), "Script Arguments Mismatch", detail)
+}
+
+func (r *BulkRunScriptResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *BulkRunScriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data BulkRunScriptResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    args := []string{}
+    var agentIds []string
+    if !data.Args.IsNull() {
+        resp.Diagnostics.Append(data.Args.ElementsAs(ctx, &args, false)...)
+    }
+    if !data.AgentIds.IsNull() {
+        resp.Diagnostics.Append(data.AgentIds.ElementsAs(ctx, &agentIds, false)...)
+    }
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    monitoringType := "all"
+    if !data.MonitoringType.IsNull() {
+        monitoringType = data.MonitoringType.ValueString()
+    }
+
+    targets := r.targetAgents(ctx, &data, agentIds, monitoringType, data.OnlineOnly.ValueBool(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    targetValues := make([]attr.Value, len(targets))
+    for i, agentId := range targets {
+        targetValues[i] = types.StringValue(agentId)
+    }
+    targetList, diags := types.ListValue(types.StringType, targetValues)
+    resp.Diagnostics.Append(diags...)
+
+    data.Id = types.StringValue(actionID())
+    data.TargetAgentIds = targetList
+    data.AgentCount = types.Int64Value(int64(len(targets)))
+    data.Message = types.StringNull()
+
+    if data.DryRun.ValueBool() {
+        resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+        return
+    }
+
+    if data.OnlineOnly.ValueBool() && len(targets) == 0 {
+        resp.Diagnostics.AddWarning(
+            "No Online Agents",
+            "No agent in scope is online, so the script was not run.",
+        )
+        resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+        return
+    }
+
+    timeout := int64(defaultScriptOutputTimeout)
+    if !data.Timeout.IsNull() {
+        timeout = data.Timeout.ValueInt64()
+    }
+
+    // Create API request body
+    body := map[string]interface{}{
+        "mode":     "script",
+        "monType":  bulkMonitoringTypes[monitoringType],
+        "osType":   "all",
+        "script":   data.ScriptId.ValueInt64(),
+        "args":     args,
+        "env_vars": []string{},
+        "timeout":  timeout,
+    }
+    switch {
+    case data.OnlineOnly.ValueBool():
+        // The server would also run on the offline agents of a client or site
+        body["target"] = "agents"
+        body["agents"] = targets
+    case !data.ClientId.IsNull():
+        body["target"] = "client"
+        body["client"] = data.ClientId.ValueInt64()
+    case !data.SiteId.IsNull():
+        body["target"] = "site"
+        body["site"] = data.SiteId.ValueInt64()
+    default:
+        // The resolved list already excludes other monitoring types
+        body["target"] = "agents"
+        body["agents"] = targets
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/agents/actions/bulk/", r.client.BaseURL), body)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to run bulk script, got error: %s", err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to run bulk script, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    // The server count wins, it may skip agents the list still showed
+    message := errorMessage(respBody)
+    if match := bulkRunCountPattern.FindStringSubmatch(message); match != nil {
+        if count, err := strconv.ParseInt(match[1], 10, 64); err == nil {
+            data.AgentCount = types.Int64Value(count)
+        }
+    }
+    data.Message = types.StringValue(message)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BulkRunScriptResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Running a script is a one-off operation, there is no remote object to refresh
+}
+
+func (r *BulkRunScriptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data BulkRunScriptResourceModel
+    var state BulkRunScriptResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.AgentCount = state.AgentCount
+    data.TargetAgentIds = state.TargetAgentIds
+    data.Message = state.Message
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BulkRunScriptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // A script that ran cannot be undone, removing the resource only drops it from state
+}
+
+// targetAgents resolves the sorted IDs of the agents in scope. Explicit agent
+// IDs must all exist and are filtered by monitoring type and online status
+// like a client or site scope.
+func (r *BulkRunScriptResource) targetAgents(ctx context.Context, data *BulkRunScriptResourceModel, agentIds []string, monitoringType string, onlineOnly bool, diags *diag.Diagnostics) []string {
+    // The agents list can be filtered by site or client
+    listURL := fmt.Sprintf("%s/agents/", r.client.BaseURL)
+    switch {
+    case !data.ClientId.IsNull():
+        listURL = fmt.Sprintf("%s/agents/?client=%d", r.client.BaseURL, data.ClientId.ValueInt64())
+    case !data.SiteId.IsNull():
+        listURL = fmt.Sprintf("%s/agents/?site=%d", r.client.BaseURL, data.SiteId.ValueInt64())
+    }
+
+    agents, err := r.client.listObjects(ctx, listURL)
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+        return nil
+    }
+
+    var wanted map[string]bool
+    if agentIds != nil {
+        wanted = make(map[string]bool, len(agentIds))
+        for _, agentId := range agentIds {
+            wanted[agentId] = true
+        }
+    }
+
+    now := time.Now().UTC()
+    targets := []string{}
+    for _, agent := range agents {
+        agentId, ok := agent["agent_id"].(string)
+        if !ok || (wanted != nil && !wanted[agentId]) {
+            continue
+        }
+        if wanted != nil {
+            delete(wanted, agentId)
+        }
+        if monitoringType != "all" && agent["monitoring_type"] != monitoringType {
+            continue
+        }
+        if onlineOnly {
+            lastSeen, seen := agentLastSeen(agent)
+            if agentStatus(agent, lastSeen, seen, now) != agentStatusOnline {
+                continue
+            }
+        }
+        targets = append(targets, agentId)
+    }
+
+    if len(wanted) > 0 {
+        missing := make([]string, 0, len(wanted))
+        for agentId := range wanted {
+            missing = append(missing, agentId)
+        }
+        sort.Strings(missing)
+        diags.AddAttributeError(
+            path.Root("agent_ids"),
+            "Agent Not Found",
+            fmt.Sprintf("These agents do not exist: %s", strings.Join(missing, ", ")),
+        )
+        return nil
+    }
+
+    sort.Strings(targets)
+    return targets
+}
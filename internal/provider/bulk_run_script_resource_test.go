@@ -0,0 +1,214 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// bulkRunScriptServer lists three agents of client 1, of which ws-2 is
+// overdue, and records bulk action requests.
+func bulkRunScriptServer(t *testing.T, sent *[]map[string]interface{}) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/":
+            now := time.Now().UTC()
+            agents := []map[string]interface{}{
+                {"agent_id": "ws-2", "hostname": "WS2", "monitoring_type": "workstation", "last_seen": now.Add(-2 * time.Hour).Format(time.RFC3339)},
+                {"agent_id": "srv-1", "hostname": "SRV1", "monitoring_type": "server", "last_seen": now.Format(time.RFC3339)},
+                {"agent_id": "ws-1", "hostname": "WS1", "monitoring_type": "workstation", "last_seen": now.Format(time.RFC3339)},
+            }
+            writeJSON(t, w, agents)
+        case r.Method == http.MethodPost && r.URL.Path == "/agents/actions/bulk/":
+            var body map[string]interface{}
+            json.NewDecoder(r.Body).Decode(&body)
+            *sent = append(*sent, body)
+            writeJSON(t, w, "Cleanup will now be run on 2 agents")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func bulkRunScriptModel() *BulkRunScriptResourceModel {
+    return &BulkRunScriptResourceModel{
+        ScriptId:       types.Int64Value(4),
+        Args:           types.ListNull(types.StringType),
+        ClientId:       types.Int64Null(),
+        SiteId:         types.Int64Null(),
+        AgentIds:       types.ListNull(types.StringType),
+        Triggers:       types.MapNull(types.StringType),
+        TargetAgentIds: types.ListUnknown(types.StringType),
+    }
+}
+
+func TestBulkRunScriptResourceCreate_ClientScope(t *testing.T) {
+    var sent []map[string]interface{}
+    server := bulkRunScriptServer(t, &sent)
+
+    model := bulkRunScriptModel()
+    model.ClientId = types.Int64Value(1)
+    model.MonitoringType = types.StringValue("workstation")
+
+    r := &BulkRunScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(sent) != 1 {
+        t.Fatalf("expected 1 bulk request, got %d", len(sent))
+    }
+    body := sent[0]
+    if body["mode"] != "script" || body["target"] != "client" || body["client"] != float64(1) || body["monType"] != "workstations" || body["script"] != float64(4) {
+        t.Errorf("unexpected request body: %v", body)
+    }
+
+    var state BulkRunScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.AgentCount.ValueInt64() != 2 || state.Message.ValueString() != "Cleanup will now be run on 2 agents" {
+        t.Errorf("unexpected result: count %d, message %q", state.AgentCount.ValueInt64(), state.Message.ValueString())
+    }
+    var targets []string
+    state.TargetAgentIds.ElementsAs(context.Background(), &targets, false)
+    if len(targets) != 2 || targets[0] != "ws-1" || targets[1] != "ws-2" {
+        t.Errorf("expected the sorted workstations, got %v", targets)
+    }
+}
+
+func TestBulkRunScriptResourceCreate_DryRun(t *testing.T) {
+    var sent []map[string]interface{}
+    server := bulkRunScriptServer(t, &sent)
+
+    model := bulkRunScriptModel()
+    model.AgentIds = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("srv-1"), types.StringValue("ws-2")})
+    model.DryRun = types.BoolValue(true)
+
+    r := &BulkRunScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(sent) != 0 {
+        t.Errorf("expected no bulk request for a dry run, got %v", sent)
+    }
+
+    var state BulkRunScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.AgentCount.ValueInt64() != 2 || !state.Message.IsNull() {
+        t.Errorf("unexpected dry run result: count %d, message %v", state.AgentCount.ValueInt64(), state.Message)
+    }
+}
+
+func TestBulkRunScriptResourceCreate_UnknownAgent(t *testing.T) {
+    var sent []map[string]interface{}
+    server := bulkRunScriptServer(t, &sent)
+
+    model := bulkRunScriptModel()
+    model.AgentIds = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ws-1"), types.StringValue("gone")})
+
+    r := &BulkRunScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Agent Not Found" {
+        t.Fatalf("expected an unknown agent error, got: %v", resp.Diagnostics)
+    }
+    if len(sent) != 0 {
+        t.Errorf("expected no bulk request, got %v", sent)
+    }
+}
+
+func TestBulkRunScriptResourceCreate_OnlineOnly(t *testing.T) {
+    var sent []map[string]interface{}
+    server := bulkRunScriptServer(t, &sent)
+
+    model := bulkRunScriptModel()
+    model.ClientId = types.Int64Value(1)
+    model.OnlineOnly = types.BoolValue(true)
+
+    r := &BulkRunScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(sent) != 1 {
+        t.Fatalf("expected 1 bulk request, got %d", len(sent))
+    }
+    // A client target would include the overdue agent, so the online agents are sent
+    agents, _ := sent[0]["agents"].([]interface{})
+    if sent[0]["target"] != "agents" || len(agents) != 2 || agents[0] != "srv-1" || agents[1] != "ws-1" {
+        t.Errorf("expected the online agents to be targeted, got: %v", sent[0])
+    }
+}
+
+func TestBulkRunScriptResourceCreate_NoOnlineAgents(t *testing.T) {
+    var sent []map[string]interface{}
+    server := bulkRunScriptServer(t, &sent)
+
+    model := bulkRunScriptModel()
+    model.AgentIds = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ws-2")})
+    model.OnlineOnly = types.BoolValue(true)
+
+    r := &BulkRunScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(sent) != 0 {
+        t.Errorf("expected no bulk request, got %v", sent)
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 1 || warnings[0].Summary() != "No Online Agents" {
+        t.Errorf("expected a no online agents warning, got: %v", resp.Diagnostics)
+    }
+}
+
+func TestBulkRunScriptResourceModifyPlan_ScriptArgs(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/scripts/4/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, map[string]interface{}{"id": 4, "name": "Cleanup", "syntax": "-OlderThanDays <int> [-WhatIf]", "args": []string{}})
+    }))
+    t.Cleanup(server.Close)
+
+    r := &BulkRunScriptResource{client: newTestClient(server)}
+    plan := func(args ...string) resource.ModifyPlanResponse {
+        model := bulkRunScriptModel()
+        model.ClientId = types.Int64Value(1)
+        values := make([]attr.Value, len(args))
+        for i, arg := range args {
+            values[i] = types.StringValue(arg)
+        }
+        model.Args = types.ListValueMust(types.StringType, values)
+        return planCreate(t, r, model)
+    }
+
+    resp := plan("-OlderThanDays", "30", "-WhatIf")
+    if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 0 {
+        t.Errorf("expected compatible args to pass, got: %v", resp.Diagnostics)
+    }
+
+    resp = plan("-Days", "30")
+    warnings := resp.Diagnostics.Warnings()
+    if resp.Diagnostics.HasError() || len(warnings) != 1 || warnings[0].Summary() != "Script Arguments Mismatch" {
+        t.Fatalf("expected a script arguments warning, got: %v", resp.Diagnostics)
+    }
+    for _, problem := range []string{"-Days is not a parameter", "-OlderThanDays is missing", "Syntax: -OlderThanDays <int> [-WhatIf]"} {
+        if !strings.Contains(warnings[0].Detail(), problem) {
+            t.Errorf("expected the warning to contain %q, got: %s", problem, warnings[0].Detail())
+        }
+    }
+}
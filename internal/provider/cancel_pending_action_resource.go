@@ -0,0 +1,274 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CancelPendingActionResource{}
+var _ resource.ResourceWithValidateConfig = &CancelPendingActionResource{}
+
+func NewCancelPendingActionResource() resource.Resource {
+    return &CancelPendingActionResource{}
+}
+
+// CancelPendingActionResource cancels pending actions when it is created.
+// It does not track a remote object, so Read and Delete only touch state.
+type CancelPendingActionResource struct {
+    client *ClientConfig
+}
+
+// CancelPendingActionResourceModel describes the resource data model.
+type CancelPendingActionResourceModel struct {
+    Id             types.String `tfsdk:"id"`
+    ActionId       types.Int64  `tfsdk:"action_id"`
+    AgentId        types.String `tfsdk:"agent_id"`
+    ActionType     types.String `tfsdk:"action_type"`
+    Triggers       types.Map    `tfsdk:"triggers"`
+    CancelledIds   types.List   `tfsdk:"cancelled_ids"`
+    CancelledCount types.Int64  `tfsdk:"cancelled_count"`
+}
+
+func (r *CancelPendingActionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_cancel_pending_action"
+}
+
+func (r *CancelPendingActionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Cancels Tactical RMM pending actions (scheduled reboots, software installs, etc.) when created. " +
+            "Either cancel a single action by `action_id`, or every pending action of an agent by `agent_id`, optionally filtered by `action_type`. " +
+            "Actions that already completed are skipped rather than reported as errors. Destroying this resource does not restore cancelled actions.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this cancellation",
+                Computed:            true,
+            },
+            "action_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the pending action to cancel. Conflicts with `agent_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent whose pending actions are all cancelled. Conflicts with `action_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "action_type": schema.StringAttribute{
+                MarkdownDescription: "Only cancel pending actions of this type when using `agent_id` (e.g. schedreboot, chocoinstall, taskaction).",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the cancellation to run again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "cancelled_ids": schema.ListAttribute{
+                MarkdownDescription: "IDs of the pending actions that were cancelled",
+                Computed:            true,
+                ElementType:         types.Int64Type,
+            },
+            "cancelled_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of pending actions that were cancelled",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *CancelPendingActionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data CancelPendingActionResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.ActionId.IsUnknown() || data.AgentId.IsUnknown() {
+        return
+    }
+
+    if data.ActionId.IsNull() == data.AgentId.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Pending Action Selection",
+            "Exactly one of 'action_id' or 'agent_id' must be specified.",
+        )
+        return
+    }
+
+    if !data.ActionType.IsNull() && data.AgentId.IsNull() {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("action_type"),
+            "Invalid Pending Action Selection",
+            "'action_type' can only be used together with 'agent_id'.",
+        )
+    }
+}
+
+func (r *CancelPendingActionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *CancelPendingActionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data CancelPendingActionResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Pending actions are listed globally, or per agent for bulk cancellation
+    listURL := fmt.Sprintf("%s/logs/pendingactions/", r.client.BaseURL)
+    if !data.AgentId.IsNull() {
+        listURL = fmt.Sprintf("%s/agents/%s/pendingactions/", r.client.BaseURL, data.AgentId.ValueString())
+    }
+
+    actions, err := r.client.listObjects(ctx, listURL)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list pending actions, got error: %s", err))
+        return
+    }
+
+    // Select the actions that are still pending and match the requested scope
+    var targetIds []int64
+    for _, action := range actions {
+        id, ok := action["id"].(float64)
+        if !ok {
+            continue
+        }
+        if status, ok := action["status"].(string); ok && status != "pending" {
+            continue
+        }
+        if !data.ActionId.IsNull() && int64(id) != data.ActionId.ValueInt64() {
+            continue
+        }
+        if !data.ActionType.IsNull() {
+            if actionType, ok := action["action_type"].(string); !ok || actionType != data.ActionType.ValueString() {
+                continue
+            }
+        }
+        targetIds = append(targetIds, int64(id))
+    }
+
+    if !data.ActionId.IsNull() && len(targetIds) == 0 {
+        resp.Diagnostics.AddWarning(
+            "Pending Action Already Completed",
+            fmt.Sprintf("Pending action %d is no longer pending; nothing was cancelled.", data.ActionId.ValueInt64()),
+        )
+    }
+
+    cancelled := make([]attr.Value, 0, len(targetIds))
+    for _, id := range targetIds {
+        httpReq, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/logs/pendingactions/%d/", r.client.BaseURL, id), nil)
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to cancel pending action %d, got error: %s", id, err))
+            return
+        }
+
+        httpResp, err := r.client.Do(httpReq)
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to cancel pending action %d, got error: %s", id, err))
+            return
+        }
+        httpResp.Body.Close()
+
+        // The action may have completed between listing and cancelling it
+        if httpResp.StatusCode == http.StatusNotFound {
+            continue
+        }
+
+        if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to cancel pending action %d, status code: %d", id, httpResp.StatusCode))
+            return
+        }
+
+        cancelled = append(cancelled, types.Int64Value(id))
+    }
+
+    cancelledIds, diags := types.ListValue(types.Int64Type, cancelled)
+    resp.Diagnostics.Append(diags...)
+    data.CancelledIds = cancelledIds
+    data.CancelledCount = types.Int64Value(int64(len(cancelled)))
+    data.Id = types.StringValue(actionID())
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CancelPendingActionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Cancellation is a one-off operation, there is no remote object to refresh
+}
+
+func (r *CancelPendingActionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data CancelPendingActionResourceModel
+    var state CancelPendingActionResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.CancelledIds = state.CancelledIds
+    data.CancelledCount = state.CancelledCount
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CancelPendingActionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Cancelled actions cannot be restored, removing the resource only drops it from state
+}
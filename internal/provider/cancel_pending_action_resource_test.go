@@ -0,0 +1,178 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// pendingActionsServer lists the pending actions of agent-1: 1 and 2 are
+// pending, 3 already completed. Cancelling action 2 finds it gone and
+// cancelling action 4, listed globally, fails. Cancelled IDs are recorded.
+func pendingActionsServer(t *testing.T) (*httptest.Server, *[]string) {
+    t.Helper()
+    var deleted []string
+
+    agentActions := []map[string]interface{}{
+        {"id": 1, "action_type": "schedreboot", "status": "pending"},
+        {"id": 2, "action_type": "chocoinstall", "status": "pending"},
+        {"id": 3, "action_type": "schedreboot", "status": "completed"},
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/agent-1/pendingactions/":
+            writeJSON(t, w, agentActions)
+        case r.Method == http.MethodGet && r.URL.Path == "/logs/pendingactions/":
+            writeJSON(t, w, append(agentActions, map[string]interface{}{"id": 4, "action_type": "taskaction", "status": "pending"}))
+        case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/logs/pendingactions/"):
+            id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/logs/pendingactions/"), "/")
+            deleted = append(deleted, id)
+            switch id {
+            case "2":
+                http.NotFound(w, r)
+            case "4":
+                w.WriteHeader(http.StatusInternalServerError)
+            default:
+                writeJSON(t, w, "Pending action was cancelled")
+            }
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &deleted
+}
+
+func cancelPendingActionModel() *CancelPendingActionResourceModel {
+    return &CancelPendingActionResourceModel{
+        ActionId:       types.Int64Null(),
+        AgentId:        types.StringNull(),
+        ActionType:     types.StringNull(),
+        Triggers:       types.MapNull(types.StringType),
+        CancelledIds:   types.ListUnknown(types.Int64Type),
+        CancelledCount: types.Int64Unknown(),
+    }
+}
+
+// cancelledIds returns the cancelled_ids of the created state.
+func cancelledIds(t *testing.T, state CancelPendingActionResourceModel) []int64 {
+    t.Helper()
+    var ids []int64
+    if diags := state.CancelledIds.ElementsAs(context.Background(), &ids, false); diags.HasError() {
+        t.Fatalf("unable to read cancelled_ids: %v", diags)
+    }
+    return ids
+}
+
+func TestCancelPendingActionResourceCreate_SingleAction(t *testing.T) {
+    server, deleted := pendingActionsServer(t)
+
+    model := cancelPendingActionModel()
+    model.ActionId = types.Int64Value(1)
+
+    r := &CancelPendingActionResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 0 {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if strings.Join(*deleted, ",") != "1" {
+        t.Errorf("expected only action 1 to be cancelled, got %v", *deleted)
+    }
+
+    var state CancelPendingActionResourceModel
+    resp.State.Get(context.Background(), &state)
+    if ids := cancelledIds(t, state); len(ids) != 1 || ids[0] != 1 || state.CancelledCount.ValueInt64() != 1 {
+        t.Errorf("unexpected results: %v, count %s", ids, state.CancelledCount)
+    }
+    if state.Id.ValueString() == "" {
+        t.Error("expected an ID to be set")
+    }
+}
+
+func TestCancelPendingActionResourceCreate_AgentSkipsGoneActions(t *testing.T) {
+    server, deleted := pendingActionsServer(t)
+
+    model := cancelPendingActionModel()
+    model.AgentId = types.StringValue("agent-1")
+
+    r := &CancelPendingActionResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    // The completed action is not cancelled, and action 2 was gone by the
+    // time it was cancelled
+    if strings.Join(*deleted, ",") != "1,2" {
+        t.Errorf("expected the pending actions to be cancelled, got %v", *deleted)
+    }
+    var state CancelPendingActionResourceModel
+    resp.State.Get(context.Background(), &state)
+    if ids := cancelledIds(t, state); len(ids) != 1 || ids[0] != 1 || state.CancelledCount.ValueInt64() != 1 {
+        t.Errorf("expected only action 1 to be reported as cancelled, got %v, count %s", ids, state.CancelledCount)
+    }
+}
+
+func TestCancelPendingActionResourceCreate_ActionType(t *testing.T) {
+    server, deleted := pendingActionsServer(t)
+
+    model := cancelPendingActionModel()
+    model.AgentId = types.StringValue("agent-1")
+    model.ActionType = types.StringValue("schedreboot")
+
+    r := &CancelPendingActionResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if strings.Join(*deleted, ",") != "1" {
+        t.Errorf("expected only the pending reboot to be cancelled, got %v", *deleted)
+    }
+}
+
+func TestCancelPendingActionResourceCreate_AlreadyCompleted(t *testing.T) {
+    server, deleted := pendingActionsServer(t)
+
+    model := cancelPendingActionModel()
+    model.ActionId = types.Int64Value(3)
+
+    r := &CancelPendingActionResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != "Pending Action Already Completed" {
+        t.Errorf("expected a warning about the completed action, got %v", resp.Diagnostics)
+    }
+    if len(*deleted) != 0 {
+        t.Errorf("expected nothing to be cancelled, got %v", *deleted)
+    }
+
+    var state CancelPendingActionResourceModel
+    resp.State.Get(context.Background(), &state)
+    if ids := cancelledIds(t, state); len(ids) != 0 || state.CancelledCount.ValueInt64() != 0 {
+        t.Errorf("expected no results, got %v, count %s", ids, state.CancelledCount)
+    }
+}
+
+func TestCancelPendingActionResourceCreate_DeleteFails(t *testing.T) {
+    server, _ := pendingActionsServer(t)
+
+    model := cancelPendingActionModel()
+    model.ActionId = types.Int64Value(4)
+
+    r := &CancelPendingActionResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "Unable to cancel pending action 4, status code: 500") {
+        t.Fatalf("expected an error about action 4, got %v", resp.Diagnostics)
+    }
+    if !resp.State.Raw.IsNull() {
+        t.Error("expected no state to be stored after a failed cancellation")
+    }
+}
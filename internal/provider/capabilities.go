@@ -0,0 +1,77 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// serverFeature is an optional part of the Tactical RMM API. Sponsor-only
+// features such as single sign-on are not served by every installation.
+type serverFeature struct {
+    // name is used in diagnostics.
+    name string
+
+    // probePath is a GET endpoint below BaseURL that only exists when the
+    // feature is available.
+    probePath string
+
+    // requirement explains to the user what the feature needs.
+    requirement string
+}
+
+var featureSSO = serverFeature{
+    name:        "Single sign-on",
+    probePath:   "/accounts/ssoproviders/",
+    requirement: "Single sign-on is only available on sponsored Tactical RMM installations running a release with SSO support.",
+}
+
+// hasFeature reports whether the server provides feature. The probe endpoint
+// is requested once per provider process; a 404, or a 403 mentioning the
+// sponsorship, means the feature is missing. Other responses are not cached
+// and count as available, so the request that follows reports the actual
+// error, e.g. an invalid API key.
+func (c *ClientConfig) hasFeature(ctx context.Context, feature serverFeature) (bool, error) {
+    if available, ok := c.features.Load(feature.probePath); ok {
+        return available.(bool), nil
+    }
+
+    statusCode, respBody, err := c.sendJSON(ctx, "GET", c.BaseURL+feature.probePath, nil)
+    if err != nil {
+        return false, err
+    }
+
+    switch {
+    case statusCode == http.StatusOK:
+        c.features.Store(feature.probePath, true)
+        return true, nil
+    case statusCode == http.StatusNotFound,
+        statusCode == http.StatusForbidden && strings.Contains(strings.ToLower(errorMessage(respBody)), "sponsor"):
+        c.features.Store(feature.probePath, false)
+        return false, nil
+    default:
+        return true, nil
+    }
+}
+
+// requireFeature reports an error and returns false when the server does not
+// provide feature. Resources built on optional features call it before their
+// first request so a missing feature is not reported as an obscure 404.
+func (c *ClientConfig) requireFeature(ctx context.Context, feature serverFeature, diags *diag.Diagnostics) bool {
+    available, err := c.hasFeature(ctx, feature)
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to detect whether the server supports %s, got error: %s", strings.ToLower(feature.name), err))
+        return false
+    }
+    if !available {
+        diags.AddError(
+            "Feature Not Available",
+            fmt.Sprintf("%s is not available on this server. %s", feature.name, feature.requirement),
+        )
+        return false
+    }
+    return true
+}
@@ -0,0 +1,271 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "math"
+    "net/http"
+    "sort"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CheckHistoryDataSource{}
+
+// metricCheckTypes record a measurement in their history rather than a
+// pass/fail flag, so no status can be derived from a history point.
+var metricCheckTypes = map[string]bool{
+    "cpuload":   true,
+    "memory":    true,
+    "diskspace": true,
+}
+
+var checkHistoryPointAttrTypes = map[string]attr.Type{
+    "timestamp": types.StringType,
+    "status":    types.StringType,
+    "value":     types.Float64Type,
+    "results":   types.StringType,
+}
+
+func NewCheckHistoryDataSource() datasource.DataSource {
+    return &CheckHistoryDataSource{}
+}
+
+// CheckHistoryDataSource reads the historical results of a check.
+type CheckHistoryDataSource struct {
+    client *ClientConfig
+}
+
+// CheckHistoryDataSourceModel describes the data source data model.
+type CheckHistoryDataSourceModel struct {
+    CheckId   types.Int64  `tfsdk:"check_id"`
+    AgentId   types.String `tfsdk:"agent_id"`
+    Start     types.String `tfsdk:"start"`
+    End       types.String `tfsdk:"end"`
+    Limit     types.Int64  `tfsdk:"limit"`
+    CheckType types.String `tfsdk:"check_type"`
+    Points    types.List   `tfsdk:"points"`
+}
+
+func (d *CheckHistoryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_check_history"
+}
+
+func (d *CheckHistoryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Check history data source for Tactical RMM. Returns the historical result points of a check within a time range for trend analysis. " +
+            "A check without history returns an empty list.",
+
+        Attributes: map[string]schema.Attribute{
+            "check_id": schema.Int64Attribute{
+                MarkdownDescription: "Check identifier",
+                Required:            true,
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent whose results are returned. Needed for checks defined by an automation policy, which have results per agent.",
+                Optional:            true,
+            },
+            "start": schema.StringAttribute{
+                MarkdownDescription: "Only return points at or after this RFC 3339 timestamp. Defaults to all available history.",
+                Optional:            true,
+            },
+            "end": schema.StringAttribute{
+                MarkdownDescription: "Only return points at or before this RFC 3339 timestamp. Defaults to now.",
+                Optional:            true,
+            },
+            "limit": schema.Int64Attribute{
+                MarkdownDescription: "Maximum number of points to return; the most recent points are kept",
+                Optional:            true,
+            },
+            "check_type": schema.StringAttribute{
+                MarkdownDescription: "Type of the check, e.g. diskspace, cpuload, script",
+                Computed:            true,
+            },
+            "points": schema.ListNestedAttribute{
+                MarkdownDescription: "History points ordered from oldest to newest",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "timestamp": schema.StringAttribute{
+                            MarkdownDescription: "Time of the result (RFC 3339, UTC)",
+                            Computed:            true,
+                        },
+                        "status": schema.StringAttribute{
+                            MarkdownDescription: "passing or failing; null for cpuload, memory and diskspace checks, whose value is a measurement",
+                            Computed:            true,
+                        },
+                        "value": schema.Float64Attribute{
+                            MarkdownDescription: "Recorded value: a percentage for cpuload, memory and diskspace checks, otherwise 1 for failing and 0 for passing",
+                            Computed:            true,
+                        },
+                        "results": schema.StringAttribute{
+                            MarkdownDescription: "Additional result details recorded with the point, JSON encoded when structured",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *CheckHistoryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *CheckHistoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data CheckHistoryDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var start, end time.Time
+    if !data.Start.IsNull() {
+        parsed, err := time.Parse(time.RFC3339, data.Start.ValueString())
+        if err != nil {
+            resp.Diagnostics.AddAttributeError(path.Root("start"), "Invalid Timestamp", fmt.Sprintf("start must be an RFC 3339 timestamp, got error: %s", err))
+        }
+        start = parsed
+    }
+    if !data.End.IsNull() {
+        parsed, err := time.Parse(time.RFC3339, data.End.ValueString())
+        if err != nil {
+            resp.Diagnostics.AddAttributeError(path.Root("end"), "Invalid Timestamp", fmt.Sprintf("end must be an RFC 3339 timestamp, got error: %s", err))
+        }
+        end = parsed
+    }
+    if !data.Limit.IsNull() && data.Limit.ValueInt64() < 0 {
+        resp.Diagnostics.AddAttributeError(path.Root("limit"), "Invalid Limit", "limit must not be negative.")
+    }
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    check, err := d.client.getObject(ctx, fmt.Sprintf("%s/checks/%d/", d.client.BaseURL, data.CheckId.ValueInt64()))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read check, got error: %s", err))
+        return
+    }
+    checkType, _ := check["check_type"].(string)
+    data.CheckType = types.StringValue(checkType)
+
+    // The history endpoint filters by whole days back from now, 0 meaning all
+    body := map[string]interface{}{"timeFilter": 0}
+    if !start.IsZero() {
+        body["timeFilter"] = int64(math.Ceil(time.Since(start).Hours() / 24))
+    }
+    if !data.AgentId.IsNull() {
+        body["agent_id"] = data.AgentId.ValueString()
+    }
+
+    // The history query is sent as a PATCH body but does not modify the check
+    statusCode, respBody, err := d.client.sendJSON(withReadRequest(ctx), "PATCH", fmt.Sprintf("%s/checks/%d/history/", d.client.BaseURL, data.CheckId.ValueInt64()), body)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read check history, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read check history, status code: %d", statusCode))
+        return
+    }
+
+    var history []map[string]interface{}
+    if err := json.Unmarshal(respBody, &history); err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse check history, got error: %s", err))
+        return
+    }
+
+    type point struct {
+        timestamp time.Time
+        value     float64
+        results   string
+    }
+
+    var points []point
+    for _, entry := range history {
+        timestamp, ok := entry["x"].(string)
+        if !ok {
+            continue
+        }
+        parsed, err := parseAPITime(timestamp)
+        if err != nil {
+            resp.Diagnostics.AddWarning("Invalid History Point", fmt.Sprintf("Skipping check history point: %s", err))
+            continue
+        }
+        if (!start.IsZero() && parsed.Before(start)) || (!end.IsZero() && parsed.After(end)) {
+            continue
+        }
+
+        value, _ := entry["y"].(float64)
+        results := ""
+        switch detail := entry["results"].(type) {
+        case nil:
+        case string:
+            results = detail
+        default:
+            if encoded, err := json.Marshal(detail); err == nil {
+                results = string(encoded)
+            }
+        }
+
+        points = append(points, point{timestamp: parsed, value: value, results: results})
+    }
+
+    // Newest first to apply the limit, then oldest first for the output
+    sort.Slice(points, func(i, j int) bool { return points[i].timestamp.After(points[j].timestamp) })
+    if !data.Limit.IsNull() && int64(len(points)) > data.Limit.ValueInt64() {
+        points = points[:data.Limit.ValueInt64()]
+    }
+    sort.Slice(points, func(i, j int) bool { return points[i].timestamp.Before(points[j].timestamp) })
+
+    pointValues := make([]attr.Value, 0, len(points))
+    for _, p := range points {
+        status := types.StringNull()
+        if !metricCheckTypes[checkType] {
+            if p.value != 0 {
+                status = types.StringValue("failing")
+            } else {
+                status = types.StringValue("passing")
+            }
+        }
+
+        pointValue, diags := types.ObjectValue(checkHistoryPointAttrTypes, map[string]attr.Value{
+            "timestamp": types.StringValue(p.timestamp.UTC().Format(time.RFC3339)),
+            "status":    status,
+            "value":     types.Float64Value(p.value),
+            "results":   types.StringValue(p.results),
+        })
+        resp.Diagnostics.Append(diags...)
+        pointValues = append(pointValues, pointValue)
+    }
+
+    pointsValue, diags := types.ListValue(types.ObjectType{AttrTypes: checkHistoryPointAttrTypes}, pointValues)
+    resp.Diagnostics.Append(diags...)
+    data.Points = pointsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
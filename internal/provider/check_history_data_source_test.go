@@ -0,0 +1,91 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type checkHistoryPoint struct {
+    Timestamp string  `tfsdk:"timestamp"`
+    Status    *string `tfsdk:"status"`
+    Value     float64 `tfsdk:"value"`
+    Results   string  `tfsdk:"results"`
+}
+
+func readCheckHistory(t *testing.T, checkType string, history []map[string]interface{}, model *CheckHistoryDataSourceModel) []checkHistoryPoint {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/checks/5/":
+            writeJSON(t, w, map[string]interface{}{"id": 5, "check_type": checkType})
+        case r.Method == http.MethodPatch && r.URL.Path == "/checks/5/history/":
+            writeJSON(t, w, history)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    model.CheckId = types.Int64Value(5)
+    model.Points = types.ListNull(types.ObjectType{AttrTypes: checkHistoryPointAttrTypes})
+
+    d := &CheckHistoryDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state CheckHistoryDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Points.IsNull() {
+        t.Fatal("expected points to be set")
+    }
+    points := []checkHistoryPoint{}
+    state.Points.ElementsAs(context.Background(), &points, false)
+    return points
+}
+
+func TestCheckHistoryDataSourceRead_AppliesRangeAndLimit(t *testing.T) {
+    history := []map[string]interface{}{
+        {"x": "2026-03-04T10:00:00Z", "y": 0, "results": nil},
+        {"x": "2026-03-03T10:00:00.123456+01:00", "y": 1, "results": map[string]interface{}{"stdout": "disk full"}},
+        {"x": "2026-03-02 10:00:00", "y": 0, "results": "ok"},
+        {"x": "2026-03-01T10:00:00Z", "y": 0, "results": "ok"},
+        {"x": "not a timestamp", "y": 1},
+    }
+
+    points := readCheckHistory(t, "script", history, &CheckHistoryDataSourceModel{
+        Start: types.StringValue("2026-03-02T00:00:00Z"),
+        End:   types.StringValue("2026-03-05T00:00:00Z"),
+        Limit: types.Int64Value(2),
+    })
+
+    failing := "failing"
+    passing := "passing"
+    expected := []checkHistoryPoint{
+        {Timestamp: "2026-03-03T09:00:00Z", Status: &failing, Value: 1, Results: `{"stdout":"disk full"}`},
+        {Timestamp: "2026-03-04T10:00:00Z", Status: &passing, Value: 0, Results: ""},
+    }
+    if !reflect.DeepEqual(points, expected) {
+        t.Errorf("expected %+v, got %+v", expected, points)
+    }
+}
+
+func TestCheckHistoryDataSourceRead_MetricChecksAndEmptyHistory(t *testing.T) {
+    points := readCheckHistory(t, "diskspace", []map[string]interface{}{
+        {"x": "2026-03-01T10:00:00Z", "y": 87},
+    }, &CheckHistoryDataSourceModel{})
+    if len(points) != 1 || points[0].Status != nil || points[0].Value != 87 {
+        t.Errorf("expected one measurement without status, got %+v", points)
+    }
+
+    points = readCheckHistory(t, "ping", []map[string]interface{}{}, &CheckHistoryDataSourceModel{})
+    if len(points) != 0 {
+        t.Errorf("expected no points, got %+v", points)
+    }
+}
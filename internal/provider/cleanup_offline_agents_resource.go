@@ -0,0 +1,349 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "sort"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CleanupOfflineAgentsResource{}
+var _ resource.ResourceWithValidateConfig = &CleanupOfflineAgentsResource{}
+
+var offlineAgentAttrTypes = map[string]attr.Type{
+    "agent_id":  types.StringType,
+    "hostname":  types.StringType,
+    "last_seen": types.StringType,
+}
+
+func NewCleanupOfflineAgentsResource() resource.Resource {
+    return &CleanupOfflineAgentsResource{}
+}
+
+// CleanupOfflineAgentsResource deletes the agents that have been offline for
+// longer than a number of days when it is created, or only reports them in
+// dry run mode.
+type CleanupOfflineAgentsResource struct {
+    client *ClientConfig
+}
+
+// CleanupOfflineAgentsResourceModel describes the resource data model.
+type CleanupOfflineAgentsResourceModel struct {
+    Id              types.String `tfsdk:"id"`
+    OfflineDays     types.Int64  `tfsdk:"offline_days"`
+    ClientId        types.Int64  `tfsdk:"client_id"`
+    SiteId          types.Int64  `tfsdk:"site_id"`
+    DryRun          types.Bool   `tfsdk:"dry_run"`
+    ConfirmDeletion types.Bool   `tfsdk:"confirm_deletion"`
+    Triggers        types.Map    `tfsdk:"triggers"`
+    Candidates      types.List   `tfsdk:"candidates"`
+    DeletedAgentIds types.List   `tfsdk:"deleted_agent_ids"`
+}
+
+func (r *CleanupOfflineAgentsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_cleanup_offline_agents"
+}
+
+func (r *CleanupOfflineAgentsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Removes agents that have been offline for longer than `offline_days` when created, e.g. agents of machines that were wiped without uninstalling. " +
+            "By default this is a dry run that only reports the candidates; deleting requires `dry_run = false` and `confirm_deletion = true`. " +
+            "Destroying this resource does not restore deleted agents.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this cleanup",
+                Computed:            true,
+            },
+            "offline_days": schema.Int64Attribute{
+                MarkdownDescription: "Agents last seen more than this many days ago are candidates. Must be at least 1.",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Only consider agents of this client. Conflicts with `site_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Only consider agents of this site. Conflicts with `client_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "dry_run": schema.BoolAttribute{
+                MarkdownDescription: "Only report the candidates without deleting them. Defaults to true.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "confirm_deletion": schema.BoolAttribute{
+                MarkdownDescription: "Must be true when `dry_run` is false, confirming that the candidates are deleted",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the cleanup to run again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "candidates": schema.ListNestedAttribute{
+                MarkdownDescription: "Agents offline for longer than `offline_days`, ordered by last seen time",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "agent_id": schema.StringAttribute{
+                            MarkdownDescription: "Agent identifier",
+                            Computed:            true,
+                        },
+                        "hostname": schema.StringAttribute{
+                            MarkdownDescription: "Agent hostname",
+                            Computed:            true,
+                        },
+                        "last_seen": schema.StringAttribute{
+                            MarkdownDescription: "Time the agent last checked in (RFC 3339, UTC)",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+            "deleted_agent_ids": schema.ListAttribute{
+                MarkdownDescription: "Agents that were deleted, empty in dry run mode",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+        },
+    }
+}
+
+func (r *CleanupOfflineAgentsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data CleanupOfflineAgentsResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.OfflineDays.IsNull() && !data.OfflineDays.IsUnknown() && data.OfflineDays.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("offline_days"),
+            "Invalid Offline Duration",
+            "offline_days must be at least 1.",
+        )
+    }
+
+    if !data.ClientId.IsNull() && !data.SiteId.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Cleanup Scope",
+            "Only one of 'client_id' or 'site_id' may be specified.",
+        )
+    }
+
+    if data.DryRun.IsUnknown() || data.ConfirmDeletion.IsUnknown() {
+        return
+    }
+
+    if !cleanupDryRun(data) && !data.ConfirmDeletion.ValueBool() {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("confirm_deletion"),
+            "Deletion Not Confirmed",
+            "Set confirm_deletion = true to delete agents with dry_run = false. Run with dry_run = true first to review the candidates.",
+        )
+    }
+}
+
+func (r *CleanupOfflineAgentsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *CleanupOfflineAgentsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data CleanupOfflineAgentsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    candidates := r.offlineAgents(ctx, &data, time.Now().UTC(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    candidateValues := make([]attr.Value, 0, len(candidates))
+    for _, candidate := range candidates {
+        candidateValue, diags := types.ObjectValue(offlineAgentAttrTypes, map[string]attr.Value{
+            "agent_id":  types.StringValue(candidate.agentId),
+            "hostname":  types.StringValue(candidate.hostname),
+            "last_seen": types.StringValue(candidate.lastSeen.Format(time.RFC3339)),
+        })
+        resp.Diagnostics.Append(diags...)
+        candidateValues = append(candidateValues, candidateValue)
+    }
+
+    deleted := []attr.Value{}
+    if !cleanupDryRun(data) {
+        for _, candidate := range candidates {
+            tflog.Info(r.client.logContext(ctx), "Deleting offline agent", map[string]interface{}{
+                "agent_id":  candidate.agentId,
+                "hostname":  candidate.hostname,
+                "last_seen": candidate.lastSeen.Format(time.RFC3339),
+            })
+
+            statusCode, respBody, err := r.client.sendJSON(ctx, "DELETE", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, candidate.agentId), nil)
+            if err != nil {
+                resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete agent %s (%s), got error: %s", candidate.agentId, candidate.hostname, err))
+                continue
+            }
+            if statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+                resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete agent %s (%s), status code: %d, response: %s", candidate.agentId, candidate.hostname, statusCode, errorMessage(respBody)))
+                continue
+            }
+            deleted = append(deleted, types.StringValue(candidate.agentId))
+        }
+    }
+
+    candidatesValue, diags := types.ListValue(types.ObjectType{AttrTypes: offlineAgentAttrTypes}, candidateValues)
+    resp.Diagnostics.Append(diags...)
+    deletedValue, diags := types.ListValue(types.StringType, deleted)
+    resp.Diagnostics.Append(diags...)
+
+    data.Id = types.StringValue(actionID())
+    data.Candidates = candidatesValue
+    data.DeletedAgentIds = deletedValue
+
+    // Set even after failed deletes so the agents already removed are recorded
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// offlineAgent is an agent that has been offline for longer than offline_days.
+type offlineAgent struct {
+    agentId  string
+    hostname string
+    lastSeen time.Time
+}
+
+// offlineAgents returns the agents in scope last seen before the cutoff,
+// oldest first. Agents that never checked in have no last seen time to
+// compare and are left alone.
+func (r *CleanupOfflineAgentsResource) offlineAgents(ctx context.Context, data *CleanupOfflineAgentsResourceModel, now time.Time, diags *diag.Diagnostics) []offlineAgent {
+    // The agents list can be filtered by site or client
+    listURL := fmt.Sprintf("%s/agents/", r.client.BaseURL)
+    switch {
+    case !data.ClientId.IsNull():
+        listURL = fmt.Sprintf("%s/agents/?client=%d", r.client.BaseURL, data.ClientId.ValueInt64())
+    case !data.SiteId.IsNull():
+        listURL = fmt.Sprintf("%s/agents/?site=%d", r.client.BaseURL, data.SiteId.ValueInt64())
+    }
+
+    agents, err := r.client.listObjects(ctx, listURL)
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+        return nil
+    }
+
+    cutoff := now.Add(-time.Duration(data.OfflineDays.ValueInt64()) * 24 * time.Hour)
+    candidates := []offlineAgent{}
+    for _, agent := range agents {
+        agentId, ok := agent["agent_id"].(string)
+        if !ok {
+            continue
+        }
+        lastSeen, seen := agentLastSeen(agent)
+        if !seen || !lastSeen.Before(cutoff) {
+            continue
+        }
+        hostname, _ := agent["hostname"].(string)
+        candidates = append(candidates, offlineAgent{agentId: agentId, hostname: hostname, lastSeen: lastSeen.UTC()})
+    }
+
+    sort.Slice(candidates, func(i, j int) bool { return candidates[i].lastSeen.Before(candidates[j].lastSeen) })
+    return candidates
+}
+
+// cleanupDryRun reports whether the cleanup only lists candidates, the
+// default when dry_run is not set.
+func cleanupDryRun(data CleanupOfflineAgentsResourceModel) bool {
+    return data.DryRun.IsNull() || data.DryRun.ValueBool()
+}
+
+func (r *CleanupOfflineAgentsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // The cleanup is a one-off operation, there is no remote object to refresh
+}
+
+func (r *CleanupOfflineAgentsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data CleanupOfflineAgentsResourceModel
+    var state CleanupOfflineAgentsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.Candidates = state.Candidates
+    data.DeletedAgentIds = state.DeletedAgentIds
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CleanupOfflineAgentsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Deleted agents cannot be restored, removing the resource only drops it from state
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// offlineAgentsServer serves the agents of site 3, two of which have been
+// offline for weeks, and records the agents deleted.
+func offlineAgentsServer(t *testing.T) (*httptest.Server, func() []string) {
+    t.Helper()
+    now := time.Now().UTC()
+
+    var mu sync.Mutex
+    var deleted []string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/":
+            if r.URL.Query().Get("site") != "3" {
+                t.Errorf("expected the agents list to be filtered by site, got %s", r.URL.RawQuery)
+            }
+            writeJSON(t, w, []map[string]interface{}{
+                {"agent_id": "online", "hostname": "ws-1", "last_seen": now.Add(-time.Minute).Format(time.RFC3339)},
+                {"agent_id": "wiped", "hostname": "ws-2", "last_seen": now.Add(-60 * 24 * time.Hour).Format(time.RFC3339)},
+                {"agent_id": "stale", "hostname": "ws-3", "last_seen": now.Add(-45 * 24 * time.Hour).Format(time.RFC3339)},
+                {"agent_id": "recent", "hostname": "ws-4", "last_seen": now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)},
+                {"agent_id": "never", "hostname": "ws-5"},
+            })
+        case r.Method == http.MethodDelete:
+            mu.Lock()
+            deleted = append(deleted, r.URL.Path)
+            mu.Unlock()
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, func() []string {
+        mu.Lock()
+        defer mu.Unlock()
+        return append([]string{}, deleted...)
+    }
+}
+
+func cleanupModel(dryRun types.Bool, confirm types.Bool) *CleanupOfflineAgentsResourceModel {
+    return &CleanupOfflineAgentsResourceModel{
+        Id:              types.StringUnknown(),
+        OfflineDays:     types.Int64Value(30),
+        ClientId:        types.Int64Null(),
+        SiteId:          types.Int64Value(3),
+        DryRun:          dryRun,
+        ConfirmDeletion: confirm,
+        Triggers:        types.MapNull(types.StringType),
+        Candidates:      types.ListUnknown(types.ObjectType{AttrTypes: offlineAgentAttrTypes}),
+        DeletedAgentIds: types.ListUnknown(types.StringType),
+    }
+}
+
+func TestCleanupOfflineAgentsResourceCreate_DryRunByDefault(t *testing.T) {
+    server, deleted := offlineAgentsServer(t)
+    r := &CleanupOfflineAgentsResource{client: newTestClient(server)}
+
+    resp := createResource(t, r, cleanupModel(types.BoolNull(), types.BoolNull()))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if got := deleted(); len(got) != 0 {
+        t.Errorf("expected no deletes in dry run mode, got %v", got)
+    }
+
+    var state CleanupOfflineAgentsResourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var candidates []struct {
+        AgentId  types.String `tfsdk:"agent_id"`
+        Hostname types.String `tfsdk:"hostname"`
+        LastSeen types.String `tfsdk:"last_seen"`
+    }
+    state.Candidates.ElementsAs(context.Background(), &candidates, false)
+    if len(candidates) != 2 || candidates[0].Hostname.ValueString() != "ws-2" || candidates[1].Hostname.ValueString() != "ws-3" {
+        t.Fatalf("expected ws-2 and ws-3 oldest first, got %+v", candidates)
+    }
+    if _, err := time.Parse(time.RFC3339, candidates[0].LastSeen.ValueString()); err != nil {
+        t.Errorf("expected an RFC 3339 last_seen, got %q", candidates[0].LastSeen.ValueString())
+    }
+    if len(state.DeletedAgentIds.Elements()) != 0 {
+        t.Errorf("expected no deleted agents, got %v", state.DeletedAgentIds)
+    }
+}
+
+func TestCleanupOfflineAgentsResourceCreate_DeletesWhenConfirmed(t *testing.T) {
+    server, deleted := offlineAgentsServer(t)
+    r := &CleanupOfflineAgentsResource{client: newTestClient(server)}
+
+    resp := createResource(t, r, cleanupModel(types.BoolValue(false), types.BoolValue(true)))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    got := deleted()
+    if len(got) != 2 || got[0] != "/agents/wiped/" || got[1] != "/agents/stale/" {
+        t.Errorf("expected the offline agents to be deleted, got %v", got)
+    }
+
+    var state CleanupOfflineAgentsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.DeletedAgentIds.Equal(stringListOf("wiped", "stale")) {
+        t.Errorf("unexpected deleted_agent_ids: %v", state.DeletedAgentIds)
+    }
+}
+
+func TestCleanupOfflineAgentsResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &CleanupOfflineAgentsResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    cases := map[string]struct {
+        modify  func(m *CleanupOfflineAgentsResourceModel)
+        summary string
+    }{
+        "dry run": {
+            modify: func(m *CleanupOfflineAgentsResourceModel) {},
+        },
+        "confirmed": {
+            modify: func(m *CleanupOfflineAgentsResourceModel) {
+                m.DryRun = types.BoolValue(false)
+                m.ConfirmDeletion = types.BoolValue(true)
+            },
+        },
+        "not confirmed": {
+            modify: func(m *CleanupOfflineAgentsResourceModel) {
+                m.DryRun = types.BoolValue(false)
+            },
+            summary: "Deletion Not Confirmed",
+        },
+        "zero days": {
+            modify: func(m *CleanupOfflineAgentsResourceModel) {
+                m.OfflineDays = types.Int64Value(0)
+            },
+            summary: "Invalid Offline Duration",
+        },
+        "client and site": {
+            modify: func(m *CleanupOfflineAgentsResourceModel) {
+                m.ClientId = types.Int64Value(1)
+            },
+            summary: "Invalid Cleanup Scope",
+        },
+    }
+
+    for name, tc := range cases {
+        model := cleanupModel(types.BoolNull(), types.BoolNull())
+        model.Id = types.StringNull()
+        model.Candidates = types.ListNull(types.ObjectType{AttrTypes: offlineAgentAttrTypes})
+        model.DeletedAgentIds = types.ListNull(types.StringType)
+        tc.modify(model)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if tc.summary == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != tc.summary {
+            t.Errorf("%s: expected a %q error, got: %v", name, tc.summary, resp.Diagnostics)
+        }
+    }
+}
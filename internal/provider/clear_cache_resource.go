@@ -0,0 +1,148 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ClearCacheResource{}
+
+func NewClearCacheResource() resource.Resource {
+    return &ClearCacheResource{}
+}
+
+// ClearCacheResource clears the server cache when it is created, the same as
+// Clear Cache in the Server Maintenance dialog.
+type ClearCacheResource struct {
+    client *ClientConfig
+}
+
+// ClearCacheResourceModel describes the resource data model.
+type ClearCacheResourceModel struct {
+    Id       types.String `tfsdk:"id"`
+    Triggers types.Map    `tfsdk:"triggers"`
+    Message  types.String `tfsdk:"message"`
+}
+
+func (r *ClearCacheResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_clear_cache"
+}
+
+func (r *ClearCacheResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Clears the Tactical RMM server cache when created, like Clear Cache in the Server Maintenance dialog, " +
+            "so the web UI shows agent counts and settings changed by a large apply right away. Clearing the cache is safe to repeat. " +
+            "Destroying this resource does nothing on the server.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this cache clear",
+                Computed:            true,
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the cache to be cleared again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "message": schema.StringAttribute{
+                MarkdownDescription: "Response message returned by Tactical RMM",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *ClearCacheResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *ClearCacheResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data ClearCacheResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "GET", fmt.Sprintf("%s/core/clearcache/", r.client.BaseURL), nil)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to clear the server cache, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to clear the server cache, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.Message = types.StringValue(errorMessage(respBody))
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClearCacheResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Clearing the cache is a one-off operation, there is no remote object to refresh
+}
+
+func (r *ClearCacheResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data ClearCacheResourceModel
+    var state ClearCacheResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.Message = state.Message
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ClearCacheResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Nothing was created on the server, removing the resource only drops it from state
+}
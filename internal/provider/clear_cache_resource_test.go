@@ -0,0 +1,65 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestClearCacheResourceCreate(t *testing.T) {
+    var clears int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/core/clearcache/" {
+            http.NotFound(w, r)
+            return
+        }
+        atomic.AddInt32(&clears, 1)
+        writeJSON(t, w, "Cache was cleared!")
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ClearCacheResource{client: newTestClient(server)}
+
+    // Every run clears the cache again and gets a new ID
+    var ids []string
+    for run := 0; run < 2; run++ {
+        resp := createResource(t, r, &ClearCacheResourceModel{Triggers: types.MapNull(types.StringType)})
+        if resp.Diagnostics.HasError() {
+            t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+        }
+
+        var state ClearCacheResourceModel
+        resp.State.Get(context.Background(), &state)
+        if state.Message.ValueString() != "Cache was cleared!" {
+            t.Errorf("unexpected message: %s", state.Message)
+        }
+        ids = append(ids, state.Id.ValueString())
+    }
+    if got := atomic.LoadInt32(&clears); got != 2 {
+        t.Errorf("expected 2 cache clears, got %d", got)
+    }
+    if ids[0] == "" || ids[0] == ids[1] {
+        t.Errorf("expected a new ID per run, got %v", ids)
+    }
+}
+
+func TestClearCacheResourceCreate_Forbidden(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusForbidden)
+        writeJSON(t, w, map[string]interface{}{"detail": "You do not have permission to perform this action."})
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ClearCacheResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ClearCacheResourceModel{Triggers: types.MapNull(types.StringType)})
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a forbidden cache clear")
+    }
+    if detail := resp.Diagnostics.Errors()[0].Detail(); detail != "Unable to clear the server cache, status code: 403, response: You do not have permission to perform this action." {
+        t.Errorf("unexpected error: %s", detail)
+    }
+}
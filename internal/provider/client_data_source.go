@@ -0,0 +1,144 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ClientDataSource{}
+
+func NewClientDataSource() datasource.DataSource {
+    return &ClientDataSource{}
+}
+
+// ClientDataSource looks up a single client by ID or name.
+type ClientDataSource struct {
+    client *ClientConfig
+}
+
+// ClientDataSourceModel describes the data source data model.
+type ClientDataSourceModel struct {
+    Id               types.Int64  `tfsdk:"id"`
+    Name             types.String `tfsdk:"name"`
+    SiteCount        types.Int64  `tfsdk:"site_count"`
+    AgentCount       types.Int64  `tfsdk:"agent_count"`
+    CustomFields     types.Map    `tfsdk:"custom_fields"`
+    CustomFieldLists types.Map    `tfsdk:"custom_field_lists"`
+}
+
+func (d *ClientDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_client"
+}
+
+func (d *ClientDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Client data source for Tactical RMM. Use this to look up an existing client by ID or name together with its site and agent counts and custom field values.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: "Client identifier. Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Client name. Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "site_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of sites of the client",
+                Computed:            true,
+            },
+            "agent_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of agents across all sites of the client",
+                Computed:            true,
+            },
+            "custom_fields": schema.MapAttribute{
+                MarkdownDescription: clientCustomFieldsDescription,
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "custom_field_lists": schema.MapAttribute{
+                MarkdownDescription: clientCustomFieldListsDescription,
+                Computed:            true,
+                ElementType:         types.ListType{ElemType: types.StringType},
+            },
+        },
+    }
+}
+
+func (d *ClientDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *ClientDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ClientDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Validate that either ID or name is provided
+    if data.Id.IsNull() && data.Name.IsNull() {
+        resp.Diagnostics.AddError(
+            "Missing Client Identifier",
+            "Either 'id' or 'name' must be specified to look up a client.",
+        )
+        return
+    }
+
+    clients, err := d.client.listObjects(ctx, fmt.Sprintf("%s/clients/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list clients, got error: %s", err))
+        return
+    }
+
+    client := findClient(clients, data.Id, data.Name)
+    if client == nil {
+        resp.Diagnostics.AddError("Client Not Found", fmt.Sprintf("No client matches %s.", describeClientFilter(data.Id, data.Name)))
+        return
+    }
+
+    siteCounts, agentCounts, err := d.client.clientCounts(ctx, []map[string]interface{}{client})
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to count sites and agents, got error: %s", err))
+        return
+    }
+
+    customFields, customFieldLists, err := d.client.clientCustomFields(ctx, []map[string]interface{}{client}, &resp.Diagnostics)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read client custom fields, got error: %s", err))
+        return
+    }
+
+    id, _ := client["id"].(float64)
+    data.Id = types.Int64Value(int64(id))
+    data.Name = stringValue(client["name"])
+    data.SiteCount = types.Int64Value(siteCounts[int64(id)])
+    data.AgentCount = types.Int64Value(agentCounts[int64(id)])
+    data.CustomFields = customFields[int64(id)]
+    data.CustomFieldLists = customFieldLists[int64(id)]
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ClientsDataSource{}
+
+// clientAttrTypes describes one entry of the clients list.
+var clientAttrTypes = map[string]attr.Type{
+    "id":                 types.Int64Type,
+    "name":               types.StringType,
+    "site_count":         types.Int64Type,
+    "agent_count":        types.Int64Type,
+    "custom_fields":      types.MapType{ElemType: types.StringType},
+    "custom_field_lists": types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+}
+
+func NewClientsDataSource() datasource.DataSource {
+    return &ClientsDataSource{}
+}
+
+// ClientsDataSource lists all clients together with their site and agent counts
+// and custom field values.
+type ClientsDataSource struct {
+    client *ClientConfig
+}
+
+// ClientsDataSourceModel describes the data source data model.
+type ClientsDataSourceModel struct {
+    Clients types.List `tfsdk:"clients"`
+}
+
+func (d *ClientsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_clients"
+}
+
+func (d *ClientsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Clients data source for Tactical RMM. Lists all clients with the number of sites and agents each one has and their custom field values.",
+
+        Attributes: map[string]schema.Attribute{
+            "clients": schema.ListNestedAttribute{
+                MarkdownDescription: "All clients, sorted by name",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Client identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Client name",
+                            Computed:            true,
+                        },
+                        "site_count": schema.Int64Attribute{
+                            MarkdownDescription: "Number of sites of the client",
+                            Computed:            true,
+                        },
+                        "agent_count": schema.Int64Attribute{
+                            MarkdownDescription: "Number of agents across all sites of the client",
+                            Computed:            true,
+                        },
+                        "custom_fields": schema.MapAttribute{
+                            MarkdownDescription: clientCustomFieldsDescription,
+                            Computed:            true,
+                            ElementType:         types.StringType,
+                        },
+                        "custom_field_lists": schema.MapAttribute{
+                            MarkdownDescription: clientCustomFieldListsDescription,
+                            Computed:            true,
+                            ElementType:         types.ListType{ElemType: types.StringType},
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *ClientsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *ClientsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ClientsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    clients, err := d.client.listObjects(ctx, fmt.Sprintf("%s/clients/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list clients, got error: %s", err))
+        return
+    }
+
+    siteCounts, agentCounts, err := d.client.clientCounts(ctx, clients)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to count sites and agents, got error: %s", err))
+        return
+    }
+
+    customFields, customFieldLists, err := d.client.clientCustomFields(ctx, clients, &resp.Diagnostics)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read client custom fields, got error: %s", err))
+        return
+    }
+
+    sortByName(clients)
+
+    clientValues := make([]attr.Value, 0, len(clients))
+    for _, client := range clients {
+        id, _ := client["id"].(float64)
+        clientValue, diags := types.ObjectValue(clientAttrTypes, map[string]attr.Value{
+            "id":                 types.Int64Value(int64(id)),
+            "name":               stringValue(client["name"]),
+            "site_count":         types.Int64Value(siteCounts[int64(id)]),
+            "agent_count":        types.Int64Value(agentCounts[int64(id)]),
+            "custom_fields":      customFields[int64(id)],
+            "custom_field_lists": customFieldLists[int64(id)],
+        })
+        resp.Diagnostics.Append(diags...)
+        clientValues = append(clientValues, clientValue)
+    }
+
+    clientsValue, diags := types.ListValue(types.ObjectType{AttrTypes: clientAttrTypes}, clientValues)
+    resp.Diagnostics.Append(diags...)
+    data.Clients = clientsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// clientCounts returns the number of sites and agents of every client, keyed
+// by client ID. The counts are taken from the client serializer (the nested
+// sites and agent_count) and only computed from the sites or agents list for
+// servers whose serializer does not include them.
+func (c *ClientConfig) clientCounts(ctx context.Context, clients []map[string]interface{}) (map[int64]int64, map[int64]int64, error) {
+    siteCounts := make(map[int64]int64, len(clients))
+    agentCounts := make(map[int64]int64, len(clients))
+    clientIds := make(map[string]int64, len(clients))
+    var missingSites, missingAgents bool
+
+    for _, client := range clients {
+        id, _ := client["id"].(float64)
+        if name, ok := client["name"].(string); ok {
+            clientIds[name] = int64(id)
+        }
+
+        if sites, ok := client["sites"].([]interface{}); ok {
+            siteCounts[int64(id)] = int64(len(sites))
+        } else {
+            missingSites = true
+        }
+        if count, ok := client["agent_count"].(float64); ok {
+            agentCounts[int64(id)] = int64(count)
+        } else {
+            missingAgents = true
+        }
+    }
+
+    if missingSites {
+        sites, err := c.listObjects(ctx, fmt.Sprintf("%s/clients/sites/", c.BaseURL))
+        if err != nil {
+            return nil, nil, fmt.Errorf("unable to list sites: %w", err)
+        }
+        for id := range siteCounts {
+            delete(siteCounts, id)
+        }
+        for _, site := range sites {
+            if id, ok := site["client"].(float64); ok {
+                siteCounts[int64(id)]++
+            }
+        }
+    }
+
+    if missingAgents {
+        // The agents list names the client but does not carry its ID. Client
+        // names are unique, so they identify the client.
+        agents, err := c.listObjects(ctx, fmt.Sprintf("%s/agents/", c.BaseURL))
+        if err != nil {
+            return nil, nil, fmt.Errorf("unable to list agents: %w", err)
+        }
+        for id := range agentCounts {
+            delete(agentCounts, id)
+        }
+        for _, agent := range agents {
+            if name, ok := agent["client_name"].(string); ok {
+                if id, ok := clientIds[name]; ok {
+                    agentCounts[id]++
+                }
+            }
+        }
+    }
+
+    return siteCounts, agentCounts, nil
+}
+
+// Descriptions of the custom field attributes shared by the client data sources.
+const (
+    clientCustomFieldsDescription = "Client custom field values by field name. Checkbox values are `\"true\"` or `\"false\"`. " +
+        "Multiple choice fields are in `custom_field_lists`, and fields without a value are omitted so `lookup()` defaults apply."
+    clientCustomFieldListsDescription = "Values of the client's multiple choice custom fields by field name. Fields with nothing selected are omitted."
+)
+
+// clientCustomFields returns the custom_fields and custom_field_lists values of
+// every client, keyed by client ID. The values are taken from the client
+// serializer; clients listed without custom_fields are fetched individually.
+func (c *ClientConfig) clientCustomFields(ctx context.Context, clients []map[string]interface{}, diags *diag.Diagnostics) (map[int64]types.Map, map[int64]types.Map, error) {
+    definitions, err := c.customFieldDefinitions(ctx, "client")
+    if err != nil {
+        return nil, nil, fmt.Errorf("unable to list custom fields: %w", err)
+    }
+
+    values := make(map[int64]types.Map, len(clients))
+    lists := make(map[int64]types.Map, len(clients))
+    for _, client := range clients {
+        id, _ := client["id"].(float64)
+
+        if _, ok := client["custom_fields"]; !ok {
+            detail, err := c.getObject(ctx, fmt.Sprintf("%s/clients/%d/", c.BaseURL, int64(id)))
+            if err != nil {
+                return nil, nil, fmt.Errorf("client %d: %w", int64(id), err)
+            }
+            client = detail
+        }
+
+        clientValues, clientLists, mapDiags := customFieldMaps(definitions, storedCustomFields(client))
+        diags.Append(mapDiags...)
+        values[int64(id)] = clientValues
+        lists[int64(id)] = clientLists
+    }
+
+    return values, lists, nil
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type clientEntry struct {
+    Id               types.Int64  `tfsdk:"id"`
+    Name             types.String `tfsdk:"name"`
+    SiteCount        types.Int64  `tfsdk:"site_count"`
+    AgentCount       types.Int64  `tfsdk:"agent_count"`
+    CustomFields     types.Map    `tfsdk:"custom_fields"`
+    CustomFieldLists types.Map    `tfsdk:"custom_field_lists"`
+}
+
+func TestClientsDataSourceRead_CountsFromSerializer(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/clients/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 2, "name": "Globex", "agent_count": 3, "sites": []map[string]interface{}{{"id": 11}}, "custom_fields": []interface{}{}},
+                {"id": 1, "name": "Acme", "agent_count": 7, "sites": []map[string]interface{}{{"id": 10}, {"id": 12}}, "custom_fields": []interface{}{}},
+            })
+        case "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{})
+        default:
+            t.Errorf("unexpected request to %s, counts are in the client serializer", r.URL.Path)
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &ClientsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ClientsDataSourceModel{
+        Clients: types.ListNull(types.ObjectType{AttrTypes: clientAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ClientsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var clients []clientEntry
+    state.Clients.ElementsAs(context.Background(), &clients, false)
+
+    if len(clients) != 2 || clients[0].Name.ValueString() != "Acme" {
+        t.Fatalf("expected clients sorted by name, got %v", clients)
+    }
+    if clients[0].SiteCount.ValueInt64() != 2 || clients[0].AgentCount.ValueInt64() != 7 {
+        t.Errorf("unexpected Acme counts: %d sites, %d agents", clients[0].SiteCount.ValueInt64(), clients[0].AgentCount.ValueInt64())
+    }
+    if clients[1].SiteCount.ValueInt64() != 1 || clients[1].AgentCount.ValueInt64() != 3 {
+        t.Errorf("unexpected Globex counts: %d sites, %d agents", clients[1].SiteCount.ValueInt64(), clients[1].AgentCount.ValueInt64())
+    }
+}
+
+func TestClientDataSourceRead_CountsComputedFromLists(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/clients/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "name": "Acme"},
+                {"id": 2, "name": "Globex"},
+            })
+        case "/clients/sites/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 10, "client": 1},
+                {"id": 11, "client": 2},
+                {"id": 12, "client": 1},
+            })
+        case "/agents/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"agent_id": "a", "client_name": "Acme"},
+                {"agent_id": "b", "client_name": "Globex"},
+                {"agent_id": "c", "client_name": "Acme"},
+                {"agent_id": "d", "client_name": "Acme"},
+            })
+        case "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{})
+        case "/clients/1/":
+            writeJSON(t, w, map[string]interface{}{"id": 1, "name": "Acme", "custom_fields": []interface{}{}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &ClientDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ClientDataSourceModel{
+        Name:             types.StringValue("Acme"),
+        CustomFields:     types.MapNull(types.StringType),
+        CustomFieldLists: types.MapNull(types.ListType{ElemType: types.StringType}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ClientDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 1 {
+        t.Errorf("expected id 1, got %d", state.Id.ValueInt64())
+    }
+    if state.SiteCount.ValueInt64() != 2 {
+        t.Errorf("expected 2 sites, got %d", state.SiteCount.ValueInt64())
+    }
+    if state.AgentCount.ValueInt64() != 3 {
+        t.Errorf("expected 3 agents, got %d", state.AgentCount.ValueInt64())
+    }
+}
+
+// clientCustomFieldsServer serves two clients with custom field values. The
+// list serializer of this server omits custom_fields, so they are read from
+// the client details.
+func clientCustomFieldsServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/clients/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "name": "Acme", "agent_count": 1, "sites": []interface{}{}},
+                {"id": 2, "name": "Globex", "agent_count": 0, "sites": []interface{}{}},
+            })
+        case "/core/customfields/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "model": "client", "name": "PSA ID", "type": "text"},
+                {"id": 2, "model": "client", "name": "Contract", "type": "text"},
+                {"id": 3, "model": "client", "name": "Services", "type": "multiple"},
+                {"id": 4, "model": "client", "name": "Managed", "type": "checkbox"},
+                {"id": 5, "model": "agent", "name": "Asset Tag", "type": "text"},
+            })
+        case "/clients/1/":
+            writeJSON(t, w, map[string]interface{}{"id": 1, "name": "Acme", "custom_fields": []map[string]interface{}{
+                {"field": 1, "string_value": "C-1001"},
+                {"field": 2, "string_value": ""},
+                {"field": 3, "multiple_value": []string{"Backup", "Patching"}},
+                {"field": 4, "bool_value": false},
+            }})
+        case "/clients/2/":
+            writeJSON(t, w, map[string]interface{}{"id": 2, "name": "Globex", "custom_fields": []map[string]interface{}{
+                {"field": 3, "multiple_value": []string{}},
+            }})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func TestClientsDataSourceRead_CustomFields(t *testing.T) {
+    server := clientCustomFieldsServer(t)
+
+    d := &ClientsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ClientsDataSourceModel{
+        Clients: types.ListNull(types.ObjectType{AttrTypes: clientAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ClientsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var clients []clientEntry
+    state.Clients.ElementsAs(context.Background(), &clients, false)
+    if len(clients) != 2 {
+        t.Fatalf("expected 2 clients, got %v", clients)
+    }
+
+    var acmeFields map[string]string
+    clients[0].CustomFields.ElementsAs(context.Background(), &acmeFields, false)
+    if !reflect.DeepEqual(acmeFields, map[string]string{"PSA ID": "C-1001", "Managed": "false"}) {
+        t.Errorf("unexpected Acme custom_fields: %v", acmeFields)
+    }
+    var acmeLists map[string][]string
+    clients[0].CustomFieldLists.ElementsAs(context.Background(), &acmeLists, false)
+    if !reflect.DeepEqual(acmeLists, map[string][]string{"Services": {"Backup", "Patching"}}) {
+        t.Errorf("unexpected Acme custom_field_lists: %v", acmeLists)
+    }
+
+    if len(clients[1].CustomFields.Elements()) != 0 || len(clients[1].CustomFieldLists.Elements()) != 0 {
+        t.Errorf("expected fields without a value to be omitted for Globex, got %v and %v", clients[1].CustomFields, clients[1].CustomFieldLists)
+    }
+}
+
+func TestClientDataSourceRead_CustomFields(t *testing.T) {
+    server := clientCustomFieldsServer(t)
+
+    d := &ClientDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ClientDataSourceModel{
+        Id:               types.Int64Value(1),
+        CustomFields:     types.MapNull(types.StringType),
+        CustomFieldLists: types.MapNull(types.ListType{ElemType: types.StringType}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ClientDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if value, ok := state.CustomFields.Elements()["PSA ID"]; !ok || value.(types.String).ValueString() != "C-1001" {
+        t.Errorf("expected the PSA ID to be set, got %v", state.CustomFields)
+    }
+    if _, ok := state.CustomFields.Elements()["Contract"]; ok {
+        t.Errorf("expected the empty Contract field to be omitted, got %v", state.CustomFields)
+    }
+}
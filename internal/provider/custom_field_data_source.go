@@ -0,0 +1,200 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CustomFieldDataSource{}
+
+func NewCustomFieldDataSource() datasource.DataSource {
+    return &CustomFieldDataSource{}
+}
+
+// CustomFieldDataSource looks up a single custom field definition.
+type CustomFieldDataSource struct {
+    client *ClientConfig
+}
+
+// CustomFieldDataSourceModel describes the data source data model.
+type CustomFieldDataSourceModel struct {
+    Id           types.Int64  `tfsdk:"id"`
+    Model        types.String `tfsdk:"model"`
+    Name         types.String `tfsdk:"name"`
+    Type         types.String `tfsdk:"type"`
+    Options      types.List   `tfsdk:"options"`
+    DefaultValue types.String `tfsdk:"default_value"`
+    Required     types.Bool   `tfsdk:"required"`
+    HideInUI     types.Bool   `tfsdk:"hide_in_ui"`
+}
+
+// customFieldModels are the objects custom fields can be defined for.
+var customFieldModels = map[string]bool{
+    "agent":  true,
+    "client": true,
+    "site":   true,
+}
+
+func (d *CustomFieldDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_custom_field"
+}
+
+func (d *CustomFieldDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Custom field data source for Tactical RMM. Looks up one custom field definition by `model` and `name`, or by `id`, " +
+            "e.g. to get the numeric field ID needed by task collector options.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: "Custom field identifier. Either `id` or `model` and `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "model": schema.StringAttribute{
+                MarkdownDescription: "Object the field is defined for: `agent`, `client` or `site`",
+                Optional:            true,
+                Computed:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Field name (exact match)",
+                Optional:            true,
+                Computed:            true,
+            },
+            "type": schema.StringAttribute{
+                MarkdownDescription: "Field type: `text`, `number`, `single`, `multiple`, `checkbox` or `datetime`",
+                Computed:            true,
+            },
+            "options": schema.ListAttribute{
+                MarkdownDescription: "Choices of a `single` or `multiple` field, empty for other types",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "default_value": schema.StringAttribute{
+                MarkdownDescription: "Default value in the string form used by `tacticalrmm_agent_custom_fields`: `\"true\"` or `\"false\"` " +
+                    "for checkbox fields and a JSON encoded list for multiple choice fields",
+                Computed: true,
+            },
+            "required": schema.BoolAttribute{
+                MarkdownDescription: "Whether a value is required",
+                Computed:            true,
+            },
+            "hide_in_ui": schema.BoolAttribute{
+                MarkdownDescription: "Whether the field is hidden in the web UI",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *CustomFieldDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *CustomFieldDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data CustomFieldDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Names are only unique per model, so a name needs its model
+    if data.Id.IsNull() && (data.Model.IsNull() || data.Name.IsNull()) {
+        resp.Diagnostics.AddError(
+            "Missing Custom Field Identifier",
+            "Either 'id' or both 'model' and 'name' must be specified to look up a custom field.",
+        )
+        return
+    }
+    if !data.Model.IsNull() && !customFieldModels[data.Model.ValueString()] {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("model"),
+            "Invalid Custom Field Model",
+            fmt.Sprintf("model must be one of agent, client or site, got: %q.", data.Model.ValueString()),
+        )
+        return
+    }
+
+    fields, err := d.client.listObjects(ctx, fmt.Sprintf("%s/core/customfields/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list custom fields, got error: %s", err))
+        return
+    }
+
+    var field map[string]interface{}
+    for _, f := range fields {
+        if !data.Id.IsNull() && customFieldId(f) != data.Id.ValueInt64() {
+            continue
+        }
+        if !data.Model.IsNull() && f["model"] != data.Model.ValueString() {
+            continue
+        }
+        if !data.Name.IsNull() && f["name"] != data.Name.ValueString() {
+            continue
+        }
+        field = f
+        break
+    }
+
+    if field == nil {
+        var criteria []string
+        if !data.Id.IsNull() {
+            criteria = append(criteria, fmt.Sprintf("ID %d", data.Id.ValueInt64()))
+        }
+        if !data.Model.IsNull() {
+            criteria = append(criteria, fmt.Sprintf("model '%s'", data.Model.ValueString()))
+        }
+        if !data.Name.IsNull() {
+            criteria = append(criteria, fmt.Sprintf("name '%s'", data.Name.ValueString()))
+        }
+        resp.Diagnostics.AddError("Custom Field Not Found", fmt.Sprintf("Custom field with %s not found", strings.Join(criteria, " and ")))
+        return
+    }
+
+    data.Id = types.Int64Value(customFieldId(field))
+    data.Model = stringValue(field["model"])
+    data.Name = stringValue(field["name"])
+    data.Type = types.StringValue(customFieldType(field))
+
+    options := stringSlice(field["options"])
+    if options == nil {
+        options = []string{}
+    }
+    optionsValue, diags := types.ListValueFrom(ctx, types.StringType, options)
+    resp.Diagnostics.Append(diags...)
+    data.Options = optionsValue
+
+    if defaultValue, ok := customFieldValueString(field, customFieldDefaultPayload(field)); ok {
+        data.DefaultValue = types.StringValue(defaultValue)
+    } else {
+        data.DefaultValue = types.StringNull()
+    }
+    required, _ := field["required"].(bool)
+    data.Required = types.BoolValue(required)
+    hidden, _ := field["hide_in_ui"].(bool)
+    data.HideInUI = types.BoolValue(hidden)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// customFieldDefinitionsServer serves custom fields of every model, with the
+// name "Owner" used by both an agent and a client field.
+func customFieldDefinitionsServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/core/customfields/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": 1, "model": "client", "name": "Owner", "type": "text", "options": []string{}, "default_value_string": "", "required": false},
+            {"id": 2, "model": "agent", "name": "Owner", "type": "text", "options": []string{}, "default_value_string": "IT", "required": true},
+            {"id": 3, "model": "agent", "name": "Roles", "type": "multiple", "options": []string{"web", "db"},
+                "default_values_multiple": []string{"web"}, "hide_in_ui": true},
+            {"id": 4, "model": "site", "name": "Backup", "type": "checkbox", "default_value_bool": true},
+        })
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func customFieldModel(id int64, model, name string) *CustomFieldDataSourceModel {
+    data := &CustomFieldDataSourceModel{
+        Id:           types.Int64Null(),
+        Model:        types.StringNull(),
+        Name:         types.StringNull(),
+        Type:         types.StringNull(),
+        Options:      types.ListNull(types.StringType),
+        DefaultValue: types.StringNull(),
+        Required:     types.BoolNull(),
+        HideInUI:     types.BoolNull(),
+    }
+    if id != 0 {
+        data.Id = types.Int64Value(id)
+    }
+    if model != "" {
+        data.Model = types.StringValue(model)
+    }
+    if name != "" {
+        data.Name = types.StringValue(name)
+    }
+    return data
+}
+
+func TestCustomFieldDataSourceRead(t *testing.T) {
+    tests := map[string]struct {
+        config       *CustomFieldDataSourceModel
+        id           int64
+        defaultValue string
+        options      int
+    }{
+        "agent by name":  {config: customFieldModel(0, "agent", "Owner"), id: 2, defaultValue: "IT"},
+        "client by name": {config: customFieldModel(0, "client", "Owner"), id: 1, defaultValue: ""},
+        "multiple by id": {config: customFieldModel(3, "", ""), id: 3, defaultValue: `["web"]`, options: 2},
+        "checkbox":       {config: customFieldModel(0, "site", "Backup"), id: 4, defaultValue: "true"},
+    }
+
+    for name, test := range tests {
+        t.Run(name, func(t *testing.T) {
+            d := &CustomFieldDataSource{client: newTestClient(customFieldDefinitionsServer(t))}
+            resp := readDataSource(t, d, test.config)
+            if resp.Diagnostics.HasError() {
+                t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+            }
+
+            var data CustomFieldDataSourceModel
+            resp.State.Get(context.Background(), &data)
+            if data.Id.ValueInt64() != test.id || data.DefaultValue.ValueString() != test.defaultValue || len(data.Options.Elements()) != test.options {
+                t.Errorf("unexpected custom field: %+v", data)
+            }
+            if data.Model.IsNull() || data.Name.IsNull() || data.Type.IsNull() {
+                t.Errorf("expected model, name and type to be set, got %+v", data)
+            }
+        })
+    }
+}
+
+func TestCustomFieldDataSourceRead_Errors(t *testing.T) {
+    tests := map[string]struct {
+        config   *CustomFieldDataSourceModel
+        summary  string
+        contains string
+    }{
+        "name without model": {config: customFieldModel(0, "", "Owner"), summary: "Missing Custom Field Identifier"},
+        "invalid model":      {config: customFieldModel(0, "user", "Owner"), summary: "Invalid Custom Field Model"},
+        "no match":           {config: customFieldModel(0, "site", "Owner"), summary: "Custom Field Not Found", contains: "model 'site' and name 'Owner'"},
+        "id of other model":  {config: customFieldModel(4, "agent", ""), summary: "Custom Field Not Found", contains: "ID 4 and model 'agent'"},
+    }
+
+    for name, test := range tests {
+        t.Run(name, func(t *testing.T) {
+            d := &CustomFieldDataSource{client: newTestClient(customFieldDefinitionsServer(t))}
+            resp := readDataSource(t, d, test.config)
+            if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != test.summary {
+                t.Fatalf("expected a %q error, got %v", test.summary, resp.Diagnostics)
+            }
+            if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, test.contains) {
+                t.Errorf("expected %q in the detail, got %q", test.contains, detail)
+            }
+        })
+    }
+}
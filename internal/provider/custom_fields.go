@@ -0,0 +1,242 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "math/big"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Custom field types supported by Tactical RMM.
+const (
+    customFieldTypeText     = "text"
+    customFieldTypeNumber   = "number"
+    customFieldTypeSingle   = "single"
+    customFieldTypeMultiple = "multiple"
+    customFieldTypeCheckbox = "checkbox"
+    customFieldTypeDateTime = "datetime"
+)
+
+// customFieldDefinitions returns the custom field definitions for a model
+// (agent, client or site) keyed by field name.
+func (c *ClientConfig) customFieldDefinitions(ctx context.Context, model string) (map[string]map[string]interface{}, error) {
+    fields, err := c.listObjects(ctx, fmt.Sprintf("%s/core/customfields/", c.BaseURL))
+    if err != nil {
+        return nil, err
+    }
+
+    definitions := make(map[string]map[string]interface{})
+    for _, field := range fields {
+        if fieldModel, ok := field["model"].(string); !ok || fieldModel != model {
+            continue
+        }
+        if name, ok := field["name"].(string); ok {
+            definitions[name] = field
+        }
+    }
+
+    return definitions, nil
+}
+
+// customFieldType returns the type of a custom field definition.
+func customFieldType(definition map[string]interface{}) string {
+    fieldType, _ := definition["type"].(string)
+    return fieldType
+}
+
+// customFieldId returns the numeric ID of a custom field definition.
+func customFieldId(definition map[string]interface{}) int64 {
+    id, _ := definition["id"].(float64)
+    return int64(id)
+}
+
+// customFieldPayload converts a Terraform string value into the typed value
+// object TRMM expects for the field. Checkbox values are "true" or "false" and
+// multiple choice values are JSON encoded lists, e.g. jsonencode(["a", "b"]).
+func customFieldPayload(definition map[string]interface{}, value string) (map[string]interface{}, error) {
+    payload := map[string]interface{}{
+        "field": customFieldId(definition),
+    }
+
+    switch customFieldType(definition) {
+    case customFieldTypeCheckbox:
+        boolValue, err := strconv.ParseBool(value)
+        if err != nil {
+            return nil, fmt.Errorf("checkbox value must be \"true\" or \"false\", got %q", value)
+        }
+        payload["bool_value"] = boolValue
+    case customFieldTypeMultiple:
+        var values []string
+        if err := json.Unmarshal([]byte(value), &values); err != nil {
+            return nil, fmt.Errorf("multiple choice value must be a JSON encoded list of strings, got %q", value)
+        }
+        payload["multiple_value"] = values
+    case customFieldTypeNumber:
+        if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+            return nil, fmt.Errorf("number value must be numeric, got %q", value)
+        }
+        payload["string_value"] = value
+    default:
+        payload["string_value"] = value
+    }
+
+    return payload, nil
+}
+
+// customFieldDefaultPayload returns the value object that resets a field to the
+// default from its definition.
+func customFieldDefaultPayload(definition map[string]interface{}) map[string]interface{} {
+    payload := map[string]interface{}{
+        "field":          customFieldId(definition),
+        "string_value":   "",
+        "bool_value":     false,
+        "multiple_value": []string{},
+    }
+
+    if value, ok := definition["default_value_string"].(string); ok {
+        payload["string_value"] = value
+    }
+    if value, ok := definition["default_value_bool"].(bool); ok {
+        payload["bool_value"] = value
+    }
+    if values, ok := definition["default_values_multiple"].([]interface{}); ok {
+        payload["multiple_value"] = values
+    }
+
+    return payload
+}
+
+// customFieldValueString converts a stored custom field value object into the
+// string form used by Terraform, the inverse of customFieldPayload. It returns
+// false when the object holds no value for the field.
+func customFieldValueString(definition map[string]interface{}, value map[string]interface{}) (string, bool) {
+    switch customFieldType(definition) {
+    case customFieldTypeCheckbox:
+        boolValue, ok := value["bool_value"].(bool)
+        if !ok {
+            return "", false
+        }
+        return strconv.FormatBool(boolValue), true
+    case customFieldTypeMultiple:
+        values := stringSlice(value["multiple_value"])
+        if values == nil {
+            values = []string{}
+        }
+        encoded, err := json.Marshal(values)
+        if err != nil {
+            return "", false
+        }
+        return string(encoded), true
+    default:
+        str, ok := value["string_value"].(string)
+        return str, ok
+    }
+}
+
+// customFieldAttrValue converts a stored custom field value object into a
+// typed Terraform value: checkbox fields become bools, number fields numbers
+// and multiple choice fields lists of strings. Other fields are strings.
+func customFieldAttrValue(definition map[string]interface{}, value map[string]interface{}) (attr.Value, attr.Type) {
+    switch customFieldType(definition) {
+    case customFieldTypeCheckbox:
+        boolValue, _ := value["bool_value"].(bool)
+        return types.BoolValue(boolValue), types.BoolType
+    case customFieldTypeMultiple:
+        items := []attr.Value{}
+        for _, item := range stringSlice(value["multiple_value"]) {
+            items = append(items, types.StringValue(item))
+        }
+        return types.ListValueMust(types.StringType, items), types.ListType{ElemType: types.StringType}
+    case customFieldTypeNumber:
+        str, _ := value["string_value"].(string)
+        number, ok := new(big.Float).SetString(strings.TrimSpace(str))
+        if !ok {
+            return types.NumberNull(), types.NumberType
+        }
+        return types.NumberValue(number), types.NumberType
+    default:
+        str, _ := value["string_value"].(string)
+        return types.StringValue(str), types.StringType
+    }
+}
+
+// customFieldValues returns the typed values of all custom fields for one
+// object, falling back to the definition default when no value is stored.
+func customFieldValues(definitions map[string]map[string]interface{}, stored map[int64]map[string]interface{}) (types.Object, diag.Diagnostics) {
+    attrTypes := make(map[string]attr.Type, len(definitions))
+    attrValues := make(map[string]attr.Value, len(definitions))
+
+    for name, definition := range definitions {
+        value, ok := stored[customFieldId(definition)]
+        if !ok {
+            value = customFieldDefaultPayload(definition)
+        }
+        attrValues[name], attrTypes[name] = customFieldAttrValue(definition, value)
+    }
+
+    return types.ObjectValue(attrTypes, attrValues)
+}
+
+// storedCustomFields indexes the custom_fields of an agent, client or site
+// detail response by field ID.
+func storedCustomFields(object map[string]interface{}) map[int64]map[string]interface{} {
+    stored := make(map[int64]map[string]interface{})
+    items, _ := object["custom_fields"].([]interface{})
+    for _, item := range items {
+        value, ok := item.(map[string]interface{})
+        if !ok {
+            continue
+        }
+        if fieldId, ok := value["field"].(float64); ok {
+            stored[int64(fieldId)] = value
+        }
+    }
+    return stored
+}
+
+// customFieldMaps converts the stored custom field values of one object into
+// maps keyed by field name: multiple choice fields become lists in the second
+// map, all other fields their Terraform string form in the first. Fields
+// without a value, including empty strings and empty selections, are omitted
+// rather than filled with the definition default, so lookup() defaults apply.
+func customFieldMaps(definitions map[string]map[string]interface{}, stored map[int64]map[string]interface{}) (types.Map, types.Map, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    values := map[string]attr.Value{}
+    lists := map[string]attr.Value{}
+
+    for name, definition := range definitions {
+        value, ok := stored[customFieldId(definition)]
+        if !ok {
+            continue
+        }
+
+        if customFieldType(definition) == customFieldTypeMultiple {
+            items := stringSlice(value["multiple_value"])
+            if len(items) == 0 {
+                continue
+            }
+            elements := make([]attr.Value, 0, len(items))
+            for _, item := range items {
+                elements = append(elements, types.StringValue(item))
+            }
+            lists[name] = types.ListValueMust(types.StringType, elements)
+            continue
+        }
+
+        if str, ok := customFieldValueString(definition, value); ok && str != "" {
+            values[name] = types.StringValue(str)
+        }
+    }
+
+    valuesMap, mapDiags := types.MapValue(types.StringType, values)
+    diags.Append(mapDiags...)
+    listsMap, mapDiags := types.MapValue(types.ListType{ElemType: types.StringType}, lists)
+    diags.Append(mapDiags...)
+    return valuesMap, listsMap, diags
+}
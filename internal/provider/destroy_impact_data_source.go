@@ -0,0 +1,287 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DestroyImpactDataSource{}
+
+func NewDestroyImpactDataSource() datasource.DataSource {
+    return &DestroyImpactDataSource{}
+}
+
+// DestroyImpactDataSource reports the objects that depend on a script or
+// script snippet, so pipelines can check what a destroy would break.
+type DestroyImpactDataSource struct {
+    client *ClientConfig
+}
+
+// DestroyImpactDataSourceModel describes the data source data model.
+type DestroyImpactDataSourceModel struct {
+    ResourceType  types.String `tfsdk:"resource_type"`
+    ResourceId    types.Int64  `tfsdk:"resource_id"`
+    Dependents    types.List   `tfsdk:"dependents"`
+    HasDependents types.Bool   `tfsdk:"has_dependents"`
+}
+
+const (
+    impactResourceScript  = "script"
+    impactResourceSnippet = "script_snippet"
+)
+
+// dependentAttrTypes describes an object depending on the inspected resource.
+var dependentAttrTypes = map[string]attr.Type{
+    "kind":      types.StringType,
+    "id":        types.Int64Type,
+    "name":      types.StringType,
+    "policy_id": types.Int64Type,
+}
+
+func (d *DestroyImpactDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_destroy_impact"
+}
+
+func (d *DestroyImpactDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Destroy impact data source for Tactical RMM. Lists the objects that depend on a script or script snippet, " +
+            "so a pipeline can refuse to destroy something still in use. Scripts are looked up in automated tasks, checks and alert templates; " +
+            "snippets in the bodies of user defined scripts.",
+
+        Attributes: map[string]schema.Attribute{
+            "resource_type": schema.StringAttribute{
+                MarkdownDescription: "Type of the inspected object: `script` or `script_snippet`",
+                Required:            true,
+            },
+            "resource_id": schema.Int64Attribute{
+                MarkdownDescription: "Identifier of the inspected object",
+                Required:            true,
+            },
+            "dependents": schema.ListNestedAttribute{
+                MarkdownDescription: "Objects depending on the inspected object, ordered by kind and ID",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "kind": schema.StringAttribute{
+                            MarkdownDescription: "Kind of the dependent object: `task`, `check`, `alert_template` or `script`",
+                            Computed:            true,
+                        },
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Identifier of the dependent object",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Name or description of the dependent object",
+                            Computed:            true,
+                        },
+                        "policy_id": schema.Int64Attribute{
+                            MarkdownDescription: "Policy the task or check belongs to, null for agent tasks and checks and for other kinds",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+            "has_dependents": schema.BoolAttribute{
+                MarkdownDescription: "Whether anything depends on the inspected object",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *DestroyImpactDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+// dependent is an object depending on the inspected resource.
+type dependent struct {
+    kind     string
+    id       int64
+    name     string
+    policyId types.Int64
+}
+
+func (d *DestroyImpactDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data DestroyImpactDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var dependents []dependent
+    switch data.ResourceType.ValueString() {
+    case impactResourceScript:
+        dependents = d.scriptDependents(ctx, data.ResourceId.ValueInt64(), &resp.Diagnostics)
+    case impactResourceSnippet:
+        dependents = d.snippetDependents(ctx, data.ResourceId.ValueInt64(), &resp.Diagnostics)
+    default:
+        resp.Diagnostics.AddAttributeError(
+            path.Root("resource_type"),
+            "Unsupported Resource Type",
+            fmt.Sprintf("resource_type must be one of %s, got: %q.", strings.Join([]string{impactResourceScript, impactResourceSnippet}, ", "), data.ResourceType.ValueString()),
+        )
+    }
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    sort.SliceStable(dependents, func(i, j int) bool {
+        if dependents[i].kind != dependents[j].kind {
+            return dependents[i].kind < dependents[j].kind
+        }
+        return dependents[i].id < dependents[j].id
+    })
+
+    values := []attr.Value{}
+    for _, dep := range dependents {
+        value, diags := types.ObjectValue(dependentAttrTypes, map[string]attr.Value{
+            "kind":      types.StringValue(dep.kind),
+            "id":        types.Int64Value(dep.id),
+            "name":      types.StringValue(dep.name),
+            "policy_id": dep.policyId,
+        })
+        resp.Diagnostics.Append(diags...)
+        values = append(values, value)
+    }
+
+    dependentsValue, diags := types.ListValue(types.ObjectType{AttrTypes: dependentAttrTypes}, values)
+    resp.Diagnostics.Append(diags...)
+    data.Dependents = dependentsValue
+    data.HasDependents = types.BoolValue(len(dependents) > 0)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// scriptDependents returns the tasks running the script as one of their
+// actions, the script checks running it and the alert templates using it as
+// failure or resolved action.
+func (d *DestroyImpactDataSource) scriptDependents(ctx context.Context, scriptId int64, diags *diag.Diagnostics) []dependent {
+    if _, err := d.client.getObject(ctx, fmt.Sprintf("%s/scripts/%d/", d.client.BaseURL, scriptId)); err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read script %d, got error: %s", scriptId, err))
+        return nil
+    }
+
+    tasks, err := d.client.listObjects(ctx, fmt.Sprintf("%s/tasks/", d.client.BaseURL))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list tasks, got error: %s", err))
+        return nil
+    }
+    checks, err := d.client.listObjects(ctx, fmt.Sprintf("%s/checks/", d.client.BaseURL))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list checks, got error: %s", err))
+        return nil
+    }
+    templates, err := d.client.listObjects(ctx, fmt.Sprintf("%s/alerts/templates/", d.client.BaseURL))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list alert templates, got error: %s", err))
+        return nil
+    }
+
+    var dependents []dependent
+    for _, task := range tasks {
+        if taskRunsScript(task, scriptId) {
+            dependents = append(dependents, newDependent("task", task, "name"))
+        }
+    }
+    for _, check := range checks {
+        if checkRunsScript(check, scriptId) {
+            dependents = append(dependents, newDependent("check", check, "readable_desc", "name"))
+        }
+    }
+    for _, template := range templates {
+        if referencedId(template["action"]) == scriptId || referencedId(template["resolved_action"]) == scriptId {
+            dependents = append(dependents, newDependent("alert_template", template, "name"))
+        }
+    }
+
+    return dependents
+}
+
+// snippetDependents returns the user defined scripts whose body references the
+// snippet.
+func (d *DestroyImpactDataSource) snippetDependents(ctx context.Context, snippetId int64, diags *diag.Diagnostics) []dependent {
+    snippet, err := d.client.getObject(ctx, fmt.Sprintf("%s/scripts/snippets/%d/", d.client.BaseURL, snippetId))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to read script snippet %d, got error: %s", snippetId, err))
+        return nil
+    }
+    name, _ := snippet["name"].(string)
+    token := snippetToken(name)
+
+    scripts, err := d.client.listObjects(ctx, d.client.allScriptsURL())
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return nil
+    }
+
+    ids := userDefinedScriptIds(scripts)
+    details, failures := d.client.fetchScriptDetails(ctx, ids)
+
+    var dependents []dependent
+    for _, id := range ids {
+        if err, failed := failures[id]; failed {
+            addItemFetchWarning(diags, fmt.Sprintf("script ID %d", id), err)
+            continue
+        }
+        if body, _ := details[id]["script_body"].(string); strings.Contains(body, token) {
+            dependents = append(dependents, newDependent("script", details[id], "name"))
+        }
+    }
+
+    return dependents
+}
+
+// newDependent builds a dependent from a listed object, named after the first
+// non-empty of nameKeys.
+func newDependent(kind string, object map[string]interface{}, nameKeys ...string) dependent {
+    dep := dependent{kind: kind, id: referencedId(object["id"]), policyId: types.Int64Null()}
+    for _, key := range nameKeys {
+        if name, ok := object[key].(string); ok && name != "" {
+            dep.name = name
+            break
+        }
+    }
+    if kind == "task" || kind == "check" {
+        dep.policyId = int64Value(object["policy"])
+    }
+    return dep
+}
+
+// referencedId returns the ID of a related object, which TRMM serializes
+// either as the bare ID or as the nested object. It returns 0 when there is
+// no related object.
+func referencedId(value interface{}) int64 {
+    if nested, ok := value.(map[string]interface{}); ok {
+        value = nested["id"]
+    }
+    if id, ok := value.(float64); ok {
+        return int64(id)
+    }
+    return 0
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// destroyImpactServer serves script 5, which is run by an agent task, a
+// policy check and an alert template, and script 6, which nothing uses.
+func destroyImpactServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/scripts/5/":
+            writeJSON(t, w, map[string]interface{}{"id": 5, "name": "Disk Cleanup"})
+        case "/scripts/6/":
+            writeJSON(t, w, map[string]interface{}{"id": 6, "name": "Unused"})
+        case "/tasks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 11, "name": "Nightly Cleanup", "agent": 3, "policy": nil, "actions": []map[string]interface{}{
+                    {"type": "cmd", "command": "echo start"},
+                    {"type": "script", "script": 5, "name": "Disk Cleanup"},
+                }},
+                {"id": 12, "name": "Other Task", "agent": 3, "policy": nil, "actions": []map[string]interface{}{
+                    {"type": "script", "script": 8, "name": "Other"},
+                }},
+            })
+        case "/checks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 21, "check_type": "script", "script": map[string]interface{}{"id": 5, "name": "Disk Cleanup"}, "readable_desc": "Script check: Disk Cleanup", "policy": 2},
+                {"id": 22, "check_type": "diskspace", "readable_desc": "Disk space check: C:"},
+            })
+        case "/alerts/templates/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 31, "name": "Servers", "action": nil, "resolved_action": 5},
+                {"id": 32, "name": "Workstations", "action": 8, "resolved_action": nil},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func destroyImpactModel(resourceType string, id int64) *DestroyImpactDataSourceModel {
+    return &DestroyImpactDataSourceModel{
+        ResourceType:  types.StringValue(resourceType),
+        ResourceId:    types.Int64Value(id),
+        Dependents:    types.ListNull(types.ObjectType{AttrTypes: dependentAttrTypes}),
+        HasDependents: types.BoolNull(),
+    }
+}
+
+// dependentKeys returns "kind/id" for every dependent in state.
+func dependentKeys(t *testing.T, data DestroyImpactDataSourceModel) []string {
+    t.Helper()
+    var dependents []struct {
+        Kind     types.String `tfsdk:"kind"`
+        Id       types.Int64  `tfsdk:"id"`
+        Name     types.String `tfsdk:"name"`
+        PolicyId types.Int64  `tfsdk:"policy_id"`
+    }
+    if diags := data.Dependents.ElementsAs(context.Background(), &dependents, false); diags.HasError() {
+        t.Fatalf("unable to read dependents: %v", diags)
+    }
+
+    keys := []string{}
+    for _, dep := range dependents {
+        keys = append(keys, dep.Kind.ValueString()+"/"+dep.Id.String())
+    }
+    return keys
+}
+
+func TestDestroyImpactDataSourceRead_ScriptWithDependents(t *testing.T) {
+    server := destroyImpactServer(t)
+    d := &DestroyImpactDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, destroyImpactModel("script", 5))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var data DestroyImpactDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if !data.HasDependents.ValueBool() {
+        t.Error("expected has_dependents to be true")
+    }
+    want := []string{"alert_template/31", "check/21", "task/11"}
+    if got := dependentKeys(t, data); !reflect.DeepEqual(got, want) {
+        t.Errorf("expected dependents %v, got %v", want, got)
+    }
+
+    check := data.Dependents.Elements()[1].(types.Object).Attributes()
+    if check["name"].(types.String).ValueString() != "Script check: Disk Cleanup" || check["policy_id"].(types.Int64).ValueInt64() != 2 {
+        t.Errorf("unexpected check dependent: %v", check)
+    }
+    task := data.Dependents.Elements()[2].(types.Object).Attributes()
+    if !task["policy_id"].(types.Int64).IsNull() {
+        t.Errorf("expected an agent task without policy, got: %v", task)
+    }
+}
+
+func TestDestroyImpactDataSourceRead_ScriptWithoutDependents(t *testing.T) {
+    server := destroyImpactServer(t)
+    d := &DestroyImpactDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, destroyImpactModel("script", 6))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var data DestroyImpactDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if data.HasDependents.ValueBool() {
+        t.Error("expected has_dependents to be false")
+    }
+    if got := dependentKeys(t, data); len(got) != 0 {
+        t.Errorf("expected no dependents, got %v", got)
+    }
+}
+
+func TestDestroyImpactDataSourceRead_SnippetDependents(t *testing.T) {
+    server, _ := scriptBodiesServer(t, map[int]string{
+        1: "{{GetDiskSpace}}\nWrite-Output 'done'",
+        2: "Remove-Item $env:TEMP",
+    })
+    d := &DestroyImpactDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, destroyImpactModel("script_snippet", 7))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var data DestroyImpactDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if got := dependentKeys(t, data); !reflect.DeepEqual(got, []string{"script/1"}) {
+        t.Errorf("expected script 1 as the only dependent, got %v", got)
+    }
+}
+
+func TestDestroyImpactDataSourceRead_UnsupportedType(t *testing.T) {
+    d := &DestroyImpactDataSource{client: &ClientConfig{}}
+
+    resp := readDataSource(t, d, destroyImpactModel("agent", 1))
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Unsupported Resource Type" {
+        t.Fatalf("expected an unsupported resource type error, got: %v", resp.Diagnostics)
+    }
+}
+
+func TestDestroyImpactDataSourceRead_MissingScript(t *testing.T) {
+    server := destroyImpactServer(t)
+    d := &DestroyImpactDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, destroyImpactModel("script", 404))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a script that does not exist")
+    }
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DurationFunction{}
+
+func NewDurationSecondsFunction() function.Function {
+    return &DurationFunction{name: "duration_seconds", unit: time.Second, unitName: "seconds"}
+}
+
+func NewDurationMinutesFunction() function.Function {
+    return &DurationFunction{name: "duration_minutes", unit: time.Minute, unitName: "minutes"}
+}
+
+// DurationFunction converts a Go duration string such as "15m" into the whole
+// number of seconds or minutes that interval, timeout and overdue attributes
+// of the API expect.
+type DurationFunction struct {
+    name     string
+    unit     time.Duration
+    unitName string
+}
+
+func (f *DurationFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+    resp.Name = f.name
+}
+
+func (f *DurationFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+    resp.Definition = function.Definition{
+        Summary: fmt.Sprintf("Converts a duration string into %s", f.unitName),
+        MarkdownDescription: fmt.Sprintf("Parses a Go duration string such as `\"90s\"`, `\"15m\"` or `\"1h30m\"` and returns it as a whole number of %s, "+
+            "for attributes the API expresses in %s. Negative durations and durations that are not a whole number of %s are an error.", f.unitName, f.unitName, f.unitName),
+
+        Parameters: []function.Parameter{
+            function.StringParameter{
+                Name:                "duration",
+                MarkdownDescription: "Duration with units `h`, `m`, `s`, `ms`, `us` or `ns`, e.g. `\"2h\"`",
+            },
+        },
+        Return: function.Int64Return{},
+    }
+}
+
+func (f *DurationFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+    var duration string
+
+    resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &duration))
+    if resp.Error != nil {
+        return
+    }
+
+    parsed, err := time.ParseDuration(strings.TrimSpace(duration))
+    if err != nil {
+        resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid duration %q: use a Go duration such as \"90s\", \"15m\" or \"2h\".", duration))
+        return
+    }
+    if parsed < 0 {
+        resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid duration %q: the duration must not be negative.", duration))
+        return
+    }
+    if parsed%f.unit != 0 {
+        resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid duration %q: the duration must be a whole number of %s.", duration, f.unitName))
+        return
+    }
+
+    resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, int64(parsed/f.unit)))
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/function"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// runFunction calls f with a single string argument and returns the response.
+func runFunction(f function.Function, argument string) function.RunResponse {
+    resp := function.RunResponse{Result: function.NewResultData(types.Int64Unknown())}
+    f.Run(context.Background(), function.RunRequest{
+        Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(argument)}),
+    }, &resp)
+    return resp
+}
+
+func TestDurationFunctionRun(t *testing.T) {
+    seconds, minutes := NewDurationSecondsFunction(), NewDurationMinutesFunction()
+
+    tests := map[string]struct {
+        function function.Function
+        argument string
+        expected int64
+        err      string
+    }{
+        "seconds":                 {function: seconds, argument: "15m", expected: 900},
+        "seconds compound":        {function: seconds, argument: "1h30m15s", expected: 5415},
+        "seconds zero":            {function: seconds, argument: "0s", expected: 0},
+        "minutes":                 {function: minutes, argument: "2h", expected: 120},
+        "minutes with whitespace": {function: minutes, argument: " 45m ", expected: 45},
+        "negative":                {function: seconds, argument: "-5m", err: "must not be negative"},
+        "sub-second":              {function: seconds, argument: "1500ms", err: "whole number of seconds"},
+        "partial minute":          {function: minutes, argument: "90s", err: "whole number of minutes"},
+        "days are not supported":  {function: seconds, argument: "1d", err: "use a Go duration"},
+        "no unit":                 {function: minutes, argument: "15", err: "use a Go duration"},
+    }
+
+    for name, test := range tests {
+        resp := runFunction(test.function, test.argument)
+        if test.err != "" {
+            if resp.Error == nil || !strings.Contains(resp.Error.Text, test.err) {
+                t.Errorf("%s: expected an error containing %q, got %v", name, test.err, resp.Error)
+            }
+            if resp.Error != nil && (resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0) {
+                t.Errorf("%s: expected the error to point at the argument, got %v", name, resp.Error.FunctionArgument)
+            }
+            continue
+        }
+
+        if resp.Error != nil {
+            t.Errorf("%s: unexpected error: %s", name, resp.Error)
+            continue
+        }
+        result, _ := resp.Result.Value().(types.Int64)
+        if result.ValueInt64() != test.expected {
+            t.Errorf("%s: expected %d, got %v", name, test.expected, result)
+        }
+    }
+}
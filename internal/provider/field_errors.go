@@ -0,0 +1,161 @@
+package provider
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// apiFieldPaths maps the fields of an API request body to the attributes
+// their values come from.
+type apiFieldPaths map[string]path.Path
+
+// scriptFieldPaths covers the script request body. env_vars is left out as
+// the API receives env_vars and sensitive_env_vars merged into one list, so
+// its errors cannot be attributed to either attribute.
+var scriptFieldPaths = apiFieldPaths{
+    "name":                path.Root("name"),
+    "description":         path.Root("description"),
+    "shell":               path.Root("shell"),
+    "script_body":         path.Root("script_body"),
+    "category":            path.Root("category"),
+    "default_timeout":     path.Root("default_timeout"),
+    "favorite":            path.Root("favorite"),
+    "hidden":              path.Root("hidden"),
+    "run_as_user":         path.Root("run_as_user"),
+    "syntax":              path.Root("syntax"),
+    "args":                path.Root("args"),
+    "supported_platforms": path.Root("supported_platforms"),
+}
+
+var scriptSnippetFieldPaths = apiFieldPaths{
+    "name":  path.Root("name"),
+    "desc":  path.Root("desc"),
+    "code":  path.Root("code"),
+    "shell": path.Root("shell"),
+}
+
+// keyStoreFieldPaths returns the paths for a keystore request body, whose
+// value is sent from either value or value_json.
+func keyStoreFieldPaths(data KeyStoreResourceModel) apiFieldPaths {
+    value := path.Root("value")
+    if !data.ValueJSON.IsNull() {
+        value = path.Root("value_json")
+    }
+    return apiFieldPaths{
+        "name":  path.Root("name"),
+        "value": value,
+    }
+}
+
+// addAPIFieldErrors reports a Django REST Framework validation response, an
+// object of field name to messages, as attribute errors. Errors of list
+// elements, keyed by their index, are attached to the element. Fields missing
+// from paths and non_field_errors are reported on the resource. It returns
+// false, adding nothing, unless the response is a 400 with field errors, so
+// the caller can report it as usual.
+func addAPIFieldErrors(diags *diag.Diagnostics, action string, statusCode int, body []byte, paths apiFieldPaths) bool {
+    if statusCode != http.StatusBadRequest {
+        return false
+    }
+
+    var fields map[string]interface{}
+    if err := json.Unmarshal(body, &fields); err != nil {
+        return false
+    }
+    if _, ok := fields["detail"]; ok || len(fields) == 0 {
+        return false
+    }
+
+    // Sorted so the diagnostics are in a stable order
+    names := make([]string, 0, len(fields))
+    for name := range fields {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        attribute, mapped := paths[name]
+        for _, fieldError := range apiFieldErrorMessages(fields[name], nil) {
+            if !mapped {
+                field := name
+                if len(fieldError.indexes) > 0 {
+                    field = fmt.Sprintf("%s[%s]", name, strings.Join(fieldError.indexes, "]["))
+                }
+                if name == "non_field_errors" {
+                    diags.AddError("Invalid Request", fmt.Sprintf("%s: %s", action, fieldError.message))
+                } else {
+                    diags.AddError("Invalid Request", fmt.Sprintf("%s, field '%s': %s", action, field, fieldError.message))
+                }
+                continue
+            }
+
+            elementPath := attribute
+            for _, index := range fieldError.indexes {
+                i, _ := strconv.Atoi(index)
+                elementPath = elementPath.AtListIndex(i)
+            }
+            diags.AddAttributeError(elementPath, "Invalid Attribute Value", fmt.Sprintf("%s: %s", action, fieldError.message))
+        }
+    }
+
+    return true
+}
+
+// apiFieldError is one message of a field, with the list indexes of the
+// element it belongs to.
+type apiFieldError struct {
+    indexes []string
+    message string
+}
+
+// apiFieldErrorMessages flattens the errors of one field. DRF reports a list
+// of messages for the field itself and an object keyed by index for the
+// elements of a list field.
+func apiFieldErrorMessages(value interface{}, indexes []string) []apiFieldError {
+    switch v := value.(type) {
+    case string:
+        return []apiFieldError{{indexes: indexes, message: v}}
+    case []interface{}:
+        var errors []apiFieldError
+        for _, item := range v {
+            errors = append(errors, apiFieldErrorMessages(item, indexes)...)
+        }
+        return errors
+    case map[string]interface{}:
+        keys := make([]string, 0, len(v))
+        for key := range v {
+            keys = append(keys, key)
+        }
+        sort.Slice(keys, func(i, j int) bool {
+            a, errA := strconv.Atoi(keys[i])
+            b, errB := strconv.Atoi(keys[j])
+            if errA == nil && errB == nil {
+                return a < b
+            }
+            return keys[i] < keys[j]
+        })
+
+        var errors []apiFieldError
+        for _, key := range keys {
+            if _, err := strconv.Atoi(key); err != nil {
+                // Not a list element, keep the key in the message
+                for _, nested := range apiFieldErrorMessages(v[key], indexes) {
+                    nested.message = fmt.Sprintf("%s: %s", key, nested.message)
+                    errors = append(errors, nested)
+                }
+                continue
+            }
+            elementIndexes := append(append([]string{}, indexes...), key)
+            errors = append(errors, apiFieldErrorMessages(v[key], elementIndexes)...)
+        }
+        return errors
+    }
+    return nil
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// attributeErrorPaths returns the path of every attribute error, and the
+// number of errors without a path.
+func attributeErrorPaths(diags diag.Diagnostics) ([]string, int) {
+    var paths []string
+    unattributed := 0
+    for _, d := range diags.Errors() {
+        if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+            paths = append(paths, withPath.Path().String())
+            continue
+        }
+        unattributed++
+    }
+    return paths, unattributed
+}
+
+func TestAddAPIFieldErrors(t *testing.T) {
+    paths := apiFieldPaths{
+        "name": path.Root("name"),
+        "args": path.Root("args"),
+    }
+
+    tests := []struct {
+        name         string
+        statusCode   int
+        body         string
+        handled      bool
+        paths        []string
+        unattributed int
+    }{
+        {
+            name:       "field error",
+            statusCode: http.StatusBadRequest,
+            body:       `{"name": ["Ensure this field has no more than 40 characters."]}`,
+            handled:    true,
+            paths:      []string{"name"},
+        },
+        {
+            name:       "list element errors",
+            statusCode: http.StatusBadRequest,
+            body:       `{"args": {"10": ["Not a valid string."], "2": ["This field may not be null."]}}`,
+            handled:    true,
+            paths:      []string{"args[2]", "args[10]"},
+        },
+        {
+            name:         "unmapped and non-field errors",
+            statusCode:   http.StatusBadRequest,
+            body:         `{"env_vars": {"0": ["Not a valid string."]}, "non_field_errors": ["Invalid data."], "name": "Required."}`,
+            handled:      true,
+            paths:        []string{"name"},
+            unattributed: 2,
+        },
+        {
+            name:       "detail",
+            statusCode: http.StatusBadRequest,
+            body:       `{"detail": "Permission denied."}`,
+        },
+        {
+            name:       "plain message",
+            statusCode: http.StatusBadRequest,
+            body:       `"Script name already exists"`,
+        },
+        {
+            name:       "other status",
+            statusCode: http.StatusInternalServerError,
+            body:       `{"name": ["Something went wrong."]}`,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var diags diag.Diagnostics
+            handled := addAPIFieldErrors(&diags, "Unable to create script", tt.statusCode, []byte(tt.body), paths)
+            if handled != tt.handled {
+                t.Fatalf("expected handled %t, got %t", tt.handled, handled)
+            }
+            if !handled {
+                if diags.HasError() {
+                    t.Errorf("expected no diagnostics when not handled, got: %v", diags)
+                }
+                return
+            }
+
+            got, unattributed := attributeErrorPaths(diags)
+            if len(got) != len(tt.paths) {
+                t.Fatalf("expected paths %v, got %v", tt.paths, got)
+            }
+            for i := range got {
+                if got[i] != tt.paths[i] {
+                    t.Errorf("expected paths %v, got %v", tt.paths, got)
+                    break
+                }
+            }
+            if unattributed != tt.unattributed {
+                t.Errorf("expected %d resource level errors, got %d: %v", tt.unattributed, unattributed, diags)
+            }
+        })
+    }
+}
+
+func TestScriptResourceCreate_FieldErrorOnArgsElement(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet && r.URL.Path == "/scripts/" {
+            writeJSON(t, w, []map[string]interface{}{})
+            return
+        }
+        if r.Method != http.MethodPost || r.URL.Path != "/scripts/" {
+            http.NotFound(w, r)
+            return
+        }
+        w.WriteHeader(http.StatusBadRequest)
+        writeJSON(t, w, map[string]interface{}{
+            "args": map[string]interface{}{"1": []string{"Ensure this field has no more than 255 characters."}},
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Sync Tickets"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Sync-Tickets"),
+        Args:               stringListOf("-Verbose", "-Filter"),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+
+    paths, unattributed := attributeErrorPaths(resp.Diagnostics)
+    if len(paths) != 1 || paths[0] != "args[1]" || unattributed != 0 {
+        t.Errorf("expected a single error on args[1], got: %v", resp.Diagnostics)
+    }
+}
+
+func TestScriptSnippetResourceCreate_FieldErrorOnName(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/snippets/":
+            w.WriteHeader(http.StatusBadRequest)
+            writeJSON(t, w, map[string]interface{}{
+                "name": []string{"Ensure this field has no more than 40 characters."},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("AVeryLongSnippetNameThatExceedsTheLimitOfTheServer"),
+        Code:         types.StringValue("Get-PSDrive"),
+        ReferencedBy: types.ListNull(types.StringType),
+    })
+
+    paths, unattributed := attributeErrorPaths(resp.Diagnostics)
+    if len(paths) != 1 || paths[0] != "name" || unattributed != 0 {
+        t.Errorf("expected a single error on name, got: %v", resp.Diagnostics)
+    }
+}
+
+func TestKeyStoreFieldPaths_ValueJSON(t *testing.T) {
+    paths := keyStoreFieldPaths(KeyStoreResourceModel{
+        Value:     types.StringNull(),
+        ValueJSON: types.StringValue(`{"a":1}`),
+    })
+    if got := paths["value"].String(); got != "value_json" {
+        t.Errorf("expected value errors on value_json, got %s", got)
+    }
+}
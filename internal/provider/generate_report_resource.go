@@ -0,0 +1,397 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GenerateReportResource{}
+var _ resource.ResourceWithValidateConfig = &GenerateReportResource{}
+
+// defaultReportTimeout is the number of seconds a report render is awaited
+// when timeout is not set.
+const defaultReportTimeout = 300
+
+// reportPollInterval is the delay between render status requests. It is a
+// variable so tests can shorten it.
+var reportPollInterval = 2 * time.Second
+
+func NewGenerateReportResource() resource.Resource {
+    return &GenerateReportResource{}
+}
+
+// GenerateReportResource renders a report template for a client or site when
+// it is created and keeps the result in state.
+type GenerateReportResource struct {
+    client *ClientConfig
+}
+
+// GenerateReportResourceModel describes the resource data model.
+type GenerateReportResourceModel struct {
+    Id            types.String `tfsdk:"id"`
+    TemplateId    types.Int64  `tfsdk:"template_id"`
+    ClientId      types.Int64  `tfsdk:"client_id"`
+    SiteId        types.Int64  `tfsdk:"site_id"`
+    Start         types.String `tfsdk:"start"`
+    End           types.String `tfsdk:"end"`
+    Format        types.String `tfsdk:"format"`
+    Timeout       types.Int64  `tfsdk:"timeout"`
+    Triggers      types.Map    `tfsdk:"triggers"`
+    Output        types.String `tfsdk:"output"`
+    ContentBase64 types.String `tfsdk:"content_base64"`
+    DownloadURL   types.String `tfsdk:"download_url"`
+}
+
+func (r *GenerateReportResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_generate_report"
+}
+
+func (r *GenerateReportResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Renders a report template of the reporting addon for a client or site and time range when created, and keeps the result in state. " +
+            "Exactly one of `client_id` or `site_id` must be set. Destroying this resource does nothing on the server.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this render",
+                Computed:            true,
+            },
+            "template_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the report template to render",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Render the report for this client",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Render the report for this site",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "start": schema.StringAttribute{
+                MarkdownDescription: "Start of the reported time range, as RFC 3339 timestamp or `YYYY-MM-DD` date in UTC",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "end": schema.StringAttribute{
+                MarkdownDescription: "End of the reported time range, as RFC 3339 timestamp or `YYYY-MM-DD` date in UTC",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "format": schema.StringAttribute{
+                MarkdownDescription: "Output format: `pdf`, `html` (default) or `plaintext`",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "timeout": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Seconds to wait for the report to render, defaults to %d", defaultReportTimeout),
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the report to be rendered again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "output": schema.StringAttribute{
+                MarkdownDescription: "Rendered report for the `html` and `plaintext` formats, null for `pdf`",
+                Computed:            true,
+            },
+            "content_base64": schema.StringAttribute{
+                MarkdownDescription: "Base64 encoded PDF for the `pdf` format when the server returns the document itself, null otherwise",
+                Computed:            true,
+            },
+            "download_url": schema.StringAttribute{
+                MarkdownDescription: "URL the rendered report can be downloaded from, null when the server does not return one",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *GenerateReportResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data GenerateReportResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.ClientId.IsUnknown() && !data.SiteId.IsUnknown() && data.ClientId.IsNull() == data.SiteId.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Report Target",
+            "Exactly one of 'client_id' or 'site_id' must be specified.",
+        )
+    }
+
+    if !data.Format.IsNull() && !data.Format.IsUnknown() && !isReportFormat(data.Format.ValueString()) {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("format"),
+            "Invalid Report Format",
+            fmt.Sprintf("format must be one of %s, got %q.", strings.Join(reportFormats, ", "), data.Format.ValueString()),
+        )
+    }
+
+    if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() && data.Timeout.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("timeout"),
+            "Invalid Report Timeout",
+            "timeout must be at least 1 second.",
+        )
+    }
+
+    if data.Start.IsNull() || data.Start.IsUnknown() || data.End.IsNull() || data.End.IsUnknown() {
+        return
+    }
+
+    start, startErr := parseAPITime(data.Start.ValueString())
+    end, endErr := parseAPITime(data.End.ValueString())
+    for attribute, err := range map[string]error{"start": startErr, "end": endErr} {
+        if err != nil {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(attribute),
+                "Invalid Report Time Range",
+                fmt.Sprintf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date: %s.", attribute, err),
+            )
+        }
+    }
+    if startErr == nil && endErr == nil && !start.Before(end) {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("end"),
+            "Invalid Report Time Range",
+            "end must be after start.",
+        )
+    }
+}
+
+func (r *GenerateReportResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *GenerateReportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data GenerateReportResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    start, err := parseAPITime(data.Start.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddAttributeError(path.Root("start"), "Invalid Report Time Range", err.Error())
+        return
+    }
+    end, err := parseAPITime(data.End.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddAttributeError(path.Root("end"), "Invalid Report Time Range", err.Error())
+        return
+    }
+
+    format := "html"
+    if !data.Format.IsNull() {
+        format = data.Format.ValueString()
+    }
+    timeout := int64(defaultReportTimeout)
+    if !data.Timeout.IsNull() {
+        timeout = data.Timeout.ValueInt64()
+    }
+
+    dependencies := map[string]interface{}{}
+    if !data.ClientId.IsNull() {
+        dependencies["client"] = data.ClientId.ValueInt64()
+    } else {
+        dependencies["site"] = data.SiteId.ValueInt64()
+    }
+
+    body := map[string]interface{}{
+        "template":     data.TemplateId.ValueInt64(),
+        "dependencies": dependencies,
+        "date_range": map[string]interface{}{
+            "start": start.UTC().Format(time.RFC3339),
+            "end":   end.UTC().Format(time.RFC3339),
+        },
+        "format": format,
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/reporting/reports/generate/", r.client.BaseURL), body)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to generate report, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK && statusCode != http.StatusCreated && statusCode != http.StatusAccepted {
+        resp.Diagnostics.AddError("Report Render Failed", fmt.Sprintf("Unable to generate report, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    var render map[string]interface{}
+    if err := json.Unmarshal(respBody, &render); err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return
+    }
+
+    render = r.awaitRender(ctx, render, time.Duration(timeout)*time.Second, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.Output = types.StringNull()
+    data.ContentBase64 = types.StringNull()
+    if content, ok := render["output"].(string); ok && content != "" {
+        if format == "pdf" {
+            data.ContentBase64 = types.StringValue(content)
+        } else {
+            data.Output = types.StringValue(content)
+        }
+    }
+    data.DownloadURL = stringValue(render["download_url"])
+    if !data.DownloadURL.IsNull() && data.DownloadURL.ValueString() == "" {
+        data.DownloadURL = types.StringNull()
+    }
+
+    if data.Output.IsNull() && data.ContentBase64.IsNull() && data.DownloadURL.IsNull() {
+        resp.Diagnostics.AddError("Report Render Failed", "The server reported the report as rendered but returned neither its content nor a download URL.")
+        return
+    }
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GenerateReportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // A render is a one-off operation, there is no remote object to refresh
+}
+
+func (r *GenerateReportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data GenerateReportResourceModel
+    var state GenerateReportResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.Output = state.Output
+    data.ContentBase64 = state.ContentBase64
+    data.DownloadURL = state.DownloadURL
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GenerateReportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Nothing was created on the server, removing the resource only drops it from state
+}
+
+// awaitRender polls a render started by the generate endpoint until it is
+// completed or failed. Renders that finish right away are returned as is.
+// A failed render is reported with the server's error.
+func (r *GenerateReportResource) awaitRender(ctx context.Context, render map[string]interface{}, timeout time.Duration, diags *diag.Diagnostics) map[string]interface{} {
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    for {
+        status, _ := render["status"].(string)
+        switch status {
+        case "", "completed":
+            return render
+        case "failed", "error":
+            message, _ := render["error"].(string)
+            if message == "" {
+                message = "no error message returned"
+            }
+            diags.AddError("Report Render Failed", fmt.Sprintf("The report template could not be rendered: %s", message))
+            return nil
+        }
+
+        id, ok := render["id"].(float64)
+        if !ok {
+            diags.AddError("Client Error", fmt.Sprintf("Report render is %s but has no ID to poll", status))
+            return nil
+        }
+
+        select {
+        case <-ctx.Done():
+            diags.AddError("Report Render Timeout", fmt.Sprintf("Report %d is still %s after %s, got error: %s", int64(id), status, timeout, ctx.Err()))
+            return nil
+        case <-time.After(reportPollInterval):
+        }
+
+        next, err := r.client.getObject(ctx, fmt.Sprintf("%s/reporting/reports/generate/%d/", r.client.BaseURL, int64(id)))
+        if err != nil {
+            if ctx.Err() != nil {
+                continue
+            }
+            diags.AddError("Client Error", fmt.Sprintf("Unable to read report render status, got error: %s", err))
+            return nil
+        }
+        render = next
+    }
+}
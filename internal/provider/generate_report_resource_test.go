@@ -0,0 +1,142 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// reportRenderServer starts render 5 on POST and answers status requests
+// with the given responses in turn, repeating the last one.
+func reportRenderServer(t *testing.T, sent *map[string]interface{}, statuses ...map[string]interface{}) (*httptest.Server, *int32) {
+    t.Helper()
+    var polls int32
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/reporting/reports/generate/":
+            json.NewDecoder(r.Body).Decode(sent)
+            writeJSON(t, w, map[string]interface{}{"id": 5, "status": "pending"})
+        case r.Method == http.MethodGet && r.URL.Path == "/reporting/reports/generate/5/":
+            i := int(atomic.AddInt32(&polls, 1)) - 1
+            if i >= len(statuses) {
+                i = len(statuses) - 1
+            }
+            writeJSON(t, w, statuses[i])
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &polls
+}
+
+func shortenReportPoll(t *testing.T) {
+    t.Helper()
+    previous := reportPollInterval
+    reportPollInterval = time.Millisecond
+    t.Cleanup(func() { reportPollInterval = previous })
+}
+
+func generateReportModel() *GenerateReportResourceModel {
+    return &GenerateReportResourceModel{
+        TemplateId: types.Int64Value(3),
+        ClientId:   types.Int64Value(12),
+        SiteId:     types.Int64Null(),
+        Start:      types.StringValue("2026-09-01"),
+        End:        types.StringValue("2026-10-01"),
+        Triggers:   types.MapNull(types.StringType),
+    }
+}
+
+func TestGenerateReportResourceCreate_PollsUntilRendered(t *testing.T) {
+    shortenReportPoll(t)
+    var sent map[string]interface{}
+    server, polls := reportRenderServer(t, &sent,
+        map[string]interface{}{"id": 5, "status": "running"},
+        map[string]interface{}{"id": 5, "status": "completed", "output": "<h1>Patch Status</h1>", "download_url": "https://rmm.example.com/reports/5.html"},
+    )
+
+    r := &GenerateReportResource{client: newTestClient(server)}
+    resp := createResource(t, r, generateReportModel())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if got := atomic.LoadInt32(polls); got != 2 {
+        t.Errorf("expected 2 status requests, got %d", got)
+    }
+    dateRange, _ := sent["date_range"].(map[string]interface{})
+    if sent["format"] != "html" || dateRange["start"] != "2026-09-01T00:00:00Z" || dateRange["end"] != "2026-10-01T00:00:00Z" {
+        t.Errorf("unexpected request body: %v", sent)
+    }
+
+    var state GenerateReportResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Output.ValueString() != "<h1>Patch Status</h1>" || !state.ContentBase64.IsNull() {
+        t.Errorf("unexpected output: %s, content %s", state.Output, state.ContentBase64)
+    }
+    if state.DownloadURL.ValueString() != "https://rmm.example.com/reports/5.html" {
+        t.Errorf("unexpected download URL: %s", state.DownloadURL)
+    }
+}
+
+func TestGenerateReportResourceCreate_PDF(t *testing.T) {
+    shortenReportPoll(t)
+    var sent map[string]interface{}
+    server, _ := reportRenderServer(t, &sent, map[string]interface{}{"id": 5, "status": "completed", "output": "JVBERi0xLjQK"})
+
+    model := generateReportModel()
+    model.Format = types.StringValue("pdf")
+
+    r := &GenerateReportResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state GenerateReportResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.ContentBase64.ValueString() != "JVBERi0xLjQK" || !state.Output.IsNull() || !state.DownloadURL.IsNull() {
+        t.Errorf("unexpected result: output %s, content %s, URL %s", state.Output, state.ContentBase64, state.DownloadURL)
+    }
+}
+
+func TestGenerateReportResourceCreate_RenderError(t *testing.T) {
+    shortenReportPoll(t)
+    var sent map[string]interface{}
+    server, _ := reportRenderServer(t, &sent, map[string]interface{}{"id": 5, "status": "failed", "error": "TemplateSyntaxError: unexpected '}'"})
+
+    r := &GenerateReportResource{client: newTestClient(server)}
+    resp := createResource(t, r, generateReportModel())
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a broken template")
+    }
+    err := resp.Diagnostics.Errors()[0]
+    if err.Summary() != "Report Render Failed" || !strings.Contains(err.Detail(), "TemplateSyntaxError") {
+        t.Errorf("expected the server's render error, got: %v", resp.Diagnostics)
+    }
+}
+
+func TestGenerateReportResourceCreate_Timeout(t *testing.T) {
+    shortenReportPoll(t)
+    var sent map[string]interface{}
+    server, _ := reportRenderServer(t, &sent, map[string]interface{}{"id": 5, "status": "running"})
+
+    model := generateReportModel()
+    model.Timeout = types.Int64Value(1)
+
+    r := &GenerateReportResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Report Render Timeout" {
+        t.Errorf("expected a timeout error, got: %v", resp.Diagnostics)
+    }
+}
@@ -0,0 +1,475 @@
+package provider
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// createLookupAttempts bounds how many times a create-by-name lookup lists the
+// collection before giving up on finding the newly created object.
+const createLookupAttempts = 5
+
+// createLookupInterval is the initial delay between create-by-name lookups. It
+// doubles after every miss. It is a variable so tests can shorten it.
+var createLookupInterval = 250 * time.Millisecond
+
+// detailFetchConcurrency limits how many detail requests are in flight at once
+// when details are fetched for many objects, e.g. script bodies or agents.
+const detailFetchConcurrency = 4
+
+// findCreatedByName locates an object that was just created through an
+// endpoint which does not return the new object. TRMM list endpoints can lag
+// behind the POST, so the list is polled a bounded number of times before
+// the object is reported missing.
+func (c *ClientConfig) findCreatedByName(ctx context.Context, listURL string, name string) (map[string]interface{}, error) {
+    return pollCreated(ctx, name, func() (map[string]interface{}, error) {
+        return c.findByName(ctx, listURL, name)
+    })
+}
+
+// findCreatedByFilteredName is findCreatedByName for list endpoints that may
+// support a ?name= filter. Only the candidates are downloaded when the filter
+// is supported; a server that ignores it returns the full list, which is
+// scanned by name as usual. Once a filtered request fails, the remaining
+// attempts list the unfiltered endpoint. Objects for which skip returns true
+// are never the created one, e.g. built-in objects sharing the name.
+func (c *ClientConfig) findCreatedByFilteredName(ctx context.Context, listURL string, name string, skip func(item map[string]interface{}) bool) (map[string]interface{}, error) {
+    filtered := true
+    return pollCreated(ctx, name, func() (map[string]interface{}, error) {
+        var items []map[string]interface{}
+        var err error
+        if filtered {
+            items, err = c.listObjects(ctx, nameFilterURL(listURL, name))
+            if err != nil {
+                filtered = false
+            }
+        }
+        if !filtered {
+            items, err = c.listObjects(ctx, listURL)
+            if err != nil {
+                return nil, err
+            }
+        }
+        for _, item := range objectsNamed(items, name) {
+            if skip == nil || !skip(item) {
+                return item, nil
+            }
+        }
+        return nil, nil
+    })
+}
+
+// idsNamed returns the IDs of the objects of a TRMM list endpoint with the
+// given name, using the ?name= filter when the server supports it. Creates
+// take this snapshot before the POST, so findCreatedByFilteredName can skip
+// objects that already had the name.
+func (c *ClientConfig) idsNamed(ctx context.Context, listURL string, name string) (map[int64]bool, error) {
+    items, err := c.listObjects(ctx, nameFilterURL(listURL, name))
+    if err != nil {
+        items, err = c.listObjects(ctx, listURL)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    ids := make(map[int64]bool)
+    for _, item := range objectsNamed(items, name) {
+        ids[referencedId(item["id"])] = true
+    }
+    return ids, nil
+}
+
+// allScriptsURL is the scripts list including hidden scripts, which the list
+// endpoint leaves out unless asked for them.
+func (c *ClientConfig) allScriptsURL() string {
+    return fmt.Sprintf("%s/scripts/?showHiddenScripts=true", c.BaseURL)
+}
+
+// nameFilterURL returns listURL narrowed to objects with the given name.
+func nameFilterURL(listURL string, name string) string {
+    separator := "?"
+    if strings.Contains(listURL, "?") {
+        separator = "&"
+    }
+    return listURL + separator + url.Values{"name": {name}}.Encode()
+}
+
+// pollCreated calls lookup until it finds the object named name, waiting a
+// doubling interval between attempts, see findCreatedByName.
+func pollCreated(ctx context.Context, name string, lookup func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+    interval := createLookupInterval
+
+    for attempt := 1; ; attempt++ {
+        item, err := lookup()
+        if err != nil {
+            return nil, err
+        }
+        if item != nil {
+            return item, nil
+        }
+
+        if attempt >= createLookupAttempts {
+            return nil, fmt.Errorf("object named %q not found after %d attempts", name, attempt)
+        }
+
+        select {
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        case <-time.After(interval):
+        }
+        interval *= 2
+    }
+}
+
+// findByName returns the object of a TRMM list endpoint with the given name,
+// or nil when there is none.
+func (c *ClientConfig) findByName(ctx context.Context, listURL string, name string) (map[string]interface{}, error) {
+    items, err := c.listObjects(ctx, listURL)
+    if err != nil {
+        return nil, err
+    }
+
+    return objectNamed(items, name), nil
+}
+
+// objectNamed returns the object of a decoded TRMM list with the given name,
+// or nil when there is none.
+func objectNamed(items []map[string]interface{}, name string) map[string]interface{} {
+    for _, item := range items {
+        if itemName, ok := item["name"].(string); ok && itemName == name {
+            return item
+        }
+    }
+    return nil
+}
+
+// listObjects fetches a TRMM list endpoint that returns a JSON array of objects.
+func (c *ClientConfig) listObjects(ctx context.Context, listURL string) ([]map[string]interface{}, error) {
+    httpReq, err := http.NewRequestWithContext(ctx, "GET", listURL, nil)
+    if err != nil {
+        return nil, fmt.Errorf("unable to create request: %w", err)
+    }
+
+    httpResp, err := c.Do(httpReq)
+    if err != nil {
+        return nil, fmt.Errorf("unable to list objects: %w", err)
+    }
+    defer httpResp.Body.Close()
+
+    if httpResp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
+    }
+
+    var items []map[string]interface{}
+    if err := json.NewDecoder(httpResp.Body).Decode(&items); err != nil {
+        return nil, fmt.Errorf("unable to parse response: %w", err)
+    }
+
+    return items, nil
+}
+
+// actionID returns the identifier stored for action resources. They perform a
+// one-off operation on create and have no remote object whose ID could be used.
+func actionID() string {
+    return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// getObject fetches a TRMM detail endpoint that returns a single JSON object.
+func (c *ClientConfig) getObject(ctx context.Context, objectURL string) (map[string]interface{}, error) {
+    httpReq, err := http.NewRequestWithContext(ctx, "GET", objectURL, nil)
+    if err != nil {
+        return nil, fmt.Errorf("unable to create request: %w", err)
+    }
+
+    httpResp, err := c.Do(httpReq)
+    if err != nil {
+        return nil, fmt.Errorf("unable to fetch object: %w", err)
+    }
+    defer httpResp.Body.Close()
+
+    if httpResp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
+    }
+
+    var result map[string]interface{}
+    if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
+        return nil, fmt.Errorf("unable to parse response: %w", err)
+    }
+
+    return result, nil
+}
+
+// scriptsReferencing returns the names of the user defined scripts whose body
+// contains token.
+func (c *ClientConfig) scriptsReferencing(ctx context.Context, token string) ([]string, error) {
+    scripts, err := c.listObjects(ctx, c.allScriptsURL())
+    if err != nil {
+        return nil, err
+    }
+
+    ids := userDefinedScriptIds(scripts)
+    details, failures := c.fetchScriptDetails(ctx, ids)
+
+    names := []string{}
+    for _, id := range ids {
+        if err, ok := failures[id]; ok {
+            return nil, fmt.Errorf("script %d: %w", id, err)
+        }
+
+        detail := details[id]
+        if body, ok := detail["script_body"].(string); ok && strings.Contains(body, token) {
+            if name, ok := detail["name"].(string); ok {
+                names = append(names, name)
+            }
+        }
+    }
+
+    return names, nil
+}
+
+// sendJSON issues a request with an optional JSON body and returns the response
+// status code together with the raw response body.
+func (c *ClientConfig) sendJSON(ctx context.Context, method string, requestURL string, body interface{}) (int, []byte, error) {
+    var reader io.Reader
+    if body != nil {
+        jsonBody, err := json.Marshal(body)
+        if err != nil {
+            return 0, nil, fmt.Errorf("unable to encode request: %w", err)
+        }
+        reader = bytes.NewReader(jsonBody)
+    }
+
+    httpReq, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+    if err != nil {
+        return 0, nil, fmt.Errorf("unable to create request: %w", err)
+    }
+
+    httpResp, err := c.Do(httpReq)
+    if err != nil {
+        return 0, nil, err
+    }
+    defer httpResp.Body.Close()
+
+    respBody, err := io.ReadAll(httpResp.Body)
+    if err != nil {
+        return httpResp.StatusCode, nil, fmt.Errorf("unable to read response: %w", err)
+    }
+
+    return httpResp.StatusCode, respBody, nil
+}
+
+// addItemFetchWarning reports an item a data source could not fetch while
+// enriching a list. The read carries on without it, so one broken object does
+// not fail the whole data source.
+func addItemFetchWarning(diags *diag.Diagnostics, item string, err error) {
+    diags.AddWarning(
+        "Incomplete Results",
+        fmt.Sprintf("Unable to read %s, got error: %s\n\nThe results are returned without it.", item, err),
+    )
+}
+
+// stringListValue converts a decoded JSON array of strings into a list value.
+// Anything else, including a missing attribute, becomes an empty list.
+func stringListValue(value interface{}) types.List {
+    elements := []attr.Value{}
+    if items, ok := value.([]interface{}); ok {
+        for _, item := range items {
+            if str, ok := item.(string); ok {
+                elements = append(elements, types.StringValue(str))
+            }
+        }
+    }
+    return types.ListValueMust(types.StringType, elements)
+}
+
+// stringListFrom converts a decoded JSON array into a list of strings without
+// trusting its element types. Nulls are skipped and numbers, booleans and
+// nested values are stringified, each with a warning on attribute, so a
+// malformed API response is reported instead of causing a panic. Anything
+// that is not an array becomes an empty list.
+func stringListFrom(value interface{}, attribute path.Path, diags *diag.Diagnostics) types.List {
+    list, listDiags := types.ListValue(types.StringType, stringElementsFrom(value, attribute, diags))
+    diags.Append(listDiags...)
+    return list
+}
+
+// stringSetFrom is stringListFrom for set attributes. Duplicate elements are
+// dropped as a set cannot hold them.
+func stringSetFrom(value interface{}, attribute path.Path, diags *diag.Diagnostics) types.Set {
+    seen := make(map[string]bool)
+    elements := []attr.Value{}
+    for _, element := range stringElementsFrom(value, attribute, diags) {
+        str := element.(types.String).ValueString()
+        if seen[str] {
+            continue
+        }
+        seen[str] = true
+        elements = append(elements, element)
+    }
+
+    set, setDiags := types.SetValue(types.StringType, elements)
+    diags.Append(setDiags...)
+    return set
+}
+
+// stringElementsFrom returns the elements of a decoded JSON array as string
+// values, see stringListFrom.
+func stringElementsFrom(value interface{}, attribute path.Path, diags *diag.Diagnostics) []attr.Value {
+    elements := []attr.Value{}
+    items, _ := value.([]interface{})
+    for i, item := range items {
+        switch v := item.(type) {
+        case string:
+            elements = append(elements, types.StringValue(v))
+        case nil:
+            diags.AddAttributeWarning(
+                attribute,
+                "Unexpected List Element",
+                fmt.Sprintf("The API returned null at index %d where a string was expected. The element was skipped.", i),
+            )
+        default:
+            str := fmt.Sprint(v)
+            if encoded, err := json.Marshal(v); err == nil {
+                str = string(encoded)
+            }
+            diags.AddAttributeWarning(
+                attribute,
+                "Unexpected List Element",
+                fmt.Sprintf("The API returned %s at index %d where a string was expected. The element was converted to %q.", str, i, str),
+            )
+            elements = append(elements, types.StringValue(str))
+        }
+    }
+    return elements
+}
+
+// stringSlice returns the strings of a decoded JSON array. A []string is
+// returned as is; anything else yields nil.
+func stringSlice(value interface{}) []string {
+    switch items := value.(type) {
+    case []string:
+        return items
+    case []interface{}:
+        values := make([]string, 0, len(items))
+        for _, item := range items {
+            if str, ok := item.(string); ok {
+                values = append(values, str)
+            }
+        }
+        return values
+    }
+    return nil
+}
+
+// boolValue converts a decoded JSON bool, which TRMM sometimes leaves null.
+func boolValue(value interface{}) types.Bool {
+    if b, ok := value.(bool); ok {
+        return types.BoolValue(b)
+    }
+    return types.BoolNull()
+}
+
+// int64Value converts a decoded JSON number, which may be null.
+func int64Value(value interface{}) types.Int64 {
+    if n, ok := value.(float64); ok {
+        return types.Int64Value(int64(n))
+    }
+    return types.Int64Null()
+}
+
+// stringValue converts a decoded JSON string, which may be null.
+func stringValue(value interface{}) types.String {
+    if str, ok := value.(string); ok {
+        return types.StringValue(str)
+    }
+    return types.StringNull()
+}
+
+// errorMessage extracts the human readable message of a TRMM response. Action
+// endpoints answer with a JSON string and errors may also be a list of
+// strings or an object with a detail or non_field_errors entry. Anything else
+// is returned as the trimmed raw body.
+func errorMessage(body []byte) string {
+    var decoded interface{}
+    if err := json.Unmarshal(body, &decoded); err != nil {
+        return strings.TrimSpace(string(body))
+    }
+
+    switch value := decoded.(type) {
+    case string:
+        return value
+    case []interface{}:
+        return strings.Join(stringSlice(value), "; ")
+    case map[string]interface{}:
+        if detail, ok := value["detail"].(string); ok {
+            return detail
+        }
+        if errors := stringSlice(value["non_field_errors"]); len(errors) > 0 {
+            return strings.Join(errors, "; ")
+        }
+    }
+    return strings.TrimSpace(string(body))
+}
+
+// dependentsPattern matches TRMM messages rejecting a delete because other
+// objects still use the object, e.g. "Script is used by automated tasks: ...".
+var dependentsPattern = regexp.MustCompile(`(?i)\b(used by|in use|referenced|depend|protected foreign key)`)
+
+// addDeleteError reports a DELETE that did not succeed, with the message of
+// the server verbatim. Only rejections that name dependents as the reason
+// are reported as blocked by them.
+func addDeleteError(diags *diag.Diagnostics, objectKind string, httpResp *http.Response) {
+    body, _ := io.ReadAll(httpResp.Body)
+    message := errorMessage(body)
+
+    if message == "" {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to delete %s, status code: %d", objectKind, httpResp.StatusCode))
+        return
+    }
+
+    if (httpResp.StatusCode == http.StatusBadRequest || httpResp.StatusCode == http.StatusConflict) && dependentsPattern.MatchString(message) {
+        diags.AddError(
+            "Delete Blocked By Dependents",
+            fmt.Sprintf("Tactical RMM refused to delete the %s: %s\n\nRemove or reassign the objects that still depend on it, then apply again.", objectKind, message),
+        )
+        return
+    }
+
+    diags.AddError("Client Error", fmt.Sprintf("Unable to delete %s, status code: %d, response: %s", objectKind, httpResp.StatusCode, message))
+}
+
+// apiTimeLayouts are the timestamp formats seen in TRMM responses, which
+// depend on the serializer and on whether a timezone was applied.
+var apiTimeLayouts = []string{
+    time.RFC3339Nano,
+    "2006-01-02T15:04:05.999999999",
+    "2006-01-02 15:04:05.999999999Z07:00",
+    "2006-01-02 15:04:05.999999999",
+    "2006-01-02T15:04",
+    "2006-01-02",
+}
+
+// parseAPITime parses a TRMM timestamp. Timestamps without a zone are UTC.
+func parseAPITime(value string) (time.Time, error) {
+    value = strings.TrimSpace(value)
+    for _, layout := range apiTimeLayouts {
+        if parsed, err := time.Parse(layout, value); err == nil {
+            return parsed, nil
+        }
+    }
+    return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
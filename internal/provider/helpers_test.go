@@ -0,0 +1,413 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newTestClient returns a ClientConfig pointed at the given test server.
+func newTestClient(server *httptest.Server) *ClientConfig {
+    return &ClientConfig{
+        BaseURL:    server.URL,
+        APIKey:     "test-key",
+        HTTPClient: server.Client(),
+    }
+}
+
+// writeJSON encodes v as the response body of a test handler.
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+    t.Helper()
+    w.Header().Set("Content-Type", "application/json")
+    if err := json.NewEncoder(w).Encode(v); err != nil {
+        t.Fatalf("unable to encode response: %s", err)
+    }
+}
+
+// resourceSchemaFor returns the schema of a resource for building plans and states.
+func resourceSchemaFor(t *testing.T, r resource.Resource) resource.SchemaResponse {
+    t.Helper()
+    var resp resource.SchemaResponse
+    r.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected schema diagnostics: %v", resp.Diagnostics)
+    }
+    return resp
+}
+
+// createResource runs Create for r with a plan built from model and returns the response.
+func createResource(t *testing.T, r resource.Resource, model interface{}) resource.CreateResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    plan := tfsdk.Plan{Schema: schemaResp.Schema}
+    if diags := plan.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build plan: %v", diags)
+    }
+
+    resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+    r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+    return resp
+}
+
+// updateResource runs Update for r with a prior state built from prior and a plan built from planned.
+func updateResource(t *testing.T, r resource.Resource, prior interface{}, planned interface{}) resource.UpdateResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, prior); diags.HasError() {
+        t.Fatalf("unable to build state: %v", diags)
+    }
+
+    plan := tfsdk.Plan{Schema: schemaResp.Schema}
+    if diags := plan.Set(ctx, planned); diags.HasError() {
+        t.Fatalf("unable to build plan: %v", diags)
+    }
+
+    resp := resource.UpdateResponse{State: state}
+    r.Update(ctx, resource.UpdateRequest{Plan: plan, State: state}, &resp)
+    return resp
+}
+
+// readResource runs Read for r with a prior state built from model and returns the response.
+func readResource(t *testing.T, r resource.Resource, model interface{}) resource.ReadResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build state: %v", diags)
+    }
+
+    resp := resource.ReadResponse{State: state}
+    r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+    return resp
+}
+
+// deleteResource runs Delete for r with a prior state built from model and returns the response.
+func deleteResource(t *testing.T, r resource.Resource, model interface{}) resource.DeleteResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build state: %v", diags)
+    }
+
+    resp := resource.DeleteResponse{State: state}
+    r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+    return resp
+}
+
+// planCreate runs ModifyPlan for r with a plan built from planned and no prior state, as when the resource is created.
+func planCreate(t *testing.T, r resource.ResourceWithModifyPlan, planned interface{}) resource.ModifyPlanResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    plan := tfsdk.Plan{Schema: schemaResp.Schema}
+    if diags := plan.Set(ctx, planned); diags.HasError() {
+        t.Fatalf("unable to build plan: %v", diags)
+    }
+    state := tfsdk.State{
+        Schema: schemaResp.Schema,
+        Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+    }
+
+    resp := resource.ModifyPlanResponse{Plan: plan}
+    r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: plan, State: state}, &resp)
+    return resp
+}
+
+// importResource runs ImportState for r with the given import ID and returns the response.
+func importResource(t *testing.T, r resource.ResourceWithImportState, id string) resource.ImportStateResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    resp := resource.ImportStateResponse{State: tfsdk.State{
+        Schema: schemaResp.Schema,
+        Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+    }}
+    r.ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+    return resp
+}
+
+// readDataSource runs Read for d with a config built from model and returns the response.
+func readDataSource(t *testing.T, d datasource.DataSource, model interface{}) datasource.ReadResponse {
+    t.Helper()
+    ctx := context.Background()
+
+    var schemaResp datasource.SchemaResponse
+    d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+    if schemaResp.Diagnostics.HasError() {
+        t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+    }
+
+    // Config has no Set method, so the raw value is built through a state
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build config: %v", diags)
+    }
+
+    resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+    d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+    return resp
+}
+
+// laggingListServer serves POST as a plain "ok" and only includes the created
+// object in the list response once misses list calls have been answered.
+func laggingListServer(t *testing.T, listPath string, misses int32, created map[string]interface{}) (*httptest.Server, *int32) {
+    t.Helper()
+    var listCalls int32
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != listPath {
+            http.NotFound(w, r)
+            return
+        }
+        switch r.Method {
+        case http.MethodPost:
+            writeJSON(t, w, "ok")
+        case http.MethodGet:
+            if atomic.AddInt32(&listCalls, 1) <= misses {
+                writeJSON(t, w, []map[string]interface{}{})
+                return
+            }
+            writeJSON(t, w, []map[string]interface{}{created})
+        default:
+            w.WriteHeader(http.StatusMethodNotAllowed)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &listCalls
+}
+
+func shortenCreateLookup(t *testing.T) {
+    t.Helper()
+    previous := createLookupInterval
+    createLookupInterval = 0
+    t.Cleanup(func() { createLookupInterval = previous })
+}
+
+func TestFindCreatedByName_GivesUpAfterBoundedAttempts(t *testing.T) {
+    shortenCreateLookup(t)
+    server, listCalls := laggingListServer(t, "/scripts/", 100, map[string]interface{}{"id": 1, "name": "never"})
+
+    _, err := newTestClient(server).findCreatedByName(context.Background(), server.URL+"/scripts/", "never")
+    if err == nil {
+        t.Fatal("expected an error when the object never appears")
+    }
+    if got := atomic.LoadInt32(listCalls); got != createLookupAttempts {
+        t.Errorf("expected %d list calls, got %d", createLookupAttempts, got)
+    }
+}
+
+func TestScriptResourceCreate_RetriesListUntilFound(t *testing.T) {
+    shortenCreateLookup(t)
+    // The first list call looks for existing scripts before the POST
+    server, listCalls := laggingListServer(t, "/scripts/", 3, map[string]interface{}{
+        "id":              float64(42),
+        "name":            "Test Script",
+        "script_type":     "userdefined",
+        "default_timeout": float64(90),
+    })
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Test Script"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Write-Output 'Test'"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 42 {
+        t.Errorf("expected id 42, got %d", state.Id.ValueInt64())
+    }
+    if got := atomic.LoadInt32(listCalls); got != 4 {
+        t.Errorf("expected 4 list calls, got %d", got)
+    }
+}
+
+func TestScriptSnippetResourceCreate_RetriesListUntilFound(t *testing.T) {
+    shortenCreateLookup(t)
+    // The first list call is the duplicate name check before the POST
+    server, listCalls := laggingListServer(t, "/scripts/snippets/", 2, map[string]interface{}{
+        "id":   float64(7),
+        "name": "GetDiskSpace",
+    })
+
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("GetDiskSpace"),
+        Code:         types.StringValue("Get-PSDrive"),
+        ReferencedBy: types.ListNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptSnippetResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 7 {
+        t.Errorf("expected id 7, got %d", state.Id.ValueInt64())
+    }
+    if got := atomic.LoadInt32(listCalls); got != 3 {
+        t.Errorf("expected 3 list calls, got %d", got)
+    }
+}
+
+func TestKeyStoreResourceCreate_RetriesListUntilFound(t *testing.T) {
+    shortenCreateLookup(t)
+    server, listCalls := laggingListServer(t, "/core/keystore/", 1, map[string]interface{}{
+        "id":    float64(3),
+        "name":  "api_token",
+        "value": "secret",
+    })
+
+    r := &KeyStoreResource{client: newTestClient(server)}
+    resp := createResource(t, r, &KeyStoreResourceModel{
+        Name:  types.StringValue("api_token"),
+        Value: types.StringValue("secret"),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state KeyStoreResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 3 {
+        t.Errorf("expected id 3, got %d", state.Id.ValueInt64())
+    }
+    if got := atomic.LoadInt32(listCalls); got != 2 {
+        t.Errorf("expected 2 list calls, got %d", got)
+    }
+}
+
+func TestScriptResourceCreate_UsesNameFilter(t *testing.T) {
+    var queries []string
+    created := false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            created = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/" && !created:
+            queries = append(queries, r.URL.RawQuery)
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            queries = append(queries, r.URL.RawQuery)
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": float64(42), "name": "Test Script", "script_type": "userdefined", "default_timeout": float64(90)},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Test Script"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Write-Output 'Test'"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    // One list call before the POST for existing scripts, one to find the
+    // created script, both including hidden scripts
+    filtered := "showHiddenScripts=true&name=Test+Script"
+    if len(queries) != 2 || queries[0] != filtered || queries[1] != filtered {
+        t.Errorf("expected two filtered list calls, got queries %q", queries)
+    }
+}
+
+func TestFindCreatedByFilteredName_FallsBackWhenFilterRejected(t *testing.T) {
+    shortenCreateLookup(t)
+    var queries []string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        queries = append(queries, r.URL.RawQuery)
+        if r.URL.RawQuery != "" {
+            w.WriteHeader(http.StatusBadRequest)
+            return
+        }
+        // The first unfiltered list lags behind the create
+        if len(queries) == 2 {
+            writeJSON(t, w, []map[string]interface{}{})
+            return
+        }
+        writeJSON(t, w, []map[string]interface{}{{"id": float64(5), "name": "Other"}, {"id": float64(6), "name": "Cleanup"}})
+    }))
+    t.Cleanup(server.Close)
+
+    item, err := newTestClient(server).findCreatedByFilteredName(context.Background(), server.URL+"/scripts/", "Cleanup", nil)
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+    if item["id"] != float64(6) {
+        t.Errorf("expected script 6, got %v", item)
+    }
+    if expected := []string{"name=Cleanup", "", ""}; strings.Join(queries, ",") != strings.Join(expected, ",") {
+        t.Errorf("expected queries %q, got %q", expected, queries)
+    }
+}
+
+func TestAddDeleteError(t *testing.T) {
+    cases := map[string]struct {
+        status  int
+        body    string
+        summary string
+        detail  string
+    }{
+        "dependents":           {status: http.StatusBadRequest, body: `"Script is used by automated tasks: Nightly Cleanup"`, summary: "Delete Blocked By Dependents", detail: "Nightly Cleanup"},
+        "protected":            {status: http.StatusConflict, body: `{"detail": "Cannot delete because it is referenced through protected foreign keys"}`, summary: "Delete Blocked By Dependents", detail: "protected foreign keys"},
+        "other rejection":      {status: http.StatusBadRequest, body: `"Community scripts cannot be deleted"`, summary: "Client Error", detail: "status code: 400, response: Community scripts cannot be deleted"},
+        "dependents not a 400": {status: http.StatusForbidden, body: `{"detail": "You do not have permission to delete objects in use"}`, summary: "Client Error", detail: "status code: 403, response: You do not have permission"},
+        "no message":           {status: http.StatusInternalServerError, body: "", summary: "Client Error", detail: "Unable to delete script, status code: 500"},
+    }
+
+    for name, tc := range cases {
+        var diags diag.Diagnostics
+        addDeleteError(&diags, "script", &http.Response{StatusCode: tc.status, Body: io.NopCloser(strings.NewReader(tc.body))})
+        if diags.ErrorsCount() != 1 {
+            t.Fatalf("%s: expected one error, got %v", name, diags)
+        }
+        if summary := diags.Errors()[0].Summary(); summary != tc.summary {
+            t.Errorf("%s: expected summary %q, got %q", name, tc.summary, summary)
+        }
+        if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, tc.detail) {
+            t.Errorf("%s: expected %q in the detail, got %q", name, tc.detail, detail)
+        }
+    }
+}
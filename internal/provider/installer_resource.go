@@ -0,0 +1,327 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &InstallerResource{}
+var _ resource.ResourceWithValidateConfig = &InstallerResource{}
+
+// defaultInstallerExpiry is the number of hours an installer is valid for
+// when expires_hours is not set, the same as in the web UI.
+const defaultInstallerExpiry = 24
+
+// installerAgentTypes are the agent types an installer can enroll.
+var installerAgentTypes = []string{"server", "workstation"}
+
+// installerArchitectures are the Windows architectures installers exist for.
+var installerArchitectures = []string{"amd64", "386", "arm64"}
+
+func NewInstallerResource() resource.Resource {
+    return &InstallerResource{}
+}
+
+// InstallerResource generates a one-off Windows agent installer for a client
+// and site when it is created and keeps its download URL and token in state.
+type InstallerResource struct {
+    client *ClientConfig
+}
+
+// InstallerResourceModel describes the resource data model.
+type InstallerResourceModel struct {
+    Id             types.String `tfsdk:"id"`
+    ClientId       types.Int64  `tfsdk:"client_id"`
+    SiteId         types.Int64  `tfsdk:"site_id"`
+    AgentType      types.String `tfsdk:"agent_type"`
+    Arch           types.String `tfsdk:"arch"`
+    ExpiresHours   types.Int64  `tfsdk:"expires_hours"`
+    Triggers       types.Map    `tfsdk:"triggers"`
+    DownloadURL    types.String `tfsdk:"download_url"`
+    InstallCommand types.String `tfsdk:"install_command"`
+    AuthToken      types.String `tfsdk:"auth_token"`
+    ExpiresAt      types.String `tfsdk:"expires_at"`
+}
+
+func (r *InstallerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_installer"
+}
+
+func (r *InstallerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Generates a one-off Windows agent installer for a client and site when created, like the manual method of the Install Agent dialog, " +
+            "and keeps its download URL, install command and token in state. Destroying this resource does nothing on the server; the token stays valid until it expires.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this installer",
+                Computed:            true,
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Client the agent is enrolled in",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Site of the client the agent is enrolled in",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "agent_type": schema.StringAttribute{
+                MarkdownDescription: "Agent type: `server` or `workstation`",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "arch": schema.StringAttribute{
+                MarkdownDescription: "Windows architecture: `amd64` (default), `386` or `arm64`",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "expires_hours": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Hours the installer token is valid for, defaults to %d", defaultInstallerExpiry),
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause a new installer to be generated when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "download_url": schema.StringAttribute{
+                MarkdownDescription: "URL the agent setup program is downloaded from",
+                Computed:            true,
+            },
+            "install_command": schema.StringAttribute{
+                MarkdownDescription: "Command that installs and enrolls the agent, run from the folder of the downloaded setup program. It contains the token.",
+                Computed:            true,
+                Sensitive:           true,
+            },
+            "auth_token": schema.StringAttribute{
+                MarkdownDescription: "Token the agent enrolls with, null when the install command does not contain one",
+                Computed:            true,
+                Sensitive:           true,
+            },
+            "expires_at": schema.StringAttribute{
+                MarkdownDescription: "RFC 3339 time the token expires, counted from when the installer was generated",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *InstallerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data InstallerResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.AgentType.IsNull() && !data.AgentType.IsUnknown() && !isOneOf(data.AgentType.ValueString(), installerAgentTypes) {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("agent_type"),
+            "Invalid Agent Type",
+            fmt.Sprintf("agent_type must be one of %s, got %q.", strings.Join(installerAgentTypes, ", "), data.AgentType.ValueString()),
+        )
+    }
+
+    if !data.Arch.IsNull() && !data.Arch.IsUnknown() && !isOneOf(data.Arch.ValueString(), installerArchitectures) {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("arch"),
+            "Invalid Installer Architecture",
+            fmt.Sprintf("arch must be one of %s, got %q.", strings.Join(installerArchitectures, ", "), data.Arch.ValueString()),
+        )
+    }
+
+    if !data.ExpiresHours.IsNull() && !data.ExpiresHours.IsUnknown() && data.ExpiresHours.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("expires_hours"),
+            "Invalid Installer Expiry",
+            "expires_hours must be at least 1 hour.",
+        )
+    }
+}
+
+func (r *InstallerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *InstallerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data InstallerResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    arch := "amd64"
+    if !data.Arch.IsNull() {
+        arch = data.Arch.ValueString()
+    }
+    expires := int64(defaultInstallerExpiry)
+    if !data.ExpiresHours.IsNull() {
+        expires = data.ExpiresHours.ValueInt64()
+    }
+
+    // The manual method returns the download URL and command instead of a
+    // file, and the agent connects to the same API the provider uses
+    body := map[string]interface{}{
+        "installMethod": "manual",
+        "client":        data.ClientId.ValueInt64(),
+        "site":          data.SiteId.ValueInt64(),
+        "agenttype":     data.AgentType.ValueString(),
+        "goarch":        arch,
+        "plat":          "windows",
+        "expires":       expires,
+        "api":           r.client.BaseURL,
+        "rdp":           0,
+        "ping":          0,
+        "power":         0,
+        "fileName":      "trmm-installer.exe",
+    }
+
+    generatedAt := time.Now().UTC()
+    statusCode, respBody, err := r.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/agents/installer/", r.client.BaseURL), body)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to generate installer, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to generate installer, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    var installer map[string]interface{}
+    if err := json.Unmarshal(respBody, &installer); err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return
+    }
+
+    command, _ := installer["cmd"].(string)
+    downloadURL, _ := installer["url"].(string)
+    if command == "" || downloadURL == "" {
+        resp.Diagnostics.AddError("Client Error", "The installer response has no install command or download URL.")
+        return
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.DownloadURL = types.StringValue(downloadURL)
+    data.InstallCommand = types.StringValue(command)
+    data.AuthToken = types.StringNull()
+    if token := installerToken(command); token != "" {
+        r.client.addSecret(token)
+        data.AuthToken = types.StringValue(token)
+    }
+    data.ExpiresAt = types.StringValue(generatedAt.Add(time.Duration(expires) * time.Hour).Format(time.RFC3339))
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *InstallerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // An installer is a one-off operation, there is no remote object to refresh
+}
+
+func (r *InstallerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data InstallerResourceModel
+    var state InstallerResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.DownloadURL = state.DownloadURL
+    data.InstallCommand = state.InstallCommand
+    data.AuthToken = state.AuthToken
+    data.ExpiresAt = state.ExpiresAt
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *InstallerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // The token cannot be revoked through the API, removing the resource only
+    // drops it from state
+}
+
+// installerToken returns the value of the --auth flag of an install command,
+// or "" when there is none.
+func installerToken(command string) string {
+    fields := strings.Fields(command)
+    for i, field := range fields {
+        if field == "--auth" && i+1 < len(fields) {
+            return fields[i+1]
+        }
+    }
+    return ""
+}
+
+// isOneOf reports whether value is one of allowed.
+func isOneOf(value string, allowed []string) bool {
+    for _, candidate := range allowed {
+        if value == candidate {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const installerCommand = `tacticalagent-v2.9.1-windows-amd64.exe /VERYSILENT /SUPPRESSMSGBOXES && ping 127.0.0.1 -n 5 && ` +
+    `"C:\Program Files\TacticalAgent\tacticalrmm.exe" -m install --api https://api.example.com --client-id 3 --site-id 12 ` +
+    `--agent-type workstation --auth 4f1e0c0b2d9a7e6f5c4b3a2918d7e6f5c4b3a291`
+
+func installerModel() *InstallerResourceModel {
+    return &InstallerResourceModel{
+        ClientId:     types.Int64Value(3),
+        SiteId:       types.Int64Value(12),
+        AgentType:    types.StringValue("workstation"),
+        Arch:         types.StringNull(),
+        ExpiresHours: types.Int64Value(8),
+        Triggers:     types.MapNull(types.StringType),
+    }
+}
+
+func TestInstallerResourceCreate(t *testing.T) {
+    var sent map[string]interface{}
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost || r.URL.Path != "/agents/installer/" {
+            http.NotFound(w, r)
+            return
+        }
+        if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+            t.Errorf("unable to decode request: %s", err)
+        }
+        writeJSON(t, w, map[string]interface{}{
+            "cmd": installerCommand,
+            "url": "https://agents.tacticalrmm.com/api/v2/agents?version=2.9.1&arch=amd64&token=abc&plat=windows&api=api.example.com",
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    r := &InstallerResource{client: newTestClient(server)}
+    before := time.Now().UTC()
+    resp := createResource(t, r, installerModel())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if sent["installMethod"] != "manual" || sent["plat"] != "windows" || sent["goarch"] != "amd64" || sent["agenttype"] != "workstation" ||
+        sent["client"] != float64(3) || sent["site"] != float64(12) || sent["expires"] != float64(8) || sent["api"] != server.URL {
+        t.Errorf("unexpected request body: %v", sent)
+    }
+
+    var state InstallerResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.InstallCommand.ValueString() != installerCommand || !strings.HasPrefix(state.DownloadURL.ValueString(), "https://agents.tacticalrmm.com/") {
+        t.Errorf("unexpected installer in state: %+v", state)
+    }
+    if state.AuthToken.ValueString() != "4f1e0c0b2d9a7e6f5c4b3a2918d7e6f5c4b3a291" {
+        t.Errorf("expected the token of the install command, got %v", state.AuthToken)
+    }
+
+    expiresAt, err := time.Parse(time.RFC3339, state.ExpiresAt.ValueString())
+    if err != nil || expiresAt.Before(before.Add(8*time.Hour).Truncate(time.Second)) || expiresAt.After(time.Now().UTC().Add(8*time.Hour)) {
+        t.Errorf("expected expires_at 8 hours from now, got %v", state.ExpiresAt)
+    }
+
+    // The token must not leak through later diagnostics
+    if redacted := r.client.redact("token " + state.AuthToken.ValueString()); strings.Contains(redacted, state.AuthToken.ValueString()) {
+        t.Errorf("expected the token to be redacted, got %q", redacted)
+    }
+}
+
+func TestInstallerResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &InstallerResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    tests := map[string]struct {
+        modify  func(m *InstallerResourceModel)
+        summary string
+    }{
+        "valid": {
+            modify: func(m *InstallerResourceModel) {
+                m.Arch = types.StringValue("arm64")
+            },
+        },
+        "agent type": {
+            modify: func(m *InstallerResourceModel) {
+                m.AgentType = types.StringValue("desktop")
+            },
+            summary: "Invalid Agent Type",
+        },
+        "arch": {
+            modify: func(m *InstallerResourceModel) {
+                m.Arch = types.StringValue("x86_64")
+            },
+            summary: "Invalid Installer Architecture",
+        },
+        "expiry": {
+            modify: func(m *InstallerResourceModel) {
+                m.ExpiresHours = types.Int64Value(0)
+            },
+            summary: "Invalid Installer Expiry",
+        },
+    }
+
+    for name, test := range tests {
+        model := installerModel()
+        test.modify(model)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if test.summary == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != test.summary {
+            t.Errorf("%s: expected a %q error, got: %v", name, test.summary, resp.Diagnostics)
+        }
+    }
+}
@@ -0,0 +1,283 @@
+package provider
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &InventoryDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &InventoryDataSource{}
+
+func NewInventoryDataSource() datasource.DataSource {
+    return &InventoryDataSource{}
+}
+
+// InventoryDataSource exports scripts, snippets and keystore entries as a
+// single JSON document.
+type InventoryDataSource struct {
+    client *ClientConfig
+}
+
+// InventoryDataSourceModel describes the data source data model.
+type InventoryDataSourceModel struct {
+    IncludeScriptBodies types.Bool   `tfsdk:"include_script_bodies"`
+    HashSecrets         types.Bool   `tfsdk:"hash_secrets"`
+    HashKey             types.String `tfsdk:"hash_key"`
+    Inventory           types.String `tfsdk:"inventory"`
+    ScriptCount         types.Int64  `tfsdk:"script_count"`
+    SnippetCount        types.Int64  `tfsdk:"snippet_count"`
+    KeyStoreCount       types.Int64  `tfsdk:"keystore_count"`
+}
+
+// inventoryScriptFields are the script attributes copied into the inventory.
+var inventoryScriptFields = []string{
+    "id", "name", "description", "shell", "category", "default_timeout", "run_as_user",
+    "args", "env_vars", "supported_platforms", "syntax", "hidden",
+}
+
+// inventorySnippetFields are the snippet attributes copied into the inventory.
+var inventorySnippetFields = []string{"id", "name", "desc", "shell", "code"}
+
+func (d *InventoryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_inventory"
+}
+
+func (d *InventoryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Inventory data source for Tactical RMM. Exports user defined scripts, script snippets and keystore entries as one JSON document for documentation or backup. " +
+            "Secret values are never included: keystore entries are exported by name and script environment variables by key, optionally with an HMAC-SHA256 of each value.",
+
+        Attributes: map[string]schema.Attribute{
+            "include_script_bodies": schema.BoolAttribute{
+                MarkdownDescription: "When true, includes the body of every script. This requires additional API calls per script.",
+                Optional:            true,
+            },
+            "hash_secrets": schema.BoolAttribute{
+                MarkdownDescription: "When true, includes an HMAC-SHA256 of each keystore value and script environment variable value, keyed with `hash_key`, so changes can be detected without exposing the values.",
+                Optional:            true,
+            },
+            "hash_key": schema.StringAttribute{
+                MarkdownDescription: "Secret key of the HMAC. Required with `hash_secrets`. Keep it stable to compare inventories, and out of the inventory's reach, since anyone holding it can test guesses against the hashes.",
+                Optional:            true,
+                Sensitive:           true,
+            },
+            "inventory": schema.StringAttribute{
+                MarkdownDescription: "JSON document with `scripts`, `snippets` and `keystores` arrays, each sorted by name",
+                Computed:            true,
+            },
+            "script_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of scripts in the inventory",
+                Computed:            true,
+            },
+            "snippet_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of snippets in the inventory",
+                Computed:            true,
+            },
+            "keystore_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of keystore entries in the inventory",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *InventoryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *InventoryDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+    var data InventoryDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.HashSecrets.ValueBool() && data.HashKey.IsNull() {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("hash_key"),
+            "Missing Hash Key",
+            "hash_key is required with hash_secrets = true. Unkeyed hashes of short secrets can be reversed by guessing.",
+        )
+    }
+    if !data.HashKey.IsNull() && !data.HashKey.IsUnknown() && data.HashKey.ValueString() == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("hash_key"), "Missing Hash Key", "hash_key must not be empty.")
+    }
+}
+
+func (d *InventoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data InventoryDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // hash_key may only have been known now, so it is checked again
+    hashSecrets := data.HashSecrets.ValueBool()
+    if hashSecrets && data.HashKey.ValueString() == "" {
+        resp.Diagnostics.AddAttributeError(path.Root("hash_key"), "Missing Hash Key", "hash_key is required with hash_secrets = true.")
+        return
+    }
+    hash := func(value string) string {
+        mac := hmac.New(sha256.New, []byte(data.HashKey.ValueString()))
+        mac.Write([]byte(value))
+        return hex.EncodeToString(mac.Sum(nil))
+    }
+
+    scripts, err := d.client.listObjects(ctx, d.client.allScriptsURL())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return
+    }
+
+    snippets, err := d.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
+        return
+    }
+
+    keystores, err := d.client.listObjects(ctx, fmt.Sprintf("%s/core/keystore/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list keystore entries, got error: %s", err))
+        return
+    }
+
+    // Builtin scripts ship with Tactical RMM and are not part of the managed configuration
+    ids := userDefinedScriptIds(scripts)
+    userDefined := make(map[int64]bool, len(ids))
+    for _, id := range ids {
+        userDefined[id] = true
+    }
+
+    var details map[int64]map[string]interface{}
+    if data.IncludeScriptBodies.ValueBool() {
+        var failures map[int64]error
+        details, failures = d.client.fetchScriptDetails(ctx, ids)
+        // Scripts whose body could not be fetched are exported without it
+        for _, id := range ids {
+            if err, failed := failures[id]; failed {
+                addItemFetchWarning(&resp.Diagnostics, fmt.Sprintf("the body of script ID %d", id), err)
+            }
+        }
+    }
+
+    scriptItems := []map[string]interface{}{}
+    for _, script := range scripts {
+        id, ok := script["id"].(float64)
+        if !ok || !userDefined[int64(id)] {
+            continue
+        }
+        item := pickFields(script, inventoryScriptFields)
+        if envVars, ok := item["env_vars"].([]interface{}); ok {
+            item["env_vars"] = inventoryEnvVars(envVars, hashSecrets, hash)
+        }
+        if details != nil {
+            item["script_body"] = details[int64(id)]["script_body"]
+        }
+        scriptItems = append(scriptItems, item)
+    }
+
+    snippetItems := []map[string]interface{}{}
+    for _, snippet := range snippets {
+        snippetItems = append(snippetItems, pickFields(snippet, inventorySnippetFields))
+    }
+
+    // Keystore values are secrets; only names (and optionally hashes) are exported
+    keyStoreItems := []map[string]interface{}{}
+    for _, keystore := range keystores {
+        item := pickFields(keystore, []string{"id", "name"})
+        if hashSecrets {
+            value, _ := keystore["value"].(string)
+            item["value_hmac_sha256"] = hash(value)
+        }
+        keyStoreItems = append(keyStoreItems, item)
+    }
+
+    for _, items := range [][]map[string]interface{}{scriptItems, snippetItems, keyStoreItems} {
+        sortByName(items)
+    }
+
+    inventory, err := json.MarshalIndent(map[string]interface{}{
+        "scripts":   scriptItems,
+        "snippets":  snippetItems,
+        "keystores": keyStoreItems,
+    }, "", "  ")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to encode inventory, got error: %s", err))
+        return
+    }
+
+    data.Inventory = types.StringValue(string(inventory))
+    data.ScriptCount = types.Int64Value(int64(len(scriptItems)))
+    data.SnippetCount = types.Int64Value(int64(len(snippetItems)))
+    data.KeyStoreCount = types.Int64Value(int64(len(keyStoreItems)))
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// inventoryEnvVars returns the keys of KEY=value environment entries, which
+// often hold credentials. With hashSecrets the value is replaced by its hash
+// instead, as KEY=hmac-sha256:<hex>.
+func inventoryEnvVars(envVars []interface{}, hashSecrets bool, hash func(string) string) []string {
+    exported := make([]string, 0, len(envVars))
+    for _, envVar := range envVars {
+        entry, ok := envVar.(string)
+        if !ok {
+            continue
+        }
+        key, value, _ := strings.Cut(entry, "=")
+        if hashSecrets {
+            key = fmt.Sprintf("%s=hmac-sha256:%s", key, hash(value))
+        }
+        exported = append(exported, key)
+    }
+    return exported
+}
+
+// pickFields copies the listed keys that are present in object.
+func pickFields(object map[string]interface{}, fields []string) map[string]interface{} {
+    picked := make(map[string]interface{}, len(fields))
+    for _, field := range fields {
+        if value, ok := object[field]; ok {
+            picked[field] = value
+        }
+    }
+    return picked
+}
+
+// sortByName orders objects by their name attribute for a stable document.
+func sortByName(items []map[string]interface{}) {
+    sort.SliceStable(items, func(i, j int) bool {
+        a, _ := items[i]["name"].(string)
+        b, _ := items[j]["name"].(string)
+        return a < b
+    })
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func inventoryServer(t *testing.T) *httptest.Server {
+    t.Helper()
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/scripts/":
+            scripts := []map[string]interface{}{
+                {"id": 2, "name": "Zeta", "shell": "powershell", "script_type": "userdefined", "env_vars": []string{"API_TOKEN=abc123", "MODE"}},
+                {"id": 1, "name": "Alpha", "shell": "python", "script_type": "userdefined"},
+                {"id": 900, "name": "Builtin", "shell": "powershell", "script_type": "builtin"},
+            }
+            // Hidden scripts are only listed when asked for
+            if r.URL.Query().Get("showHiddenScripts") == "true" {
+                scripts = append(scripts, map[string]interface{}{"id": 3, "name": "Zulu", "shell": "cmd", "script_type": "userdefined", "hidden": true})
+            }
+            writeJSON(t, w, scripts)
+        case "/scripts/1/":
+            writeJSON(t, w, map[string]interface{}{"id": 1, "name": "Alpha", "script_body": "print('alpha')"})
+        case "/scripts/2/":
+            writeJSON(t, w, map[string]interface{}{"id": 2, "name": "Zeta", "script_body": "Write-Output 'zeta'"})
+        case "/scripts/3/":
+            writeJSON(t, w, map[string]interface{}{"id": 3, "name": "Zulu", "script_body": "echo zulu"})
+        case "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 7, "name": "GetDiskSpace", "desc": "Disk", "shell": "powershell", "code": "Get-PSDrive"},
+            })
+        case "/core/keystore/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 3, "name": "api_token", "value": "super-secret"},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server
+}
+
+// inventoryHashKey is the hash_key used when readInventory hashes secrets.
+const inventoryHashKey = "inventory-test-key"
+
+func readInventory(t *testing.T, includeBodies bool, hashSecrets bool) (InventoryDataSourceModel, map[string][]map[string]interface{}) {
+    t.Helper()
+    d := &InventoryDataSource{client: newTestClient(inventoryServer(t))}
+
+    hashKey := types.StringNull()
+    if hashSecrets {
+        hashKey = types.StringValue(inventoryHashKey)
+    }
+    resp := readDataSource(t, d, &InventoryDataSourceModel{
+        IncludeScriptBodies: types.BoolValue(includeBodies),
+        HashSecrets:         types.BoolValue(hashSecrets),
+        HashKey:             hashKey,
+        Inventory:           types.StringNull(),
+        ScriptCount:         types.Int64Null(),
+        SnippetCount:        types.Int64Null(),
+        KeyStoreCount:       types.Int64Null(),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state InventoryDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var inventory map[string][]map[string]interface{}
+    if err := json.Unmarshal([]byte(state.Inventory.ValueString()), &inventory); err != nil {
+        t.Fatalf("inventory is not valid JSON: %s", err)
+    }
+    return state, inventory
+}
+
+func TestInventoryDataSourceRead_AggregatesConfiguration(t *testing.T) {
+    state, inventory := readInventory(t, true, false)
+
+    if state.ScriptCount.ValueInt64() != 3 || state.SnippetCount.ValueInt64() != 1 || state.KeyStoreCount.ValueInt64() != 1 {
+        t.Errorf("unexpected counts: scripts=%d snippets=%d keystores=%d",
+            state.ScriptCount.ValueInt64(), state.SnippetCount.ValueInt64(), state.KeyStoreCount.ValueInt64())
+    }
+
+    scripts := inventory["scripts"]
+    if len(scripts) != 3 || scripts[0]["name"] != "Alpha" || scripts[1]["name"] != "Zeta" || scripts[2]["name"] != "Zulu" {
+        t.Fatalf("expected user defined scripts, including hidden ones, sorted by name, got %v", scripts)
+    }
+    if scripts[0]["script_body"] != "print('alpha')" {
+        t.Errorf("expected script body to be included, got %v", scripts[0]["script_body"])
+    }
+    if scripts[2]["hidden"] != true || scripts[2]["script_body"] != "echo zulu" {
+        t.Errorf("expected the hidden script with its body, got %v", scripts[2])
+    }
+    if _, ok := scripts[0]["script_type"]; ok {
+        t.Errorf("unexpected script_type in inventory: %v", scripts[0])
+    }
+
+    snippets := inventory["snippets"]
+    if len(snippets) != 1 || snippets[0]["code"] != "Get-PSDrive" {
+        t.Errorf("unexpected snippets: %v", snippets)
+    }
+
+    keystores := inventory["keystores"]
+    if len(keystores) != 1 || keystores[0]["name"] != "api_token" {
+        t.Fatalf("unexpected keystores: %v", keystores)
+    }
+    if _, ok := keystores[0]["value_hmac_sha256"]; ok {
+        t.Errorf("expected no hash unless hash_secrets is set, got %v", keystores[0])
+    }
+    if strings.Contains(state.Inventory.ValueString(), "super-secret") {
+        t.Error("inventory must not contain keystore values")
+    }
+
+    // Environment variables are exported by key only
+    if envVars := scripts[1]["env_vars"]; !reflect.DeepEqual(envVars, []interface{}{"API_TOKEN", "MODE"}) {
+        t.Errorf("expected only the environment variable keys, got %v", envVars)
+    }
+    if strings.Contains(state.Inventory.ValueString(), "abc123") {
+        t.Error("inventory must not contain environment variable values")
+    }
+}
+
+func TestInventoryDataSourceRead_HashesSecrets(t *testing.T) {
+    state, inventory := readInventory(t, false, true)
+
+    keystore := inventory["keystores"][0]
+    if keystore["value_hmac_sha256"] != inventoryHMAC("super-secret") {
+        t.Errorf("expected the keyed value hash, got %v", keystore["value_hmac_sha256"])
+    }
+    // A plain hash of a short secret can be reversed by guessing
+    unkeyed := sha256.Sum256([]byte("super-secret"))
+    if strings.Contains(state.Inventory.ValueString(), hex.EncodeToString(unkeyed[:])) {
+        t.Error("inventory must not contain unkeyed hashes")
+    }
+    if _, ok := keystore["value"]; ok {
+        t.Errorf("expected value to be excluded, got %v", keystore)
+    }
+    if _, ok := inventory["scripts"][0]["script_body"]; ok {
+        t.Errorf("expected no script bodies unless requested, got %v", inventory["scripts"][0])
+    }
+    if strings.Contains(state.Inventory.ValueString(), "super-secret") {
+        t.Error("inventory must not contain keystore values")
+    }
+
+    expected := []interface{}{"API_TOKEN=hmac-sha256:" + inventoryHMAC("abc123"), "MODE=hmac-sha256:" + inventoryHMAC("")}
+    if envVars := inventory["scripts"][1]["env_vars"]; !reflect.DeepEqual(envVars, expected) {
+        t.Errorf("unexpected environment variables:\n got: %v\nwant: %v", envVars, expected)
+    }
+    if strings.Contains(state.Inventory.ValueString(), "abc123") {
+        t.Error("inventory must not contain environment variable values")
+    }
+}
+
+func TestInventoryDataSourceValidateConfig_RequiresHashKey(t *testing.T) {
+    ctx := context.Background()
+    d := &InventoryDataSource{}
+    var schemaResp datasource.SchemaResponse
+    d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+    cases := map[string]struct {
+        hashSecrets bool
+        hashKey     types.String
+        err         bool
+    }{
+        "no hashing":  {hashKey: types.StringNull()},
+        "keyed":       {hashSecrets: true, hashKey: types.StringValue("k")},
+        "unknown key": {hashSecrets: true, hashKey: types.StringUnknown()},
+        "missing key": {hashSecrets: true, hashKey: types.StringNull(), err: true},
+        "empty key":   {hashSecrets: true, hashKey: types.StringValue(""), err: true},
+    }
+
+    for name, tc := range cases {
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, &InventoryDataSourceModel{
+            IncludeScriptBodies: types.BoolNull(),
+            HashSecrets:         types.BoolValue(tc.hashSecrets),
+            HashKey:             tc.hashKey,
+            Inventory:           types.StringNull(),
+            ScriptCount:         types.Int64Null(),
+            SnippetCount:        types.Int64Null(),
+            KeyStoreCount:       types.Int64Null(),
+        }); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp datasource.ValidateConfigResponse
+        d.ValidateConfig(ctx, datasource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if resp.Diagnostics.HasError() != tc.err {
+            t.Errorf("%s: expected error %t, got %v", name, tc.err, resp.Diagnostics)
+        }
+    }
+}
+
+// inventoryHMAC returns the hash of value with inventoryHashKey.
+func inventoryHMAC(value string) string {
+    mac := hmac.New(sha256.New, []byte(inventoryHashKey))
+    mac.Write([]byte(value))
+    return hex.EncodeToString(mac.Sum(nil))
+}
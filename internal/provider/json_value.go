@@ -0,0 +1,43 @@
+package provider
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "strings"
+)
+
+// canonicalJSON returns the minified form of a JSON document with object keys
+// sorted, so documents that only differ in whitespace or key order compare
+// equal. Numbers are kept as written.
+func canonicalJSON(value string) (string, error) {
+    decoder := json.NewDecoder(strings.NewReader(value))
+    decoder.UseNumber()
+
+    var document interface{}
+    if err := decoder.Decode(&document); err != nil {
+        return "", err
+    }
+    if _, err := decoder.Token(); err != io.EOF {
+        return "", fmt.Errorf("unexpected data after the JSON document")
+    }
+
+    var buf bytes.Buffer
+    encoder := json.NewEncoder(&buf)
+    encoder.SetEscapeHTML(false)
+    if err := encoder.Encode(document); err != nil {
+        return "", err
+    }
+    return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// jsonEqual reports whether two strings are semantically equal JSON documents.
+func jsonEqual(a string, b string) bool {
+    canonicalA, err := canonicalJSON(a)
+    if err != nil {
+        return false
+    }
+    canonicalB, err := canonicalJSON(b)
+    return err == nil && canonicalA == canonicalB
+}
@@ -0,0 +1,15 @@
+package provider
+
+import (
+    "testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+    got, err := canonicalJSON("{\n  \"b\": [1, 2.50, {\"y\": null, \"x\": \"<a&b>\"}],\n  \"a\": 12345678901234567890\n}")
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+    if want := `{"a":12345678901234567890,"b":[1,2.50,{"x":"<a&b>","y":null}]}`; got != want {
+        t.Errorf("expected %s, got %s", want, got)
+    }
+}
@@ -76,6 +76,8 @@ func (d *KeyStoreDataSource) Configure(ctx context.Context, req datasource.Confi
 }
 
 func (d *KeyStoreDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
     var data KeyStoreDataSourceModel
 
     resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -154,6 +156,7 @@ func (d *KeyStoreDataSource) Read(ctx context.Context, req datasource.ReadReques
         data.Name = types.StringValue(name)
     }
     if value, ok := foundEntry["value"].(string); ok {
+        d.client.addSecret(value)
         data.Value = types.StringValue(value)
     }
 
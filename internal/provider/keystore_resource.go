@@ -8,16 +8,20 @@ import (
     "io"
     "net/http"
     "strconv"
+    "strings"
 
     "github.com/hashicorp/terraform-plugin-framework/path"
     "github.com/hashicorp/terraform-plugin-framework/resource"
     "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/schema/validator"
     "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &KeyStoreResource{}
 var _ resource.ResourceWithImportState = &KeyStoreResource{}
+var _ resource.ResourceWithValidateConfig = &KeyStoreResource{}
+var _ resource.ResourceWithIdentity = &KeyStoreResource{}
 
 func NewKeyStoreResource() resource.Resource {
     return &KeyStoreResource{}
@@ -30,9 +34,10 @@ type KeyStoreResource struct {
 
 // KeyStoreResourceModel describes the resource data model based on GlobalKVStore Django model
 type KeyStoreResourceModel struct {
-    Id    types.Int64  `tfsdk:"id"`
-    Name  types.String `tfsdk:"name"`
-    Value types.String `tfsdk:"value"`
+    Id        types.Int64  `tfsdk:"id"`
+    Name      types.String `tfsdk:"name"`
+    Value     types.String `tfsdk:"value"`
+    ValueJSON types.String `tfsdk:"value_json"`
 }
 
 func (r *KeyStoreResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -53,14 +58,28 @@ func (r *KeyStoreResource) Schema(ctx context.Context, req resource.SchemaReques
                 Required:            true,
             },
             "value": schema.StringAttribute{
-                MarkdownDescription: "Key value",
-                Required:            true,
+                MarkdownDescription: "Key value. Multi-line values such as PEM keys are stored as is, without a length limit. " +
+                    "Exactly one of `value` or `value_json` must be specified.",
+                Optional:            true,
                 Sensitive:           true,
             },
+            "value_json": schema.StringAttribute{
+                MarkdownDescription: "Key value as a JSON document, e.g. from `jsonencode()`. It is stored minified with sorted keys, " +
+                    "so changes in whitespace or key order update the entry with the same stored value. Exactly one of `value` or `value_json` must be specified.",
+                Optional:  true,
+                Sensitive: true,
+                Validators: []validator.String{
+                    jsonValueValidator{},
+                },
+            },
         },
     }
 }
 
+func (r *KeyStoreResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = idIdentitySchema("Keystore entry")
+}
+
 func (r *KeyStoreResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
     if req.ProviderData == nil {
         return
@@ -78,18 +97,70 @@ func (r *KeyStoreResource) Configure(ctx context.Context, req resource.Configure
     r.client = client
 }
 
+func (r *KeyStoreResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data KeyStoreResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.Value.IsUnknown() || data.ValueJSON.IsUnknown() {
+        return
+    }
+
+    if data.Value.IsNull() == data.ValueJSON.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid KeyStore Value",
+            "Exactly one of 'value' or 'value_json' must be specified.",
+        )
+    }
+}
+
 func (r *KeyStoreResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data KeyStoreResourceModel
 
     resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
     if resp.Diagnostics.HasError() {
         return
     }
+    value, err := data.apiValue()
+    if err != nil {
+        resp.Diagnostics.AddAttributeError(path.Root("value_json"), "Invalid JSON Value", fmt.Sprintf("The value is not valid JSON: %s.", err))
+        return
+    }
+    r.client.addSecret(data.Value.ValueString())
+    r.client.addSecret(data.ValueJSON.ValueString())
+    r.client.addSecret(value)
+
+    // Keystore names are unique, so without on_conflict an existing entry
+    // fails the create below
+    listURL := fmt.Sprintf("%s/core/keystore/", r.client.BaseURL)
+    var adoptedId int64
+    if r.client.resolvesConflicts() {
+        entries, err := r.client.listObjects(ctx, listURL)
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list keystore entries, got error: %s", err))
+            return
+        }
+        adoptedId = r.client.adoptOrReplace(ctx, "keystore entry", objectsNamed(entries, data.Name.ValueString()), func(id int64) string {
+            return fmt.Sprintf("%s/core/keystore/%d/", r.client.BaseURL, id)
+        }, &resp.Diagnostics)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+    }
 
     // Create API request body
     body := map[string]interface{}{
         "name":  data.Name.ValueString(),
-        "value": data.Value.ValueString(),
+        "value": value,
     }
 
     jsonBody, err := json.Marshal(body)
@@ -98,8 +169,14 @@ func (r *KeyStoreResource) Create(ctx context.Context, req resource.CreateReques
         return
     }
 
+    // An adopted entry is updated in place
+    method, requestURL := "POST", listURL
+    if adoptedId != 0 {
+        method, requestURL = "PUT", fmt.Sprintf("%s/core/keystore/%d/", r.client.BaseURL, adoptedId)
+    }
+
     // Create HTTP request
-    httpReq, err := http.NewRequest("POST", fmt.Sprintf("%s/core/keystore/", r.client.BaseURL), bytes.NewBuffer(jsonBody))
+    httpReq, err := http.NewRequest(method, requestURL, bytes.NewBuffer(jsonBody))
     if err != nil {
         resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create keystore entry, got error: %s", err))
         return
@@ -114,42 +191,24 @@ func (r *KeyStoreResource) Create(ctx context.Context, req resource.CreateReques
     defer httpResp.Body.Close()
 
     if httpResp.StatusCode != http.StatusOK {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create keystore entry, status code: %d", httpResp.StatusCode))
+        respBody, _ := io.ReadAll(httpResp.Body)
+        if !addAPIFieldErrors(&resp.Diagnostics, "Unable to create keystore entry", httpResp.StatusCode, respBody, keyStoreFieldPaths(data)) {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create keystore entry, status code: %d", httpResp.StatusCode))
+        }
         return
     }
 
-    // Response is just "ok", so we need to get the created entry
-    // List all keystore entries to find our newly created one
-    listReq, err := http.NewRequest("GET", fmt.Sprintf("%s/core/keystore/", r.client.BaseURL), nil)
-    if err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list keystore entries, got error: %s", err))
+    if adoptedId != 0 {
+        data.Id = types.Int64Value(adoptedId)
+        resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+        setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
         return
     }
 
-    listResp, err := r.client.Do(listReq)
+    // Response is just "ok", so we need to find the created entry by name
+    createdEntry, err := r.client.findCreatedByName(ctx, listURL, data.Name.ValueString())
     if err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list keystore entries, got error: %s", err))
-        return
-    }
-    defer listResp.Body.Close()
-
-    var entries []map[string]interface{}
-    if err := json.NewDecoder(listResp.Body).Decode(&entries); err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse keystore entries list, got error: %s", err))
-        return
-    }
-
-    // Find the entry we just created by name
-    var createdEntry map[string]interface{}
-    for _, entry := range entries {
-        if name, ok := entry["name"].(string); ok && name == data.Name.ValueString() {
-            createdEntry = entry
-            break
-        }
-    }
-
-    if createdEntry == nil {
-        resp.Diagnostics.AddError("Client Error", "Unable to find created keystore entry")
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find created keystore entry, got error: %s", err))
         return
     }
 
@@ -159,9 +218,12 @@ func (r *KeyStoreResource) Create(ctx context.Context, req resource.CreateReques
     }
 
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 }
 
 func (r *KeyStoreResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
     var data KeyStoreResourceModel
 
     resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -169,6 +231,10 @@ func (r *KeyStoreResource) Read(ctx context.Context, req resource.ReadRequest, r
         return
     }
 
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
+
     // Get all keystore entries since there's no individual GET endpoint
     httpReq, err := http.NewRequest("GET", fmt.Sprintf("%s/core/keystore/", r.client.BaseURL), nil)
     if err != nil {
@@ -184,6 +250,8 @@ func (r *KeyStoreResource) Read(ctx context.Context, req resource.ReadRequest, r
     }
     defer httpResp.Body.Close()
 
+    // recreate_on_read_errors does not apply: a failed list says nothing
+    // about this entry, and removing it would drop every entry from state
     if httpResp.StatusCode != http.StatusOK {
         resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read keystore entries, status code: %d", httpResp.StatusCode))
         return
@@ -205,7 +273,8 @@ func (r *KeyStoreResource) Read(ctx context.Context, req resource.ReadRequest, r
                 data.Name = types.StringValue(name)
             }
             if value, ok := entry["value"].(string); ok {
-                data.Value = types.StringValue(value)
+                r.client.addSecret(value)
+                data.setValue(value)
             }
             break
         }
@@ -220,6 +289,12 @@ func (r *KeyStoreResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *KeyStoreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data KeyStoreResourceModel
     var state KeyStoreResourceModel
 
@@ -237,11 +312,19 @@ func (r *KeyStoreResource) Update(ctx context.Context, req resource.UpdateReques
 
     // Use the ID from the current state
     data.Id = state.Id
+    value, err := data.apiValue()
+    if err != nil {
+        resp.Diagnostics.AddAttributeError(path.Root("value_json"), "Invalid JSON Value", fmt.Sprintf("The value is not valid JSON: %s.", err))
+        return
+    }
+    r.client.addSecret(data.Value.ValueString())
+    r.client.addSecret(data.ValueJSON.ValueString())
+    r.client.addSecret(value)
 
     // Create API request body
     body := map[string]interface{}{
         "name":  data.Name.ValueString(),
-        "value": data.Value.ValueString(),
+        "value": value,
     }
 
     jsonBody, err := json.Marshal(body)
@@ -269,6 +352,9 @@ func (r *KeyStoreResource) Update(ctx context.Context, req resource.UpdateReques
     if httpResp.StatusCode != http.StatusOK {
         // Read the response body for more details
         bodyBytes, _ := io.ReadAll(httpResp.Body)
+        if addAPIFieldErrors(&resp.Diagnostics, "Unable to update keystore entry", httpResp.StatusCode, bodyBytes, keyStoreFieldPaths(data)) {
+            return
+        }
         resp.Diagnostics.AddError("Client Error", 
             fmt.Sprintf("Unable to update keystore entry ID %d, status code: %d, URL: %s, response: %s", 
                 data.Id.ValueInt64(), httpResp.StatusCode, updateURL, string(bodyBytes)))
@@ -276,9 +362,16 @@ func (r *KeyStoreResource) Update(ctx context.Context, req resource.UpdateReques
     }
 
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 }
 
 func (r *KeyStoreResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data KeyStoreResourceModel
 
     resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -302,18 +395,89 @@ func (r *KeyStoreResource) Delete(ctx context.Context, req resource.DeleteReques
     defer httpResp.Body.Close()
 
     if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete keystore entry, status code: %d", httpResp.StatusCode))
+        addDeleteError(&resp.Diagnostics, "keystore entry", httpResp)
         return
     }
 }
 
+// apiValue returns the value sent to the API: value as is, or the canonical
+// form of value_json.
+func (m *KeyStoreResourceModel) apiValue() (string, error) {
+    if !m.ValueJSON.IsNull() {
+        return canonicalJSON(m.ValueJSON.ValueString())
+    }
+    return m.Value.ValueString(), nil
+}
+
+// setValue updates the model from the stored value. A value_json document is
+// kept as written while the stored value is the same JSON, so only real
+// changes show up as a difference. TRMM strips leading and trailing
+// whitespace from stored values, so a value is also kept while it only
+// differs from the stored one by that whitespace, e.g. the final newline of
+// a PEM key read from a file or heredoc.
+func (m *KeyStoreResourceModel) setValue(value string) {
+    if m.ValueJSON.IsNull() {
+        if m.Value.IsNull() || strings.TrimSpace(m.Value.ValueString()) != value {
+            m.Value = types.StringValue(value)
+        }
+        return
+    }
+
+    if !jsonEqual(m.ValueJSON.ValueString(), value) {
+        m.ValueJSON = types.StringValue(value)
+    }
+}
+
 func (r *KeyStoreResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-    // Convert string ID to int64
-    id, err := strconv.ParseInt(req.ID, 10, 64)
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    // Import blocks generated by terraform query use the identity
+    if id, ok := identityImportID(ctx, req, &resp.Diagnostics); ok {
+        resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+        return
+    }
+
+    // Numeric IDs are imported as is, anything else is a keystore name
+    if id, err := strconv.ParseInt(req.ID, 10, 64); err == nil {
+        resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+        return
+    }
+
+    entries, err := r.client.listObjects(ctx, fmt.Sprintf("%s/core/keystore/", r.client.BaseURL))
     if err != nil {
-        resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to parse ID: %s", err))
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read keystore entries, got error: %s", err))
         return
     }
-    
-    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+
+    var matches []map[string]interface{}
+    for _, entry := range entries {
+        if name, ok := entry["name"].(string); ok && name == req.ID {
+            matches = append(matches, entry)
+        }
+    }
+
+    if len(matches) == 0 {
+        resp.Diagnostics.AddError("KeyStore Entry Not Found", fmt.Sprintf("KeyStore entry with name '%s' not found", req.ID))
+        return
+    }
+    if len(matches) > 1 {
+        resp.Diagnostics.AddError(
+            "Ambiguous KeyStore Name",
+            fmt.Sprintf("%d keystore entries are named '%s'. Import the entry by its numeric ID instead.", len(matches), req.ID),
+        )
+        return
+    }
+
+    entry := matches[0]
+    id, ok := entry["id"].(float64)
+    if !ok {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("KeyStore entry '%s' has no ID", req.ID))
+        return
+    }
+    value, _ := entry["value"].(string)
+    r.client.addSecret(value)
+
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), int64(id))...)
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value"), value)...)
 }
@@ -0,0 +1,220 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &KeyStoreUsageDataSource{}
+
+func NewKeyStoreUsageDataSource() datasource.DataSource {
+    return &KeyStoreUsageDataSource{}
+}
+
+// KeyStoreUsageDataSource reports which scripts reference a keystore entry.
+type KeyStoreUsageDataSource struct {
+    client *ClientConfig
+}
+
+// KeyStoreUsageDataSourceModel describes the data source data model.
+type KeyStoreUsageDataSourceModel struct {
+    Name     types.String `tfsdk:"name"`
+    Shell    types.String `tfsdk:"shell"`
+    Category types.String `tfsdk:"category"`
+    Token    types.String `tfsdk:"token"`
+    Scripts  types.List   `tfsdk:"scripts"`
+}
+
+// keyStoreReferenceAttrTypes describes a script referencing a keystore entry.
+var keyStoreReferenceAttrTypes = map[string]attr.Type{
+    "id":        types.Int64Type,
+    "name":      types.StringType,
+    "locations": types.ListType{ElemType: types.StringType},
+    "lines":     types.ListType{ElemType: types.Int64Type},
+}
+
+// keyStoreToken returns the placeholder scripts use to reference a keystore entry.
+func keyStoreToken(name string) string {
+    return "{{global." + name + "}}"
+}
+
+func (d *KeyStoreUsageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_keystore_usage"
+}
+
+func (d *KeyStoreUsageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Keystore usage data source for Tactical RMM. Lists the user defined scripts whose arguments, environment variables or body reference a keystore entry as `{{global.name}}`. " +
+            "Script details are not part of the scripts list, so one additional API call is made per matching script.",
+
+        Attributes: map[string]schema.Attribute{
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Name of the keystore entry",
+                Required:            true,
+            },
+            "shell": schema.StringAttribute{
+                MarkdownDescription: "Optional: Only scan scripts with this shell type.",
+                Optional:            true,
+            },
+            "category": schema.StringAttribute{
+                MarkdownDescription: "Optional: Only scan scripts in this category.",
+                Optional:            true,
+            },
+            "token": schema.StringAttribute{
+                MarkdownDescription: "Reference searched for in scripts, e.g. `{{global.api_token}}`",
+                Computed:            true,
+            },
+            "scripts": schema.ListNestedAttribute{
+                MarkdownDescription: "Scripts referencing the keystore entry",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Script identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Script name",
+                            Computed:            true,
+                        },
+                        "locations": schema.ListAttribute{
+                            MarkdownDescription: "Where the reference was found: args, env_vars and/or body",
+                            Computed:            true,
+                            ElementType:         types.StringType,
+                        },
+                        "lines": schema.ListAttribute{
+                            MarkdownDescription: "Line numbers (starting at 1) of the script body that reference the entry",
+                            Computed:            true,
+                            ElementType:         types.Int64Type,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *KeyStoreUsageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *KeyStoreUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data KeyStoreUsageDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    token := keyStoreToken(data.Name.ValueString())
+    data.Token = types.StringValue(token)
+
+    scripts, err := d.client.listObjects(ctx, d.client.allScriptsURL())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return
+    }
+
+    // Apply the filters before fetching details to keep the number of requests down
+    var filtered []map[string]interface{}
+    for _, script := range scripts {
+        if !data.Shell.IsNull() {
+            if shell, ok := script["shell"].(string); !ok || shell != data.Shell.ValueString() {
+                continue
+            }
+        }
+        if !data.Category.IsNull() {
+            if category, ok := script["category"].(string); !ok || category != data.Category.ValueString() {
+                continue
+            }
+        }
+        filtered = append(filtered, script)
+    }
+
+    ids := userDefinedScriptIds(filtered)
+    details, failures := d.client.fetchScriptDetails(ctx, ids)
+
+    references := []attr.Value{}
+    for _, id := range ids {
+        if err, failed := failures[id]; failed {
+            addItemFetchWarning(&resp.Diagnostics, fmt.Sprintf("script ID %d", id), err)
+            continue
+        }
+
+        detail := details[id]
+        var locations []string
+        if stringsContain(detail["args"], token) {
+            locations = append(locations, "args")
+        }
+        if stringsContain(detail["env_vars"], token) {
+            locations = append(locations, "env_vars")
+        }
+        body, _ := detail["script_body"].(string)
+        lines := referenceLines(body, token)
+        if len(lines) > 0 {
+            locations = append(locations, "body")
+        }
+        if len(locations) == 0 {
+            continue
+        }
+        if lines == nil {
+            lines = []int64{}
+        }
+
+        name, _ := detail["name"].(string)
+        locationsValue, diags := types.ListValueFrom(ctx, types.StringType, locations)
+        resp.Diagnostics.Append(diags...)
+        linesValue, diags := types.ListValueFrom(ctx, types.Int64Type, lines)
+        resp.Diagnostics.Append(diags...)
+        reference, diags := types.ObjectValue(keyStoreReferenceAttrTypes, map[string]attr.Value{
+            "id":        types.Int64Value(id),
+            "name":      types.StringValue(name),
+            "locations": locationsValue,
+            "lines":     linesValue,
+        })
+        resp.Diagnostics.Append(diags...)
+        references = append(references, reference)
+    }
+
+    scriptsValue, diags := types.ListValue(types.ObjectType{AttrTypes: keyStoreReferenceAttrTypes}, references)
+    resp.Diagnostics.Append(diags...)
+    data.Scripts = scriptsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// stringsContain reports whether any string in a decoded JSON array contains token.
+func stringsContain(values interface{}, token string) bool {
+    items, ok := values.([]interface{})
+    if !ok {
+        return false
+    }
+    for _, item := range items {
+        if str, ok := item.(string); ok && strings.Contains(str, token) {
+            return true
+        }
+    }
+    return false
+}
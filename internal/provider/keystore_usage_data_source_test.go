@@ -0,0 +1,112 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// keyStoreUsageServer serves a fixed set of scripts referencing the api_token
+// keystore entry in different places and counts script detail requests.
+func keyStoreUsageServer(t *testing.T) (*httptest.Server, *int32) {
+    t.Helper()
+    var detailCalls int32
+
+    scripts := map[int]map[string]interface{}{
+        1: {"name": "Args", "shell": "powershell", "args": []string{"-Token", "{{global.api_token}}"}, "env_vars": []string{}, "script_body": "Write-Output $Token"},
+        2: {"name": "Env", "shell": "python", "args": []string{}, "env_vars": []string{"TOKEN={{global.api_token}}"}, "script_body": "print('hi')\n# {{global.api_token}}"},
+        3: {"name": "Other", "shell": "powershell", "args": []string{"{{global.api_token_v2}}"}, "env_vars": []string{}, "script_body": ""},
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path == "/scripts/" {
+            list := []map[string]interface{}{}
+            for id := 1; id <= len(scripts); id++ {
+                list = append(list, map[string]interface{}{"id": id, "name": scripts[id]["name"], "shell": scripts[id]["shell"], "script_type": "userdefined"})
+            }
+            writeJSON(t, w, list)
+            return
+        }
+
+        var id int
+        if _, err := fmt.Sscanf(r.URL.Path, "/scripts/%d/", &id); err != nil || scripts[id] == nil {
+            http.NotFound(w, r)
+            return
+        }
+        atomic.AddInt32(&detailCalls, 1)
+        detail := map[string]interface{}{"id": id}
+        for k, v := range scripts[id] {
+            detail[k] = v
+        }
+        writeJSON(t, w, detail)
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &detailCalls
+}
+
+type keyStoreReference struct {
+    Id        int64    `tfsdk:"id"`
+    Name      string   `tfsdk:"name"`
+    Locations []string `tfsdk:"locations"`
+    Lines     []int64  `tfsdk:"lines"`
+}
+
+func readKeyStoreUsage(t *testing.T, d *KeyStoreUsageDataSource, shell types.String) []keyStoreReference {
+    t.Helper()
+    resp := readDataSource(t, d, &KeyStoreUsageDataSourceModel{
+        Name:     types.StringValue("api_token"),
+        Shell:    shell,
+        Category: types.StringNull(),
+        Token:    types.StringNull(),
+        Scripts:  types.ListNull(types.ObjectType{AttrTypes: keyStoreReferenceAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state KeyStoreUsageDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var references []keyStoreReference
+    state.Scripts.ElementsAs(context.Background(), &references, false)
+    return references
+}
+
+func TestKeyStoreUsageDataSourceRead_ReportsLocations(t *testing.T) {
+    server, _ := keyStoreUsageServer(t)
+    d := &KeyStoreUsageDataSource{client: newTestClient(server)}
+
+    references := readKeyStoreUsage(t, d, types.StringNull())
+
+    expected := []keyStoreReference{
+        {Id: 1, Name: "Args", Locations: []string{"args"}, Lines: []int64{}},
+        {Id: 2, Name: "Env", Locations: []string{"env_vars", "body"}, Lines: []int64{2}},
+    }
+    if !reflect.DeepEqual(references, expected) {
+        t.Errorf("expected %v, got %v", expected, references)
+    }
+}
+
+func TestKeyStoreUsageDataSourceRead_FiltersAndReusesCachedDetails(t *testing.T) {
+    server, detailCalls := keyStoreUsageServer(t)
+    d := &KeyStoreUsageDataSource{client: newTestClient(server)}
+
+    references := readKeyStoreUsage(t, d, types.StringValue("python"))
+    if len(references) != 1 || references[0].Name != "Env" {
+        t.Errorf("expected only the python script, got %v", references)
+    }
+    if got := atomic.LoadInt32(detailCalls); got != 1 {
+        t.Errorf("expected 1 detail request for the filtered scripts, got %d", got)
+    }
+
+    readKeyStoreUsage(t, d, types.StringNull())
+    if got := atomic.LoadInt32(detailCalls); got != 3 {
+        t.Errorf("expected cached details to be reused, got %d detail requests", got)
+    }
+}
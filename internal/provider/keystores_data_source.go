@@ -26,9 +26,10 @@ type KeyStoresDataSource struct {
 
 // KeyStoresDataSourceModel describes the data source data model.
 type KeyStoresDataSourceModel struct {
-    Id        types.Int64  `tfsdk:"id"`
-    Name      types.String `tfsdk:"name"`
-    Keystores types.List   `tfsdk:"keystores"`
+    Id            types.Int64  `tfsdk:"id"`
+    Name          types.String `tfsdk:"name"`
+    IncludeValues types.Bool   `tfsdk:"include_values"`
+    Keystores     types.List   `tfsdk:"keystores"`
 }
 
 // KeyStoreModel represents a single keystore entry in the list
@@ -55,6 +56,10 @@ func (d *KeyStoresDataSource) Schema(ctx context.Context, req datasource.SchemaR
                 MarkdownDescription: "Optional: Filter keystores by name (exact match).",
                 Optional:            true,
             },
+            "include_values": schema.BoolAttribute{
+                MarkdownDescription: "Optional: Whether to return the values. Set to `false` to list key names only, so no secret values are written to state. Defaults to `true`.",
+                Optional:            true,
+            },
             "keystores": schema.ListNestedAttribute{
                 MarkdownDescription: "List of keystore entries matching the filter criteria, or all entries if no filter is specified.",
                 Computed:            true,
@@ -69,7 +74,7 @@ func (d *KeyStoresDataSource) Schema(ctx context.Context, req datasource.SchemaR
                             Computed:            true,
                         },
                         "value": schema.StringAttribute{
-                            MarkdownDescription: "Key value",
+                            MarkdownDescription: "Key value, null when `include_values` is `false`",
                             Computed:            true,
                             Sensitive:           true,
                         },
@@ -98,6 +103,8 @@ func (d *KeyStoresDataSource) Configure(ctx context.Context, req datasource.Conf
 }
 
 func (d *KeyStoresDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
     var data KeyStoresDataSourceModel
 
     resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -155,6 +162,8 @@ func (d *KeyStoresDataSource) Read(ctx context.Context, req datasource.ReadReque
         filteredEntries = entries
     }
 
+    includeValues := data.IncludeValues.IsNull() || data.IncludeValues.ValueBool()
+
     // Convert to KeyStoreModel list
     keystoresList := make([]KeyStoreModel, len(filteredEntries))
     for i, entry := range filteredEntries {
@@ -166,7 +175,11 @@ func (d *KeyStoresDataSource) Read(ctx context.Context, req datasource.ReadReque
         if name, ok := entry["name"].(string); ok {
             model.Name = types.StringValue(name)
         }
-        if value, ok := entry["value"].(string); ok {
+        // Values are left null, and never kept for redaction, in names-only
+        // mode
+        model.Value = types.StringNull()
+        if value, ok := entry["value"].(string); ok && includeValues {
+            d.client.addSecret(value)
             model.Value = types.StringValue(value)
         }
         
@@ -0,0 +1,75 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// keystoresListType is the type of the keystores attribute.
+var keystoresListType = types.ObjectType{AttrTypes: map[string]attr.Type{"id": types.Int64Type, "name": types.StringType, "value": types.StringType}}
+
+func keystoresServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": 1, "name": "psa_token", "value": "psa-secret-value"},
+            {"id": 2, "name": "smtp_password", "value": "smtp-secret-value"},
+        })
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func TestKeyStoresDataSourceRead_NamesOnly(t *testing.T) {
+    client := newTestClient(keystoresServer(t))
+    resp := readDataSource(t, &KeyStoresDataSource{client: client}, &KeyStoresDataSourceModel{
+        Id:            types.Int64Null(),
+        Name:          types.StringNull(),
+        IncludeValues: types.BoolValue(false),
+        Keystores:     types.ListNull(keystoresListType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state KeyStoresDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var entries []KeyStoreModel
+    state.Keystores.ElementsAs(context.Background(), &entries, false)
+    if len(entries) != 2 || entries[0].Name.ValueString() != "psa_token" || entries[1].Id.ValueInt64() != 2 {
+        t.Fatalf("expected both entries with id and name, got %v", entries)
+    }
+    for _, entry := range entries {
+        if !entry.Value.IsNull() {
+            t.Errorf("expected no value for %s, got one", entry.Name.ValueString())
+        }
+    }
+    if got := client.redact("psa-secret-value"); got != "psa-secret-value" {
+        t.Errorf("expected values that were not read not to be registered as secrets, got %q", got)
+    }
+}
+
+func TestKeyStoresDataSourceRead_IncludesValuesByDefault(t *testing.T) {
+    resp := readDataSource(t, &KeyStoresDataSource{client: newTestClient(keystoresServer(t))}, &KeyStoresDataSourceModel{
+        Id:            types.Int64Null(),
+        Name:          types.StringValue("smtp_password"),
+        IncludeValues: types.BoolNull(),
+        Keystores:     types.ListNull(keystoresListType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state KeyStoresDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var entries []KeyStoreModel
+    state.Keystores.ElementsAs(context.Background(), &entries, false)
+    if len(entries) != 1 || entries[0].Value.ValueString() != "smtp-secret-value" {
+        t.Errorf("expected the smtp_password value, got %v", entries)
+    }
+}
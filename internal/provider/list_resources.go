@@ -0,0 +1,226 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "iter"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/list"
+    listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResourceWithConfigure = &ScriptResource{}
+var _ list.ListResourceWithConfigure = &ScriptSnippetResource{}
+var _ list.ListResourceWithConfigure = &KeyStoreResource{}
+
+func NewScriptListResource() list.ListResource {
+    return &ScriptResource{}
+}
+
+func NewScriptSnippetListResource() list.ListResource {
+    return &ScriptSnippetResource{}
+}
+
+func NewKeyStoreListResource() list.ListResource {
+    return &KeyStoreResource{}
+}
+
+// ScriptListModel describes the list resource configuration of scripts.
+type ScriptListModel struct {
+    ScriptType types.String `tfsdk:"script_type"`
+    Shell      types.String `tfsdk:"shell"`
+    Category   types.String `tfsdk:"category"`
+    Hidden     types.Bool   `tfsdk:"hidden"`
+}
+
+// KeyStoreListModel describes the list resource configuration of keystore
+// entries.
+type KeyStoreListModel struct {
+    IncludeValues types.Bool `tfsdk:"include_values"`
+}
+
+func (r *ScriptResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+    resp.Schema = listschema.Schema{
+        MarkdownDescription: "Lists scripts for `terraform query`. The filters match those of the `tacticalrmm_scripts` data source.",
+
+        Attributes: map[string]listschema.Attribute{
+            "script_type": listschema.StringAttribute{
+                MarkdownDescription: "Optional: Filter scripts by type (userdefined or builtin). Built-in scripts are only listed when set to builtin.",
+                Optional:            true,
+            },
+            "shell": listschema.StringAttribute{
+                MarkdownDescription: "Optional: Filter scripts by shell type (powershell, cmd, python, shell, nushell, deno).",
+                Optional:            true,
+            },
+            "category": listschema.StringAttribute{
+                MarkdownDescription: "Optional: Filter scripts by category.",
+                Optional:            true,
+            },
+            "hidden": listschema.BoolAttribute{
+                MarkdownDescription: "Optional: Filter scripts by hidden status. Hidden and visible scripts are listed when unset.",
+                Optional:            true,
+            },
+        },
+    }
+}
+
+func (r *ScriptResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+    var data ScriptListModel
+
+    diags := req.Config.Get(ctx, &data)
+    if diags.HasError() {
+        stream.Results = r.client.listDiagnostics(diags)
+        return
+    }
+
+    // Hidden scripts are only listed when asked for, so the hidden filter
+    // would otherwise never match
+    scripts, err := r.client.listObjects(ctx, r.client.allScriptsURL())
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        stream.Results = r.client.listDiagnostics(diags)
+        return
+    }
+
+    // A null filter matches every script, like in the scripts data source,
+    // except that built-in scripts cannot be managed and are only listed for
+    // script_type = "builtin"
+    var matches []map[string]interface{}
+    for _, script := range scripts {
+        if data.ScriptType.IsNull() && isBuiltinScript(script) {
+            continue
+        }
+        if !data.ScriptType.IsNull() && script["script_type"] != data.ScriptType.ValueString() {
+            continue
+        }
+        if !data.Shell.IsNull() && script["shell"] != data.Shell.ValueString() {
+            continue
+        }
+        if !data.Category.IsNull() && script["category"] != data.Category.ValueString() {
+            continue
+        }
+        if !data.Hidden.IsNull() && script["hidden"] != data.Hidden.ValueBool() {
+            continue
+        }
+        matches = append(matches, script)
+    }
+
+    stream.Results = r.client.listResults(ctx, req, matches, r.Read, nil)
+}
+
+func (r *ScriptSnippetResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+    resp.Schema = listschema.Schema{
+        MarkdownDescription: "Lists script snippets for `terraform query`.",
+    }
+}
+
+func (r *ScriptSnippetResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+    snippets, err := r.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL))
+    if err != nil {
+        var diags diag.Diagnostics
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
+        stream.Results = r.client.listDiagnostics(diags)
+        return
+    }
+
+    stream.Results = r.client.listResults(ctx, req, snippets, r.Read, nil)
+}
+
+func (r *KeyStoreResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+    resp.Schema = listschema.Schema{
+        MarkdownDescription: "Lists keystore entries for `terraform query`.",
+
+        Attributes: map[string]listschema.Attribute{
+            "include_values": listschema.BoolAttribute{
+                MarkdownDescription: "Optional: Whether to return the values when the resource is included. Set to `false` to list key names only. Defaults to `true`.",
+                Optional:            true,
+            },
+        },
+    }
+}
+
+func (r *KeyStoreResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+    var data KeyStoreListModel
+
+    diags := req.Config.Get(ctx, &data)
+    if diags.HasError() {
+        stream.Results = r.client.listDiagnostics(diags)
+        return
+    }
+
+    keys, err := r.client.listObjects(ctx, fmt.Sprintf("%s/core/keystore/", r.client.BaseURL))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list keystore entries, got error: %s", err))
+        stream.Results = r.client.listDiagnostics(diags)
+        return
+    }
+
+    // Values are dropped from the read resource, the same as include_values
+    // of the keystores data source
+    var withoutValues func(ctx context.Context, state *tfsdk.State) diag.Diagnostics
+    if !data.IncludeValues.IsNull() && !data.IncludeValues.ValueBool() {
+        withoutValues = func(ctx context.Context, state *tfsdk.State) diag.Diagnostics {
+            diags := state.SetAttribute(ctx, path.Root("value"), types.StringNull())
+            diags.Append(state.SetAttribute(ctx, path.Root("value_json"), types.StringNull())...)
+            return diags
+        }
+    }
+
+    stream.Results = r.client.listResults(ctx, req, keys, r.Read, withoutValues)
+}
+
+// listResults returns the list results of objects sorted by ID, with the name
+// as display name and the ID as identity. When Terraform asks for the
+// resource, it is read the same way as after an import, and then passed to
+// modify unless that is nil.
+func (c *ClientConfig) listResults(
+    ctx context.Context,
+    req list.ListRequest,
+    objects []map[string]interface{},
+    read func(context.Context, resource.ReadRequest, *resource.ReadResponse),
+    modify func(context.Context, *tfsdk.State) diag.Diagnostics,
+) iter.Seq[list.ListResult] {
+    return func(push func(list.ListResult) bool) {
+        for i, object := range sortedById(objects) {
+            if req.Limit > 0 && int64(i) >= req.Limit {
+                return
+            }
+
+            id := types.Int64Value(referencedId(object["id"]))
+            result := req.NewListResult(ctx)
+            result.DisplayName, _ = object["name"].(string)
+            result.Diagnostics.Append(result.Identity.SetAttribute(ctx, path.Root("id"), id)...)
+
+            if req.IncludeResource && !result.Diagnostics.HasError() {
+                state := tfsdk.State{Schema: req.ResourceSchema, Raw: result.Resource.Raw}
+                result.Diagnostics.Append(state.SetAttribute(ctx, path.Root("id"), id)...)
+
+                readResp := resource.ReadResponse{State: state}
+                read(ctx, resource.ReadRequest{State: state}, &readResp)
+                result.Diagnostics.Append(readResp.Diagnostics...)
+                if modify != nil && !result.Diagnostics.HasError() {
+                    result.Diagnostics.Append(modify(ctx, &readResp.State)...)
+                }
+                result.Resource.Raw = readResp.State.Raw
+            }
+
+            c.redactDiagnostics(&result.Diagnostics)
+            if !push(result) {
+                return
+            }
+        }
+    }
+}
+
+// listDiagnostics returns a list results stream reporting diags, e.g. when
+// the objects could not be listed.
+func (c *ClientConfig) listDiagnostics(diags diag.Diagnostics) iter.Seq[list.ListResult] {
+    c.redactDiagnostics(&diags)
+    return list.ListResultsStreamDiagnostics(diags)
+}
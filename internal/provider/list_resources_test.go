@@ -0,0 +1,152 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/list"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// listedResource is a list resource that is also the resource it lists.
+type listedResource interface {
+    list.ListResource
+    resource.ResourceWithIdentity
+}
+
+// listResources runs List for r with a config built from model and returns
+// the collected results.
+func listResources(t *testing.T, r listedResource, model interface{}, includeResource bool, limit int64) []list.ListResult {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    var identityResp resource.IdentitySchemaResponse
+    r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &identityResp)
+
+    var configResp list.ListResourceSchemaResponse
+    r.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &configResp)
+
+    // Config has no Set method, so the raw value is built through a state
+    state := tfsdk.State{Schema: configResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build config: %v", diags)
+    }
+
+    var stream list.ListResultsStream
+    r.List(ctx, list.ListRequest{
+        Config:                 tfsdk.Config{Schema: configResp.Schema, Raw: state.Raw},
+        IncludeResource:        includeResource,
+        Limit:                  limit,
+        ResourceSchema:         schemaResp.Schema,
+        ResourceIdentitySchema: identityResp.IdentitySchema,
+    }, &stream)
+
+    var results []list.ListResult
+    for result := range stream.Results {
+        results = append(results, result)
+    }
+    return results
+}
+
+// listedIds returns the identity IDs of results, failing on any diagnostics.
+func listedIds(t *testing.T, results []list.ListResult) []int64 {
+    t.Helper()
+    var ids []int64
+    for _, result := range results {
+        if result.Diagnostics.HasError() {
+            t.Fatalf("unexpected diagnostics: %v", result.Diagnostics)
+        }
+        var id types.Int64
+        result.Identity.GetAttribute(context.Background(), path.Root("id"), &id)
+        ids = append(ids, id.ValueInt64())
+    }
+    return ids
+}
+
+func TestScriptResourceList(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/scripts/" {
+            http.NotFound(w, r)
+            return
+        }
+        // Like Tactical RMM, hidden scripts are only listed when asked for
+        scripts := []map[string]interface{}{
+            {"id": 12, "name": "Clear Temp", "script_type": "userdefined", "shell": "powershell", "category": "Cleanup", "hidden": false},
+            {"id": 3, "name": "Disk Cleanup", "script_type": "builtin", "shell": "cmd", "category": "Cleanup", "hidden": false},
+        }
+        if r.URL.Query().Get("showHiddenScripts") == "true" {
+            scripts = append(scripts, map[string]interface{}{"id": 7, "name": "Old Backup", "script_type": "userdefined", "shell": "powershell", "hidden": true})
+        }
+        writeJSON(t, w, scripts)
+    }))
+    t.Cleanup(server.Close)
+    r := &ScriptResource{client: newTestClient(server)}
+
+    null := ScriptListModel{ScriptType: types.StringNull(), Shell: types.StringNull(), Category: types.StringNull(), Hidden: types.BoolNull()}
+    results := listResources(t, r, null, false, 0)
+    if ids := listedIds(t, results); len(ids) != 2 || ids[0] != 7 || ids[1] != 12 {
+        t.Fatalf("expected the user defined scripts sorted by ID, got %v", ids)
+    }
+    if results[0].DisplayName != "Old Backup" {
+        t.Errorf("expected the script name as display name, got %q", results[0].DisplayName)
+    }
+
+    filtered := null
+    filtered.ScriptType = types.StringValue("userdefined")
+    filtered.Hidden = types.BoolValue(false)
+    if ids := listedIds(t, listResources(t, r, filtered, false, 0)); len(ids) != 1 || ids[0] != 12 {
+        t.Errorf("expected only the visible user defined script, got %v", ids)
+    }
+
+    hidden := null
+    hidden.Hidden = types.BoolValue(true)
+    if ids := listedIds(t, listResources(t, r, hidden, false, 0)); len(ids) != 1 || ids[0] != 7 {
+        t.Errorf("expected only the hidden script, got %v", ids)
+    }
+
+    builtin := null
+    builtin.ScriptType = types.StringValue("builtin")
+    if ids := listedIds(t, listResources(t, r, builtin, false, 0)); len(ids) != 1 || ids[0] != 3 {
+        t.Errorf("expected the built-in script when asked for, got %v", ids)
+    }
+
+    if ids := listedIds(t, listResources(t, r, null, false, 2)); len(ids) != 2 {
+        t.Errorf("expected the limit to be honoured, got %v", ids)
+    }
+}
+
+func TestKeyStoreResourceList_IncludeValues(t *testing.T) {
+    server := keystoreListServer(t, []map[string]interface{}{
+        {"id": 2, "name": "smtp_password", "value": "hunter2"},
+        {"id": 1, "name": "api_token", "value": "abc123"},
+    })
+    r := &KeyStoreResource{client: newTestClient(server)}
+
+    tests := map[string]struct {
+        includeValues types.Bool
+        value         types.String
+    }{
+        "default":        {includeValues: types.BoolNull(), value: types.StringValue("abc123")},
+        "without values": {includeValues: types.BoolValue(false), value: types.StringNull()},
+    }
+    for name, test := range tests {
+        results := listResources(t, r, KeyStoreListModel{IncludeValues: test.includeValues}, true, 0)
+        if ids := listedIds(t, results); len(ids) != 2 || ids[0] != 1 {
+            t.Fatalf("%s: expected both entries sorted by ID, got %v", name, ids)
+        }
+
+        var state KeyStoreResourceModel
+        if diags := results[0].Resource.Get(context.Background(), &state); diags.HasError() {
+            t.Fatalf("%s: unable to read listed resource: %v", name, diags)
+        }
+        if state.Name.ValueString() != "api_token" || !state.Value.Equal(test.value) {
+            t.Errorf("%s: unexpected listed resource: %+v", name, state)
+        }
+    }
+}
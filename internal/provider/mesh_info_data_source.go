@@ -0,0 +1,116 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MeshInfoDataSource{}
+
+func NewMeshInfoDataSource() datasource.DataSource {
+    return &MeshInfoDataSource{}
+}
+
+// MeshInfoDataSource exposes the MeshCentral integration settings of the
+// server without the mesh token.
+type MeshInfoDataSource struct {
+    client *ClientConfig
+}
+
+// MeshInfoDataSourceModel describes the data source data model.
+type MeshInfoDataSourceModel struct {
+    MeshSite     types.String `tfsdk:"mesh_site"`
+    Username     types.String `tfsdk:"username"`
+    DeviceGroup  types.String `tfsdk:"device_group"`
+    CompanyName  types.String `tfsdk:"company_name"`
+    SyncWithTRMM types.Bool   `tfsdk:"sync_with_trmm"`
+}
+
+func (d *MeshInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_mesh_info"
+}
+
+func (d *MeshInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Mesh info data source for Tactical RMM. Exposes the MeshCentral integration settings from the core settings. " +
+            "The mesh token is never returned.",
+
+        Attributes: map[string]schema.Attribute{
+            "mesh_site": schema.StringAttribute{
+                MarkdownDescription: "URL of the MeshCentral site",
+                Computed:            true,
+            },
+            "username": schema.StringAttribute{
+                MarkdownDescription: "MeshCentral user TRMM connects as",
+                Computed:            true,
+            },
+            "device_group": schema.StringAttribute{
+                MarkdownDescription: "MeshCentral device group agents are added to",
+                Computed:            true,
+            },
+            "company_name": schema.StringAttribute{
+                MarkdownDescription: "Company name shown in the MeshCentral agent, null if none is set",
+                Computed:            true,
+            },
+            "sync_with_trmm": schema.BoolAttribute{
+                MarkdownDescription: "Whether TRMM users and permissions are synced to MeshCentral, null on servers without the setting",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *MeshInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *MeshInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data MeshInfoDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    settings, err := d.client.getObject(ctx, fmt.Sprintf("%s/core/settings/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read core settings, got error: %s", err))
+        return
+    }
+
+    // The token is not exposed, but it is redacted should a later error quote it
+    if token, ok := settings["mesh_token"].(string); ok {
+        d.client.addSecret(token)
+    }
+
+    data.MeshSite = stringValue(settings["mesh_site"])
+    data.Username = stringValue(settings["mesh_username"])
+    data.DeviceGroup = stringValue(settings["mesh_device_group"])
+    data.CompanyName = stringValue(settings["mesh_company_name"])
+    if data.CompanyName.ValueString() == "" {
+        data.CompanyName = types.StringNull()
+    }
+    data.SyncWithTRMM = boolValue(settings["sync_mesh_with_trmm"])
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
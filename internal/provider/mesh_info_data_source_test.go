@@ -0,0 +1,45 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestMeshInfoDataSource_OmitsToken(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/core/settings/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, map[string]interface{}{
+            "mesh_site":           "https://mesh.example.com",
+            "mesh_username":       "tactical",
+            "mesh_device_group":   "TacticalRMM",
+            "mesh_company_name":   "",
+            "mesh_token":          "0123456789abcdef0123456789abcdef",
+            "sync_mesh_with_trmm": true,
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    d := &MeshInfoDataSource{client: client}
+    resp := readDataSource(t, d, &MeshInfoDataSourceModel{})
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state MeshInfoDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.MeshSite.ValueString() != "https://mesh.example.com" || state.Username.ValueString() != "tactical" || state.DeviceGroup.ValueString() != "TacticalRMM" {
+        t.Errorf("unexpected mesh info: %+v", state)
+    }
+    if !state.CompanyName.IsNull() || !state.SyncWithTRMM.ValueBool() {
+        t.Errorf("unexpected company name %s or sync setting %s", state.CompanyName, state.SyncWithTRMM)
+    }
+    if got := client.redact("token 0123456789abcdef0123456789abcdef"); got != "token "+redactedValue {
+        t.Errorf("expected the mesh token to be redacted, got %q", got)
+    }
+}
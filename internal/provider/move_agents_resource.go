@@ -0,0 +1,378 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "sort"
+    "strings"
+    "sync"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &MoveAgentsResource{}
+var _ resource.ResourceWithValidateConfig = &MoveAgentsResource{}
+
+func NewMoveAgentsResource() resource.Resource {
+    return &MoveAgentsResource{}
+}
+
+// MoveAgentsResource moves an explicit agent list, or every agent of a site,
+// to another site when it is created.
+type MoveAgentsResource struct {
+    client *ClientConfig
+}
+
+// MoveAgentsResourceModel describes the resource data model.
+type MoveAgentsResourceModel struct {
+    Id             types.String `tfsdk:"id"`
+    AgentIds       types.List   `tfsdk:"agent_ids"`
+    SourceSiteId   types.Int64  `tfsdk:"source_site_id"`
+    SiteId         types.Int64  `tfsdk:"site_id"`
+    DryRun         types.Bool   `tfsdk:"dry_run"`
+    Triggers       types.Map    `tfsdk:"triggers"`
+    TargetAgentIds types.List   `tfsdk:"target_agent_ids"`
+    MovedAgentIds  types.List   `tfsdk:"moved_agent_ids"`
+    FailedAgents   types.Map    `tfsdk:"failed_agents"`
+}
+
+func (r *MoveAgentsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_move_agents"
+}
+
+func (r *MoveAgentsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Moves agents to another site when created. Exactly one of `agent_ids` or `source_site_id` must be set. " +
+            "Every agent is updated individually, agents that could not be moved are reported in `failed_agents`. Destroying this resource does not move the agents back.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this move",
+                Computed:            true,
+            },
+            "agent_ids": schema.ListAttribute{
+                MarkdownDescription: "Move these agents",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.List{
+                    listplanmodifier.RequiresReplace(),
+                },
+            },
+            "source_site_id": schema.Int64Attribute{
+                MarkdownDescription: "Move every agent of this site",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the site to move the agents to",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "dry_run": schema.BoolAttribute{
+                MarkdownDescription: "When true, only resolve the agents that would move into `target_agent_ids` without moving them",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the agents to be moved again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "target_agent_ids": schema.ListAttribute{
+                MarkdownDescription: "IDs of the agents in scope when the resource was created, sorted",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "moved_agent_ids": schema.ListAttribute{
+                MarkdownDescription: "IDs of the agents that were moved, sorted. Null for a dry run",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "failed_agents": schema.MapAttribute{
+                MarkdownDescription: "Error message by ID of every agent that could not be moved. Null for a dry run",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+        },
+    }
+}
+
+func (r *MoveAgentsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data MoveAgentsResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.AgentIds.IsUnknown() || data.SourceSiteId.IsUnknown() {
+        return
+    }
+
+    if data.AgentIds.IsNull() == data.SourceSiteId.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Move Scope",
+            "Exactly one of 'agent_ids' or 'source_site_id' must be specified.",
+        )
+        return
+    }
+
+    if !data.SourceSiteId.IsNull() && !data.SiteId.IsUnknown() && data.SourceSiteId.ValueInt64() == data.SiteId.ValueInt64() {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("source_site_id"),
+            "Invalid Move Scope",
+            "source_site_id must differ from site_id, the agents are already in that site.",
+        )
+    }
+}
+
+func (r *MoveAgentsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *MoveAgentsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data MoveAgentsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // A dry run only lists agents, so it also works in read-only mode
+    if !data.DryRun.ValueBool() && r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var agentIds []string
+    if !data.AgentIds.IsNull() {
+        resp.Diagnostics.Append(data.AgentIds.ElementsAs(ctx, &agentIds, false)...)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+    }
+
+    targets := r.targetAgents(ctx, &data, agentIds, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var diags diag.Diagnostics
+    data.Id = types.StringValue(actionID())
+    data.TargetAgentIds, diags = types.ListValueFrom(ctx, types.StringType, targets)
+    resp.Diagnostics.Append(diags...)
+    data.MovedAgentIds = types.ListNull(types.StringType)
+    data.FailedAgents = types.MapNull(types.StringType)
+
+    if data.DryRun.ValueBool() {
+        resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+        return
+    }
+
+    moved, failures := r.moveAgents(ctx, targets, data.SiteId.ValueInt64())
+
+    failed := make(map[string]attr.Value, len(failures))
+    failedIds := make([]string, 0, len(failures))
+    for agentId, err := range failures {
+        failed[agentId] = types.StringValue(err.Error())
+        failedIds = append(failedIds, agentId)
+    }
+    sort.Strings(failedIds)
+
+    // Nothing changed on the server when every move failed, so there is no
+    // state worth keeping
+    if len(targets) > 0 && len(moved) == 0 {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to move any agent, got error: %s", failures[failedIds[0]]))
+        return
+    }
+    if len(failedIds) > 0 {
+        details := make([]string, len(failedIds))
+        for i, agentId := range failedIds {
+            details[i] = fmt.Sprintf("%s: %s", agentId, failures[agentId])
+        }
+        resp.Diagnostics.AddWarning(
+            "Agents Not Moved",
+            fmt.Sprintf("%d of %d agents could not be moved and are listed in failed_agents:\n%s", len(failedIds), len(targets), strings.Join(details, "\n")),
+        )
+    }
+
+    data.MovedAgentIds, diags = types.ListValueFrom(ctx, types.StringType, moved)
+    resp.Diagnostics.Append(diags...)
+    data.FailedAgents, diags = types.MapValue(types.StringType, failed)
+    resp.Diagnostics.Append(diags...)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MoveAgentsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Moving agents is a one-off operation, there is no remote object to refresh
+}
+
+func (r *MoveAgentsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data MoveAgentsResourceModel
+    var state MoveAgentsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.TargetAgentIds = state.TargetAgentIds
+    data.MovedAgentIds = state.MovedAgentIds
+    data.FailedAgents = state.FailedAgents
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MoveAgentsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data MoveAgentsResourceModel
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // A dry run changed nothing, so it can also be removed in read-only mode
+    if !data.DryRun.ValueBool() && r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // The agents stay in their new site, removing the resource only drops it from state
+}
+
+// targetAgents resolves the sorted IDs of the agents to move. Explicit agent
+// IDs must all exist.
+func (r *MoveAgentsResource) targetAgents(ctx context.Context, data *MoveAgentsResourceModel, agentIds []string, diags *diag.Diagnostics) []string {
+    listURL := fmt.Sprintf("%s/agents/", r.client.BaseURL)
+    if !data.SourceSiteId.IsNull() {
+        listURL = fmt.Sprintf("%s/agents/?site=%d", r.client.BaseURL, data.SourceSiteId.ValueInt64())
+    }
+
+    agents, err := r.client.listObjects(ctx, listURL)
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+        return nil
+    }
+
+    var wanted map[string]bool
+    if agentIds != nil {
+        wanted = make(map[string]bool, len(agentIds))
+        for _, agentId := range agentIds {
+            wanted[agentId] = true
+        }
+    }
+
+    targets := []string{}
+    for _, agent := range agents {
+        agentId, ok := agent["agent_id"].(string)
+        if !ok || (wanted != nil && !wanted[agentId]) {
+            continue
+        }
+        if wanted != nil {
+            delete(wanted, agentId)
+        }
+        targets = append(targets, agentId)
+    }
+
+    if len(wanted) > 0 {
+        missing := make([]string, 0, len(wanted))
+        for agentId := range wanted {
+            missing = append(missing, agentId)
+        }
+        sort.Strings(missing)
+        diags.AddAttributeError(
+            path.Root("agent_ids"),
+            "Agent Not Found",
+            fmt.Sprintf("These agents do not exist: %s", strings.Join(missing, ", ")),
+        )
+        return nil
+    }
+
+    sort.Strings(targets)
+    return targets
+}
+
+// moveAgents sets the site of every agent with at most detailFetchConcurrency
+// parallel requests. It returns the sorted IDs of the moved agents and the
+// error of every agent that could not be moved.
+func (r *MoveAgentsResource) moveAgents(ctx context.Context, agentIds []string, siteId int64) ([]string, map[string]error) {
+    moved := []string{}
+    failures := make(map[string]error)
+
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+    sem := make(chan struct{}, detailFetchConcurrency)
+
+    for _, agentId := range agentIds {
+        wg.Add(1)
+        go func(agentId string) {
+            defer wg.Done()
+            sem <- struct{}{}
+            defer func() { <-sem }()
+
+            statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId), map[string]interface{}{
+                "site": siteId,
+            })
+            if err == nil && statusCode != http.StatusOK {
+                err = fmt.Errorf("status code: %d, response: %s", statusCode, errorMessage(respBody))
+            }
+
+            mu.Lock()
+            defer mu.Unlock()
+            if err != nil {
+                failures[agentId] = err
+                return
+            }
+            moved = append(moved, agentId)
+        }(agentId)
+    }
+    wg.Wait()
+
+    sort.Strings(moved)
+    return moved, failures
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// moveAgentsServer lists three agents, two of them in site 2, and records the
+// site each agent is moved to. Moving ws-2 is rejected.
+func moveAgentsServer(t *testing.T, moves map[string]float64) *httptest.Server {
+    t.Helper()
+    var mu sync.Mutex
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/":
+            agents := []map[string]interface{}{
+                {"agent_id": "ws-2", "hostname": "WS2"},
+                {"agent_id": "ws-1", "hostname": "WS1"},
+                {"agent_id": "srv-1", "hostname": "SRV1"},
+            }
+            if r.URL.Query().Get("site") == "2" {
+                agents = agents[:2]
+            }
+            writeJSON(t, w, agents)
+        case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/agents/"):
+            agentId := strings.Trim(strings.TrimPrefix(r.URL.Path, "/agents/"), "/")
+            if agentId == "ws-2" {
+                w.WriteHeader(http.StatusBadRequest)
+                writeJSON(t, w, map[string]interface{}{"site": []string{"Invalid pk \"9\" - object does not exist."}})
+                return
+            }
+            var body map[string]interface{}
+            json.NewDecoder(r.Body).Decode(&body)
+            mu.Lock()
+            moves[agentId] = body["site"].(float64)
+            mu.Unlock()
+            writeJSON(t, w, "The agent was updated successfully")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func moveAgentsModel() *MoveAgentsResourceModel {
+    return &MoveAgentsResourceModel{
+        AgentIds:       types.ListNull(types.StringType),
+        SourceSiteId:   types.Int64Null(),
+        SiteId:         types.Int64Value(9),
+        Triggers:       types.MapNull(types.StringType),
+        TargetAgentIds: types.ListUnknown(types.StringType),
+        MovedAgentIds:  types.ListUnknown(types.StringType),
+        FailedAgents:   types.MapUnknown(types.StringType),
+    }
+}
+
+func TestMoveAgentsResourceCreate_SourceSite(t *testing.T) {
+    moves := map[string]float64{}
+    server := moveAgentsServer(t, moves)
+
+    model := moveAgentsModel()
+    model.SourceSiteId = types.Int64Value(2)
+
+    r := &MoveAgentsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if resp.Diagnostics.WarningsCount() != 1 || !strings.Contains(resp.Diagnostics.Warnings()[0].Detail(), "ws-2: status code: 400") {
+        t.Errorf("expected a warning about ws-2, got %v", resp.Diagnostics)
+    }
+
+    if len(moves) != 1 || moves["ws-1"] != 9 {
+        t.Errorf("expected only ws-1 to be moved to site 9, got %v", moves)
+    }
+
+    var state MoveAgentsResourceModel
+    resp.State.Get(context.Background(), &state)
+    var targets, moved []string
+    var failed map[string]string
+    state.TargetAgentIds.ElementsAs(context.Background(), &targets, false)
+    state.MovedAgentIds.ElementsAs(context.Background(), &moved, false)
+    state.FailedAgents.ElementsAs(context.Background(), &failed, false)
+    if strings.Join(targets, ",") != "ws-1,ws-2" || strings.Join(moved, ",") != "ws-1" {
+        t.Errorf("unexpected agents: targets %v, moved %v", targets, moved)
+    }
+    if len(failed) != 1 || !strings.Contains(failed["ws-2"], "object does not exist") {
+        t.Errorf("unexpected failures: %v", failed)
+    }
+}
+
+func TestMoveAgentsResourceCreate_DryRun(t *testing.T) {
+    moves := map[string]float64{}
+    server := moveAgentsServer(t, moves)
+
+    model := moveAgentsModel()
+    model.AgentIds = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("srv-1"), types.StringValue("ws-1")})
+    model.DryRun = types.BoolValue(true)
+
+    r := &MoveAgentsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(moves) != 0 {
+        t.Errorf("expected no agent to be moved on a dry run, got %v", moves)
+    }
+
+    var state MoveAgentsResourceModel
+    resp.State.Get(context.Background(), &state)
+    var targets []string
+    state.TargetAgentIds.ElementsAs(context.Background(), &targets, false)
+    if strings.Join(targets, ",") != "srv-1,ws-1" {
+        t.Errorf("unexpected target agents: %v", targets)
+    }
+    if !state.MovedAgentIds.IsNull() || !state.FailedAgents.IsNull() {
+        t.Errorf("expected no results for a dry run, got %v and %v", state.MovedAgentIds, state.FailedAgents)
+    }
+}
+
+func TestMoveAgentsResourceCreate_ReadOnly(t *testing.T) {
+    moves := map[string]float64{}
+    server := moveAgentsServer(t, moves)
+
+    client := newTestClient(server)
+    client.ReadOnly = true
+    r := &MoveAgentsResource{client: client}
+
+    // A dry run lists the agents even in read-only mode
+    model := moveAgentsModel()
+    model.SourceSiteId = types.Int64Value(2)
+    model.DryRun = types.BoolValue(true)
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    var state MoveAgentsResourceModel
+    resp.State.Get(context.Background(), &state)
+    var targets []string
+    state.TargetAgentIds.ElementsAs(context.Background(), &targets, false)
+    if strings.Join(targets, ",") != "ws-1,ws-2" {
+        t.Errorf("unexpected target agents: %v", targets)
+    }
+    if resp := deleteResource(t, r, &state); resp.Diagnostics.HasError() {
+        t.Errorf("expected the dry run to be removable, got %v", resp.Diagnostics)
+    }
+
+    // Moving is still denied
+    model.DryRun = types.BoolValue(false)
+    resp = createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Provider Is in Read-Only Mode" {
+        t.Errorf("expected a read-only error, got %v", resp.Diagnostics)
+    }
+    if len(moves) != 0 {
+        t.Errorf("expected no agent to be moved, got %v", moves)
+    }
+
+    // As is removing an applied move
+    state.DryRun = types.BoolValue(false)
+    deleteResp := deleteResource(t, r, &state)
+    if !deleteResp.Diagnostics.HasError() || deleteResp.Diagnostics.Errors()[0].Summary() != "Provider Is in Read-Only Mode" {
+        t.Errorf("expected a read-only error, got %v", deleteResp.Diagnostics)
+    }
+}
+
+func TestMoveAgentsResourceCreate_AllFailed(t *testing.T) {
+    moves := map[string]float64{}
+    server := moveAgentsServer(t, moves)
+
+    model := moveAgentsModel()
+    model.AgentIds = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ws-2")})
+
+    r := &MoveAgentsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when no agent could be moved")
+    }
+}
+
+func TestMoveAgentsResourceCreate_UnknownAgent(t *testing.T) {
+    moves := map[string]float64{}
+    server := moveAgentsServer(t, moves)
+
+    model := moveAgentsModel()
+    model.AgentIds = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ws-1"), types.StringValue("gone")})
+
+    r := &MoveAgentsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "gone") {
+        t.Fatalf("expected an error about the unknown agent, got %v", resp.Diagnostics)
+    }
+    if len(moves) != 0 {
+        t.Errorf("expected no agent to be moved, got %v", moves)
+    }
+}
+
+func TestMoveAgentsResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &MoveAgentsResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    agents := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ws-1")})
+    tests := map[string]struct {
+        agentIds     types.List
+        sourceSiteId types.Int64
+        err          string
+    }{
+        "agent list":        {agentIds: agents, sourceSiteId: types.Int64Null()},
+        "source site":       {agentIds: types.ListNull(types.StringType), sourceSiteId: types.Int64Value(2)},
+        "neither":           {agentIds: types.ListNull(types.StringType), sourceSiteId: types.Int64Null(), err: "Exactly one"},
+        "both":              {agentIds: agents, sourceSiteId: types.Int64Value(2), err: "Exactly one"},
+        "same site":         {agentIds: types.ListNull(types.StringType), sourceSiteId: types.Int64Value(9), err: "must differ"},
+        "unknown agent ids": {agentIds: types.ListUnknown(types.StringType), sourceSiteId: types.Int64Value(2)},
+    }
+
+    for name, test := range tests {
+        model := moveAgentsModel()
+        model.AgentIds = test.agentIds
+        model.SourceSiteId = test.sourceSiteId
+
+        config := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := config.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}, &resp)
+        if test.err == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), test.err) {
+            t.Errorf("%s: expected an error containing %q, got %v", name, test.err, resp.Diagnostics)
+        }
+    }
+}
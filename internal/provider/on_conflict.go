@@ -0,0 +1,86 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// on_conflict policies for an existing object with the name of an object a
+// resource is about to create.
+const (
+    onConflictError   = "error"
+    onConflictAdopt   = "adopt"
+    onConflictReplace = "replace"
+)
+
+// onConflictPolicies are the values accepted by on_conflict.
+var onConflictPolicies = map[string]bool{
+    onConflictError:   true,
+    onConflictAdopt:   true,
+    onConflictReplace: true,
+}
+
+// resolvesConflicts reports whether creates look for existing objects with
+// the same name to adopt or replace them. In error mode, the default, every
+// resource keeps its own handling of an existing name.
+func (c *ClientConfig) resolvesConflicts() bool {
+    return c != nil && (c.onConflict == onConflictAdopt || c.onConflict == onConflictReplace)
+}
+
+// objectsNamed returns all objects of a decoded TRMM list with the given name.
+func objectsNamed(items []map[string]interface{}, name string) []map[string]interface{} {
+    var named []map[string]interface{}
+    for _, item := range items {
+        if itemName, ok := item["name"].(string); ok && itemName == name {
+            named = append(named, item)
+        }
+    }
+    return named
+}
+
+// adoptOrReplace applies on_conflict adopt or replace to the existing objects
+// named like the object a resource is about to create. In adopt mode it
+// returns the ID of the existing object, which the caller updates instead of
+// creating a new one; several objects with the name cannot be adopted. In
+// replace mode the existing objects are deleted and 0 is returned, so the
+// caller creates the object as usual.
+func (c *ClientConfig) adoptOrReplace(ctx context.Context, kind string, existing []map[string]interface{}, detailURL func(id int64) string, diags *diag.Diagnostics) int64 {
+    if len(existing) == 0 {
+        return 0
+    }
+
+    existing = sortedById(existing)
+    if c.onConflict == onConflictAdopt {
+        if len(existing) > 1 {
+            ids := make([]string, len(existing))
+            for i, object := range existing {
+                ids[i] = fmt.Sprint(referencedId(object["id"]))
+            }
+            diags.AddError(
+                "Ambiguous Existing Object",
+                fmt.Sprintf("Found %d %ss named %q (IDs %s), so on_conflict = \"adopt\" cannot choose one. "+
+                    "Import the intended one with 'terraform import' instead.", len(existing), kind, existing[0]["name"], strings.Join(ids, ", ")),
+            )
+            return 0
+        }
+        return referencedId(existing[0]["id"])
+    }
+
+    for _, object := range existing {
+        id := referencedId(object["id"])
+        statusCode, respBody, err := c.sendJSON(ctx, "DELETE", detailURL(id), nil)
+        if err != nil {
+            diags.AddError("Client Error", fmt.Sprintf("Unable to delete existing %s %d, got error: %s", kind, id, err))
+            return 0
+        }
+        if statusCode != http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotFound {
+            diags.AddError("Client Error", fmt.Sprintf("Unable to delete existing %s %d, status code: %d, response: %s", kind, id, statusCode, errorMessage(respBody)))
+            return 0
+        }
+    }
+    return 0
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// conflictingScriptServer serves a TRMM with a user-defined script and a
+// built-in community script both named "Cleanup". The user-defined script is
+// run by a check when inUse is set. It records every write request.
+func conflictingScriptServer(t *testing.T, inUse bool) (*httptest.Server, *[]string) {
+    t.Helper()
+    var mu sync.Mutex
+    var writes []string
+    created, deleted := false, false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        defer mu.Unlock()
+        if r.Method != http.MethodGet {
+            writes = append(writes, r.Method+" "+r.URL.Path)
+        }
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            scripts := []map[string]interface{}{{"id": 1, "name": "Cleanup", "script_type": "builtin"}}
+            if !deleted {
+                scripts = append(scripts, map[string]interface{}{"id": 5, "name": "Cleanup", "script_type": "userdefined"})
+            }
+            if created {
+                scripts = append(scripts, map[string]interface{}{"id": 42, "name": "Cleanup", "script_type": "userdefined"})
+            }
+            writeJSON(t, w, scripts)
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/5/":
+            writeJSON(t, w, map[string]interface{}{"id": 5, "name": "Cleanup", "script_type": "userdefined", "default_timeout": 90})
+        case r.Method == http.MethodGet && r.URL.Path == "/checks/":
+            checks := []map[string]interface{}{}
+            if inUse {
+                checks = append(checks, map[string]interface{}{"id": 21, "check_type": "script", "script": 5, "readable_desc": "Script check: Cleanup"})
+            }
+            writeJSON(t, w, checks)
+        case r.Method == http.MethodGet && r.URL.Path == "/tasks/":
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            created = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodPut && r.URL.Path == "/scripts/5/":
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodDelete && r.URL.Path == "/scripts/5/":
+            deleted = true
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server, &writes
+}
+
+func cleanupScript() *ScriptResourceModel {
+    return &ScriptResourceModel{
+        Name:               types.StringValue("Cleanup"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Remove-Item $env:TEMP\\* -Recurse"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    }
+}
+
+func TestScriptResourceCreate_OnConflict(t *testing.T) {
+    tests := map[string]struct {
+        onConflict string
+        writes     []string
+        id         int64
+    }{
+        // The existing script is left alone and not mistaken for the created one
+        "unset":   {writes: []string{"POST /scripts/"}, id: 42},
+        "error":   {onConflict: onConflictError, writes: []string{"POST /scripts/"}, id: 42},
+        "adopt":   {onConflict: onConflictAdopt, writes: []string{"PUT /scripts/5/"}, id: 5},
+        "replace": {onConflict: onConflictReplace, writes: []string{"DELETE /scripts/5/", "POST /scripts/"}, id: 42},
+    }
+
+    for name, test := range tests {
+        t.Run(name, func(t *testing.T) {
+            server, writes := conflictingScriptServer(t, false)
+            client := newTestClient(server)
+            client.onConflict = test.onConflict
+
+            resp := createResource(t, &ScriptResource{client: client}, cleanupScript())
+            if resp.Diagnostics.HasError() {
+                t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+            }
+            // The built-in script with the same name is never touched
+            if !reflect.DeepEqual(*writes, test.writes) {
+                t.Errorf("expected writes %v, got %v", test.writes, *writes)
+            }
+
+            var state ScriptResourceModel
+            resp.State.Get(context.Background(), &state)
+            if state.Id.ValueInt64() != test.id {
+                t.Errorf("expected id %d, got %d", test.id, state.Id.ValueInt64())
+            }
+        })
+    }
+}
+
+func TestScriptResourceCreate_OnConflictReplaceScriptInUse(t *testing.T) {
+    server, writes := conflictingScriptServer(t, true)
+    client := newTestClient(server)
+    client.onConflict = onConflictReplace
+
+    resp := createResource(t, &ScriptResource{client: client}, cleanupScript())
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Script In Use" {
+        t.Fatalf("expected a Script In Use error, got %v", resp.Diagnostics)
+    }
+    if len(*writes) != 0 {
+        t.Errorf("expected no writes, got %v", *writes)
+    }
+}
+
+func TestAdoptOrReplace_AmbiguousAdopt(t *testing.T) {
+    client := &ClientConfig{onConflict: onConflictAdopt}
+    existing := []map[string]interface{}{
+        {"id": float64(7), "name": "Cleanup"},
+        {"id": float64(3), "name": "Cleanup"},
+    }
+
+    var diags diag.Diagnostics
+    id := client.adoptOrReplace(context.Background(), "script", existing, nil, &diags)
+    if id != 0 || !diags.HasError() {
+        t.Fatalf("expected an error and no ID, got %d and %v", id, diags)
+    }
+    if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, "(IDs 3, 7)") {
+        t.Errorf("expected the IDs in the detail, got %q", detail)
+    }
+}
+
+func TestKeyStoreResourceCreate_OnConflictAdopt(t *testing.T) {
+    var writes []string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/core/keystore/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 3, "name": "api_token", "value": "old"}})
+        case r.Method == http.MethodPut && r.URL.Path == "/core/keystore/3/":
+            writes = append(writes, r.Method+" "+r.URL.Path)
+            writeJSON(t, w, "ok")
+        default:
+            writes = append(writes, r.Method+" "+r.URL.Path)
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.onConflict = onConflictAdopt
+    resp := createResource(t, &KeyStoreResource{client: client}, &KeyStoreResourceModel{
+        Name:  types.StringValue("api_token"),
+        Value: types.StringValue("new"),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if !reflect.DeepEqual(writes, []string{"PUT /core/keystore/3/"}) {
+        t.Errorf("expected the existing entry to be updated, got %v", writes)
+    }
+
+    var state KeyStoreResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 3 {
+        t.Errorf("expected id 3, got %d", state.Id.ValueInt64())
+    }
+}
+
+func TestProviderConfigure_OnConflict(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKey:     types.StringValue("test-key"),
+        OnConflict: types.StringValue(onConflictReplace),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if got := resp.ResourceData.(*ClientConfig).onConflict; got != onConflictReplace {
+        t.Errorf("expected on_conflict replace, got %q", got)
+    }
+
+    resp = configureProvider(t, trmmProviderModel{
+        APIKey:     types.StringValue("test-key"),
+        OnConflict: types.StringValue("overwrite"),
+    })
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Conflict Policy" {
+        t.Errorf("expected an Invalid Conflict Policy error, got %v", resp.Diagnostics)
+    }
+}
+
+func TestProviderConfigure_OnConflictDefaultsToError(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{APIKey: types.StringValue("test-key")})
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if got := resp.ResourceData.(*ClientConfig).onConflict; got != onConflictError {
+        t.Errorf("expected the error conflict policy by default, got %q", got)
+    }
+}
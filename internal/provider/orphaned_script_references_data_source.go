@@ -0,0 +1,242 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &OrphanedScriptReferencesDataSource{}
+
+// orphanedReferenceAttrTypes describes a check or task action referencing a
+// script that does not exist.
+var orphanedReferenceAttrTypes = map[string]attr.Type{
+    "kind":           types.StringType,
+    "id":             types.Int64Type,
+    "name":           types.StringType,
+    "script_id":      types.Int64Type,
+    "action_index":   types.Int64Type,
+    "agent_hostname": types.StringType,
+    "policy_id":      types.Int64Type,
+    "policy_name":    types.StringType,
+}
+
+func NewOrphanedScriptReferencesDataSource() datasource.DataSource {
+    return &OrphanedScriptReferencesDataSource{}
+}
+
+// OrphanedScriptReferencesDataSource reports the checks and automated tasks
+// that reference scripts which no longer exist.
+type OrphanedScriptReferencesDataSource struct {
+    client *ClientConfig
+}
+
+// OrphanedScriptReferencesDataSourceModel describes the data source data model.
+type OrphanedScriptReferencesDataSourceModel struct {
+    References types.List `tfsdk:"references"`
+    HasOrphans types.Bool `tfsdk:"has_orphans"`
+}
+
+// orphanedReference is a check, or an action of a task, whose script is
+// missing.
+type orphanedReference struct {
+    kind        string
+    object      map[string]interface{}
+    scriptId    int64
+    actionIndex types.Int64
+}
+
+func (d *OrphanedScriptReferencesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_orphaned_script_references"
+}
+
+func (d *OrphanedScriptReferencesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Orphaned script references data source for Tactical RMM. Cross-references the script checks and the script actions of automated tasks " +
+            "with the scripts of the server and lists those referencing a script that no longer exists, e.g. after a script was deleted by hand.",
+
+        Attributes: map[string]schema.Attribute{
+            "references": schema.ListNestedAttribute{
+                MarkdownDescription: "References to missing scripts, checks first, each ordered by ID",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "kind": schema.StringAttribute{
+                            MarkdownDescription: "`check` or `task`",
+                            Computed:            true,
+                        },
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Check or task identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Check description or task name",
+                            Computed:            true,
+                        },
+                        "script_id": schema.Int64Attribute{
+                            MarkdownDescription: "Identifier of the missing script",
+                            Computed:            true,
+                        },
+                        "action_index": schema.Int64Attribute{
+                            MarkdownDescription: "Position of the task action referencing the script, starting at 0. Null for checks",
+                            Computed:            true,
+                        },
+                        "agent_hostname": schema.StringAttribute{
+                            MarkdownDescription: "Hostname of the agent the check or task belongs to, null for policy checks and tasks",
+                            Computed:            true,
+                        },
+                        "policy_id": schema.Int64Attribute{
+                            MarkdownDescription: "Identifier of the automation policy the check or task belongs to, null for agent checks and tasks",
+                            Computed:            true,
+                        },
+                        "policy_name": schema.StringAttribute{
+                            MarkdownDescription: "Name of the automation policy the check or task belongs to, null for agent checks and tasks",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+            "has_orphans": schema.BoolAttribute{
+                MarkdownDescription: "Whether any check or task references a missing script",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *OrphanedScriptReferencesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *OrphanedScriptReferencesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data OrphanedScriptReferencesDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Hidden scripts still run, so they are not orphans
+    scripts, err := d.client.listObjects(ctx, d.client.allScriptsURL())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return
+    }
+    checks, err := d.client.listObjects(ctx, fmt.Sprintf("%s/checks/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list checks, got error: %s", err))
+        return
+    }
+    tasks, err := d.client.listObjects(ctx, fmt.Sprintf("%s/tasks/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list tasks, got error: %s", err))
+        return
+    }
+
+    existing := make(map[int64]bool, len(scripts))
+    for _, script := range scripts {
+        existing[referencedId(script["id"])] = true
+    }
+    orphans := orphanedScriptReferences(checks, tasks, existing)
+
+    // Checks and tasks reference their agent and policy by ID, so the names
+    // are looked up, but only when there are orphans
+    var parents []map[string]interface{}
+    for _, orphan := range orphans {
+        parents = append(parents, orphan.object)
+    }
+    var agentNames, policyNames map[int64]string
+    if parentsNeeded(parents, "agent") {
+        agents, err := d.client.listObjects(ctx, fmt.Sprintf("%s/agents/", d.client.BaseURL))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+            return
+        }
+        agentNames = namesById(agents, "hostname")
+    }
+    if parentsNeeded(parents, "policy") {
+        policies, err := d.client.listObjects(ctx, fmt.Sprintf("%s/automation/policies/", d.client.BaseURL))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list automation policies, got error: %s", err))
+            return
+        }
+        policyNames = namesById(policies, "name")
+    }
+
+    referenceValues := []attr.Value{}
+    for _, orphan := range orphans {
+        dep := newDependent(orphan.kind, orphan.object, "readable_desc", "name")
+        agentHostname, policyName := types.StringNull(), types.StringNull()
+        if referencedId(orphan.object["agent"]) != 0 {
+            agentHostname = parentName(orphan.object["agent"], agentNames, "hostname")
+        }
+        policyId := types.Int64Null()
+        if id := referencedId(orphan.object["policy"]); id != 0 {
+            policyId = types.Int64Value(id)
+            policyName = parentName(orphan.object["policy"], policyNames, "name")
+        }
+        value, diags := types.ObjectValue(orphanedReferenceAttrTypes, map[string]attr.Value{
+            "kind":           types.StringValue(orphan.kind),
+            "id":             types.Int64Value(dep.id),
+            "name":           types.StringValue(dep.name),
+            "script_id":      types.Int64Value(orphan.scriptId),
+            "action_index":   orphan.actionIndex,
+            "agent_hostname": agentHostname,
+            "policy_id":      policyId,
+            "policy_name":    policyName,
+        })
+        resp.Diagnostics.Append(diags...)
+        referenceValues = append(referenceValues, value)
+    }
+
+    var diags diag.Diagnostics
+    data.References, diags = types.ListValue(types.ObjectType{AttrTypes: orphanedReferenceAttrTypes}, referenceValues)
+    resp.Diagnostics.Append(diags...)
+    data.HasOrphans = types.BoolValue(len(referenceValues) > 0)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// orphanedScriptReferences returns the script checks and task script actions
+// referencing a script not in existing, checks first, each ordered by ID.
+func orphanedScriptReferences(checks []map[string]interface{}, tasks []map[string]interface{}, existing map[int64]bool) []orphanedReference {
+    var orphans []orphanedReference
+    for _, check := range sortedById(checks) {
+        scriptId := referencedId(check["script"])
+        if check["check_type"] == "script" && scriptId != 0 && !existing[scriptId] {
+            orphans = append(orphans, orphanedReference{kind: "check", object: check, scriptId: scriptId, actionIndex: types.Int64Null()})
+        }
+    }
+    for _, task := range sortedById(tasks) {
+        actions, _ := task["actions"].([]interface{})
+        for i, action := range actions {
+            action, _ := action.(map[string]interface{})
+            scriptId := referencedId(action["script"])
+            if action["type"] == "script" && scriptId != 0 && !existing[scriptId] {
+                orphans = append(orphans, orphanedReference{kind: "task", object: task, scriptId: scriptId, actionIndex: types.Int64Value(int64(i))})
+            }
+        }
+    }
+    return orphans
+}
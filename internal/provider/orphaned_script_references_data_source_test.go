@@ -0,0 +1,95 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestOrphanedScriptReferencesDataSourceRead(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/scripts/":
+            scripts := []map[string]interface{}{
+                {"id": 4, "name": "Disk Cleanup"},
+                {"id": 9, "name": "Defender Status"},
+            }
+            if r.URL.Query().Get("showHiddenScripts") == "true" {
+                scripts = append(scripts, map[string]interface{}{"id": 11, "name": "Legacy Inventory", "hidden": true})
+            }
+            writeJSON(t, w, scripts)
+        case "/checks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 20, "check_type": "script", "readable_desc": "Script check: Defender Status", "script": 9, "agent": 1},
+                {"id": 21, "check_type": "script", "readable_desc": "Script check: Old Inventory", "script": 15, "policy": 5},
+                {"id": 22, "check_type": "diskspace", "readable_desc": "Disk space Drive C: > 25%", "agent": 1},
+                {"id": 23, "check_type": "script", "readable_desc": "Script check: Legacy Inventory", "script": 11, "agent": 1},
+            })
+        case "/tasks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 31, "name": "Weekly Cleanup", "agent": 1, "actions": []map[string]interface{}{
+                    {"type": "cmd", "command": "ipconfig /flushdns", "shell": "cmd"},
+                    {"type": "script", "script": 12, "name": "Removed Script"},
+                    {"type": "script", "script": 4, "name": "Disk Cleanup"},
+                }},
+                {"id": 30, "name": "Nightly Cleanup", "policy": 5, "actions": []map[string]interface{}{
+                    {"type": "script", "script": 4, "name": "Disk Cleanup"},
+                }},
+            })
+        case "/agents/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 1, "agent_id": "abc", "hostname": "web01"}})
+        case "/automation/policies/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 5, "name": "Workstations"}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &OrphanedScriptReferencesDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &OrphanedScriptReferencesDataSourceModel{
+        References: types.ListNull(types.ObjectType{AttrTypes: orphanedReferenceAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state OrphanedScriptReferencesDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.HasOrphans.ValueBool() {
+        t.Error("expected orphans to be reported")
+    }
+
+    type reference struct {
+        Kind          string  `tfsdk:"kind"`
+        Id            int64   `tfsdk:"id"`
+        Name          string  `tfsdk:"name"`
+        ScriptId      int64   `tfsdk:"script_id"`
+        ActionIndex   *int64  `tfsdk:"action_index"`
+        AgentHostname *string `tfsdk:"agent_hostname"`
+        PolicyId      *int64  `tfsdk:"policy_id"`
+        PolicyName    *string `tfsdk:"policy_name"`
+    }
+    var references []reference
+    if diags := state.References.ElementsAs(context.Background(), &references, false); diags.HasError() {
+        t.Fatalf("unable to read references: %v", diags)
+    }
+    if len(references) != 2 {
+        t.Fatalf("expected the policy check and the task action to be reported, but not the hidden script check, got %+v", references)
+    }
+
+    check := references[0]
+    if check.Kind != "check" || check.Id != 21 || check.Name != "Script check: Old Inventory" || check.ScriptId != 15 ||
+        check.ActionIndex != nil || check.AgentHostname != nil || *check.PolicyId != 5 || *check.PolicyName != "Workstations" {
+        t.Errorf("unexpected check reference: %+v", check)
+    }
+
+    task := references[1]
+    if task.Kind != "task" || task.Id != 31 || task.Name != "Weekly Cleanup" || task.ScriptId != 12 ||
+        *task.ActionIndex != 1 || *task.AgentHostname != "web01" || task.PolicyId != nil {
+        t.Errorf("unexpected task reference: %+v", task)
+    }
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PermissionsDataSource{}
+
+func NewPermissionsDataSource() datasource.DataSource {
+    return &PermissionsDataSource{}
+}
+
+// PermissionsDataSource lists the role permission flags supported by the
+// connected server.
+type PermissionsDataSource struct {
+    client *ClientConfig
+}
+
+// PermissionsDataSourceModel describes the data source data model.
+type PermissionsDataSourceModel struct {
+    Permissions   types.List   `tfsdk:"permissions"`
+    ReferenceRole types.String `tfsdk:"reference_role"`
+}
+
+func (d *PermissionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_permissions"
+}
+
+func (d *PermissionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Permissions data source for Tactical RMM. Lists the role permission flags (`is_superuser` and the `can_*` flags) supported by the connected server, " +
+            "which change across Tactical RMM releases. The flags are read from an existing role, so at least one role must exist.",
+
+        Attributes: map[string]schema.Attribute{
+            "permissions": schema.ListAttribute{
+                MarkdownDescription: "Sorted names of the permission flags supported by the server",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "reference_role": schema.StringAttribute{
+                MarkdownDescription: "Name of the role the permission flags were read from",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *PermissionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *PermissionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data PermissionsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The roles endpoint has no schema introspection, but every role carries
+    // all permission flags the server knows about
+    roles, err := d.client.listObjects(ctx, fmt.Sprintf("%s/accounts/roles/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list roles, got error: %s", err))
+        return
+    }
+
+    if len(roles) == 0 {
+        resp.Diagnostics.AddError(
+            "No Roles Found",
+            "The supported permissions are read from an existing role, but the server has no roles. Create a role and try again.",
+        )
+        return
+    }
+
+    role := roles[0]
+    permissions := rolePermissionNames(role)
+
+    permissionsValue, diags := types.ListValueFrom(ctx, types.StringType, permissions)
+    resp.Diagnostics.Append(diags...)
+    data.Permissions = permissionsValue
+    data.ReferenceRole = stringValue(role["name"])
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// rolePermissionNames returns the sorted permission flags of a role object.
+func rolePermissionNames(role map[string]interface{}) []string {
+    permissions := []string{}
+    for name, value := range role {
+        if _, ok := value.(bool); !ok {
+            continue
+        }
+        if name == "is_superuser" || strings.HasPrefix(name, "can_") {
+            permissions = append(permissions, name)
+        }
+    }
+    sort.Strings(permissions)
+    return permissions
+}
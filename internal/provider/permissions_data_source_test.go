@@ -0,0 +1,74 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRolePermissionNames(t *testing.T) {
+    // Granted and denied flags are both supported permissions. Related
+    // objects also use the can_ prefix but are not flags.
+    role := map[string]interface{}{
+        "id":                    1,
+        "name":                  "Technicians",
+        "is_superuser":          false,
+        "can_list_agents":       true,
+        "can_edit_agent":        false,
+        "can_run_scripts":       true,
+        "can_view_clients":      []interface{}{float64(2)},
+        "can_view_sites":        []interface{}{},
+        "can_manage_accounts":   nil,
+        "can_use_webterm_since": "2.0",
+        "created_by":            "admin",
+        "modified_time":         "2024-01-01T00:00:00Z",
+    }
+
+    expected := []string{"can_edit_agent", "can_list_agents", "can_run_scripts", "is_superuser"}
+    if got := rolePermissionNames(role); !reflect.DeepEqual(got, expected) {
+        t.Errorf("unexpected permissions:\n got: %v\nwant: %v", got, expected)
+    }
+}
+
+func TestPermissionsDataSourceRead(t *testing.T) {
+    roles := []map[string]interface{}{
+        {"id": 1, "name": "Technicians", "is_superuser": false, "can_list_agents": true, "can_edit_agent": false, "can_view_clients": []interface{}{}},
+    }
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/accounts/roles/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, roles)
+    }))
+    defer server.Close()
+
+    d := &PermissionsDataSource{client: newTestClient(server)}
+    model := &PermissionsDataSourceModel{Permissions: types.ListNull(types.StringType), ReferenceRole: types.StringNull()}
+    resp := readDataSource(t, d, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state PermissionsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    var permissions []string
+    state.Permissions.ElementsAs(context.Background(), &permissions, false)
+    if !reflect.DeepEqual(permissions, []string{"can_edit_agent", "can_list_agents", "is_superuser"}) {
+        t.Errorf("unexpected permissions: %v", permissions)
+    }
+    if state.ReferenceRole.ValueString() != "Technicians" {
+        t.Errorf("expected the reference role, got %s", state.ReferenceRole)
+    }
+
+    // Without a role there is nothing to read the flags from
+    roles = nil
+    resp = readDataSource(t, d, model)
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "No Roles Found" {
+        t.Errorf("expected an error without roles, got %v", resp.Diagnostics)
+    }
+}
@@ -0,0 +1,421 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PolicyExportDataSource{}
+
+var policyCheckAttrTypes = map[string]attr.Type{
+    "id":                types.Int64Type,
+    "check_type":        types.StringType,
+    "name":              types.StringType,
+    "script_id":         types.Int64Type,
+    "script_args":       types.ListType{ElemType: types.StringType},
+    "timeout":           types.Int64Type,
+    "run_interval":      types.Int64Type,
+    "fails_b4_alert":    types.Int64Type,
+    "warning_threshold": types.Int64Type,
+    "error_threshold":   types.Int64Type,
+    "alert_severity":    types.StringType,
+    "email_alert":       types.BoolType,
+    "text_alert":        types.BoolType,
+    "dashboard_alert":   types.BoolType,
+    "config_json":       types.StringType,
+}
+
+var policyTaskActionAttrTypes = map[string]attr.Type{
+    "type":        types.StringType,
+    "script_id":   types.Int64Type,
+    "script_args": types.ListType{ElemType: types.StringType},
+    "command":     types.StringType,
+    "shell":       types.StringType,
+    "timeout":     types.Int64Type,
+}
+
+var policyTaskAttrTypes = map[string]attr.Type{
+    "id":                types.Int64Type,
+    "name":              types.StringType,
+    "task_type":         types.StringType,
+    "enabled":           types.BoolType,
+    "assigned_check_id": types.Int64Type,
+    "alert_severity":    types.StringType,
+    "actions":           types.ListType{ElemType: types.ObjectType{AttrTypes: policyTaskActionAttrTypes}},
+    "config_json":       types.StringType,
+}
+
+// policyExportRuntimeFields are the fields of policy checks and tasks that
+// belong to the server they were read from rather than to their
+// configuration, so they are left out of config_json.
+var policyExportRuntimeFields = map[string]bool{
+    "id":                   true,
+    "policy":               true,
+    "agent":                true,
+    "parent_check":         true,
+    "readable_desc":        true,
+    "check_result":         true,
+    "task_result":          true,
+    "assignedtasks":        true,
+    "assigned_tasks":       true,
+    "check_name":           true,
+    "alert_template":       true,
+    "win_task_name":        true,
+    "sync_status":          true,
+    "managed_by_policy":    true,
+    "overridden_by_policy": true,
+    "created_by":           true,
+    "created_time":         true,
+    "modified_by":          true,
+    "modified_time":        true,
+}
+
+func NewPolicyExportDataSource() datasource.DataSource {
+    return &PolicyExportDataSource{}
+}
+
+// PolicyExportDataSource reads the checks and automated tasks of an
+// automation policy in a form that can be passed on to another policy.
+type PolicyExportDataSource struct {
+    client *ClientConfig
+}
+
+// PolicyExportDataSourceModel describes the data source data model.
+type PolicyExportDataSourceModel struct {
+    PolicyId    types.Int64  `tfsdk:"policy_id"`
+    Name        types.String `tfsdk:"name"`
+    Description types.String `tfsdk:"description"`
+    Checks      types.List   `tfsdk:"checks"`
+    Tasks       types.List   `tfsdk:"tasks"`
+}
+
+func (d *PolicyExportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_policy_export"
+}
+
+func (d *PolicyExportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Policy export data source for Tactical RMM. Returns every check and automated task of an automation policy as structured lists, " +
+            "so a module can replicate a \"golden\" policy on another policy or instance. " +
+            "The common settings are exposed as attributes and the complete configuration of each check and task as `config_json`.",
+
+        Attributes: map[string]schema.Attribute{
+            "policy_id": schema.Int64Attribute{
+                MarkdownDescription: "Automation policy to export",
+                Required:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Name of the policy",
+                Computed:            true,
+            },
+            "description": schema.StringAttribute{
+                MarkdownDescription: "Description of the policy",
+                Computed:            true,
+            },
+            "checks": schema.ListNestedAttribute{
+                MarkdownDescription: "Checks of the policy, ordered by ID",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Check identifier",
+                            Computed:            true,
+                        },
+                        "check_type": schema.StringAttribute{
+                            MarkdownDescription: "Type of the check, e.g. diskspace, cpuload, script",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Readable description of the check",
+                            Computed:            true,
+                        },
+                        "script_id": schema.Int64Attribute{
+                            MarkdownDescription: "Script run by a script check, null for other checks",
+                            Computed:            true,
+                        },
+                        "script_args": schema.ListAttribute{
+                            MarkdownDescription: "Arguments passed to the script",
+                            Computed:            true,
+                            ElementType:         types.StringType,
+                        },
+                        "timeout": schema.Int64Attribute{
+                            MarkdownDescription: "Script timeout in seconds, null for checks without a timeout",
+                            Computed:            true,
+                        },
+                        "run_interval": schema.Int64Attribute{
+                            MarkdownDescription: "Seconds between runs, 0 to use the agent's check interval",
+                            Computed:            true,
+                        },
+                        "fails_b4_alert": schema.Int64Attribute{
+                            MarkdownDescription: "Number of consecutive failures before an alert is raised",
+                            Computed:            true,
+                        },
+                        "warning_threshold": schema.Int64Attribute{
+                            MarkdownDescription: "Warning threshold of cpuload, memory and diskspace checks",
+                            Computed:            true,
+                        },
+                        "error_threshold": schema.Int64Attribute{
+                            MarkdownDescription: "Error threshold of cpuload, memory and diskspace checks",
+                            Computed:            true,
+                        },
+                        "alert_severity": schema.StringAttribute{
+                            MarkdownDescription: "Severity of the alerts raised by the check",
+                            Computed:            true,
+                        },
+                        "email_alert": schema.BoolAttribute{
+                            MarkdownDescription: "Whether failures are emailed",
+                            Computed:            true,
+                        },
+                        "text_alert": schema.BoolAttribute{
+                            MarkdownDescription: "Whether failures are sent by SMS",
+                            Computed:            true,
+                        },
+                        "dashboard_alert": schema.BoolAttribute{
+                            MarkdownDescription: "Whether failures are shown on the dashboard",
+                            Computed:            true,
+                        },
+                        "config_json": schema.StringAttribute{
+                            MarkdownDescription: "Complete configuration of the check as canonical JSON, without its ID, policy and results",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+            "tasks": schema.ListNestedAttribute{
+                MarkdownDescription: "Automated tasks of the policy, ordered by ID",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Task identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Task name",
+                            Computed:            true,
+                        },
+                        "task_type": schema.StringAttribute{
+                            MarkdownDescription: "Trigger of the task, e.g. daily, weekly, checkfailure, runonce",
+                            Computed:            true,
+                        },
+                        "enabled": schema.BoolAttribute{
+                            MarkdownDescription: "Whether the task is enabled",
+                            Computed:            true,
+                        },
+                        "assigned_check_id": schema.Int64Attribute{
+                            MarkdownDescription: "Check of the policy whose failure runs the task, null when not triggered by a check",
+                            Computed:            true,
+                        },
+                        "alert_severity": schema.StringAttribute{
+                            MarkdownDescription: "Severity of the alerts raised by the task",
+                            Computed:            true,
+                        },
+                        "actions": schema.ListNestedAttribute{
+                            MarkdownDescription: "Actions run by the task, in order",
+                            Computed:            true,
+                            NestedObject: schema.NestedAttributeObject{
+                                Attributes: map[string]schema.Attribute{
+                                    "type": schema.StringAttribute{
+                                        MarkdownDescription: "Action type: `script` or `cmd`",
+                                        Computed:            true,
+                                    },
+                                    "script_id": schema.Int64Attribute{
+                                        MarkdownDescription: "Script run by a script action, null for commands",
+                                        Computed:            true,
+                                    },
+                                    "script_args": schema.ListAttribute{
+                                        MarkdownDescription: "Arguments passed to the script",
+                                        Computed:            true,
+                                        ElementType:         types.StringType,
+                                    },
+                                    "command": schema.StringAttribute{
+                                        MarkdownDescription: "Command run by a cmd action, null for scripts",
+                                        Computed:            true,
+                                    },
+                                    "shell": schema.StringAttribute{
+                                        MarkdownDescription: "Shell the command runs in, null for scripts",
+                                        Computed:            true,
+                                    },
+                                    "timeout": schema.Int64Attribute{
+                                        MarkdownDescription: "Timeout of the action in seconds",
+                                        Computed:            true,
+                                    },
+                                },
+                            },
+                        },
+                        "config_json": schema.StringAttribute{
+                            MarkdownDescription: "Complete configuration of the task as canonical JSON, without its ID, policy and results",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *PolicyExportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *PolicyExportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data PolicyExportDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    policyId := data.PolicyId.ValueInt64()
+    policyURL := fmt.Sprintf("%s/automation/policies/%d/", d.client.BaseURL, policyId)
+    policy, err := d.client.getObject(ctx, policyURL)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read automation policy %d, got error: %s", policyId, err))
+        return
+    }
+    data.Name = stringValue(policy["name"])
+    data.Description = stringValue(policy["desc"])
+
+    checks, err := d.client.listObjects(ctx, policyURL+"checks/")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list the checks of automation policy %d, got error: %s", policyId, err))
+        return
+    }
+    tasks, err := d.client.listObjects(ctx, policyURL+"tasks/")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list the tasks of automation policy %d, got error: %s", policyId, err))
+        return
+    }
+
+    checkValues := []attr.Value{}
+    for _, check := range sortedById(checks) {
+        name := newDependent("check", check, "readable_desc", "name").name
+        scriptId := types.Int64Null()
+        if id := referencedId(check["script"]); id != 0 {
+            scriptId = types.Int64Value(id)
+        }
+        value, diags := types.ObjectValue(policyCheckAttrTypes, map[string]attr.Value{
+            "id":                types.Int64Value(referencedId(check["id"])),
+            "check_type":        stringValue(check["check_type"]),
+            "name":              types.StringValue(name),
+            "script_id":         scriptId,
+            "script_args":       stringListValue(check["script_args"]),
+            "timeout":           int64Value(check["timeout"]),
+            "run_interval":      int64Value(check["run_interval"]),
+            "fails_b4_alert":    int64Value(check["fails_b4_alert"]),
+            "warning_threshold": int64Value(check["warning_threshold"]),
+            "error_threshold":   int64Value(check["error_threshold"]),
+            "alert_severity":    stringValue(check["alert_severity"]),
+            "email_alert":       boolValue(check["email_alert"]),
+            "text_alert":        boolValue(check["text_alert"]),
+            "dashboard_alert":   boolValue(check["dashboard_alert"]),
+            "config_json":       policyExportConfig(check, &resp.Diagnostics),
+        })
+        resp.Diagnostics.Append(diags...)
+        checkValues = append(checkValues, value)
+    }
+
+    taskValues := []attr.Value{}
+    for _, task := range sortedById(tasks) {
+        assignedCheck := types.Int64Null()
+        if id := referencedId(task["assigned_check"]); id != 0 {
+            assignedCheck = types.Int64Value(id)
+        }
+        value, diags := types.ObjectValue(policyTaskAttrTypes, map[string]attr.Value{
+            "id":                types.Int64Value(referencedId(task["id"])),
+            "name":              stringValue(task["name"]),
+            "task_type":         stringValue(task["task_type"]),
+            "enabled":           boolValue(task["enabled"]),
+            "assigned_check_id": assignedCheck,
+            "alert_severity":    stringValue(task["alert_severity"]),
+            "actions":           policyTaskActions(task["actions"], &resp.Diagnostics),
+            "config_json":       policyExportConfig(task, &resp.Diagnostics),
+        })
+        resp.Diagnostics.Append(diags...)
+        taskValues = append(taskValues, value)
+    }
+
+    var diags diag.Diagnostics
+    data.Checks, diags = types.ListValue(types.ObjectType{AttrTypes: policyCheckAttrTypes}, checkValues)
+    resp.Diagnostics.Append(diags...)
+    data.Tasks, diags = types.ListValue(types.ObjectType{AttrTypes: policyTaskAttrTypes}, taskValues)
+    resp.Diagnostics.Append(diags...)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// policyTaskActions converts the decoded actions of a task into a list value.
+// TRMM names the script of an action "script" and its arguments "script_args";
+// commands have "command" and "shell" instead.
+func policyTaskActions(value interface{}, diags *diag.Diagnostics) types.List {
+    actions, _ := value.([]interface{})
+    actionValues := []attr.Value{}
+    for _, action := range actions {
+        action, ok := action.(map[string]interface{})
+        if !ok {
+            continue
+        }
+        scriptId := types.Int64Null()
+        if id := referencedId(action["script"]); id != 0 {
+            scriptId = types.Int64Value(id)
+        }
+        actionValue, actionDiags := types.ObjectValue(policyTaskActionAttrTypes, map[string]attr.Value{
+            "type":        stringValue(action["type"]),
+            "script_id":   scriptId,
+            "script_args": stringListValue(action["script_args"]),
+            "command":     stringValue(action["command"]),
+            "shell":       stringValue(action["shell"]),
+            "timeout":     int64Value(action["timeout"]),
+        })
+        diags.Append(actionDiags...)
+        actionValues = append(actionValues, actionValue)
+    }
+
+    list, listDiags := types.ListValue(types.ObjectType{AttrTypes: policyTaskActionAttrTypes}, actionValues)
+    diags.Append(listDiags...)
+    return list
+}
+
+// policyExportConfig returns the configuration of a policy check or task as
+// canonical JSON, leaving out its policyExportRuntimeFields.
+func policyExportConfig(object map[string]interface{}, diags *diag.Diagnostics) types.String {
+    config := make(map[string]interface{}, len(object))
+    for key, value := range object {
+        if !policyExportRuntimeFields[key] {
+            config[key] = value
+        }
+    }
+
+    encoded, err := json.Marshal(config)
+    if err == nil {
+        var canonical string
+        if canonical, err = canonicalJSON(string(encoded)); err == nil {
+            return types.StringValue(canonical)
+        }
+    }
+    diags.AddError("Client Error", fmt.Sprintf("Unable to encode the configuration of %q, got error: %s", object["name"], err))
+    return types.StringNull()
+}
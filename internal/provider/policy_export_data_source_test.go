@@ -0,0 +1,149 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPolicyExportDataSourceRead(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/automation/policies/5/":
+            writeJSON(t, w, map[string]interface{}{"id": 5, "name": "Golden Workstation", "desc": "Baseline for all workstations"})
+        case "/automation/policies/5/checks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {
+                    "id": 21, "policy": 5, "check_type": "script", "readable_desc": "Script check: Defender Status", "script": map[string]interface{}{"id": 9, "name": "Defender Status"},
+                    "script_args": []string{"-Quick"}, "timeout": 90, "run_interval": 0, "fails_b4_alert": 2, "alert_severity": "error",
+                    "email_alert": true, "text_alert": false, "dashboard_alert": true, "check_result": map[string]interface{}{"status": "passing"},
+                },
+                {
+                    "id": 20, "policy": 5, "check_type": "diskspace", "readable_desc": "Disk space Drive C: > 25%", "disk": "C:",
+                    "warning_threshold": 25, "error_threshold": 10, "fails_b4_alert": 1, "alert_severity": "warning",
+                    "email_alert": false, "text_alert": false, "dashboard_alert": true, "script_args": []string{},
+                },
+            })
+        case "/automation/policies/5/tasks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {
+                    "id": 31, "policy": 5, "name": "Weekly Cleanup", "task_type": "weekly", "enabled": true, "assigned_check": nil, "alert_severity": "info",
+                    "run_time_bit_weekdays": 64, "task_result": map[string]interface{}{"status": "completed"},
+                    "actions": []map[string]interface{}{
+                        {"type": "script", "script": 4, "name": "Disk Cleanup", "script_args": []string{"-All"}, "timeout": 300},
+                        {"type": "cmd", "command": "ipconfig /flushdns", "shell": "cmd", "timeout": 30},
+                    },
+                },
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &PolicyExportDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &PolicyExportDataSourceModel{
+        PolicyId:    types.Int64Value(5),
+        Name:        types.StringNull(),
+        Description: types.StringNull(),
+        Checks:      types.ListNull(types.ObjectType{AttrTypes: policyCheckAttrTypes}),
+        Tasks:       types.ListNull(types.ObjectType{AttrTypes: policyTaskAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state PolicyExportDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Name.ValueString() != "Golden Workstation" || state.Description.ValueString() != "Baseline for all workstations" {
+        t.Errorf("unexpected policy: %v, %v", state.Name, state.Description)
+    }
+
+    type check struct {
+        Id               int64    `tfsdk:"id"`
+        CheckType        string   `tfsdk:"check_type"`
+        Name             string   `tfsdk:"name"`
+        ScriptId         *int64   `tfsdk:"script_id"`
+        ScriptArgs       []string `tfsdk:"script_args"`
+        Timeout          *int64   `tfsdk:"timeout"`
+        RunInterval      *int64   `tfsdk:"run_interval"`
+        FailsB4Alert     int64    `tfsdk:"fails_b4_alert"`
+        WarningThreshold *int64   `tfsdk:"warning_threshold"`
+        ErrorThreshold   *int64   `tfsdk:"error_threshold"`
+        AlertSeverity    string   `tfsdk:"alert_severity"`
+        EmailAlert       bool     `tfsdk:"email_alert"`
+        TextAlert        bool     `tfsdk:"text_alert"`
+        DashboardAlert   bool     `tfsdk:"dashboard_alert"`
+        ConfigJSON       string   `tfsdk:"config_json"`
+    }
+    var checks []check
+    if diags := state.Checks.ElementsAs(context.Background(), &checks, false); diags.HasError() {
+        t.Fatalf("unable to read checks: %v", diags)
+    }
+    if len(checks) != 2 || checks[0].Id != 20 || checks[1].Id != 21 {
+        t.Fatalf("expected both checks ordered by ID, got %+v", checks)
+    }
+
+    disk := checks[0]
+    if disk.CheckType != "diskspace" || disk.Name != "Disk space Drive C: > 25%" || disk.ScriptId != nil ||
+        *disk.WarningThreshold != 25 || *disk.ErrorThreshold != 10 || disk.AlertSeverity != "warning" || !disk.DashboardAlert {
+        t.Errorf("unexpected disk check: %+v", disk)
+    }
+    if disk.ConfigJSON != `{"alert_severity":"warning","check_type":"diskspace","dashboard_alert":true,"disk":"C:","email_alert":false,`+
+        `"error_threshold":10,"fails_b4_alert":1,"script_args":[],"text_alert":false,"warning_threshold":25}` {
+        t.Errorf("unexpected disk check config: %s", disk.ConfigJSON)
+    }
+
+    script := checks[1]
+    if script.CheckType != "script" || script.ScriptId == nil || *script.ScriptId != 9 || len(script.ScriptArgs) != 1 || script.ScriptArgs[0] != "-Quick" ||
+        *script.Timeout != 90 || script.FailsB4Alert != 2 || !script.EmailAlert || script.TextAlert || script.WarningThreshold != nil {
+        t.Errorf("unexpected script check: %+v", script)
+    }
+
+    type action struct {
+        Type       string   `tfsdk:"type"`
+        ScriptId   *int64   `tfsdk:"script_id"`
+        ScriptArgs []string `tfsdk:"script_args"`
+        Command    *string  `tfsdk:"command"`
+        Shell      *string  `tfsdk:"shell"`
+        Timeout    int64    `tfsdk:"timeout"`
+    }
+    type task struct {
+        Id              int64    `tfsdk:"id"`
+        Name            string   `tfsdk:"name"`
+        TaskType        string   `tfsdk:"task_type"`
+        Enabled         bool     `tfsdk:"enabled"`
+        AssignedCheckId *int64   `tfsdk:"assigned_check_id"`
+        AlertSeverity   string   `tfsdk:"alert_severity"`
+        Actions         []action `tfsdk:"actions"`
+        ConfigJSON      string   `tfsdk:"config_json"`
+    }
+    var tasks []task
+    if diags := state.Tasks.ElementsAs(context.Background(), &tasks, false); diags.HasError() {
+        t.Fatalf("unable to read tasks: %v", diags)
+    }
+    if len(tasks) != 1 {
+        t.Fatalf("expected one task, got %+v", tasks)
+    }
+
+    cleanup := tasks[0]
+    if cleanup.Id != 31 || cleanup.Name != "Weekly Cleanup" || cleanup.TaskType != "weekly" || !cleanup.Enabled ||
+        cleanup.AssignedCheckId != nil || cleanup.AlertSeverity != "info" || len(cleanup.Actions) != 2 {
+        t.Fatalf("unexpected task: %+v", cleanup)
+    }
+    if a := cleanup.Actions[0]; a.Type != "script" || a.ScriptId == nil || *a.ScriptId != 4 || len(a.ScriptArgs) != 1 || a.Command != nil || a.Timeout != 300 {
+        t.Errorf("unexpected script action: %+v", a)
+    }
+    if a := cleanup.Actions[1]; a.Type != "cmd" || a.ScriptId != nil || *a.Command != "ipconfig /flushdns" || *a.Shell != "cmd" || a.Timeout != 30 {
+        t.Errorf("unexpected command action: %+v", a)
+    }
+    if !jsonEqual(cleanup.ConfigJSON, `{"actions":[`+
+        `{"name":"Disk Cleanup","script":4,"script_args":["-All"],"timeout":300,"type":"script"},`+
+        `{"command":"ipconfig /flushdns","shell":"cmd","timeout":30,"type":"cmd"}],`+
+        `"alert_severity":"info","assigned_check":null,"enabled":true,"name":"Weekly Cleanup","run_time_bit_weekdays":64,"task_type":"weekly"}`) {
+        t.Errorf("expected the task config without its ID, policy and results, got %s", cleanup.ConfigJSON)
+    }
+}
@@ -2,18 +2,37 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &trmmProvider{}
+	_ provider.Provider                  = &trmmProvider{}
+	_ provider.ProviderWithListResources = &trmmProvider{}
+	_ provider.ProviderWithFunctions     = &trmmProvider{}
+)
+
+const (
+	// defaultMaxIdleConns is the default number of idle keep-alive connections
+	// kept open to the Tactical RMM API.
+	defaultMaxIdleConns = 100
+
+	// defaultIdleConnTimeout is the default time in seconds an idle keep-alive
+	// connection is kept open.
+	defaultIdleConnTimeout = 90
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -35,8 +54,16 @@ type trmmProvider struct {
 
 // trmmProviderModel describes the provider data model.
 type trmmProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	APIKey   types.String `tfsdk:"api_key"`
+	Endpoint        types.String `tfsdk:"endpoint"`
+	APIKey          types.String `tfsdk:"api_key"`
+	APIKeyCommand   types.List   `tfsdk:"api_key_command"`
+	MaxIdleConns    types.Int64  `tfsdk:"max_idle_conns"`
+	IdleConnTimeout types.Int64  `tfsdk:"idle_conn_timeout"`
+	RequestsPerSec  types.Int64  `tfsdk:"requests_per_second"`
+	ReadOnly        types.Bool   `tfsdk:"read_only"`
+	RecreateOnRead  types.List   `tfsdk:"recreate_on_read_errors"`
+	ShellTimeouts   types.Map    `tfsdk:"shell_default_timeouts"`
+	OnConflict      types.String `tfsdk:"on_conflict"`
 }
 
 // Metadata returns the provider type name.
@@ -59,6 +86,59 @@ func (p *trmmProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"api_key_command": schema.ListAttribute{
+				Description: "Command, as the program followed by its arguments, that prints the API key on stdout. It is run once when the provider is configured, " +
+					"so the key can come from a secret manager and stays out of the configuration and state. Conflicts with api_key.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"max_idle_conns": schema.Int64Attribute{
+				Description: "Maximum number of idle keep-alive connections kept open to the API. Raise it for large applies issuing many requests. Defaults to 100.",
+				Optional:    true,
+			},
+			"idle_conn_timeout": schema.Int64Attribute{
+				Description: "Time in seconds an idle keep-alive connection is kept open before being closed. Defaults to 90.",
+				Optional:    true,
+			},
+			"requests_per_second": schema.Int64Attribute{
+				Description: "Maximum number of API requests started per second, shared by all resources and data sources. Unlimited when unset or 0.",
+				Optional:    true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "When true, creating, updating or deleting any resource fails before a request is sent, and the client refuses every request " +
+					"that could modify Tactical RMM. Data sources and refreshes work as usual. Defaults to false.",
+				Optional: true,
+			},
+			"recreate_on_read_errors": schema.ListNestedAttribute{
+				Description: "API errors that mark an object as broken. When reading a script or script snippet fails with a matching error, the resource is removed " +
+					"from state and created again on the next apply instead of failing every refresh. Requires on_conflict = \"replace\", which deletes the broken object " +
+					"when the resource is created again. 401, 403 and 429 cannot be used.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"status_code": schema.Int64Attribute{
+							Description: "HTTP status code of the failed read, between 400 and 599.",
+							Required:    true,
+						},
+						"message_contains": schema.StringAttribute{
+							Description: "Only match errors whose message contains this text, compared case-insensitively. Matches any message when unset.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"shell_default_timeouts": schema.MapAttribute{
+				Description: "Default timeout in seconds by shell (powershell, cmd, python, shell, nushell, deno) for scripts created without default_timeout. " +
+					"Shells without an entry use 90 seconds.",
+				Optional:    true,
+				ElementType: types.Int64Type,
+			},
+			"on_conflict": schema.StringAttribute{
+				Description: "What creating a script, script snippet, keystore entry or SSO provider does when an object with the same name already exists: " +
+					"error fails as each resource does today, adopt updates the existing object to the configuration and manages it, " +
+					"replace deletes the existing object and creates a new one. Defaults to error.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -75,35 +155,139 @@ func (p *trmmProvider) Configure(ctx context.Context, req provider.ConfigureRequ
 	// Configuration values are now available.
 	endpoint := config.Endpoint.ValueString()
 	apiKey := config.APIKey.ValueString()
+	apiKeySource := "api_key"
+
+	// The key is not part of a client yet, e.g. while api_key_command fails
+	defer func() {
+		(&ClientConfig{APIKey: apiKey}).redactDiagnostics(&resp.Diagnostics)
+	}()
 
 	// If values aren't known, check environment variables
 	if endpoint == "" {
 		endpoint = "https://api.tactical-rmm.com" // Default endpoint
 	}
 
+	if !config.APIKeyCommand.IsNull() {
+		if apiKey != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_command"),
+				"Conflicting API Key Configuration",
+				"Only one of api_key or api_key_command can be set.",
+			)
+			return
+		}
+
+		var command []string
+		resp.Diagnostics.Append(config.APIKeyCommand.ElementsAs(ctx, &command, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resolved, err := resolveAPIKeyCommand(ctx, command)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_key_command"),
+				"Unable to Resolve API Key",
+				fmt.Sprintf("The api_key_command did not produce an API key: %s", err),
+			)
+			return
+		}
+		apiKey = resolved
+		apiKeySource = "api_key_command"
+	}
+
 	if apiKey == "" {
 		resp.Diagnostics.AddError(
 			"Missing API Key",
 			"The provider cannot create the Tactical RMM API client as there is a missing or empty value for the API key. "+
-				"Set the api_key value in the configuration or use the TRMM_API_KEY environment variable. "+
+				"Set the api_key or api_key_command value in the configuration or use the TRMM_API_KEY environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 		return
 	}
 
+	maxIdleConns := int64(defaultMaxIdleConns)
+	if !config.MaxIdleConns.IsNull() {
+		maxIdleConns = config.MaxIdleConns.ValueInt64()
+	}
+
+	idleConnTimeout := int64(defaultIdleConnTimeout)
+	if !config.IdleConnTimeout.IsNull() {
+		idleConnTimeout = config.IdleConnTimeout.ValueInt64()
+	}
+
+	if maxIdleConns < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_idle_conns"),
+			"Invalid Connection Pool Setting",
+			"max_idle_conns must not be negative.",
+		)
+	}
+	if idleConnTimeout < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("idle_conn_timeout"),
+			"Invalid Connection Pool Setting",
+			"idle_conn_timeout must not be negative.",
+		)
+	}
+	recreateRules, diags := parseRecreateRules(ctx, config.RecreateOnRead)
+	resp.Diagnostics.Append(diags...)
+	shellTimeouts, diags := parseShellDefaultTimeouts(ctx, config.ShellTimeouts)
+	resp.Diagnostics.Append(diags...)
+	if config.RequestsPerSec.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid Rate Limit",
+			"requests_per_second must not be negative.",
+		)
+	}
+	onConflict := onConflictError
+	if !config.OnConflict.IsNull() {
+		onConflict = config.OnConflict.ValueString()
+	}
+	if !onConflictPolicies[onConflict] {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("on_conflict"),
+			"Invalid Conflict Policy",
+			fmt.Sprintf("on_conflict must be one of error, adopt or replace, got: %q.", onConflict),
+		)
+	}
+	// The broken object keeps its name, so a recreated snippet would fail with
+	// a duplicate name and a recreated script would be created next to it
+	if len(recreateRules) > 0 && onConflict != onConflictReplace {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("recreate_on_read_errors"),
+			"Invalid Recreate Rules",
+			"recreate_on_read_errors requires on_conflict = \"replace\", so recreating a resource deletes the broken object with the same name first.",
+		)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create HTTP client
-	client := &http.Client{}
+	client := newHTTPClient(int(maxIdleConns), time.Duration(idleConnTimeout)*time.Second)
 
 	// Create custom client configuration
 	clientConfig := &ClientConfig{
 		BaseURL:    endpoint,
 		APIKey:     apiKey,
 		HTTPClient: client,
+		limiter:    newRequestLimiter(config.RequestsPerSec.ValueInt64()),
+		ReadOnly:   config.ReadOnly.ValueBool(),
+
+		apiKeySource:      apiKeySource,
+		requestsPerSecond: config.RequestsPerSec.ValueInt64(),
+		onConflict:        onConflict,
+
+		recreateRules:        recreateRules,
+		shellDefaultTimeouts: shellTimeouts,
 	}
 
-	// Make the client available to resources and data sources
+	// Make the client available to resources, data sources and list resources
 	resp.DataSourceData = clientConfig
 	resp.ResourceData = clientConfig
+	resp.ListResourceData = clientConfig
 }
 
 // DataSources defines the data sources implemented in the provider.
@@ -113,14 +297,55 @@ func (p *trmmProvider) DataSources(_ context.Context) []func() datasource.DataSo
 		NewScriptDataSource,
 		NewScriptSnippetDataSource,
 		NewKeyStoreDataSource,
+		NewClientDataSource,
+		NewAlertTemplateDataSource,
+		NewCustomFieldDataSource,
+		NewURLActionDataSource,
 		// Plural data sources (list all or filter)
 		NewScriptsDataSource,
 		NewScriptSnippetsDataSource,
 		NewKeyStoresDataSource,
+		NewSnippetUsageDataSource,
+		NewKeyStoreUsageDataSource,
+		NewInventoryDataSource,
+		NewAgentCustomFieldsDataSource,
+		NewPermissionsDataSource,
+		NewCheckHistoryDataSource,
+		NewSitesDataSource,
+		NewClientsDataSource,
+		NewAgentsDataSource,
+		NewRequiredScriptsDataSource,
+		NewAgentEffectivePolicyDataSource,
+		NewUsersDataSource,
+		NewScriptOutputDataSource,
+		NewTimezonesDataSource,
+		NewMeshInfoDataSource,
+		NewAgentCollectedFieldsDataSource,
+		NewDestroyImpactDataSource,
+		NewScriptUsageDataSource,
+		NewProviderConfigDataSource,
+		NewPolicyExportDataSource,
+		NewServerStatusDataSource,
+		NewOrphanedScriptReferencesDataSource,
 		// Add more data sources here as needed
-		// NewAgentsDataSource,
-		// NewClientsDataSource,
-		// NewSitesDataSource,
+	}
+}
+
+// ListResources defines the list resources implemented in the provider, which
+// back terraform query.
+func (p *trmmProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewScriptListResource,
+		NewScriptSnippetListResource,
+		NewKeyStoreListResource,
+	}
+}
+
+// Functions defines the provider functions implemented in the provider.
+func (p *trmmProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewDurationSecondsFunction,
+		NewDurationMinutesFunction,
 	}
 }
 
@@ -130,6 +355,27 @@ func (p *trmmProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewScriptResource,
 		NewScriptSnippetResource,
 		NewKeyStoreResource,
+		NewAgentCustomFieldsResource,
+		NewAlertTemplateAssignmentResource,
+		NewAlertTemplateActionsResource,
+		NewAgentPatchPolicyResource,
+		NewAgentAlertThresholdsResource,
+		NewSSOProviderResource,
+		NewScheduledReportResource,
+		// Action resources (perform an operation on create)
+		NewCancelPendingActionResource,
+		NewBulkMaintenanceResource,
+		NewBulkRunScriptResource,
+		NewGenerateReportResource,
+		NewResolveAlertsResource,
+		NewAgentRecoveryResource,
+		NewScheduleRebootResource,
+		NewRunAgentURLActionResource,
+		NewCleanupOfflineAgentsResource,
+		NewRefreshAgentResource,
+		NewInstallerResource,
+		NewClearCacheResource,
+		NewMoveAgentsResource,
 		// NewAgentResource,
 		// NewCheckResource,
 		// NewTaskResource,
@@ -138,15 +384,76 @@ func (p *trmmProvider) Resources(_ context.Context) []func() resource.Resource {
 	}
 }
 
+// newHTTPClient creates the HTTP client used for all API requests. Every request
+// goes to the same host, so the per-host idle pool is sized like the overall pool.
+func newHTTPClient(maxIdleConns int, idleConnTimeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = maxIdleConns
+	transport.IdleConnTimeout = idleConnTimeout
+
+	return &http.Client{Transport: transport}
+}
+
 // ClientConfig holds the configuration for the TRMM API client
 type ClientConfig struct {
 	BaseURL    string
 	APIKey     string
 	HTTPClient *http.Client
+
+	// ReadOnly rejects every request that could modify Tactical RMM, see
+	// allowsRequest and denyWrite.
+	ReadOnly bool
+
+	// limiter enforces requests_per_second for every request sent through Do.
+	limiter *requestLimiter
+
+	// apiKeySource and requestsPerSecond record the resolved configuration
+	// for the provider_config data source.
+	apiKeySource      string
+	requestsPerSecond int64
+
+	// onConflict is the on_conflict policy, see adoptOrReplace.
+	onConflict string
+
+	// recreateRules are the recreate_on_read_errors, see removeOnReadError.
+	recreateRules []recreateRule
+
+	// shellDefaultTimeouts are the shell_default_timeouts, see
+	// scriptDefaultTimeout.
+	shellDefaultTimeouts map[string]int64
+
+	// scriptDetails caches script details by script ID for the lifetime of
+	// the provider process, see fetchScriptDetails.
+	scriptDetails sync.Map
+
+	// secrets are values redacted from diagnostics and logs in addition to
+	// APIKey, see addSecret and redact.
+	secrets sync.Map
+
+	// features caches which optional server features are available, see
+	// hasFeature.
+	features sync.Map
+
+	// plannedSnippets maps the names of the script snippets planned in this
+	// run to their code, empty while unknown, so scripts can reference
+	// snippets that do not exist yet and cycles between new snippets are
+	// found at plan time.
+	plannedSnippets sync.Map
 }
 
 // Do performs an HTTP request with authentication
 func (c *ClientConfig) Do(req *http.Request) (*http.Response, error) {
+	if !c.allowsRequest(req) {
+		return nil, errReadOnly
+	}
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	tflog.Debug(c.logContext(req.Context()), "Sending Tactical RMM API request", map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	})
 	req.Header.Set("X-API-KEY", c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 	return c.HTTPClient.Do(req)
@@ -0,0 +1,160 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProviderConfigDataSource{}
+
+func NewProviderConfigDataSource() datasource.DataSource {
+    return &ProviderConfigDataSource{}
+}
+
+// ProviderConfigDataSource exposes the configuration the provider resolved,
+// without the API key. It sends no requests.
+type ProviderConfigDataSource struct {
+    client *ClientConfig
+}
+
+// ProviderConfigDataSourceModel describes the data source data model.
+type ProviderConfigDataSourceModel struct {
+    Endpoint        types.String `tfsdk:"endpoint"`
+    APIKeySource    types.String `tfsdk:"api_key_source"`
+    MaxIdleConns    types.Int64  `tfsdk:"max_idle_conns"`
+    IdleConnTimeout types.Int64  `tfsdk:"idle_conn_timeout"`
+    RequestsPerSec  types.Int64  `tfsdk:"requests_per_second"`
+    ReadOnly        types.Bool   `tfsdk:"read_only"`
+    RecreateOnRead  types.List   `tfsdk:"recreate_on_read_errors"`
+    ShellTimeouts   types.Map    `tfsdk:"shell_default_timeouts"`
+    OnConflict      types.String `tfsdk:"on_conflict"`
+}
+
+func (d *ProviderConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_provider_config"
+}
+
+func (d *ProviderConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Effective provider configuration, after defaults are applied, for debugging. " +
+            "The API key is never exposed, only where it came from.",
+
+        Attributes: map[string]schema.Attribute{
+            "endpoint": schema.StringAttribute{
+                MarkdownDescription: "Tactical RMM API endpoint requests are sent to",
+                Computed:            true,
+            },
+            "api_key_source": schema.StringAttribute{
+                MarkdownDescription: "Where the API key came from: `api_key` or `api_key_command`",
+                Computed:            true,
+            },
+            "max_idle_conns": schema.Int64Attribute{
+                MarkdownDescription: "Maximum number of idle keep-alive connections",
+                Computed:            true,
+            },
+            "idle_conn_timeout": schema.Int64Attribute{
+                MarkdownDescription: "Time in seconds an idle keep-alive connection is kept open",
+                Computed:            true,
+            },
+            "requests_per_second": schema.Int64Attribute{
+                MarkdownDescription: "Maximum number of API requests started per second, 0 when unlimited",
+                Computed:            true,
+            },
+            "read_only": schema.BoolAttribute{
+                MarkdownDescription: "Whether requests that could modify Tactical RMM are refused",
+                Computed:            true,
+            },
+            "recreate_on_read_errors": schema.ListNestedAttribute{
+                MarkdownDescription: "API errors that remove a script or script snippet from state so it is created again",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "status_code": schema.Int64Attribute{
+                            MarkdownDescription: "HTTP status code of the failed read",
+                            Computed:            true,
+                        },
+                        "message_contains": schema.StringAttribute{
+                            MarkdownDescription: "Text the error message must contain, empty when any message matches",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+            "shell_default_timeouts": schema.MapAttribute{
+                MarkdownDescription: "Default timeout in seconds of scripts created without default_timeout, for every shell",
+                Computed:            true,
+                ElementType:         types.Int64Type,
+            },
+            "on_conflict": schema.StringAttribute{
+                MarkdownDescription: "What creates do when an object with the same name exists: `error`, `adopt` or `replace`",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *ProviderConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *ProviderConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    data := ProviderConfigDataSourceModel{
+        Endpoint:        types.StringValue(d.client.BaseURL),
+        APIKeySource:    types.StringValue(d.client.apiKeySource),
+        MaxIdleConns:    types.Int64Null(),
+        IdleConnTimeout: types.Int64Null(),
+        RequestsPerSec:  types.Int64Value(d.client.requestsPerSecond),
+        ReadOnly:        types.BoolValue(d.client.ReadOnly),
+        OnConflict:      types.StringValue(d.client.onConflict),
+    }
+
+    // The provider always builds an *http.Transport, see newHTTPClient
+    if d.client.HTTPClient != nil {
+        if transport, ok := d.client.HTTPClient.Transport.(*http.Transport); ok {
+            data.MaxIdleConns = types.Int64Value(int64(transport.MaxIdleConns))
+            data.IdleConnTimeout = types.Int64Value(int64(transport.IdleConnTimeout.Seconds()))
+        }
+    }
+
+    rules := make([]recreateRuleModel, 0, len(d.client.recreateRules))
+    for _, rule := range d.client.recreateRules {
+        rules = append(rules, recreateRuleModel{
+            StatusCode:      types.Int64Value(int64(rule.statusCode)),
+            MessageContains: types.StringValue(rule.messageContains),
+        })
+    }
+    rulesValue, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: recreateRuleAttrTypes}, rules)
+    resp.Diagnostics.Append(diags...)
+    data.RecreateOnRead = rulesValue
+
+    timeouts := make(map[string]int64, len(scriptShells))
+    for _, shell := range scriptShells {
+        timeouts[shell] = d.client.scriptDefaultTimeout(shell)
+    }
+    timeoutsValue, diags := types.MapValueFrom(ctx, types.Int64Type, timeouts)
+    resp.Diagnostics.Append(diags...)
+    data.ShellTimeouts = timeoutsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
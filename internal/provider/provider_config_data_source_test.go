@@ -0,0 +1,104 @@
+package provider
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// emptyProviderConfig is the provider_config data source configuration, which
+// has no configurable attributes.
+func emptyProviderConfig() *ProviderConfigDataSourceModel {
+    return &ProviderConfigDataSourceModel{
+        RecreateOnRead: types.ListNull(types.ObjectType{AttrTypes: recreateRuleAttrTypes}),
+        ShellTimeouts:  types.MapNull(types.Int64Type),
+    }
+}
+
+func TestProviderConfigDataSourceRead(t *testing.T) {
+    const apiKey = "secret-key-0123456789"
+    configured := configureProvider(t, trmmProviderModel{
+        Endpoint:       types.StringValue("https://rmm.example.com/api"),
+        APIKey:         types.StringValue(apiKey),
+        MaxIdleConns:   types.Int64Value(250),
+        RequestsPerSec: types.Int64Value(5),
+        ReadOnly:       types.BoolValue(true),
+        OnConflict:     types.StringValue(onConflictReplace),
+        RecreateOnRead: types.ListValueMust(types.ObjectType{AttrTypes: recreateRuleAttrTypes}, []attr.Value{
+            types.ObjectValueMust(recreateRuleAttrTypes, map[string]attr.Value{
+                "status_code":      types.Int64Value(500),
+                "message_contains": types.StringNull(),
+            }),
+        }),
+        ShellTimeouts: types.MapValueMust(types.Int64Type, map[string]attr.Value{"python": types.Int64Value(300)}),
+    })
+    if configured.Diagnostics.HasError() {
+        t.Fatalf("unexpected configure diagnostics: %v", configured.Diagnostics)
+    }
+
+    d := &ProviderConfigDataSource{client: configured.DataSourceData.(*ClientConfig)}
+    resp := readDataSource(t, d, emptyProviderConfig())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    // The key must not appear in any attribute, nor be an attribute at all
+    if strings.Contains(resp.State.Raw.String(), apiKey) {
+        t.Errorf("the API key is exposed in the state: %s", resp.State.Raw)
+    }
+    if _, ok := resp.State.Schema.GetAttributes()["api_key"]; ok {
+        t.Error("the schema must not have an api_key attribute")
+    }
+
+    var data ProviderConfigDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if data.Endpoint.ValueString() != "https://rmm.example.com/api" || data.APIKeySource.ValueString() != "api_key" {
+        t.Errorf("unexpected endpoint or key source: %s, %s", data.Endpoint, data.APIKeySource)
+    }
+    if data.MaxIdleConns.ValueInt64() != 250 || data.IdleConnTimeout.ValueInt64() != defaultIdleConnTimeout {
+        t.Errorf("unexpected connection pool: %s, %s", data.MaxIdleConns, data.IdleConnTimeout)
+    }
+    if data.OnConflict.ValueString() != onConflictReplace {
+        t.Errorf("expected the replace conflict policy, got %s", data.OnConflict)
+    }
+    if data.RequestsPerSec.ValueInt64() != 5 || !data.ReadOnly.ValueBool() {
+        t.Errorf("unexpected rate limit or read-only mode: %s, %s", data.RequestsPerSec, data.ReadOnly)
+    }
+    if len(data.RecreateOnRead.Elements()) != 1 {
+        t.Errorf("expected one recreate rule, got %s", data.RecreateOnRead)
+    }
+
+    // Shells without an entry report the built-in default
+    timeouts := data.ShellTimeouts.Elements()
+    if len(timeouts) != len(scriptShells) || !timeouts["python"].Equal(types.Int64Value(300)) ||
+        !timeouts["cmd"].Equal(types.Int64Value(defaultScriptTimeout)) {
+        t.Errorf("unexpected shell default timeouts: %s", data.ShellTimeouts)
+    }
+}
+
+func TestProviderConfigDataSourceRead_APIKeyCommand(t *testing.T) {
+    configured := configureProvider(t, trmmProviderModel{
+        APIKeyCommand: apiKeyHelperCommand(t, "print"),
+    })
+    if configured.Diagnostics.HasError() {
+        t.Fatalf("unexpected configure diagnostics: %v", configured.Diagnostics)
+    }
+
+    d := &ProviderConfigDataSource{client: configured.DataSourceData.(*ClientConfig)}
+    resp := readDataSource(t, d, emptyProviderConfig())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if strings.Contains(resp.State.Raw.String(), "key-from-helper") {
+        t.Errorf("the API key is exposed in the state: %s", resp.State.Raw)
+    }
+
+    var data ProviderConfigDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if data.APIKeySource.ValueString() != "api_key_command" || data.RequestsPerSec.ValueInt64() != 0 {
+        t.Errorf("unexpected key source or rate limit: %s, %s", data.APIKeySource, data.RequestsPerSec)
+    }
+}
@@ -1,7 +1,13 @@
 package provider
 
 import (
+    "context"
+    "net/http"
     "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/provider"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
     "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -44,3 +50,81 @@ func TestClientConfig_Do(t *testing.T) {
         t.Errorf("Expected APIKey to be test-key, got %s", client.APIKey)
     }
 }
+
+// configureProvider runs the provider Configure method with the given configuration.
+func configureProvider(t *testing.T, model trmmProviderModel) provider.ConfigureResponse {
+    t.Helper()
+    ctx := context.Background()
+    p := &trmmProvider{version: "test"}
+
+    var schemaResp provider.SchemaResponse
+    p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+    // A zero list has no element type and cannot be converted
+    if model.APIKeyCommand.ElementType(ctx) == nil {
+        model.APIKeyCommand = types.ListNull(types.StringType)
+    }
+    if model.RecreateOnRead.ElementType(ctx) == nil {
+        model.RecreateOnRead = types.ListNull(types.ObjectType{AttrTypes: recreateRuleAttrTypes})
+    }
+    if model.ShellTimeouts.ElementType(ctx) == nil {
+        model.ShellTimeouts = types.MapNull(types.Int64Type)
+    }
+
+    // Build the raw configuration value through a state, which accepts a model
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, &model); diags.HasError() {
+        t.Fatalf("unable to build provider configuration: %v", diags)
+    }
+
+    var resp provider.ConfigureResponse
+    p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+    return resp
+}
+
+func TestProviderConfigure_TransportFromAttributes(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        Endpoint:        types.StringValue("https://test.example.com/api"),
+        APIKey:          types.StringValue("test-key"),
+        MaxIdleConns:    types.Int64Value(250),
+        IdleConnTimeout: types.Int64Value(30),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    transport := resp.ResourceData.(*ClientConfig).HTTPClient.Transport.(*http.Transport)
+    if transport.MaxIdleConns != 250 || transport.MaxIdleConnsPerHost != 250 {
+        t.Errorf("expected 250 idle connections, got %d (per host %d)", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+    }
+    if transport.IdleConnTimeout != 30*time.Second {
+        t.Errorf("expected idle timeout of 30s, got %s", transport.IdleConnTimeout)
+    }
+}
+
+func TestProviderConfigure_TransportDefaults(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKey: types.StringValue("test-key"),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    transport := resp.ResourceData.(*ClientConfig).HTTPClient.Transport.(*http.Transport)
+    if transport.MaxIdleConns != defaultMaxIdleConns || transport.MaxIdleConnsPerHost != defaultMaxIdleConns {
+        t.Errorf("expected %d idle connections, got %d (per host %d)", defaultMaxIdleConns, transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+    }
+    if transport.IdleConnTimeout != defaultIdleConnTimeout*time.Second {
+        t.Errorf("expected idle timeout of %ds, got %s", defaultIdleConnTimeout, transport.IdleConnTimeout)
+    }
+}
+
+func TestProviderConfigure_RejectsNegativePoolSettings(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKey:       types.StringValue("test-key"),
+        MaxIdleConns: types.Int64Value(-1),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a negative max_idle_conns")
+    }
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+// requestLimiter spaces API requests evenly so that at most a fixed number of
+// requests start per second. A nil limiter does not limit.
+type requestLimiter struct {
+    mu       sync.Mutex
+    interval time.Duration
+    next     time.Time
+}
+
+// newRequestLimiter returns a limiter allowing perSecond requests per second,
+// or nil when perSecond is not positive.
+func newRequestLimiter(perSecond int64) *requestLimiter {
+    if perSecond <= 0 {
+        return nil
+    }
+    return &requestLimiter{interval: time.Second / time.Duration(perSecond)}
+}
+
+// Wait blocks until the next request may start or ctx is done.
+func (l *requestLimiter) Wait(ctx context.Context) error {
+    if l == nil {
+        return nil
+    }
+
+    l.mu.Lock()
+    now := time.Now()
+    if l.next.Before(now) {
+        l.next = now
+    }
+    delay := l.next.Sub(now)
+    l.next = l.next.Add(l.interval)
+    l.mu.Unlock()
+
+    if delay <= 0 {
+        return nil
+    }
+
+    timer := time.NewTimer(delay)
+    defer timer.Stop()
+    select {
+    case <-ctx.Done():
+        return ctx.Err()
+    case <-timer.C:
+        return nil
+    }
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+    "context"
+    "testing"
+    "time"
+)
+
+func TestRequestLimiter_SpacesRequests(t *testing.T) {
+    limiter := newRequestLimiter(50)
+
+    start := time.Now()
+    for i := 0; i < 5; i++ {
+        if err := limiter.Wait(context.Background()); err != nil {
+            t.Fatalf("unexpected error: %s", err)
+        }
+    }
+
+    // The first request starts immediately, the other four are 20ms apart
+    if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+        t.Errorf("expected requests to be spaced at least 80ms in total, took %s", elapsed)
+    }
+}
+
+func TestRequestLimiter_UnlimitedWhenNotConfigured(t *testing.T) {
+    if limiter := newRequestLimiter(0); limiter != nil {
+        t.Fatalf("expected no limiter for 0 requests per second")
+    }
+
+    var limiter *requestLimiter
+    if err := limiter.Wait(context.Background()); err != nil {
+        t.Errorf("expected a nil limiter not to block, got %s", err)
+    }
+}
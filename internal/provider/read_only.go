@@ -0,0 +1,53 @@
+package provider
+
+import (
+    "context"
+    "errors"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// errReadOnly is returned by Do for requests that could modify Tactical RMM
+// while the provider is in read-only mode.
+var errReadOnly = errors.New("provider is in read-only mode")
+
+// readRequestKey marks the context of a request that uses a method other than
+// GET but does not modify anything, see withReadRequest.
+type readRequestKey struct{}
+
+// withReadRequest marks requests made with ctx as reads. A few TRMM endpoints
+// take their query as a PATCH or POST body, e.g. check history; they are still
+// allowed in read-only mode.
+func withReadRequest(ctx context.Context) context.Context {
+    return context.WithValue(ctx, readRequestKey{}, true)
+}
+
+// allowsRequest reports whether req may be sent. In read-only mode only GET,
+// HEAD and requests marked with withReadRequest are sent.
+func (c *ClientConfig) allowsRequest(req *http.Request) bool {
+    if !c.ReadOnly {
+        return true
+    }
+    if req.Method == http.MethodGet || req.Method == http.MethodHead {
+        return true
+    }
+    read, _ := req.Context().Value(readRequestKey{}).(bool)
+    return read
+}
+
+// denyWrite reports an error and returns true when the provider is in
+// read-only mode. Create, Update and Delete call it before anything else so
+// they fail without sending a single request.
+func (c *ClientConfig) denyWrite(diags *diag.Diagnostics) bool {
+    if c == nil || !c.ReadOnly {
+        return false
+    }
+
+    diags.AddError(
+        "Provider Is in Read-Only Mode",
+        "The provider is in read-only mode (read_only = true), so resources cannot be created, updated or deleted. "+
+            "Data sources and refreshing existing resources work as usual.",
+    )
+    return true
+}
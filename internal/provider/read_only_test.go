@@ -0,0 +1,82 @@
+package provider
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProviderConfigure_ReadOnly(t *testing.T) {
+    resp := configureProvider(t, trmmProviderModel{
+        APIKey:   types.StringValue("test-key"),
+        ReadOnly: types.BoolValue(true),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if client := resp.ResourceData.(*ClientConfig); !client.ReadOnly {
+        t.Error("expected the client to be read-only")
+    }
+}
+
+func TestScriptResourceCreate_ReadOnlySendsNoRequest(t *testing.T) {
+    var requests int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&requests, 1)
+        writeJSON(t, w, "ok")
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.ReadOnly = true
+    r := &ScriptResource{client: client}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Cleanup"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Remove-Item $env:TEMP"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error in read-only mode")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Provider Is in Read-Only Mode" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+    if got := atomic.LoadInt32(&requests); got != 0 {
+        t.Errorf("expected no requests, got %d", got)
+    }
+}
+
+func TestClientConfigDo_ReadOnlyRejectsWrites(t *testing.T) {
+    var requests int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&requests, 1)
+        writeJSON(t, w, "ok")
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.ReadOnly = true
+    ctx := context.Background()
+
+    if _, _, err := client.sendJSON(ctx, "DELETE", server.URL+"/scripts/1/", nil); !errors.Is(err, errReadOnly) {
+        t.Errorf("expected a read-only error for DELETE, got %v", err)
+    }
+    if _, _, err := client.sendJSON(ctx, "GET", server.URL+"/scripts/", nil); err != nil {
+        t.Errorf("expected GET to be allowed, got %v", err)
+    }
+    if _, _, err := client.sendJSON(withReadRequest(ctx), "PATCH", server.URL+"/checks/1/history/", map[string]interface{}{}); err != nil {
+        t.Errorf("expected a marked read to be allowed, got %v", err)
+    }
+    if got := atomic.LoadInt32(&requests); got != 2 {
+        t.Errorf("expected 2 requests to reach the server, got %d", got)
+    }
+}
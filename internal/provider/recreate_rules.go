@@ -0,0 +1,105 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// recreateRuleAttrTypes are the attribute types of a recreate_on_read_errors entry.
+var recreateRuleAttrTypes = map[string]attr.Type{
+    "status_code":      types.Int64Type,
+    "message_contains": types.StringType,
+}
+
+// recreateRuleModel describes a recreate_on_read_errors entry.
+type recreateRuleModel struct {
+    StatusCode      types.Int64  `tfsdk:"status_code"`
+    MessageContains types.String `tfsdk:"message_contains"`
+}
+
+// recreateRule is an API error that marks the object a resource manages as
+// broken. A Read failing with it removes the resource from state, so the next
+// apply creates it again instead of failing on every refresh. Rules require
+// on_conflict replace, which deletes the broken object on that create.
+type recreateRule struct {
+    statusCode      int
+    messageContains string
+}
+
+// matches reports whether a failed response matches the rule. The message is
+// compared case-insensitively against the TRMM error message.
+func (r recreateRule) matches(statusCode int, body []byte) bool {
+    if statusCode != r.statusCode {
+        return false
+    }
+    return strings.Contains(strings.ToLower(errorMessage(body)), strings.ToLower(r.messageContains))
+}
+
+// parseRecreateRules converts the recreate_on_read_errors configuration.
+// Status codes that say nothing about the object itself, like failed
+// authentication or throttling, are rejected so a misconfiguration cannot
+// drop every resource from state.
+func parseRecreateRules(ctx context.Context, list types.List) ([]recreateRule, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    if list.IsNull() || list.IsUnknown() {
+        return nil, diags
+    }
+
+    var models []recreateRuleModel
+    diags.Append(list.ElementsAs(ctx, &models, false)...)
+    if diags.HasError() {
+        return nil, diags
+    }
+
+    rules := make([]recreateRule, 0, len(models))
+    for i, model := range models {
+        statusCode := int(model.StatusCode.ValueInt64())
+        switch {
+        case statusCode < 400 || statusCode > 599:
+            diags.AddAttributeError(
+                path.Root("recreate_on_read_errors").AtListIndex(i).AtName("status_code"),
+                "Invalid Recreate Rule",
+                fmt.Sprintf("status_code must be an HTTP error status between 400 and 599, got %d.", statusCode),
+            )
+            continue
+        case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests:
+            diags.AddAttributeError(
+                path.Root("recreate_on_read_errors").AtListIndex(i).AtName("status_code"),
+                "Invalid Recreate Rule",
+                fmt.Sprintf("status_code %d is returned for every object when the API key or rate limit is the problem and cannot be used to recreate resources.", statusCode),
+            )
+            continue
+        }
+        rules = append(rules, recreateRule{statusCode: statusCode, messageContains: model.MessageContains.ValueString()})
+    }
+
+    return rules, diags
+}
+
+// removeOnReadError removes the resource from state when a failed read matches
+// one of the configured recreate rules and reports it as a warning. It returns
+// false when no rule matches and the caller should report the error.
+func (c *ClientConfig) removeOnReadError(ctx context.Context, state *tfsdk.State, objectKind string, statusCode int, body []byte, diags *diag.Diagnostics) bool {
+    for _, rule := range c.recreateRules {
+        if !rule.matches(statusCode, body) {
+            continue
+        }
+
+        state.RemoveResource(ctx)
+        diags.AddWarning(
+            "Resource Removed From State",
+            fmt.Sprintf("Reading the %s failed with status code %d (%s), which matches recreate_on_read_errors. "+
+                "The resource was removed from state. The next apply deletes the broken %s, as on_conflict is \"replace\", and creates it again.", objectKind, statusCode, errorMessage(body), objectKind),
+        )
+        return true
+    }
+    return false
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "sync"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func brokenScriptServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/scripts/42/" {
+            http.NotFound(w, r)
+            return
+        }
+        w.WriteHeader(http.StatusInternalServerError)
+        writeJSON(t, w, map[string]interface{}{"detail": "Script body is corrupt"})
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func brokenScriptState() *ScriptResourceModel {
+    return &ScriptResourceModel{
+        Id:                 types.Int64Value(42),
+        Name:               types.StringValue("Cleanup"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    }
+}
+
+func TestScriptResourceRead_RemovesOnConfiguredError(t *testing.T) {
+    client := newTestClient(brokenScriptServer(t))
+    client.recreateRules = []recreateRule{{statusCode: http.StatusInternalServerError, messageContains: "CORRUPT"}}
+
+    r := &ScriptResource{client: client}
+    resp := readResource(t, r, brokenScriptState())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if !resp.State.Raw.IsNull() {
+        t.Error("expected the resource to be removed from state")
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 1 || warnings[0].Summary() != "Resource Removed From State" {
+        t.Errorf("expected a removal warning, got %v", resp.Diagnostics)
+    }
+}
+
+func TestScriptResourceRead_KeepsStateOnOtherErrors(t *testing.T) {
+    client := newTestClient(brokenScriptServer(t))
+    client.recreateRules = []recreateRule{{statusCode: http.StatusInternalServerError, messageContains: "does not exist"}}
+
+    r := &ScriptResource{client: client}
+    resp := readResource(t, r, brokenScriptState())
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected the read error to be reported")
+    }
+    if resp.State.Raw.IsNull() {
+        t.Error("expected the resource to stay in state")
+    }
+}
+
+func TestKeyStoreResourceRead_IgnoresRecreateRules(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusInternalServerError)
+        writeJSON(t, w, "CORRUPT entry")
+    }))
+    t.Cleanup(server.Close)
+    client := newTestClient(server)
+    client.recreateRules = []recreateRule{{statusCode: http.StatusInternalServerError, messageContains: "CORRUPT"}}
+
+    // A failed list concerns every entry, so the entry stays in state
+    r := &KeyStoreResource{client: client}
+    resp := readResource(t, r, &KeyStoreResourceModel{
+        Id:        types.Int64Value(4),
+        Name:      types.StringValue("api_token"),
+        Value:     types.StringValue("secret"),
+        ValueJSON: types.StringNull(),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected the read error to be reported")
+    }
+    if resp.State.Raw.IsNull() {
+        t.Error("expected the resource to stay in state")
+    }
+}
+
+func TestParseRecreateRules_RejectsUnsafeStatusCodes(t *testing.T) {
+    rule := func(statusCode int64) attr.Value {
+        return types.ObjectValueMust(recreateRuleAttrTypes, map[string]attr.Value{
+            "status_code":      types.Int64Value(statusCode),
+            "message_contains": types.StringNull(),
+        })
+    }
+    list := types.ListValueMust(types.ObjectType{AttrTypes: recreateRuleAttrTypes}, []attr.Value{rule(500), rule(403), rule(200)})
+
+    rules, diags := parseRecreateRules(context.Background(), list)
+    if diags.ErrorsCount() != 2 {
+        t.Errorf("expected 403 and 200 to be rejected, got %v", diags)
+    }
+    if len(rules) != 1 || rules[0].statusCode != 500 {
+        t.Errorf("expected only the 500 rule, got %v", rules)
+    }
+}
+
+func TestProviderConfigure_RecreateRulesRequireReplace(t *testing.T) {
+    rules := types.ListValueMust(types.ObjectType{AttrTypes: recreateRuleAttrTypes}, []attr.Value{
+        types.ObjectValueMust(recreateRuleAttrTypes, map[string]attr.Value{
+            "status_code":      types.Int64Value(500),
+            "message_contains": types.StringValue("corrupt"),
+        }),
+    })
+
+    resp := configureProvider(t, trmmProviderModel{APIKey: types.StringValue("test-key"), RecreateOnRead: rules})
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Recreate Rules" {
+        t.Errorf("expected recreate rules without on_conflict = \"replace\" to be rejected, got %v", resp.Diagnostics)
+    }
+
+    resp = configureProvider(t, trmmProviderModel{APIKey: types.StringValue("test-key"), RecreateOnRead: rules, OnConflict: types.StringValue(onConflictReplace)})
+    if resp.Diagnostics.HasError() {
+        t.Errorf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+}
+
+func TestScriptSnippetResource_RecreatesBrokenSnippet(t *testing.T) {
+    var mu sync.Mutex
+    var writes []string
+    deleted, created := false, false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        defer mu.Unlock()
+        if r.Method != http.MethodGet {
+            writes = append(writes, r.Method+" "+r.URL.Path)
+        }
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/7/":
+            w.WriteHeader(http.StatusInternalServerError)
+            writeJSON(t, w, map[string]interface{}{"detail": "Snippet code is corrupt"})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/":
+            snippets := []map[string]interface{}{}
+            if !deleted {
+                snippets = append(snippets, map[string]interface{}{"id": 7, "name": "GetDiskSpace"})
+            }
+            if created {
+                snippets = append(snippets, map[string]interface{}{"id": 8, "name": "GetDiskSpace", "code": "Get-PSDrive"})
+            }
+            writeJSON(t, w, snippets)
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodDelete && r.URL.Path == "/scripts/snippets/7/":
+            deleted = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/snippets/":
+            created = true
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.onConflict = onConflictReplace
+    client.recreateRules = []recreateRule{{statusCode: http.StatusInternalServerError, messageContains: "corrupt"}}
+    r := &ScriptSnippetResource{client: client}
+
+    readResp := readResource(t, r, snippetState("GetDiskSpace", false))
+    if readResp.Diagnostics.HasError() || !readResp.State.Raw.IsNull() {
+        t.Fatalf("expected the broken snippet to be removed from state, got %v", readResp.Diagnostics)
+    }
+
+    createResp := createResource(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("GetDiskSpace"),
+        Code:         types.StringValue("Get-PSDrive"),
+        ReferencedBy: types.ListNull(types.StringType),
+    })
+    if createResp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", createResp.Diagnostics)
+    }
+    if !reflect.DeepEqual(writes, []string{"DELETE /scripts/snippets/7/", "POST /scripts/snippets/"}) {
+        t.Errorf("expected the broken snippet to be deleted before the create, got %v", writes)
+    }
+
+    var state ScriptSnippetResourceModel
+    createResp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 8 {
+        t.Errorf("expected the new snippet 8 to be tracked, got %d", state.Id.ValueInt64())
+    }
+}
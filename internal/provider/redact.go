@@ -0,0 +1,130 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/url"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedValue replaces secrets in diagnostics.
+const redactedValue = "[REDACTED]"
+
+// minSecretLength is the shortest value that is redacted. Shorter values
+// would mostly match unrelated text, e.g. a keystore entry set to "1".
+const minSecretLength = 4
+
+// secretHeaderPattern matches the value of an authentication header written
+// as "X-API-KEY: value", "Authorization=Bearer value" or as a JSON or Go map
+// entry, so headers are redacted even when the value is not a known secret.
+var secretHeaderPattern = regexp.MustCompile(`(?i)\b(x-api-key|authorization)(["']?\s*[:=]\s*\[?["']?)((?:bearer|basic|token)\s+)?[^\s"'\],;}&]+`)
+
+// addSecret registers a value that must never appear in diagnostics or logs,
+// e.g. a keystore value read from Tactical RMM. The configured API key is
+// always redacted and does not need to be added.
+func (c *ClientConfig) addSecret(value string) {
+    if c == nil || len(value) < minSecretLength {
+        return
+    }
+    c.secrets.Store(value, struct{}{})
+}
+
+// secretStrings returns every known secret in each form it can take in a
+// message: as is, quoted by %q, JSON encoded and URL encoded. The result is
+// sorted longest first so a secret containing another is replaced whole.
+func (c *ClientConfig) secretStrings() []string {
+    if c == nil {
+        return nil
+    }
+
+    seen := map[string]bool{}
+    var secrets []string
+    add := func(secret string) {
+        for _, variant := range secretVariants(secret) {
+            if len(variant) >= minSecretLength && !seen[variant] {
+                seen[variant] = true
+                secrets = append(secrets, variant)
+            }
+        }
+    }
+
+    add(c.APIKey)
+    c.secrets.Range(func(key, _ interface{}) bool {
+        add(key.(string))
+        return true
+    })
+
+    sort.Slice(secrets, func(i, j int) bool {
+        if len(secrets[i]) != len(secrets[j]) {
+            return len(secrets[i]) > len(secrets[j])
+        }
+        return secrets[i] < secrets[j]
+    })
+    return secrets
+}
+
+// secretVariants returns the encodings of secret that can end up in a
+// message, e.g. through %q, an echoed JSON request body or a URL.
+func secretVariants(secret string) []string {
+    quoted := strconv.Quote(secret)
+    variants := []string{secret, quoted[1 : len(quoted)-1], url.QueryEscape(secret), url.PathEscape(secret)}
+    if encoded, err := json.Marshal(secret); err == nil {
+        variants = append(variants, string(encoded[1:len(encoded)-1]))
+    }
+    return variants
+}
+
+// redact replaces every known secret and authentication header value in s.
+func (c *ClientConfig) redact(s string) string {
+    for _, secret := range c.secretStrings() {
+        s = strings.ReplaceAll(s, secret, redactedValue)
+    }
+    return secretHeaderPattern.ReplaceAllString(s, "${1}${2}${3}"+redactedValue)
+}
+
+// redactDiagnostics redacts the summary and detail of every diagnostic in
+// diags, keeping severities and attribute paths. Resources and data sources
+// defer it so no formatting path can leak a secret into Terraform's output.
+func (c *ClientConfig) redactDiagnostics(diags *diag.Diagnostics) {
+    if len(*diags) == 0 {
+        return
+    }
+
+    redacted := make(diag.Diagnostics, 0, len(*diags))
+    for _, d := range *diags {
+        summary, detail := c.redact(d.Summary()), c.redact(d.Detail())
+        if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+            if d.Severity() == diag.SeverityError {
+                redacted.AddAttributeError(withPath.Path(), summary, detail)
+            } else {
+                redacted.AddAttributeWarning(withPath.Path(), summary, detail)
+            }
+            continue
+        }
+        if d.Severity() == diag.SeverityError {
+            redacted.AddError(summary, detail)
+        } else {
+            redacted.AddWarning(summary, detail)
+        }
+    }
+    *diags = redacted
+}
+
+// logContext returns ctx with tflog masking for every known secret and
+// authentication header value, for use with any tflog call.
+func (c *ClientConfig) logContext(ctx context.Context) context.Context {
+    secrets := c.secretStrings()
+    if len(secrets) > 0 {
+        ctx = tflog.MaskMessageStrings(ctx, secrets...)
+        ctx = tflog.MaskAllFieldValuesStrings(ctx, secrets...)
+    }
+    ctx = tflog.MaskMessageRegexes(ctx, secretHeaderPattern)
+    ctx = tflog.MaskAllFieldValuesRegexes(ctx, secretHeaderPattern)
+    return ctx
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+const (
+    testRedactAPIKey   = "trmm-key/with+special=chars"
+    testRedactKeystore = "hunter2 \"quoted\" & more"
+)
+
+// assertRedacted fails when any form of a test secret remains in s.
+func assertRedacted(t *testing.T, s string) {
+    t.Helper()
+    for _, secret := range []string{testRedactAPIKey, testRedactKeystore} {
+        for _, variant := range secretVariants(secret) {
+            if strings.Contains(s, variant) {
+                t.Errorf("secret %q leaked into %q", variant, s)
+            }
+        }
+    }
+}
+
+func redactTestClient() *ClientConfig {
+    c := &ClientConfig{APIKey: testRedactAPIKey}
+    c.addSecret(testRedactKeystore)
+    return c
+}
+
+func TestRedact_FormattingPaths(t *testing.T) {
+    c := redactTestClient()
+
+    jsonBody, _ := json.Marshal(map[string]string{"name": "token", "value": testRedactKeystore})
+    messages := map[string]string{
+        "plain":      fmt.Sprintf("got error: %s", testRedactAPIKey),
+        "quoted":     fmt.Sprintf("value %q is invalid", testRedactKeystore),
+        "value":      fmt.Sprintf("%v", []string{testRedactAPIKey, testRedactKeystore}),
+        "json body":  fmt.Sprintf("response: %s", jsonBody),
+        "query":      "URL: https://rmm.example.com/core/?key=" + url.QueryEscape(testRedactAPIKey),
+        "path":       "URL: https://rmm.example.com/core/" + url.PathEscape(testRedactKeystore) + "/",
+        "url error":  (&url.Error{Op: "Get", URL: "https://rmm.example.com/?k=" + url.QueryEscape(testRedactAPIKey), Err: io.EOF}).Error(),
+        "error wrap": fmt.Errorf("unable to read: %w", fmt.Errorf("key %s rejected", testRedactAPIKey)).Error(),
+    }
+    for name, message := range messages {
+        t.Run(name, func(t *testing.T) {
+            redacted := c.redact(message)
+            assertRedacted(t, redacted)
+            if !strings.Contains(redacted, redactedValue) {
+                t.Errorf("expected %q to contain %s", redacted, redactedValue)
+            }
+        })
+    }
+}
+
+func TestRedact_AuthenticationHeaders(t *testing.T) {
+    var c *ClientConfig
+
+    tests := map[string]string{
+        "X-API-KEY: unknown-key-1":                             "X-API-KEY: " + redactedValue,
+        "Authorization: Bearer abc.def.ghi":                    "Authorization: Bearer " + redactedValue,
+        `{"Authorization": "Token abcdef", "ok": true}`:        `{"Authorization": "Token ` + redactedValue + `", "ok": true}`,
+        "map[Content-Type:[application/json] X-Api-Key:[k3y]]": "map[Content-Type:[application/json] X-Api-Key:[" + redactedValue + "]]",
+        "x-api-key=k3y&limit=10":                               "x-api-key=" + redactedValue + "&limit=10",
+    }
+    for input, expected := range tests {
+        if got := c.redact(input); got != expected {
+            t.Errorf("redact(%q) = %q, expected %q", input, got, expected)
+        }
+    }
+}
+
+func TestRedact_IgnoresShortSecrets(t *testing.T) {
+    c := &ClientConfig{}
+    c.addSecret("1")
+    c.addSecret("")
+
+    if got := c.redact("retry 1 of 3"); got != "retry 1 of 3" {
+        t.Errorf("expected short values to be left alone, got %q", got)
+    }
+}
+
+func TestRedact_LongestSecretFirst(t *testing.T) {
+    c := &ClientConfig{APIKey: "abcd"}
+    c.addSecret("abcdefgh")
+
+    if got := c.redact("abcdefgh"); got != redactedValue {
+        t.Errorf("expected the longer secret to be replaced whole, got %q", got)
+    }
+}
+
+func TestRedactDiagnostics_KeepsSeverityAndPath(t *testing.T) {
+    c := redactTestClient()
+
+    var diags diag.Diagnostics
+    diags.AddError("Client Error", fmt.Sprintf("Unable to read keystore entries, got error: %s", testRedactAPIKey))
+    diags.AddWarning("Warning "+testRedactKeystore, "detail")
+    diags.AddAttributeError(path.Root("value"), "Invalid Value", fmt.Sprintf("value %q is invalid", testRedactKeystore))
+    diags.AddAttributeWarning(path.Root("name"), "Name", "X-API-KEY: "+testRedactAPIKey)
+
+    c.redactDiagnostics(&diags)
+
+    if len(diags) != 4 || diags.ErrorsCount() != 2 || diags.WarningsCount() != 2 {
+        t.Fatalf("expected 2 errors and 2 warnings, got: %v", diags)
+    }
+    for _, d := range diags {
+        assertRedacted(t, d.Summary())
+        assertRedacted(t, d.Detail())
+    }
+    for i, expected := range []path.Path{path.Root("value"), path.Root("name")} {
+        withPath, ok := diags[i+2].(diag.DiagnosticWithPath)
+        if !ok || !withPath.Path().Equal(expected) {
+            t.Errorf("expected diagnostic %d to keep path %s, got %v", i+2, expected, diags[i+2])
+        }
+    }
+}
+
+func TestKeyStoreResourceUpdate_RedactsErrors(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        // Echo the request, as some validation errors do
+        body, _ := io.ReadAll(r.Body)
+        w.WriteHeader(http.StatusBadRequest)
+        fmt.Fprintf(w, "invalid request %s with X-API-KEY: %s", body, r.Header.Get("X-API-KEY"))
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.APIKey = testRedactAPIKey
+
+    r := &KeyStoreResource{client: client}
+    resp := updateResource(t, r,
+        &KeyStoreResourceModel{Id: types.Int64Value(3), Name: types.StringValue("token"), Value: types.StringValue("old-value")},
+        &KeyStoreResourceModel{Id: types.Int64Value(3), Name: types.StringValue("token"), Value: types.StringValue(testRedactKeystore)},
+    )
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for the rejected update")
+    }
+    detail := resp.Diagnostics.Errors()[0].Detail()
+    assertRedacted(t, detail)
+    if !strings.Contains(detail, "status code: 400") {
+        t.Errorf("expected the rest of the error to be kept, got %q", detail)
+    }
+}
+
+func TestKeyStoresDataSourceRead_RegistersValues(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        writeJSON(t, w, []map[string]interface{}{{"id": 1, "name": "token", "value": testRedactKeystore}})
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    readDataSource(t, &KeyStoresDataSource{client: client}, &KeyStoresDataSourceModel{
+        Id:        types.Int64Null(),
+        Name:      types.StringNull(),
+        Keystores: types.ListNull(types.ObjectType{AttrTypes: map[string]attr.Type{"id": types.Int64Type, "name": types.StringType, "value": types.StringType}}),
+    })
+
+    if got := client.redact("value " + testRedactKeystore); got != "value "+redactedValue {
+        t.Errorf("expected keystore values read by the data source to be redacted, got %q", got)
+    }
+}
+
+func TestDo_RedactsLogs(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        writeJSON(t, w, "ok")
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.APIKey = testRedactAPIKey
+    client.addSecret(testRedactKeystore)
+
+    var output bytes.Buffer
+    ctx := tflogtest.RootLogger(context.Background(), &output)
+
+    requestURL := server.URL + "/core/keystore/?key=" + url.QueryEscape(testRedactAPIKey) + "&value=" + url.QueryEscape(testRedactKeystore)
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+    if err != nil {
+        t.Fatalf("unable to build request: %s", err)
+    }
+    httpResp, err := client.Do(req)
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+    httpResp.Body.Close()
+
+    if !strings.Contains(output.String(), "Sending Tactical RMM API request") {
+        t.Fatalf("expected the request to be logged, got %q", output.String())
+    }
+    assertRedacted(t, output.String())
+}
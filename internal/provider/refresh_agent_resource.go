@@ -0,0 +1,285 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RefreshAgentResource{}
+var _ resource.ResourceWithValidateConfig = &RefreshAgentResource{}
+
+// defaultAgentRefreshTimeout is the number of seconds a check-in is awaited
+// when timeout is not set.
+const defaultAgentRefreshTimeout = 300
+
+// agentRefreshPollInterval is the delay between agent reads while waiting for
+// a check-in. It is a variable so tests can shorten it.
+var agentRefreshPollInterval = 5 * time.Second
+
+func NewRefreshAgentResource() resource.Resource {
+    return &RefreshAgentResource{}
+}
+
+// RefreshAgentResource makes an agent check in and refresh its system
+// information when it is created.
+type RefreshAgentResource struct {
+    client *ClientConfig
+}
+
+// RefreshAgentResourceModel describes the resource data model.
+type RefreshAgentResourceModel struct {
+    Id       types.String `tfsdk:"id"`
+    AgentId  types.String `tfsdk:"agent_id"`
+    Wait     types.Bool   `tfsdk:"wait"`
+    Timeout  types.Int64  `tfsdk:"timeout"`
+    Triggers types.Map    `tfsdk:"triggers"`
+    LastSeen types.String `tfsdk:"last_seen"`
+    Message  types.String `tfsdk:"message"`
+}
+
+func (r *RefreshAgentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_refresh_agent"
+}
+
+func (r *RefreshAgentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Pings an agent and makes it refresh its system information (WMI) when created, so data sources read afterwards see current data. " +
+            "With `wait`, the apply blocks until the agent's `last_seen` advances and fails if it does not within `timeout`. " +
+            "Destroying this resource does nothing on the server.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this refresh",
+                Computed:            true,
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent to refresh",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "wait": schema.BoolAttribute{
+                MarkdownDescription: "Wait until the agent checks in after the refresh, defaults to `false`",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "timeout": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Seconds to wait for the check-in when `wait` is set, defaults to %d", defaultAgentRefreshTimeout),
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the agent to be refreshed again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "last_seen": schema.StringAttribute{
+                MarkdownDescription: "Last check-in of the agent read after the refresh; with `wait`, the first check-in after the refresh",
+                Computed:            true,
+            },
+            "message": schema.StringAttribute{
+                MarkdownDescription: "Response message of the system information refresh returned by Tactical RMM",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *RefreshAgentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data RefreshAgentResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.Timeout.IsNull() && !data.Timeout.IsUnknown() && data.Timeout.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("timeout"),
+            "Invalid Refresh Timeout",
+            "timeout must be at least 1 second.",
+        )
+    }
+}
+
+func (r *RefreshAgentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *RefreshAgentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data RefreshAgentResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    agentId := data.AgentId.ValueString()
+    agentURL := fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId)
+
+    agent, err := r.client.getObject(ctx, agentURL)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent %s, got error: %s", agentId, err))
+        return
+    }
+    previous, _ := agentLastSeen(agent)
+
+    // The ping is answered by the agent itself, unlike the status derived
+    // from last_seen
+    ping, err := r.client.getObject(ctx, agentURL+"ping/")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ping agent %s, got error: %s", agentId, err))
+        return
+    }
+    if status, _ := ping["status"].(string); status != agentStatusOnline {
+        resp.Diagnostics.AddError(
+            "Agent Not Responding",
+            fmt.Sprintf("Agent %s did not answer the check-in request (status %q), so it cannot be refreshed.", agentId, status),
+        )
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "POST", agentURL+"wmi/", map[string]interface{}{})
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to refresh agent system information, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to refresh agent system information, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    if data.Wait.ValueBool() {
+        timeout := int64(defaultAgentRefreshTimeout)
+        if !data.Timeout.IsNull() {
+            timeout = data.Timeout.ValueInt64()
+        }
+        agent = r.awaitCheckIn(ctx, agentId, previous, time.Duration(timeout)*time.Second, &resp.Diagnostics)
+    } else {
+        agent, err = r.client.getObject(ctx, agentURL)
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent %s, got error: %s", agentId, err))
+        }
+    }
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.LastSeen = stringValue(agent["last_seen"])
+    data.Message = types.StringValue(errorMessage(respBody))
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RefreshAgentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // A refresh is a one-off operation, there is no remote object to refresh
+}
+
+func (r *RefreshAgentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data RefreshAgentResourceModel
+    var state RefreshAgentResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.LastSeen = state.LastSeen
+    data.Message = state.Message
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RefreshAgentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Nothing was created on the server, removing the resource only drops it from state
+}
+
+// awaitCheckIn reads the agent until its last_seen is after previous and
+// returns the agent as last read. An agent that never checked in before
+// counts as advanced on its first check-in.
+func (r *RefreshAgentResource) awaitCheckIn(ctx context.Context, agentId string, previous time.Time, timeout time.Duration, diags *diag.Diagnostics) map[string]interface{} {
+    ctx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    for {
+        select {
+        case <-ctx.Done():
+            diags.AddError(
+                "Agent Refresh Timeout",
+                fmt.Sprintf("Agent %s did not check in within %s after the refresh, got error: %s", agentId, timeout, ctx.Err()),
+            )
+            return nil
+        case <-time.After(agentRefreshPollInterval):
+        }
+
+        agent, err := r.client.getObject(ctx, fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId))
+        if err != nil {
+            if ctx.Err() != nil {
+                continue
+            }
+            diags.AddError("Client Error", fmt.Sprintf("Unable to read agent %s, got error: %s", agentId, err))
+            return nil
+        }
+
+        if lastSeen, seen := agentLastSeen(agent); seen && lastSeen.After(previous) {
+            return agent
+        }
+    }
+}
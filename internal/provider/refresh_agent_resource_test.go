@@ -0,0 +1,127 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// refreshServer serves agent agent-1 answering pings with pingStatus. The
+// agent's last_seen is taken from lastSeen by read count, repeating the last
+// value, and system information refreshes are counted.
+func refreshServer(t *testing.T, pingStatus string, lastSeen ...string) (*httptest.Server, *int32) {
+    t.Helper()
+    var reads, refreshes int32
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/agent-1/":
+            index := int(atomic.AddInt32(&reads, 1)) - 1
+            if index >= len(lastSeen) {
+                index = len(lastSeen) - 1
+            }
+            writeJSON(t, w, map[string]interface{}{"agent_id": "agent-1", "hostname": "web01", "last_seen": lastSeen[index]})
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/agent-1/ping/":
+            writeJSON(t, w, map[string]interface{}{"name": "web01", "status": pingStatus})
+        case r.Method == http.MethodPost && r.URL.Path == "/agents/agent-1/wmi/":
+            atomic.AddInt32(&refreshes, 1)
+            writeJSON(t, w, "Agent WMI data refreshed successfully")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &refreshes
+}
+
+func refreshModel(wait bool, timeout int64) *RefreshAgentResourceModel {
+    return &RefreshAgentResourceModel{
+        AgentId:  types.StringValue("agent-1"),
+        Wait:     types.BoolValue(wait),
+        Timeout:  types.Int64Value(timeout),
+        Triggers: types.MapNull(types.StringType),
+    }
+}
+
+func shortenAgentRefreshPoll(t *testing.T) {
+    t.Helper()
+    previous := agentRefreshPollInterval
+    agentRefreshPollInterval = time.Millisecond
+    t.Cleanup(func() { agentRefreshPollInterval = previous })
+}
+
+func TestRefreshAgentResourceCreate_WithoutWait(t *testing.T) {
+    server, refreshes := refreshServer(t, agentStatusOnline, "2026-10-01T10:00:00Z", "2026-10-01T10:00:30Z")
+
+    r := &RefreshAgentResource{client: newTestClient(server)}
+    resp := createResource(t, r, refreshModel(false, 60))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if got := atomic.LoadInt32(refreshes); got != 1 {
+        t.Errorf("expected 1 refresh call, got %d", got)
+    }
+
+    var state RefreshAgentResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.LastSeen.ValueString() != "2026-10-01T10:00:30Z" {
+        t.Errorf("expected last_seen read after the refresh, got %s", state.LastSeen)
+    }
+    if state.Message.ValueString() != "Agent WMI data refreshed successfully" {
+        t.Errorf("unexpected message: %s", state.Message)
+    }
+}
+
+func TestRefreshAgentResourceCreate_WaitsForCheckIn(t *testing.T) {
+    shortenAgentRefreshPoll(t)
+    server, _ := refreshServer(t, agentStatusOnline,
+        "2026-10-01T10:00:00Z", "2026-10-01T10:00:00Z", "2026-10-01T10:00:00Z", "2026-10-01T10:01:00Z")
+
+    r := &RefreshAgentResource{client: newTestClient(server)}
+    resp := createResource(t, r, refreshModel(true, 60))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state RefreshAgentResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.LastSeen.ValueString() != "2026-10-01T10:01:00Z" {
+        t.Errorf("expected the advanced last_seen, got %s", state.LastSeen)
+    }
+}
+
+func TestRefreshAgentResourceCreate_CheckInTimeout(t *testing.T) {
+    shortenAgentRefreshPoll(t)
+    server, _ := refreshServer(t, agentStatusOnline, "2026-10-01T10:00:00Z")
+
+    r := &RefreshAgentResource{client: newTestClient(server)}
+    resp := createResource(t, r, refreshModel(true, 1))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when last_seen never advances")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Agent Refresh Timeout" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+}
+
+func TestRefreshAgentResourceCreate_AgentNotResponding(t *testing.T) {
+    server, refreshes := refreshServer(t, agentStatusOffline, "2026-10-01T10:00:00Z")
+
+    r := &RefreshAgentResource{client: newTestClient(server)}
+    resp := createResource(t, r, refreshModel(true, 60))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for an agent not answering the ping")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Agent Not Responding" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+    if got := atomic.LoadInt32(refreshes); got != 0 {
+        t.Errorf("expected no refresh calls, got %d", got)
+    }
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// Report schedule frequencies supported by the reporting addon.
+const (
+    reportFrequencyDaily   = "daily"
+    reportFrequencyWeekly  = "weekly"
+    reportFrequencyMonthly = "monthly"
+)
+
+// maxReportDayOfMonth is the last day of month a monthly report can run on.
+// Later days do not exist in every month and the scheduler does not move
+// them, so they are rejected.
+const maxReportDayOfMonth = 28
+
+// reportWeekdays are the day_of_week values, indexed by their cron number.
+var reportWeekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// reportSchedule is a schedule the reporting addon can represent: once a day,
+// week or month at a fixed time.
+type reportSchedule struct {
+    frequency  string
+    dayOfWeek  string
+    dayOfMonth int64
+    timeOfDay  string
+}
+
+// apiBody returns the schedule as sent to and returned by the API.
+func (s reportSchedule) apiBody() map[string]interface{} {
+    body := map[string]interface{}{
+        "frequency": s.frequency,
+        "time":      s.timeOfDay,
+    }
+    switch s.frequency {
+    case reportFrequencyWeekly:
+        body["day_of_week"] = s.dayOfWeek
+    case reportFrequencyMonthly:
+        body["day_of_month"] = s.dayOfMonth
+    }
+    return body
+}
+
+// cron returns the schedule as a cron expression, the inverse of
+// parseReportCron.
+func (s reportSchedule) cron() string {
+    hour, minute, _ := parseTimeOfDay(s.timeOfDay)
+    switch s.frequency {
+    case reportFrequencyWeekly:
+        for i, day := range reportWeekdays {
+            if day == s.dayOfWeek {
+                return fmt.Sprintf("%d %d * * %d", minute, hour, i)
+            }
+        }
+    case reportFrequencyMonthly:
+        return fmt.Sprintf("%d %d %d * *", minute, hour, s.dayOfMonth)
+    }
+    return fmt.Sprintf("%d %d * * *", minute, hour)
+}
+
+// reportScheduleFromAPI converts the schedule of an API response.
+func reportScheduleFromAPI(value interface{}) (reportSchedule, bool) {
+    body, ok := value.(map[string]interface{})
+    if !ok {
+        return reportSchedule{}, false
+    }
+
+    schedule := reportSchedule{}
+    schedule.frequency, _ = body["frequency"].(string)
+    schedule.timeOfDay, _ = body["time"].(string)
+    schedule.dayOfWeek, _ = body["day_of_week"].(string)
+    if day, ok := body["day_of_month"].(float64); ok {
+        schedule.dayOfMonth = int64(day)
+    }
+    return schedule, schedule.frequency != ""
+}
+
+// validate returns why the schedule cannot be represented, or nil.
+func (s reportSchedule) validate() error {
+    if _, _, err := parseTimeOfDay(s.timeOfDay); err != nil {
+        return err
+    }
+
+    switch s.frequency {
+    case reportFrequencyDaily:
+        if s.dayOfWeek != "" || s.dayOfMonth != 0 {
+            return fmt.Errorf("daily reports take neither day_of_week nor day_of_month")
+        }
+    case reportFrequencyWeekly:
+        if s.dayOfMonth != 0 {
+            return fmt.Errorf("weekly reports do not take day_of_month")
+        }
+        for _, day := range reportWeekdays {
+            if day == s.dayOfWeek {
+                return nil
+            }
+        }
+        return fmt.Errorf("weekly reports need day_of_week set to one of %s", strings.Join(reportWeekdays, ", "))
+    case reportFrequencyMonthly:
+        if s.dayOfWeek != "" {
+            return fmt.Errorf("monthly reports do not take day_of_week")
+        }
+        if s.dayOfMonth < 1 || s.dayOfMonth > maxReportDayOfMonth {
+            return fmt.Errorf("monthly reports need day_of_month between 1 and %d, later days do not exist in every month", maxReportDayOfMonth)
+        }
+    default:
+        return fmt.Errorf("frequency must be one of %s, %s or %s, got %q", reportFrequencyDaily, reportFrequencyWeekly, reportFrequencyMonthly, s.frequency)
+    }
+    return nil
+}
+
+// parseTimeOfDay parses a 24 hour "HH:MM" time.
+func parseTimeOfDay(value string) (int, int, error) {
+    hourPart, minutePart, ok := strings.Cut(value, ":")
+    hour, hourErr := strconv.Atoi(hourPart)
+    minute, minuteErr := strconv.Atoi(minutePart)
+    if !ok || len(minutePart) != 2 || hourErr != nil || minuteErr != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+        return 0, 0, fmt.Errorf("time_of_day must be a 24 hour HH:MM time, got %q", value)
+    }
+    return hour, minute, nil
+}
+
+// parseReportCron converts a five field cron expression into a schedule. Only
+// expressions that run once a day, week or month at a fixed time can be
+// represented: minute and hour must be single values, and exactly one of day
+// of month and day of week may be set. Ranges, lists, steps and month
+// restrictions are rejected.
+func parseReportCron(expression string) (reportSchedule, error) {
+    fields := strings.Fields(expression)
+    if len(fields) != 5 {
+        return reportSchedule{}, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+    }
+
+    minute, err := strconv.Atoi(fields[0])
+    if err != nil || minute < 0 || minute > 59 {
+        return reportSchedule{}, fmt.Errorf("minute must be a single value between 0 and 59, got %q", fields[0])
+    }
+    hour, err := strconv.Atoi(fields[1])
+    if err != nil || hour < 0 || hour > 23 {
+        return reportSchedule{}, fmt.Errorf("hour must be a single value between 0 and 23, got %q", fields[1])
+    }
+    if fields[3] != "*" {
+        return reportSchedule{}, fmt.Errorf("reports cannot be limited to some months, the month field must be *")
+    }
+
+    schedule := reportSchedule{frequency: reportFrequencyDaily, timeOfDay: fmt.Sprintf("%02d:%02d", hour, minute)}
+    dayOfMonth, dayOfWeek := fields[2], fields[4]
+
+    switch {
+    case dayOfMonth != "*" && dayOfWeek != "*":
+        return reportSchedule{}, fmt.Errorf("only one of day-of-month and day-of-week can be set")
+    case dayOfMonth != "*":
+        day, err := strconv.ParseInt(dayOfMonth, 10, 64)
+        if err != nil || day < 1 || day > maxReportDayOfMonth {
+            return reportSchedule{}, fmt.Errorf("day-of-month must be a single value between 1 and %d, got %q", maxReportDayOfMonth, dayOfMonth)
+        }
+        schedule.frequency = reportFrequencyMonthly
+        schedule.dayOfMonth = day
+    case dayOfWeek != "*":
+        day, err := strconv.Atoi(dayOfWeek)
+        if err != nil || day < 0 || day > 7 {
+            return reportSchedule{}, fmt.Errorf("day-of-week must be a single value between 0 and 7, got %q", dayOfWeek)
+        }
+        schedule.frequency = reportFrequencyWeekly
+        schedule.dayOfWeek = reportWeekdays[day%7]
+    }
+
+    return schedule, nil
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RequiredScriptsDataSource{}
+
+func NewRequiredScriptsDataSource() datasource.DataSource {
+    return &RequiredScriptsDataSource{}
+}
+
+// RequiredScriptsDataSource reports which of a set of script names exist, so
+// modules can assert their script dependencies up front.
+type RequiredScriptsDataSource struct {
+    client *ClientConfig
+}
+
+// RequiredScriptsDataSourceModel describes the data source data model.
+type RequiredScriptsDataSourceModel struct {
+    Names      types.List `tfsdk:"names"`
+    RequireAll types.Bool `tfsdk:"require_all"`
+    Existing   types.List `tfsdk:"existing"`
+    Missing    types.List `tfsdk:"missing"`
+    AllPresent types.Bool `tfsdk:"all_present"`
+}
+
+func (d *RequiredScriptsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_required_scripts"
+}
+
+func (d *RequiredScriptsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Required scripts data source for Tactical RMM. Reports which of the given script names exist, user defined or builtin, " +
+            "so a module can assert its script dependencies in preconditions. With `require_all = true` any missing script is an error.",
+
+        Attributes: map[string]schema.Attribute{
+            "names": schema.ListAttribute{
+                MarkdownDescription: "Script names to look for (exact match)",
+                Required:            true,
+                ElementType:         types.StringType,
+            },
+            "require_all": schema.BoolAttribute{
+                MarkdownDescription: "Fail when any of the scripts is missing. Defaults to false.",
+                Optional:            true,
+            },
+            "existing": schema.ListAttribute{
+                MarkdownDescription: "Names that match an existing script, in the order given",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "missing": schema.ListAttribute{
+                MarkdownDescription: "Names that match no script, in the order given",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "all_present": schema.BoolAttribute{
+                MarkdownDescription: "Whether every name matches an existing script",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *RequiredScriptsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *RequiredScriptsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data RequiredScriptsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    var names []string
+    resp.Diagnostics.Append(data.Names.ElementsAs(ctx, &names, false)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    scripts, err := d.client.listObjects(ctx, d.client.allScriptsURL())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return
+    }
+
+    available := make(map[string]bool, len(scripts))
+    for _, script := range scripts {
+        if name, ok := script["name"].(string); ok {
+            available[name] = true
+        }
+    }
+
+    existing := []attr.Value{}
+    missing := []attr.Value{}
+    var missingNames []string
+    for _, name := range names {
+        if available[name] {
+            existing = append(existing, types.StringValue(name))
+            continue
+        }
+        missing = append(missing, types.StringValue(name))
+        missingNames = append(missingNames, name)
+    }
+
+    if data.RequireAll.ValueBool() && len(missingNames) > 0 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("names"),
+            "Required Scripts Missing",
+            fmt.Sprintf("The following scripts do not exist: %s", strings.Join(missingNames, ", ")),
+        )
+        return
+    }
+
+    data.Existing = types.ListValueMust(types.StringType, existing)
+    data.Missing = types.ListValueMust(types.StringType, missing)
+    data.AllPresent = types.BoolValue(len(missingNames) == 0)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func requiredScriptsServer(t *testing.T) (*httptest.Server, *int32) {
+    t.Helper()
+    var lists int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/scripts/" {
+            http.NotFound(w, r)
+            return
+        }
+        atomic.AddInt32(&lists, 1)
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": 1, "name": "Disk Cleanup", "script_type": "userdefined"},
+            {"id": 2, "name": "Restart Print Spooler", "script_type": "builtin"},
+        })
+    }))
+    t.Cleanup(server.Close)
+    return server, &lists
+}
+
+func readRequiredScripts(t *testing.T, requireAll bool, names ...string) (RequiredScriptsDataSourceModel, bool, int32) {
+    t.Helper()
+    server, lists := requiredScriptsServer(t)
+
+    values := []attr.Value{}
+    for _, name := range names {
+        values = append(values, types.StringValue(name))
+    }
+
+    d := &RequiredScriptsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &RequiredScriptsDataSourceModel{
+        Names:      types.ListValueMust(types.StringType, values),
+        RequireAll: types.BoolValue(requireAll),
+        Existing:   types.ListNull(types.StringType),
+        Missing:    types.ListNull(types.StringType),
+    })
+
+    var state RequiredScriptsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    return state, resp.Diagnostics.HasError(), atomic.LoadInt32(lists)
+}
+
+func listStrings(t *testing.T, list types.List) []string {
+    t.Helper()
+    values := []string{}
+    list.ElementsAs(context.Background(), &values, false)
+    return values
+}
+
+func TestRequiredScriptsDataSourceRead_AllPresent(t *testing.T) {
+    for _, requireAll := range []bool{false, true} {
+        state, failed, lists := readRequiredScripts(t, requireAll, "Restart Print Spooler", "Disk Cleanup")
+        if failed {
+            t.Fatalf("require_all=%t: unexpected error", requireAll)
+        }
+        if lists != 1 {
+            t.Errorf("require_all=%t: expected a single list call, got %d", requireAll, lists)
+        }
+        if !state.AllPresent.ValueBool() {
+            t.Errorf("require_all=%t: expected all_present", requireAll)
+        }
+        if got := listStrings(t, state.Existing); !reflect.DeepEqual(got, []string{"Restart Print Spooler", "Disk Cleanup"}) {
+            t.Errorf("require_all=%t: unexpected existing: %v", requireAll, got)
+        }
+        if got := listStrings(t, state.Missing); len(got) != 0 {
+            t.Errorf("require_all=%t: unexpected missing: %v", requireAll, got)
+        }
+    }
+}
+
+func TestRequiredScriptsDataSourceRead_SomeMissing(t *testing.T) {
+    state, failed, _ := readRequiredScripts(t, false, "Disk Cleanup", "Install Agent", "Backup")
+    if failed {
+        t.Fatal("unexpected error without require_all")
+    }
+    if state.AllPresent.ValueBool() {
+        t.Error("expected all_present to be false")
+    }
+    if got := listStrings(t, state.Missing); !reflect.DeepEqual(got, []string{"Install Agent", "Backup"}) {
+        t.Errorf("unexpected missing: %v", got)
+    }
+    if got := listStrings(t, state.Existing); !reflect.DeepEqual(got, []string{"Disk Cleanup"}) {
+        t.Errorf("unexpected existing: %v", got)
+    }
+}
+
+func TestRequiredScriptsDataSourceRead_SomeMissingRequireAll(t *testing.T) {
+    _, failed, _ := readRequiredScripts(t, true, "Disk Cleanup", "Install Agent")
+    if !failed {
+        t.Fatal("expected an error with require_all and a missing script")
+    }
+}
@@ -0,0 +1,384 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ResolveAlertsResource{}
+var _ resource.ResourceWithValidateConfig = &ResolveAlertsResource{}
+
+// alertResolveBatchSize is the number of alerts resolved per bulk request.
+const alertResolveBatchSize = 100
+
+func NewResolveAlertsResource() resource.Resource {
+    return &ResolveAlertsResource{}
+}
+
+// ResolveAlertsResource resolves all open alerts matching its filters when it
+// is created.
+type ResolveAlertsResource struct {
+    client *ClientConfig
+}
+
+// ResolveAlertsResourceModel describes the resource data model.
+type ResolveAlertsResourceModel struct {
+    Id            types.String `tfsdk:"id"`
+    ClientId      types.Int64  `tfsdk:"client_id"`
+    SiteId        types.Int64  `tfsdk:"site_id"`
+    AgentId       types.String `tfsdk:"agent_id"`
+    Severities    types.List   `tfsdk:"severities"`
+    OlderThan     types.String `tfsdk:"older_than"`
+    DryRun        types.Bool   `tfsdk:"dry_run"`
+    Triggers      types.Map    `tfsdk:"triggers"`
+    MatchedIds    types.List   `tfsdk:"matched_ids"`
+    MatchedCount  types.Int64  `tfsdk:"matched_count"`
+    ResolvedCount types.Int64  `tfsdk:"resolved_count"`
+}
+
+func (r *ResolveAlertsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_resolve_alerts"
+}
+
+func (r *ResolveAlertsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Resolves open Tactical RMM alerts matching the given filters when created, e.g. to clean up after a maintenance window. " +
+            "Alerts are resolved in batches and every request honors the provider's `requests_per_second` limit. " +
+            "With `dry_run` the matching alerts are only reported. Destroying this resource does not reopen alerts.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this resolve operation",
+                Computed:            true,
+            },
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Only resolve alerts of agents of this client. Conflicts with `site_id` and `agent_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "site_id": schema.Int64Attribute{
+                MarkdownDescription: "Only resolve alerts of agents of this site. Conflicts with `client_id` and `agent_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Only resolve alerts of this agent. Conflicts with `client_id` and `site_id`.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "severities": schema.ListAttribute{
+                MarkdownDescription: "Only resolve alerts with one of these severities: info, warning, error",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.List{
+                    listplanmodifier.RequiresReplace(),
+                },
+            },
+            "older_than": schema.StringAttribute{
+                MarkdownDescription: "Only resolve alerts raised longer ago than this duration, e.g. `24h` or `90m`",
+                Optional:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "dry_run": schema.BoolAttribute{
+                MarkdownDescription: "When true, only report the matching alerts without resolving them",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the alerts to be resolved again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "matched_ids": schema.ListAttribute{
+                MarkdownDescription: "IDs of the open alerts that matched the filters",
+                Computed:            true,
+                ElementType:         types.Int64Type,
+            },
+            "matched_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of open alerts that matched the filters",
+                Computed:            true,
+            },
+            "resolved_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of alerts that were resolved, 0 for a dry run",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *ResolveAlertsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data ResolveAlertsResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    scopes := 0
+    for _, isNull := range []bool{data.ClientId.IsNull(), data.SiteId.IsNull(), data.AgentId.IsNull()} {
+        if !isNull {
+            scopes++
+        }
+    }
+    if scopes > 1 {
+        resp.Diagnostics.AddError(
+            "Invalid Alert Filter",
+            "Only one of 'client_id', 'site_id' or 'agent_id' can be specified.",
+        )
+    }
+
+    if !data.OlderThan.IsNull() && !data.OlderThan.IsUnknown() {
+        if _, err := time.ParseDuration(data.OlderThan.ValueString()); err != nil {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("older_than"),
+                "Invalid Duration",
+                fmt.Sprintf("older_than must be a duration such as '24h', got error: %s", err),
+            )
+        }
+    }
+}
+
+func (r *ResolveAlertsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *ResolveAlertsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ResolveAlertsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // A dry run only lists alerts, so it also works in read-only mode
+    if !data.DryRun.ValueBool() && r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // The alerts endpoint filters with a PATCH body; client and severity
+    // filters are applied by the server, the others below
+    filter := map[string]interface{}{
+        "resolvedFilter": false,
+        "snoozedFilter":  true,
+    }
+    if !data.ClientId.IsNull() {
+        filter["clientFilter"] = []int64{data.ClientId.ValueInt64()}
+    }
+    if !data.Severities.IsNull() {
+        var severities []string
+        resp.Diagnostics.Append(data.Severities.ElementsAs(ctx, &severities, false)...)
+        filter["severityFilter"] = severities
+    }
+
+    var cutoff time.Time
+    if !data.OlderThan.IsNull() {
+        olderThan, err := time.ParseDuration(data.OlderThan.ValueString())
+        if err != nil {
+            resp.Diagnostics.AddAttributeError(path.Root("older_than"), "Invalid Duration", err.Error())
+            return
+        }
+        cutoff = time.Now().Add(-olderThan)
+    }
+
+    // Site alerts are matched through the agents of the site
+    var siteAgents map[string]bool
+    if !data.SiteId.IsNull() {
+        agents, err := r.client.listObjects(ctx, fmt.Sprintf("%s/agents/?site=%d", r.client.BaseURL, data.SiteId.ValueInt64()))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list site agents, got error: %s", err))
+            return
+        }
+        siteAgents = make(map[string]bool, len(agents))
+        for _, agent := range agents {
+            if agentId, ok := agent["agent_id"].(string); ok {
+                siteAgents[agentId] = true
+            }
+        }
+    }
+
+    alerts, err := r.listAlerts(ctx, filter)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list alerts, got error: %s", err))
+        return
+    }
+
+    var matched []int64
+    for _, alert := range alerts {
+        id, ok := alert["id"].(float64)
+        if !ok {
+            continue
+        }
+        if resolved, ok := alert["resolved"].(bool); ok && resolved {
+            continue
+        }
+        agentId, _ := alert["agent_id"].(string)
+        if !data.AgentId.IsNull() && agentId != data.AgentId.ValueString() {
+            continue
+        }
+        if siteAgents != nil && !siteAgents[agentId] {
+            continue
+        }
+        if !cutoff.IsZero() {
+            alertTime, ok := alert["alert_time"].(string)
+            if !ok {
+                continue
+            }
+            raised, err := parseAPITime(alertTime)
+            if err != nil || !raised.Before(cutoff) {
+                continue
+            }
+        }
+        matched = append(matched, int64(id))
+    }
+
+    resolved := 0
+    if !data.DryRun.ValueBool() {
+        for start := 0; start < len(matched); start += alertResolveBatchSize {
+            end := start + alertResolveBatchSize
+            if end > len(matched) {
+                end = len(matched)
+            }
+
+            statusCode, respBody, err := r.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/alerts/bulk/", r.client.BaseURL), map[string]interface{}{
+                "bulk_action": "resolve",
+                "alerts":      matched[start:end],
+            })
+            if err == nil && statusCode != http.StatusOK {
+                err = fmt.Errorf("status code: %d, response: %s", statusCode, errorMessage(respBody))
+            }
+            if err != nil {
+                resp.Diagnostics.AddError(
+                    "Client Error",
+                    fmt.Sprintf("Unable to resolve alerts, %d of %d resolved before error: %s", resolved, len(matched), err),
+                )
+                return
+            }
+            resolved = end
+        }
+    }
+
+    matchedIds := make([]attr.Value, len(matched))
+    for i, id := range matched {
+        matchedIds[i] = types.Int64Value(id)
+    }
+    matchedIdsValue, diags := types.ListValue(types.Int64Type, matchedIds)
+    resp.Diagnostics.Append(diags...)
+
+    data.Id = types.StringValue(actionID())
+    data.MatchedIds = matchedIdsValue
+    data.MatchedCount = types.Int64Value(int64(len(matched)))
+    data.ResolvedCount = types.Int64Value(int64(resolved))
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// listAlerts returns the alerts matching a filter body of the alerts endpoint.
+// The endpoint returns every match at once, it is not paginated. Listing
+// uses PATCH but changes nothing, so it is allowed in read-only mode.
+func (r *ResolveAlertsResource) listAlerts(ctx context.Context, filter map[string]interface{}) ([]map[string]interface{}, error) {
+    statusCode, respBody, err := r.client.sendJSON(withReadRequest(ctx), "PATCH", fmt.Sprintf("%s/alerts/", r.client.BaseURL), filter)
+    if err != nil {
+        return nil, err
+    }
+
+    if statusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+    }
+
+    var alerts []map[string]interface{}
+    if err := json.Unmarshal(respBody, &alerts); err != nil {
+        return nil, fmt.Errorf("unable to parse response: %w", err)
+    }
+
+    return alerts, nil
+}
+
+func (r *ResolveAlertsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Resolving alerts is a one-off operation, there is no remote object to refresh
+}
+
+func (r *ResolveAlertsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data ResolveAlertsResourceModel
+    var state ResolveAlertsResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.MatchedIds = state.MatchedIds
+    data.MatchedCount = state.MatchedCount
+    data.ResolvedCount = state.ResolvedCount
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ResolveAlertsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ResolveAlertsResourceModel
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // A dry run changed nothing, so it can also be removed in read-only mode
+    if !data.DryRun.ValueBool() && r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // Resolved alerts are not reopened, removing the resource only drops it from state
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// alertsServer serves alerts and records the alert IDs of every bulk resolve.
+func alertsServer(t *testing.T, alerts []map[string]interface{}) (*httptest.Server, *[][]int64) {
+    t.Helper()
+    var batches [][]int64
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPatch && r.URL.Path == "/alerts/":
+            writeJSON(t, w, alerts)
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/" && r.URL.Query().Get("site") == "9":
+            writeJSON(t, w, []map[string]interface{}{{"agent_id": "site-agent"}})
+        case r.Method == http.MethodPost && r.URL.Path == "/alerts/bulk/":
+            var body struct {
+                BulkAction string  `json:"bulk_action"`
+                Alerts     []int64 `json:"alerts"`
+            }
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.BulkAction != "resolve" {
+                t.Errorf("unexpected bulk request: %v %v", body, err)
+            }
+            batches = append(batches, body.Alerts)
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &batches
+}
+
+func resolveAlertsModel() *ResolveAlertsResourceModel {
+    return &ResolveAlertsResourceModel{
+        Severities: types.ListNull(types.StringType),
+        Triggers:   types.MapNull(types.StringType),
+        MatchedIds: types.ListNull(types.Int64Type),
+    }
+}
+
+func TestResolveAlertsResourceCreate_FiltersAndResolves(t *testing.T) {
+    old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+    recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+    server, batches := alertsServer(t, []map[string]interface{}{
+        {"id": 1, "agent_id": "site-agent", "alert_time": old, "resolved": false},
+        {"id": 2, "agent_id": "site-agent", "alert_time": recent, "resolved": false},
+        {"id": 3, "agent_id": "other-agent", "alert_time": old, "resolved": false},
+        {"id": 4, "agent_id": "site-agent", "alert_time": old, "resolved": true},
+    })
+
+    model := resolveAlertsModel()
+    model.SiteId = types.Int64Value(9)
+    model.OlderThan = types.StringValue("24h")
+
+    r := &ResolveAlertsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if !reflect.DeepEqual(*batches, [][]int64{{1}}) {
+        t.Errorf("expected only alert 1 to be resolved, got %v", *batches)
+    }
+
+    var state ResolveAlertsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.MatchedCount.ValueInt64() != 1 || state.ResolvedCount.ValueInt64() != 1 {
+        t.Errorf("expected 1 matched and resolved, got %d and %d", state.MatchedCount.ValueInt64(), state.ResolvedCount.ValueInt64())
+    }
+}
+
+func TestResolveAlertsResourceCreate_ResolvesInBatches(t *testing.T) {
+    var alerts []map[string]interface{}
+    for id := 1; id <= alertResolveBatchSize+20; id++ {
+        alerts = append(alerts, map[string]interface{}{"id": id, "agent_id": "a", "resolved": false})
+    }
+    server, batches := alertsServer(t, alerts)
+
+    r := &ResolveAlertsResource{client: newTestClient(server)}
+    resp := createResource(t, r, resolveAlertsModel())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(*batches) != 2 || len((*batches)[0]) != alertResolveBatchSize || len((*batches)[1]) != 20 {
+        t.Errorf("expected batches of %d and 20 alerts, got %d batches", alertResolveBatchSize, len(*batches))
+    }
+}
+
+func TestResolveAlertsResourceCreate_DryRunOnlyReports(t *testing.T) {
+    server, batches := alertsServer(t, []map[string]interface{}{
+        {"id": 1, "agent_id": "a", "resolved": false},
+        {"id": 2, "agent_id": "a", "resolved": false},
+    })
+
+    model := resolveAlertsModel()
+    model.DryRun = types.BoolValue(true)
+
+    r := &ResolveAlertsResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if len(*batches) != 0 {
+        t.Errorf("expected no alerts to be resolved in a dry run, got %v", *batches)
+    }
+
+    var state ResolveAlertsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.MatchedCount.ValueInt64() != 2 || state.ResolvedCount.ValueInt64() != 0 {
+        t.Errorf("expected 2 matched and 0 resolved, got %d and %d", state.MatchedCount.ValueInt64(), state.ResolvedCount.ValueInt64())
+    }
+}
+
+func TestResolveAlertsResourceCreate_ReadOnly(t *testing.T) {
+    server, batches := alertsServer(t, []map[string]interface{}{
+        {"id": 1, "agent_id": "a", "resolved": false},
+    })
+
+    client := newTestClient(server)
+    client.ReadOnly = true
+    r := &ResolveAlertsResource{client: client}
+
+    // A dry run lists the alerts even in read-only mode
+    model := resolveAlertsModel()
+    model.DryRun = types.BoolValue(true)
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    var state ResolveAlertsResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.MatchedCount.ValueInt64() != 1 {
+        t.Errorf("expected 1 matched alert, got %d", state.MatchedCount.ValueInt64())
+    }
+    if resp := deleteResource(t, r, &state); resp.Diagnostics.HasError() {
+        t.Errorf("expected the dry run to be removable, got %v", resp.Diagnostics)
+    }
+
+    // Resolving is still denied
+    model.DryRun = types.BoolValue(false)
+    resp = createResource(t, r, model)
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Provider Is in Read-Only Mode" {
+        t.Errorf("expected a read-only error, got %v", resp.Diagnostics)
+    }
+    if len(*batches) != 0 {
+        t.Errorf("expected no alerts to be resolved, got %v", *batches)
+    }
+
+    // As is removing applied resolutions
+    state.DryRun = types.BoolValue(false)
+    deleteResp := deleteResource(t, r, &state)
+    if !deleteResp.Diagnostics.HasError() || deleteResp.Diagnostics.Errors()[0].Summary() != "Provider Is in Read-Only Mode" {
+        t.Errorf("expected a read-only error, got %v", deleteResp.Diagnostics)
+    }
+}
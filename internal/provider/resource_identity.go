@@ -0,0 +1,78 @@
+package provider
+
+import (
+    "context"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// idIdentitySchema is the identity of resources managing a TRMM object with a
+// numeric ID. terraform query reports it for every listed object, and import
+// blocks can use it instead of an ID string.
+func idIdentitySchema(object string) identityschema.Schema {
+    return identityschema.Schema{
+        Attributes: map[string]identityschema.Attribute{
+            "id": identityschema.Int64Attribute{
+                Description:       object + " identifier",
+                RequiredForImport: true,
+            },
+        },
+    }
+}
+
+// agentIdentitySchema is the identity of agent scoped resources, of which
+// there is one per agent.
+func agentIdentitySchema() identityschema.Schema {
+    return identityschema.Schema{
+        Attributes: map[string]identityschema.Attribute{
+            "agent_id": identityschema.StringAttribute{
+                Description:       "Agent ID",
+                RequiredForImport: true,
+            },
+        },
+    }
+}
+
+// setIdentity stores id as the identity of a resource with idIdentitySchema.
+func setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, id types.Int64, diags *diag.Diagnostics) {
+    setIdentityAttribute(ctx, identity, "id", id, diags)
+}
+
+// setIdentityAttribute stores value as an attribute of the identity. identity
+// is nil when the request carries no identity, e.g. in unit tests.
+func setIdentityAttribute(ctx context.Context, identity *tfsdk.ResourceIdentity, attribute string, value attr.Value, diags *diag.Diagnostics) {
+    if identity == nil {
+        return
+    }
+    diags.Append(identity.SetAttribute(ctx, path.Root(attribute), value)...)
+}
+
+// identityImportID returns the ID of an import by identity. ok is false for
+// an import by ID string, which the resource parses as before.
+func identityImportID(ctx context.Context, req resource.ImportStateRequest, diags *diag.Diagnostics) (id int64, ok bool) {
+    return identityImportInt64(ctx, req, "id", diags)
+}
+
+// identityImportInt64 returns a numeric attribute of the identity of an import
+// by identity, like identityImportID.
+func identityImportInt64(ctx context.Context, req resource.ImportStateRequest, attribute string, diags *diag.Diagnostics) (value int64, ok bool) {
+    if !importsByIdentity(req) {
+        return 0, false
+    }
+
+    var identityValue types.Int64
+    diags.Append(req.Identity.GetAttribute(ctx, path.Root(attribute), &identityValue)...)
+    return identityValue.ValueInt64(), true
+}
+
+// importsByIdentity reports whether an import uses the identity attribute of
+// an import block instead of an ID string.
+func importsByIdentity(req resource.ImportStateRequest) bool {
+    return req.ID == "" && req.Identity != nil
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+    "context"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// identityFor returns an identity of r with the given attributes set and all
+// others null.
+func identityFor(t *testing.T, r resource.ResourceWithIdentity, attributes map[string]attr.Value) *tfsdk.ResourceIdentity {
+    t.Helper()
+    ctx := context.Background()
+
+    var resp resource.IdentitySchemaResponse
+    r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &resp)
+    identity := &tfsdk.ResourceIdentity{
+        Schema: resp.IdentitySchema,
+        Raw:    tftypes.NewValue(resp.IdentitySchema.Type().TerraformType(ctx), nil),
+    }
+    for attribute, value := range attributes {
+        if diags := identity.SetAttribute(ctx, path.Root(attribute), value); diags.HasError() {
+            t.Fatalf("unable to build identity: %v", diags)
+        }
+    }
+    return identity
+}
+
+// identityResource is an importable resource with an identity.
+type identityResource interface {
+    resource.ResourceWithImportState
+    resource.ResourceWithIdentity
+}
+
+// importByIdentity runs ImportState for r the way an import block with the
+// given identity does and returns the response.
+func importByIdentity(t *testing.T, r identityResource, identity *tfsdk.ResourceIdentity) resource.ImportStateResponse {
+    t.Helper()
+    ctx := context.Background()
+    schemaResp := resourceSchemaFor(t, r)
+
+    resp := resource.ImportStateResponse{
+        State: tfsdk.State{
+            Schema: schemaResp.Schema,
+            Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+        },
+        Identity: identity,
+    }
+    r.ImportState(ctx, resource.ImportStateRequest{Identity: identity}, &resp)
+    return resp
+}
+
+func TestImportStateByIdentity(t *testing.T) {
+    tests := map[string]struct {
+        resource identityResource
+        identity map[string]attr.Value
+        state    map[string]attr.Value
+    }{
+        "script snippet": {
+            resource: &ScriptSnippetResource{client: &ClientConfig{}},
+            identity: map[string]attr.Value{"id": types.Int64Value(9)},
+            state:    map[string]attr.Value{"id": types.Int64Value(9)},
+        },
+        "sso provider": {
+            resource: &SSOProviderResource{client: &ClientConfig{}},
+            identity: map[string]attr.Value{"id": types.Int64Value(2)},
+            state:    map[string]attr.Value{"id": types.Int64Value(2)},
+        },
+        "agent patch policy": {
+            resource: &AgentPatchPolicyResource{},
+            identity: map[string]attr.Value{"agent_id": types.StringValue("abc123")},
+            state:    map[string]attr.Value{"agent_id": types.StringValue("abc123"), "id": types.StringValue("abc123")},
+        },
+        "alert template actions": {
+            resource: &AlertTemplateActionsResource{},
+            identity: map[string]attr.Value{"alert_template_id": types.Int64Value(4)},
+            state:    map[string]attr.Value{"alert_template_id": types.Int64Value(4), "id": types.StringValue("4")},
+        },
+        "alert template assignment": {
+            resource: &AlertTemplateAssignmentResource{},
+            identity: map[string]attr.Value{"site_id": types.Int64Value(3)},
+            state:    map[string]attr.Value{"site_id": types.Int64Value(3), "client_id": types.Int64Null(), "id": types.StringValue("site/3")},
+        },
+    }
+
+    for name, test := range tests {
+        resp := importByIdentity(t, test.resource, identityFor(t, test.resource, test.identity))
+        if resp.Diagnostics.HasError() {
+            t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            continue
+        }
+
+        for attribute, expected := range test.state {
+            var value attr.Value
+            resp.State.GetAttribute(context.Background(), path.Root(attribute), &value)
+            if !value.Equal(expected) {
+                t.Errorf("%s: expected %s to be %v, got %v", name, attribute, expected, value)
+            }
+        }
+    }
+}
+
+func TestAlertTemplateAssignmentResourceImportState_IdentityNeedsOneTarget(t *testing.T) {
+    r := &AlertTemplateAssignmentResource{}
+
+    for name, attributes := range map[string]map[string]attr.Value{
+        "none": {},
+        "two":  {"client_id": types.Int64Value(3), "policy_id": types.Int64Value(4)},
+    } {
+        resp := importByIdentity(t, r, identityFor(t, r, attributes))
+        if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Identity" {
+            t.Errorf("%s: expected an invalid identity error, got: %v", name, resp.Diagnostics)
+        }
+    }
+}
+
+func TestAlertTemplateAssignmentResourceRead_SetsIdentityWhenRemoved(t *testing.T) {
+    ctx := context.Background()
+    server, _ := assignmentServer(t, map[string]interface{}{"/clients/3/": nil})
+    r := &AlertTemplateAssignmentResource{client: newTestClient(server)}
+    schemaResp := resourceSchemaFor(t, r)
+
+    model := assignmentModel(8, types.Int64Value(3), types.Int64Null(), types.Int64Null())
+    model.Id = types.StringValue("client/3")
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build state: %v", diags)
+    }
+
+    // The state of an older provider version has no identity yet, and
+    // Terraform rejects a read without one even when the resource is gone
+    resp := resource.ReadResponse{State: state, Identity: identityFor(t, r, nil)}
+    r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if !resp.State.Raw.IsNull() {
+        t.Error("expected the detached assignment to be removed from state")
+    }
+
+    var clientId types.Int64
+    resp.Identity.GetAttribute(ctx, path.Root("client_id"), &clientId)
+    if clientId.ValueInt64() != 3 {
+        t.Errorf("expected client_id 3 in the identity, got %v", clientId)
+    }
+}
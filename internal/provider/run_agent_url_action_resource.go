@@ -0,0 +1,218 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RunAgentURLActionResource{}
+
+func NewRunAgentURLActionResource() resource.Resource {
+    return &RunAgentURLActionResource{}
+}
+
+// RunAgentURLActionResource runs a URL action against an agent when it is
+// created.
+type RunAgentURLActionResource struct {
+    client *ClientConfig
+}
+
+// RunAgentURLActionResourceModel describes the resource data model.
+type RunAgentURLActionResourceModel struct {
+    Id          types.String `tfsdk:"id"`
+    AgentId     types.String `tfsdk:"agent_id"`
+    URLActionId types.Int64  `tfsdk:"url_action_id"`
+    Triggers    types.Map    `tfsdk:"triggers"`
+    URL         types.String `tfsdk:"url"`
+    Response    types.String `tfsdk:"response"`
+}
+
+func (r *RunAgentURLActionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_run_agent_url_action"
+}
+
+func (r *RunAgentURLActionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Runs a URL action against an agent when created, like \"Run URL Action\" in the agent menu. " +
+            "Tactical RMM substitutes the agent, client and site placeholders of the action and returns the resulting URL; " +
+            "REST actions are also sent by the server and their response is returned. Destroying this resource does nothing on the server.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this run",
+                Computed:            true,
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent whose fields are substituted into the URL action",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "url_action_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the URL action to run",
+                Required:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the URL action to run again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "url": schema.StringAttribute{
+                MarkdownDescription: "URL with all placeholders substituted",
+                Computed:            true,
+            },
+            "response": schema.StringAttribute{
+                MarkdownDescription: "Response of the remote endpoint for REST actions, null for web actions",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (r *RunAgentURLActionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *RunAgentURLActionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data RunAgentURLActionResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PATCH", fmt.Sprintf("%s/core/urlaction/run/", r.client.BaseURL), map[string]interface{}{
+        "agent_id": data.AgentId.ValueString(),
+        "action":   data.URLActionId.ValueInt64(),
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to run URL action, got error: %s", err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to run URL action, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    url, response, err := parseURLActionResult(respBody)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return
+    }
+
+    data.Id = types.StringValue(actionID())
+    data.URL = types.StringValue(url)
+    data.Response = response
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseURLActionResult extracts the substituted URL and, for REST actions, the
+// remote response from a run URL action response. Web actions return the URL
+// as a JSON string, REST actions an object with the URL and the result.
+func parseURLActionResult(body []byte) (string, types.String, error) {
+    var decoded interface{}
+    if err := json.Unmarshal(body, &decoded); err != nil {
+        return "", types.StringNull(), err
+    }
+
+    switch value := decoded.(type) {
+    case string:
+        return value, types.StringNull(), nil
+    case map[string]interface{}:
+        url, _ := value["url"].(string)
+        for _, key := range []string{"result", "response"} {
+            switch result := value[key].(type) {
+            case nil:
+                continue
+            case string:
+                return url, types.StringValue(result), nil
+            default:
+                encoded, err := json.Marshal(result)
+                if err != nil {
+                    return "", types.StringNull(), err
+                }
+                return url, types.StringValue(string(encoded)), nil
+            }
+        }
+        return url, types.StringNull(), nil
+    }
+
+    return "", types.StringNull(), fmt.Errorf("unexpected response: %s", string(body))
+}
+
+func (r *RunAgentURLActionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Running a URL action is a one-off operation, there is no remote object to refresh
+}
+
+func (r *RunAgentURLActionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data RunAgentURLActionResourceModel
+    var state RunAgentURLActionResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.URL = state.URL
+    data.Response = state.Response
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RunAgentURLActionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // A URL action that ran cannot be undone, removing the resource only drops it from state
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func urlActionServer(t *testing.T, result interface{}) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPatch || r.URL.Path != "/core/urlaction/run/" {
+            http.NotFound(w, r)
+            return
+        }
+        var body map[string]interface{}
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+            t.Errorf("unable to decode request: %s", err)
+        }
+        if body["agent_id"] != "agent-1" || body["action"] != float64(3) {
+            t.Errorf("unexpected request body: %v", body)
+        }
+        writeJSON(t, w, result)
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func runURLAction(t *testing.T, server *httptest.Server) RunAgentURLActionResourceModel {
+    t.Helper()
+    r := &RunAgentURLActionResource{client: newTestClient(server)}
+    resp := createResource(t, r, &RunAgentURLActionResourceModel{
+        AgentId:     types.StringValue("agent-1"),
+        URLActionId: types.Int64Value(3),
+        Triggers:    types.MapNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state RunAgentURLActionResourceModel
+    resp.State.Get(context.Background(), &state)
+    return state
+}
+
+func TestRunAgentURLActionResourceCreate_WebAction(t *testing.T) {
+    state := runURLAction(t, urlActionServer(t, "https://psa.example.com/tickets/new?host=web01&client=Acme"))
+
+    if state.URL.ValueString() != "https://psa.example.com/tickets/new?host=web01&client=Acme" {
+        t.Errorf("unexpected url: %s", state.URL.ValueString())
+    }
+    if !state.Response.IsNull() {
+        t.Errorf("expected no response for a web action, got %s", state.Response.ValueString())
+    }
+}
+
+func TestRunAgentURLActionResourceCreate_RESTAction(t *testing.T) {
+    state := runURLAction(t, urlActionServer(t, map[string]interface{}{
+        "url":    "https://psa.example.com/api/tickets",
+        "result": map[string]interface{}{"ticket": 42},
+    }))
+
+    if state.URL.ValueString() != "https://psa.example.com/api/tickets" {
+        t.Errorf("unexpected url: %s", state.URL.ValueString())
+    }
+    if state.Response.ValueString() != `{"ticket":42}` {
+        t.Errorf("unexpected response: %s", state.Response.ValueString())
+    }
+}
@@ -0,0 +1,396 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "time"
+    // Agent time zones must resolve on hosts without a zoneinfo database, e.g. Windows
+    _ "time/tzdata"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ScheduleRebootResource{}
+var _ resource.ResourceWithValidateConfig = &ScheduleRebootResource{}
+var _ resource.ResourceWithModifyPlan = &ScheduleRebootResource{}
+
+// scheduledRebootLayout is the agent local time format expected by the
+// reboot endpoint.
+const scheduledRebootLayout = "2006-01-02T15:04:05"
+
+// pendingActionScheduledReboot is the pending action type of a scheduled reboot.
+const pendingActionScheduledReboot = "schedreboot"
+
+func NewScheduleRebootResource() resource.Resource {
+    return &ScheduleRebootResource{}
+}
+
+// ScheduleRebootResource schedules a reboot of an agent when it is created.
+type ScheduleRebootResource struct {
+    client *ClientConfig
+}
+
+// ScheduleRebootResourceModel describes the resource data model.
+type ScheduleRebootResourceModel struct {
+    Id              types.String `tfsdk:"id"`
+    AgentId         types.String `tfsdk:"agent_id"`
+    RebootAt        types.String `tfsdk:"reboot_at"`
+    ReplaceExisting types.Bool   `tfsdk:"replace_existing"`
+    Triggers        types.Map    `tfsdk:"triggers"`
+    PendingActionId types.Int64  `tfsdk:"pending_action_id"`
+    TaskName        types.String `tfsdk:"task_name"`
+    AgentTime       types.String `tfsdk:"agent_time"`
+    ReplacedIds     types.List   `tfsdk:"replaced_ids"`
+}
+
+func (r *ScheduleRebootResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_schedule_reboot"
+}
+
+func (r *ScheduleRebootResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Schedules a reboot of a Windows agent at a given time when created, like the \"Reboot Later\" action of the UI. " +
+            "The time is converted to the agent's time zone, which is how Tactical RMM interprets it. " +
+            "Reboots already scheduled for the agent are cancelled first unless `replace_existing = false`. " +
+            "The created pending action can be cancelled with `tacticalrmm_cancel_pending_action`; destroying this resource does not cancel it.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "Identifier of this scheduling operation",
+                Computed:            true,
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent to reboot",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "reboot_at": schema.StringAttribute{
+                MarkdownDescription: "Time of the reboot as an RFC3339 timestamp, e.g. `2024-06-01T22:00:00Z`. Must be in the future.",
+                Required:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.RequiresReplace(),
+                },
+            },
+            "replace_existing": schema.BoolAttribute{
+                MarkdownDescription: "Cancel reboots already scheduled for the agent before scheduling this one. Defaults to true.",
+                Optional:            true,
+                PlanModifiers: []planmodifier.Bool{
+                    boolplanmodifier.RequiresReplace(),
+                },
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values that cause the reboot to be scheduled again when changed",
+                Optional:            true,
+                ElementType:         types.StringType,
+                PlanModifiers: []planmodifier.Map{
+                    mapplanmodifier.RequiresReplace(),
+                },
+            },
+            "pending_action_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the pending action of the scheduled reboot, null if it could not be determined",
+                Computed:            true,
+            },
+            "task_name": schema.StringAttribute{
+                MarkdownDescription: "Name of the scheduled task created on the agent",
+                Computed:            true,
+            },
+            "agent_time": schema.StringAttribute{
+                MarkdownDescription: "Reboot time in the agent's time zone as sent to Tactical RMM",
+                Computed:            true,
+            },
+            "replaced_ids": schema.ListAttribute{
+                MarkdownDescription: "IDs of previously scheduled reboot pending actions that were cancelled",
+                Computed:            true,
+                ElementType:         types.Int64Type,
+            },
+        },
+    }
+}
+
+func (r *ScheduleRebootResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data ScheduleRebootResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.RebootAt.IsNull() || data.RebootAt.IsUnknown() {
+        return
+    }
+
+    // Whether the time is still in the future is checked when planning and
+    // applying a new reboot; a scheduled one may be in the past by now
+    parseRebootTime(data.RebootAt.ValueString(), &resp.Diagnostics)
+}
+
+func (r *ScheduleRebootResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+    // Only a plan that schedules a reboot needs a time in the future
+    if req.Plan.Raw.IsNull() || (!req.State.Raw.IsNull() && len(resp.RequiresReplace) == 0) {
+        return
+    }
+
+    var data ScheduleRebootResourceModel
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() || data.RebootAt.IsUnknown() {
+        return
+    }
+
+    validateRebootTime(data.RebootAt.ValueString(), &resp.Diagnostics)
+}
+
+// parseRebootTime parses reboot_at and reports an error unless it is a valid
+// RFC3339 timestamp.
+func parseRebootTime(value string, diags *diag.Diagnostics) (time.Time, bool) {
+    rebootAt, err := time.Parse(time.RFC3339, value)
+    if err != nil {
+        diags.AddAttributeError(
+            path.Root("reboot_at"),
+            "Invalid Reboot Time",
+            fmt.Sprintf("reboot_at must be an RFC3339 timestamp such as '2024-06-01T22:00:00Z', got error: %s", err),
+        )
+        return time.Time{}, false
+    }
+    return rebootAt, true
+}
+
+// validateRebootTime parses reboot_at and reports an error unless it is a
+// valid RFC3339 timestamp in the future.
+func validateRebootTime(value string, diags *diag.Diagnostics) (time.Time, bool) {
+    rebootAt, ok := parseRebootTime(value, diags)
+    if !ok {
+        return time.Time{}, false
+    }
+
+    if !rebootAt.After(time.Now()) {
+        diags.AddAttributeError(
+            path.Root("reboot_at"),
+            "Invalid Reboot Time",
+            fmt.Sprintf("reboot_at must be in the future, got %s.", value),
+        )
+        return time.Time{}, false
+    }
+
+    return rebootAt, true
+}
+
+func (r *ScheduleRebootResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *ScheduleRebootResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data ScheduleRebootResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The plan may have been created a while ago, so check again
+    rebootAt, ok := validateRebootTime(data.RebootAt.ValueString(), &resp.Diagnostics)
+    if !ok {
+        return
+    }
+
+    agentId := data.AgentId.ValueString()
+    agent, err := r.client.getObject(ctx, fmt.Sprintf("%s/agents/%s/", r.client.BaseURL, agentId))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read agent %s, got error: %s", agentId, err))
+        return
+    }
+
+    // Tactical RMM reads the time without zone in the agent's time zone
+    location, err := r.agentLocation(ctx, agent)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to determine the time zone of agent %s, got error: %s", agentId, err))
+        return
+    }
+    agentTime := rebootAt.In(location).Format(scheduledRebootLayout)
+
+    pendingURL := fmt.Sprintf("%s/agents/%s/pendingactions/", r.client.BaseURL, agentId)
+
+    replaced := []attr.Value{}
+    if data.ReplaceExisting.IsNull() || data.ReplaceExisting.ValueBool() {
+        existing, err := r.client.listObjects(ctx, pendingURL)
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list pending actions, got error: %s", err))
+            return
+        }
+
+        for _, id := range scheduledRebootIds(existing) {
+            statusCode, _, err := r.client.sendJSON(ctx, "DELETE", fmt.Sprintf("%s/logs/pendingactions/%d/", r.client.BaseURL, id), nil)
+            if err != nil {
+                resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to cancel scheduled reboot %d, got error: %s", id, err))
+                return
+            }
+            // The reboot may have run between listing and cancelling it
+            if statusCode == http.StatusNotFound {
+                continue
+            }
+            if statusCode != http.StatusOK && statusCode != http.StatusNoContent {
+                resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to cancel scheduled reboot %d, status code: %d", id, statusCode))
+                return
+            }
+            replaced = append(replaced, types.Int64Value(id))
+        }
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PATCH", fmt.Sprintf("%s/agents/%s/reboot/", r.client.BaseURL, agentId), map[string]interface{}{
+        "datetime": agentTime,
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to schedule reboot, got error: %s", err))
+        return
+    }
+
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to schedule reboot, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    var result map[string]interface{}
+    if err := json.Unmarshal(respBody, &result); err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return
+    }
+    taskName, _ := result["task_name"].(string)
+
+    // The response does not include the pending action, find it by task name
+    data.PendingActionId = types.Int64Null()
+    actions, err := r.client.listObjects(ctx, pendingURL)
+    if err != nil {
+        resp.Diagnostics.AddWarning(
+            "Unable to Determine Pending Action",
+            fmt.Sprintf("The reboot was scheduled, but listing pending actions failed: %s", err),
+        )
+    } else {
+        for _, action := range actions {
+            details, _ := action["details"].(map[string]interface{})
+            if name, ok := details["taskname"].(string); ok && name == taskName {
+                data.PendingActionId = int64Value(action["id"])
+                break
+            }
+        }
+    }
+
+    replacedIds, diags := types.ListValue(types.Int64Type, replaced)
+    resp.Diagnostics.Append(diags...)
+
+    data.Id = types.StringValue(actionID())
+    data.TaskName = types.StringValue(taskName)
+    data.AgentTime = types.StringValue(agentTime)
+    data.ReplacedIds = replacedIds
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// agentLocation returns the time zone of an agent: its own when set, the
+// server default otherwise.
+func (r *ScheduleRebootResource) agentLocation(ctx context.Context, agent map[string]interface{}) (*time.Location, error) {
+    name, _ := agent["time_zone"].(string)
+    if name == "" {
+        name, _ = agent["timezone"].(string)
+    }
+    if name == "" {
+        settings, err := r.client.getObject(ctx, fmt.Sprintf("%s/core/settings/", r.client.BaseURL))
+        if err != nil {
+            return nil, err
+        }
+        name, _ = settings["default_time_zone"].(string)
+    }
+    if name == "" {
+        return time.UTC, nil
+    }
+    return time.LoadLocation(name)
+}
+
+// scheduledRebootIds returns the IDs of the pending scheduled reboots in a
+// pending actions list.
+func scheduledRebootIds(actions []map[string]interface{}) []int64 {
+    var ids []int64
+    for _, action := range actions {
+        if actionType, _ := action["action_type"].(string); actionType != pendingActionScheduledReboot {
+            continue
+        }
+        if status, ok := action["status"].(string); ok && status != "pending" {
+            continue
+        }
+        if id, ok := action["id"].(float64); ok {
+            ids = append(ids, int64(id))
+        }
+    }
+    return ids
+}
+
+func (r *ScheduleRebootResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    // Scheduling is a one-off operation, the pending action is not tracked
+}
+
+func (r *ScheduleRebootResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data ScheduleRebootResourceModel
+    var state ScheduleRebootResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Every input forces replacement, so only keep the previous results
+    data.Id = state.Id
+    data.PendingActionId = state.PendingActionId
+    data.TaskName = state.TaskName
+    data.AgentTime = state.AgentTime
+    data.ReplacedIds = state.ReplacedIds
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ScheduleRebootResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    // The scheduled reboot is left in place, cancel it with tacticalrmm_cancel_pending_action
+}
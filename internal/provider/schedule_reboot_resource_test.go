@@ -0,0 +1,209 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// rebootServer serves a New York agent with one reboot already scheduled and
+// records deleted pending actions and the datetime of every reboot request.
+func rebootServer(t *testing.T) (*httptest.Server, *[]string, *[]string) {
+    t.Helper()
+    var mu sync.Mutex
+    var deleted, scheduled []string
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        defer mu.Unlock()
+
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/agent-1/":
+            writeJSON(t, w, map[string]interface{}{"agent_id": "agent-1", "time_zone": "America/New_York"})
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/agent-1/pendingactions/":
+            actions := []map[string]interface{}{
+                {"id": 4, "action_type": "chocoinstall", "status": "pending"},
+            }
+            if len(deleted) == 0 {
+                actions = append(actions, map[string]interface{}{"id": 5, "action_type": "schedreboot", "status": "pending", "details": map[string]interface{}{"taskname": "TacticalRMM_SchedReboot_old"}})
+            }
+            if len(scheduled) > 0 {
+                actions = append(actions, map[string]interface{}{"id": 9, "action_type": "schedreboot", "status": "pending", "details": map[string]interface{}{"taskname": "TacticalRMM_SchedReboot_new"}})
+            }
+            writeJSON(t, w, actions)
+        case r.Method == http.MethodDelete && r.URL.Path == "/logs/pendingactions/5/":
+            deleted = append(deleted, "5")
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodPatch && r.URL.Path == "/agents/agent-1/reboot/":
+            var body struct {
+                Datetime string `json:"datetime"`
+            }
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode reboot request: %s", err)
+            }
+            scheduled = append(scheduled, body.Datetime)
+            writeJSON(t, w, map[string]interface{}{"time": "later", "agent": "web01", "task_name": "TacticalRMM_SchedReboot_new"})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &deleted, &scheduled
+}
+
+func rebootModel(rebootAt time.Time) *ScheduleRebootResourceModel {
+    return &ScheduleRebootResourceModel{
+        AgentId:     types.StringValue("agent-1"),
+        RebootAt:    types.StringValue(rebootAt.Format(time.RFC3339)),
+        Triggers:    types.MapNull(types.StringType),
+        ReplacedIds: types.ListNull(types.Int64Type),
+    }
+}
+
+func TestScheduleRebootResourceCreate_ReplacesExisting(t *testing.T) {
+    server, deleted, scheduled := rebootServer(t)
+    rebootAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Minute)
+
+    r := &ScheduleRebootResource{client: newTestClient(server)}
+    resp := createResource(t, r, rebootModel(rebootAt))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    newYork, err := time.LoadLocation("America/New_York")
+    if err != nil {
+        t.Skipf("time zone database unavailable: %s", err)
+    }
+    expectedTime := rebootAt.In(newYork).Format(scheduledRebootLayout)
+    if !reflect.DeepEqual(*scheduled, []string{expectedTime}) {
+        t.Errorf("expected reboot at agent time %s, got %v", expectedTime, *scheduled)
+    }
+    if !reflect.DeepEqual(*deleted, []string{"5"}) {
+        t.Errorf("expected the existing reboot to be cancelled, got %v", *deleted)
+    }
+
+    var state ScheduleRebootResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.PendingActionId.ValueInt64() != 9 {
+        t.Errorf("expected pending_action_id 9, got %d", state.PendingActionId.ValueInt64())
+    }
+    if state.TaskName.ValueString() != "TacticalRMM_SchedReboot_new" {
+        t.Errorf("unexpected task_name: %s", state.TaskName.ValueString())
+    }
+    var replaced []int64
+    state.ReplacedIds.ElementsAs(context.Background(), &replaced, false)
+    if !reflect.DeepEqual(replaced, []int64{5}) {
+        t.Errorf("expected replaced_ids [5], got %v", replaced)
+    }
+}
+
+func TestScheduleRebootResourceCreate_StacksWhenNotReplacing(t *testing.T) {
+    server, deleted, scheduled := rebootServer(t)
+
+    model := rebootModel(time.Now().Add(time.Hour))
+    model.ReplaceExisting = types.BoolValue(false)
+
+    r := &ScheduleRebootResource{client: newTestClient(server)}
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if len(*deleted) != 0 {
+        t.Errorf("expected no cancellation, got %v", *deleted)
+    }
+    if len(*scheduled) != 1 {
+        t.Errorf("expected one reboot to be scheduled, got %v", *scheduled)
+    }
+}
+
+func TestScheduleRebootResourceCreate_RejectsPastTime(t *testing.T) {
+    server, _, scheduled := rebootServer(t)
+
+    r := &ScheduleRebootResource{client: newTestClient(server)}
+    resp := createResource(t, r, rebootModel(time.Now().Add(-time.Hour)))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a reboot time in the past")
+    }
+    if len(*scheduled) != 0 {
+        t.Errorf("expected no reboot to be scheduled, got %v", *scheduled)
+    }
+}
+
+func TestScheduleRebootResourceModifyPlan_PastTime(t *testing.T) {
+    ctx := context.Background()
+    r := &ScheduleRebootResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    scheduled := rebootModel(time.Now().Add(-time.Hour))
+    scheduled.Id = types.StringValue("agent-1")
+    scheduled.ReplacedIds = types.ListValueMust(types.Int64Type, nil)
+
+    // A new reboot must be in the future
+    resp := planCreate(t, r, scheduled)
+    if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Invalid Reboot Time" {
+        t.Errorf("expected an error for a new reboot in the past, got %v", resp.Diagnostics)
+    }
+
+    // A reboot scheduled earlier plans cleanly once its time has passed
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, scheduled); diags.HasError() {
+        t.Fatalf("unable to build state: %v", diags)
+    }
+    plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: state.Raw}
+    resp = resource.ModifyPlanResponse{Plan: plan}
+    r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: plan, State: state}, &resp)
+    if resp.Diagnostics.HasError() {
+        t.Errorf("unexpected diagnostics for an existing reboot: %v", resp.Diagnostics)
+    }
+
+    // Replacing it schedules a new reboot, which is checked again
+    resp = resource.ModifyPlanResponse{Plan: plan, RequiresReplace: path.Paths{path.Root("triggers")}}
+    r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: plan, State: state}, &resp)
+    if !resp.Diagnostics.HasError() {
+        t.Error("expected an error when replacing with a reboot in the past")
+    }
+}
+
+func TestScheduleRebootResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &ScheduleRebootResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    cases := map[string]struct {
+        rebootAt string
+        err      bool
+    }{
+        "future":   {rebootAt: time.Now().Add(time.Hour).Format(time.RFC3339)},
+        "past":     {rebootAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+        "no zone":  {rebootAt: "2024-06-01T22:00:00", err: true},
+        "not time": {rebootAt: "tonight", err: true},
+    }
+
+    for name, tc := range cases {
+        model := rebootModel(time.Now())
+        model.RebootAt = types.StringValue(tc.rebootAt)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if resp.Diagnostics.HasError() != tc.err {
+            t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+        }
+    }
+}
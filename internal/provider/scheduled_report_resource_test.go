@@ -0,0 +1,239 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseReportCron(t *testing.T) {
+    cases := []struct {
+        cron    string
+        want    reportSchedule
+        wantErr bool
+    }{
+        {cron: "30 7 * * *", want: reportSchedule{frequency: "daily", timeOfDay: "07:30"}},
+        {cron: "0 18 * * 5", want: reportSchedule{frequency: "weekly", dayOfWeek: "friday", timeOfDay: "18:00"}},
+        {cron: "0 6 * * 7", want: reportSchedule{frequency: "weekly", dayOfWeek: "sunday", timeOfDay: "06:00"}},
+        {cron: "15 0 1 * *", want: reportSchedule{frequency: "monthly", dayOfMonth: 1, timeOfDay: "00:15"}},
+        {cron: "*/15 * * * *", wantErr: true},
+        {cron: "0 7 * * 1-5", wantErr: true},
+        {cron: "0 7 31 * *", wantErr: true},
+        {cron: "0 7 1 1 *", wantErr: true},
+        {cron: "0 7 1 * 1", wantErr: true},
+        {cron: "0 7 * *", wantErr: true},
+    }
+
+    for _, tc := range cases {
+        got, err := parseReportCron(tc.cron)
+        if tc.wantErr {
+            if err == nil {
+                t.Errorf("%q: expected an error, got %+v", tc.cron, got)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("%q: unexpected error: %s", tc.cron, err)
+            continue
+        }
+        if got != tc.want {
+            t.Errorf("%q: expected %+v, got %+v", tc.cron, tc.want, got)
+        }
+        if err := got.validate(); err != nil {
+            t.Errorf("%q: parsed schedule does not validate: %s", tc.cron, err)
+        }
+    }
+}
+
+func scheduledReportModel() *ScheduledReportResourceModel {
+    return &ScheduledReportResourceModel{
+        Id:              types.Int64Null(),
+        Name:            types.StringValue("Weekly Patch Status"),
+        TemplateId:      types.Int64Value(3),
+        ClientId:        types.Int64Value(12),
+        SiteId:          types.Int64Null(),
+        Cron:            types.StringNull(),
+        Frequency:       types.StringValue("weekly"),
+        DayOfWeek:       types.StringValue("monday"),
+        DayOfMonth:      types.Int64Null(),
+        TimeOfDay:       types.StringValue("07:00"),
+        Format:          types.StringNull(),
+        EmailRecipients: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("it@example.com")}),
+        Enabled:         types.BoolNull(),
+    }
+}
+
+func TestScheduledReportResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &ScheduledReportResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    cases := map[string]struct {
+        modify  func(*ScheduledReportResourceModel)
+        summary string
+    }{
+        "valid": {modify: func(m *ScheduledReportResourceModel) {}},
+        "cron with steps": {
+            modify: func(m *ScheduledReportResourceModel) {
+                m.Frequency, m.DayOfWeek, m.TimeOfDay = types.StringNull(), types.StringNull(), types.StringNull()
+                m.Cron = types.StringValue("*/30 * * * *")
+            },
+            summary: "Unsupported Schedule",
+        },
+        "last day of month": {
+            modify: func(m *ScheduledReportResourceModel) {
+                m.Frequency, m.DayOfWeek = types.StringValue("monthly"), types.StringNull()
+                m.DayOfMonth = types.Int64Value(31)
+            },
+            summary: "Unsupported Schedule",
+        },
+        "cron and frequency": {
+            modify:  func(m *ScheduledReportResourceModel) { m.Cron = types.StringValue("0 7 * * 1") },
+            summary: "Invalid Report Schedule",
+        },
+        "client and site": {
+            modify:  func(m *ScheduledReportResourceModel) { m.SiteId = types.Int64Value(4) },
+            summary: "Invalid Report Target",
+        },
+        "unknown format": {
+            modify:  func(m *ScheduledReportResourceModel) { m.Format = types.StringValue("docx") },
+            summary: "Invalid Report Format",
+        },
+        "invalid recipient": {
+            modify: func(m *ScheduledReportResourceModel) {
+                m.EmailRecipients = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("it@example.com, ops@example.com")})
+            },
+            summary: "Invalid Email Recipient",
+        },
+    }
+
+    for name, tc := range cases {
+        model := scheduledReportModel()
+        tc.modify(model)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if tc.summary == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != tc.summary {
+            t.Errorf("%s: expected a %q error, got: %v", name, tc.summary, resp.Diagnostics)
+        }
+    }
+}
+
+// scheduledReportServer stores the last created or updated schedule as
+// report 9 and serves it back.
+func scheduledReportServer(t *testing.T) (*httptest.Server, *map[string]interface{}) {
+    t.Helper()
+    stored := map[string]interface{}{}
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/reporting/schedules/":
+            if err := json.NewDecoder(r.Body).Decode(&stored); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            stored["id"] = 9
+            writeJSON(t, w, stored)
+        case r.Method == http.MethodGet && r.URL.Path == "/reporting/schedules/9/":
+            writeJSON(t, w, stored)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &stored
+}
+
+func TestScheduledReportResourceCreate_KeepsCron(t *testing.T) {
+    server, stored := scheduledReportServer(t)
+    r := &ScheduledReportResource{client: newTestClient(server)}
+
+    model := scheduledReportModel()
+    model.Frequency, model.DayOfWeek, model.TimeOfDay = types.StringNull(), types.StringNull(), types.StringNull()
+    model.Cron = types.StringValue("0 7 * * 1")
+
+    resp := createResource(t, r, model)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    schedule, _ := (*stored)["schedule"].(map[string]interface{})
+    if schedule["frequency"] != "weekly" || schedule["day_of_week"] != "monday" || schedule["time"] != "07:00" {
+        t.Errorf("unexpected schedule sent: %v", schedule)
+    }
+    dependencies, _ := (*stored)["dependencies"].(map[string]interface{})
+    if dependencies["client"] != float64(12) {
+        t.Errorf("expected the report to target client 12, got: %v", dependencies)
+    }
+
+    var created ScheduledReportResourceModel
+    resp.Diagnostics.Append(resp.State.Get(context.Background(), &created)...)
+    if created.Id.ValueInt64() != 9 {
+        t.Errorf("expected id 9, got %s", created.Id)
+    }
+    if created.Cron.ValueString() != "0 7 * * 1" || !created.Frequency.IsNull() {
+        t.Errorf("expected the configured cron to be kept, got cron %s and frequency %s", created.Cron, created.Frequency)
+    }
+    if created.Format.ValueString() != "pdf" || !created.Enabled.ValueBool() {
+        t.Errorf("expected format pdf and enabled, got %s and %s", created.Format, created.Enabled)
+    }
+}
+
+func TestScheduledReportResourceImport(t *testing.T) {
+    server, stored := scheduledReportServer(t)
+    *stored = map[string]interface{}{
+        "id":               9,
+        "name":             "Monthly Summary",
+        "template":         3,
+        "dependencies":     map[string]interface{}{"site": 4},
+        "schedule":         map[string]interface{}{"frequency": "monthly", "day_of_month": 15, "time": "06:30"},
+        "format":           "html",
+        "email_recipients": []string{"it@example.com"},
+        "enabled":          false,
+    }
+    r := &ScheduledReportResource{client: newTestClient(server)}
+
+    importResp := importResource(t, r, "9")
+    if importResp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", importResp.Diagnostics)
+    }
+
+    var imported ScheduledReportResourceModel
+    importResp.Diagnostics.Append(importResp.State.Get(context.Background(), &imported)...)
+
+    readResp := readResource(t, r, &imported)
+    if readResp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", readResp.Diagnostics)
+    }
+
+    var read ScheduledReportResourceModel
+    readResp.Diagnostics.Append(readResp.State.Get(context.Background(), &read)...)
+    if read.SiteId.ValueInt64() != 4 || !read.ClientId.IsNull() {
+        t.Errorf("expected site 4, got client %s and site %s", read.ClientId, read.SiteId)
+    }
+    if read.Frequency.ValueString() != "monthly" || read.DayOfMonth.ValueInt64() != 15 || read.TimeOfDay.ValueString() != "06:30" || !read.DayOfWeek.IsNull() {
+        t.Errorf("unexpected schedule: %s %s %s %s", read.Frequency, read.DayOfWeek, read.DayOfMonth, read.TimeOfDay)
+    }
+    if read.Format.ValueString() != "html" || read.Enabled.ValueBool() || len(read.EmailRecipients.Elements()) != 1 {
+        t.Errorf("unexpected report settings: %s %s %s", read.Format, read.Enabled, read.EmailRecipients)
+    }
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+    "fmt"
+    "regexp"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// scriptParameterPattern matches a named parameter such as -Days or --force,
+// optionally followed by =value or :value.
+var scriptParameterPattern = regexp.MustCompile(`^--?([A-Za-z][\w-]*)(?:[=:].*)?$`)
+
+// scriptPlaceholderPattern matches a positional placeholder such as <path>.
+var scriptPlaceholderPattern = regexp.MustCompile(`<[^<>]+>`)
+
+// scriptOptionalPattern matches an optional part of a syntax, e.g. [-Force].
+var scriptOptionalPattern = regexp.MustCompile(`\[[^\[\]]*\]`)
+
+// scriptParameter is a named parameter declared by a script.
+type scriptParameter struct {
+    name     string
+    required bool
+}
+
+// scriptArgSpec is the argument usage a script declares through its syntax
+// and default args. Named parameters are compared case-insensitively since
+// most scripts are PowerShell. Positional placeholders are only counted when
+// the script declares no named parameter.
+type scriptArgSpec struct {
+    parameters map[string]scriptParameter
+    required   int
+    positional int
+}
+
+// parseScriptArgSpec reads the named parameters and positional placeholders
+// of a script syntax such as "-Days <int> [-Force]". Parameters only found in
+// the default args are optional.
+func parseScriptArgSpec(syntax string, defaults []string) scriptArgSpec {
+    spec := scriptArgSpec{parameters: make(map[string]scriptParameter)}
+    requiredPart := scriptOptionalPattern.ReplaceAllString(syntax, " ")
+
+    for _, name := range scriptParameterNames(syntax) {
+        spec.parameters[strings.ToLower(name)] = scriptParameter{name: name}
+    }
+    for _, name := range scriptParameterNames(requiredPart) {
+        spec.parameters[strings.ToLower(name)] = scriptParameter{name: name, required: true}
+    }
+    for _, name := range scriptParameterNames(strings.Join(defaults, " ")) {
+        if _, ok := spec.parameters[strings.ToLower(name)]; !ok {
+            spec.parameters[strings.ToLower(name)] = scriptParameter{name: name}
+        }
+    }
+
+    if len(spec.parameters) == 0 {
+        spec.positional = len(scriptPlaceholderPattern.FindAllString(syntax, -1))
+        spec.required = len(scriptPlaceholderPattern.FindAllString(requiredPart, -1))
+    }
+    return spec
+}
+
+// scriptParameterNames returns the named parameters in text, in order.
+func scriptParameterNames(text string) []string {
+    var names []string
+    fields := strings.FieldsFunc(text, func(r rune) bool {
+        return unicode.IsSpace(r) || strings.ContainsRune("[]|,", r)
+    })
+    for _, field := range fields {
+        if match := scriptParameterPattern.FindStringSubmatch(field); match != nil {
+            names = append(names, match[1])
+        }
+    }
+    return names
+}
+
+// problems returns how args do not match the declared usage, sorted. Nothing
+// is reported when the script declares neither parameters nor placeholders.
+func (spec scriptArgSpec) problems(args []string) []string {
+    var problems []string
+
+    if len(spec.parameters) > 0 {
+        passed := make(map[string]bool)
+        for _, name := range scriptParameterNames(strings.Join(args, " ")) {
+            key := strings.ToLower(name)
+            if passed[key] {
+                continue
+            }
+            if _, ok := spec.parameters[key]; !ok {
+                problems = append(problems, fmt.Sprintf("-%s is not a parameter of the script", name))
+            }
+            passed[key] = true
+        }
+        for key, parameter := range spec.parameters {
+            if parameter.required && !passed[key] {
+                problems = append(problems, fmt.Sprintf("the required parameter -%s is missing", parameter.name))
+            }
+        }
+        sort.Strings(problems)
+        return problems
+    }
+
+    if spec.positional > 0 {
+        if len(args) < spec.required {
+            problems = append(problems, fmt.Sprintf("the script expects at least %d arguments, got %d", spec.required, len(args)))
+        }
+        if len(args) > spec.positional {
+            problems = append(problems, fmt.Sprintf("the script expects at most %d arguments, got %d", spec.positional, len(args)))
+        }
+    }
+    return problems
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestScriptArgSpecProblems(t *testing.T) {
+    tests := map[string]struct {
+        syntax   string
+        defaults []string
+        args     []string
+        expected []string
+    }{
+        "named parameters": {
+            syntax: "-Days <int> [-Force]",
+            args:   []string{"-Days 30", "-Force"},
+        },
+        "named parameters are case-insensitive": {
+            syntax: "-Days <int>",
+            args:   []string{"-days=30"},
+        },
+        "missing required parameter": {
+            syntax:   "-Days <int> [-Force]",
+            args:     []string{"-Force"},
+            expected: []string{"the required parameter -Days is missing"},
+        },
+        "unknown parameter": {
+            syntax:   "-Days <int>",
+            args:     []string{"-Days 30", "-Verbose", "-Verbose"},
+            expected: []string{"-Verbose is not a parameter of the script"},
+        },
+        "parameters from default args": {
+            defaults: []string{"-Path C:\\Temp"},
+            args:     []string{"-Path", "D:\\Logs"},
+        },
+        "negative values are not parameters": {
+            syntax: "-Offset <int>",
+            args:   []string{"-Offset", "-5"},
+        },
+        "positional placeholders": {
+            syntax: "<source> <destination> [<pattern>]",
+            args:   []string{"C:\\a", "C:\\b"},
+        },
+        "too few positional arguments": {
+            syntax:   "<source> <destination> [<pattern>]",
+            args:     []string{"C:\\a"},
+            expected: []string{"the script expects at least 2 arguments, got 1"},
+        },
+        "too many positional arguments": {
+            syntax:   "<source> <destination> [<pattern>]",
+            args:     []string{"C:\\a", "C:\\b", "*.log", "extra"},
+            expected: []string{"the script expects at most 3 arguments, got 4"},
+        },
+        "nothing declared": {
+            args: []string{"anything", "-Goes"},
+        },
+        "free text syntax": {
+            syntax: "Pass the share name",
+            args:   []string{"backups", "daily"},
+        },
+    }
+
+    for name, test := range tests {
+        problems := parseScriptArgSpec(test.syntax, test.defaults).problems(test.args)
+        if !reflect.DeepEqual(problems, test.expected) {
+            t.Errorf("%s: expected %v, got %v", name, test.expected, problems)
+        }
+    }
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// scriptBodyHTTPClient fetches script_body_url. It is separate from the API
+// client so the API key is never sent to another host, and it does not
+// follow redirects so the content always comes from the configured URL.
+var scriptBodyHTTPClient = &http.Client{
+    Timeout: 60 * time.Second,
+    CheckRedirect: func(req *http.Request, via []*http.Request) error {
+        return http.ErrUseLastResponse
+    },
+}
+
+// validateScriptBodyURL checks that value is an absolute http or https URL.
+func validateScriptBodyURL(value string) error {
+    parsed, err := url.Parse(value)
+    if err != nil {
+        return err
+    }
+    if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+        return fmt.Errorf("expected an absolute http or https URL, got %q", value)
+    }
+    return nil
+}
+
+// fetchScriptBody downloads a script body from bodyURL. Anything but a 200
+// response is an error, including redirects, and so is content larger than
+// maxBytes. The content is returned as is, like an inline script_body.
+func fetchScriptBody(ctx context.Context, bodyURL string, maxBytes int64) (string, error) {
+    httpReq, err := http.NewRequestWithContext(ctx, "GET", bodyURL, nil)
+    if err != nil {
+        return "", fmt.Errorf("unable to create request: %w", err)
+    }
+
+    httpResp, err := scriptBodyHTTPClient.Do(httpReq)
+    if err != nil {
+        return "", err
+    }
+    defer httpResp.Body.Close()
+
+    if httpResp.StatusCode >= 300 && httpResp.StatusCode < 400 {
+        return "", fmt.Errorf("%s redirects to %q (status code %d). Redirects are not followed; set script_body_url to the final URL",
+            bodyURL, httpResp.Header.Get("Location"), httpResp.StatusCode)
+    }
+    if httpResp.StatusCode != http.StatusOK {
+        return "", fmt.Errorf("%s returned status code %d", bodyURL, httpResp.StatusCode)
+    }
+
+    content, err := io.ReadAll(io.LimitReader(httpResp.Body, maxBytes+1))
+    if err != nil {
+        return "", fmt.Errorf("unable to read %s: %w", bodyURL, err)
+    }
+    if int64(len(content)) > maxBytes {
+        return "", fmt.Errorf("%s is larger than the limit of %d bytes set by max_body_bytes", bodyURL, maxBytes)
+    }
+
+    return string(content), nil
+}
+
+// scriptBodyHash returns the hex encoded SHA-256 of a script body. A null or
+// unknown body yields a null or unknown hash.
+func scriptBodyHash(body types.String) types.String {
+    if body.IsNull() {
+        return types.StringNull()
+    }
+    if body.IsUnknown() {
+        return types.StringUnknown()
+    }
+    sum := sha256.Sum256([]byte(body.ValueString()))
+    return types.StringValue(hex.EncodeToString(sum[:]))
+}
+
+// maxBodyBytes returns the configured script body size limit.
+func (m ScriptResourceModel) maxBodyBytes() int64 {
+    if m.MaxBodyBytes.IsNull() || m.MaxBodyBytes.IsUnknown() {
+        return defaultMaxScriptBodyBytes
+    }
+    return m.MaxBodyBytes.ValueInt64()
+}
+
+// fetchPinnedScriptBody fetches the script_body_url of m and checks it
+// against script_body_sha256 when set. Failures are added to diags and
+// return an unknown body.
+func fetchPinnedScriptBody(ctx context.Context, m ScriptResourceModel, diags *diag.Diagnostics) types.String {
+    bodyURL := m.ScriptBodyURL.ValueString()
+    content, err := fetchScriptBody(ctx, bodyURL, m.maxBodyBytes())
+    if err != nil {
+        diags.AddAttributeError(
+            path.Root("script_body_url"),
+            "Unable to Fetch Script Body",
+            fmt.Sprintf("Unable to fetch script_body_url, got error: %s", err),
+        )
+        return types.StringUnknown()
+    }
+
+    body := types.StringValue(content)
+    if pin := m.ScriptBodySHA256; !pin.IsNull() {
+        if hash := scriptBodyHash(body).ValueString(); !strings.EqualFold(hash, pin.ValueString()) {
+            diags.AddAttributeError(
+                path.Root("script_body_sha256"),
+                "Script Body Checksum Mismatch",
+                fmt.Sprintf("The content of %s has SHA-256 %s, but script_body_sha256 pins %s. "+
+                    "Review the changed script and update script_body_sha256 to accept it.", bodyURL, hash, pin.ValueString()),
+            )
+            return types.StringUnknown()
+        }
+    }
+    return body
+}
+
+// resolveScriptBody fetches script_body_url during apply when it could not be
+// fetched at plan time, and sets script_body_hash for the body to send.
+func resolveScriptBody(ctx context.Context, data *ScriptResourceModel, diags *diag.Diagnostics) {
+    if data.ScriptBody.IsUnknown() && !data.ScriptBodyURL.IsNull() {
+        data.ScriptBody = fetchPinnedScriptBody(ctx, *data, diags)
+    }
+    data.ScriptBodyHash = scriptBodyHash(data.ScriptBody)
+}
@@ -0,0 +1,264 @@
+package provider
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const sharedScriptBody = "Get-Service | Where-Object Status -eq 'Stopped'\r\n"
+
+// scriptRepoServer serves a shared script repository: the script at
+// /scripts/stopped.ps1, a redirect at /moved.ps1 and nothing else.
+func scriptRepoServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("X-API-KEY") != "" {
+            t.Errorf("the API key must not be sent to the script repository")
+        }
+        switch r.URL.Path {
+        case "/scripts/stopped.ps1":
+            w.Write([]byte(sharedScriptBody))
+        case "/moved.ps1":
+            http.Redirect(w, r, "/scripts/stopped.ps1", http.StatusMovedPermanently)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func sha256Hex(content string) string {
+    sum := sha256.Sum256([]byte(content))
+    return hex.EncodeToString(sum[:])
+}
+
+// urlScript is a script planned with script_body_url, so script_body is
+// unknown until the content is fetched.
+func urlScript(bodyURL string) *ScriptResourceModel {
+    return &ScriptResourceModel{
+        Id:                 types.Int64Unknown(),
+        Name:               types.StringValue("Stopped Services"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringUnknown(),
+        ScriptBodyURL:      types.StringValue(bodyURL),
+        ScriptBodyHash:     types.StringUnknown(),
+        DefaultTimeout:     types.Int64Value(90),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    }
+}
+
+func TestFetchScriptBody(t *testing.T) {
+    server := scriptRepoServer(t)
+    ctx := context.Background()
+
+    body, err := fetchScriptBody(ctx, server.URL+"/scripts/stopped.ps1", 1024)
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+    // The content is used byte for byte, like an inline script_body
+    if body != sharedScriptBody {
+        t.Errorf("unexpected body: %q", body)
+    }
+
+    tests := map[string]struct {
+        path     string
+        maxBytes int64
+        contains string
+    }{
+        "not found": {path: "/missing.ps1", maxBytes: 1024, contains: "status code 404"},
+        "redirect":  {path: "/moved.ps1", maxBytes: 1024, contains: `redirects to "/scripts/stopped.ps1" (status code 301)`},
+        "too large": {path: "/scripts/stopped.ps1", maxBytes: 10, contains: "larger than the limit of 10 bytes"},
+    }
+    for name, test := range tests {
+        _, err := fetchScriptBody(ctx, server.URL+test.path, test.maxBytes)
+        if err == nil || !strings.Contains(err.Error(), test.contains) {
+            t.Errorf("%s: expected an error containing %q, got %v", name, test.contains, err)
+        }
+    }
+}
+
+func TestScriptResourceModifyPlan_FetchesScriptBodyURL(t *testing.T) {
+    server := scriptRepoServer(t)
+    r := &ScriptResource{client: &ClientConfig{}}
+
+    resp := planCreate(t, r, urlScript(server.URL+"/scripts/stopped.ps1"))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var body, hash types.String
+    resp.Plan.GetAttribute(context.Background(), path.Root("script_body"), &body)
+    resp.Plan.GetAttribute(context.Background(), path.Root("script_body_hash"), &hash)
+    if body.ValueString() != sharedScriptBody {
+        t.Errorf("expected the fetched body in the plan, got %v", body)
+    }
+    if hash.ValueString() != sha256Hex(sharedScriptBody) {
+        t.Errorf("expected the hash of the fetched body, got %v", hash)
+    }
+}
+
+func TestScriptResourceModifyPlan_ScriptBodyChecksum(t *testing.T) {
+    server := scriptRepoServer(t)
+    r := &ScriptResource{client: &ClientConfig{}}
+
+    pinned := urlScript(server.URL + "/scripts/stopped.ps1")
+    pinned.ScriptBodySHA256 = types.StringValue(strings.ToUpper(sha256Hex(sharedScriptBody)))
+    if resp := planCreate(t, r, pinned); resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics for a matching pin: %v", resp.Diagnostics)
+    }
+
+    pinned.ScriptBodySHA256 = types.StringValue(sha256Hex("Get-Service"))
+    resp := planCreate(t, r, pinned)
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a mismatching pin")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Script Body Checksum Mismatch" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+}
+
+func TestScriptResourceModifyPlan_ScriptBodyURLNotFound(t *testing.T) {
+    server := scriptRepoServer(t)
+    r := &ScriptResource{client: &ClientConfig{}}
+
+    resp := planCreate(t, r, urlScript(server.URL+"/missing.ps1"))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a missing script")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Unable to Fetch Script Body" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+}
+
+func TestScriptResourceCreate_FetchesUnknownScriptBody(t *testing.T) {
+    repo := scriptRepoServer(t)
+    var sent interface{}
+    created := false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            var body map[string]interface{}
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            sent = body["script_body"]
+            created = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/" && !created:
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 42, "name": "Stopped Services"}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, urlScript(repo.URL+"/scripts/stopped.ps1"))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if sent != sharedScriptBody {
+        t.Errorf("expected the fetched body to be sent, got %v", sent)
+    }
+
+    var state ScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.ScriptBodyHash.ValueString() != sha256Hex(sharedScriptBody) {
+        t.Errorf("expected the hash of the fetched body in state, got %v", state.ScriptBodyHash)
+    }
+}
+
+func TestScriptResourceValidateConfig_ScriptBodySource(t *testing.T) {
+    ctx := context.Background()
+    r := &ScriptResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    tests := map[string]struct {
+        modify  func(m *ScriptResourceModel)
+        summary string
+    }{
+        "url": {
+            modify: func(m *ScriptResourceModel) {},
+        },
+        "url with pin": {
+            modify: func(m *ScriptResourceModel) {
+                m.ScriptBodySHA256 = types.StringValue(sha256Hex(sharedScriptBody))
+            },
+        },
+        "both": {
+            modify: func(m *ScriptResourceModel) {
+                m.ScriptBody = types.StringValue("Get-Service")
+            },
+            summary: "Invalid Script Body",
+        },
+        "neither": {
+            modify: func(m *ScriptResourceModel) {
+                m.ScriptBodyURL = types.StringNull()
+            },
+            summary: "Invalid Script Body",
+        },
+        "not http": {
+            modify: func(m *ScriptResourceModel) {
+                m.ScriptBodyURL = types.StringValue("file:///etc/passwd")
+            },
+            summary: "Invalid Script Body URL",
+        },
+        "short pin": {
+            modify: func(m *ScriptResourceModel) {
+                m.ScriptBodySHA256 = types.StringValue("abc123")
+            },
+            summary: "Invalid Script Body Checksum",
+        },
+        "pin without url": {
+            modify: func(m *ScriptResourceModel) {
+                m.ScriptBody = types.StringValue("Get-Service")
+                m.ScriptBodyURL = types.StringNull()
+                m.ScriptBodySHA256 = types.StringValue(sha256Hex("Get-Service"))
+            },
+            summary: "Invalid Script Body Checksum",
+        },
+    }
+
+    for name, test := range tests {
+        model := urlScript("https://raw.githubusercontent.com/acme/scripts/main/stopped.ps1")
+        model.Id = types.Int64Null()
+        model.ScriptBody = types.StringNull()
+        model.ScriptBodyHash = types.StringNull()
+        test.modify(model)
+
+        // Config has no Set method, so the raw value is built through a state
+        state := tfsdk.State{Schema: schemaResp.Schema}
+        if diags := state.Set(ctx, model); diags.HasError() {
+            t.Fatalf("%s: unable to build config: %v", name, diags)
+        }
+
+        var resp resource.ValidateConfigResponse
+        r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+        if test.summary == "" {
+            if resp.Diagnostics.HasError() {
+                t.Errorf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+            }
+            continue
+        }
+        if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != test.summary {
+            t.Errorf("%s: expected a %q error, got: %v", name, test.summary, resp.Diagnostics)
+        }
+    }
+}
@@ -6,9 +6,9 @@ import (
     "fmt"
     "net/http"
 
-    "github.com/hashicorp/terraform-plugin-framework/attr"
     "github.com/hashicorp/terraform-plugin-framework/datasource"
     "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
     "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -41,7 +41,7 @@ type ScriptDataSourceModel struct {
     RunAsUser            types.Bool   `tfsdk:"run_as_user"`
     Args                 types.List   `tfsdk:"args"`
     EnvVars              types.List   `tfsdk:"env_vars"`
-    SupportedPlatforms   types.List   `tfsdk:"supported_platforms"`
+    SupportedPlatforms   types.Set    `tfsdk:"supported_platforms"`
     Syntax               types.String `tfsdk:"syntax"`
 }
 
@@ -118,7 +118,7 @@ func (d *ScriptDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
                 Computed:            true,
                 ElementType:         types.StringType,
             },
-            "supported_platforms": schema.ListAttribute{
+            "supported_platforms": schema.SetAttribute{
                 MarkdownDescription: "Supported platforms",
                 Computed:            true,
                 ElementType:         types.StringType,
@@ -149,6 +149,8 @@ func (d *ScriptDataSource) Configure(ctx context.Context, req datasource.Configu
 }
 
 func (d *ScriptDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
     var data ScriptDataSourceModel
 
     resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -292,39 +294,21 @@ func (d *ScriptDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
     // Handle arrays
     if args, ok := script["args"].([]interface{}); ok && len(args) > 0 {
-        argsList := make([]attr.Value, len(args))
-        for i, arg := range args {
-            if str, ok := arg.(string); ok {
-                argsList[i] = types.StringValue(str)
-            }
-        }
-        data.Args = types.ListValueMust(types.StringType, argsList)
+        data.Args = stringListFrom(args, path.Root("args"), &resp.Diagnostics)
     } else {
         data.Args = types.ListNull(types.StringType)
     }
 
     if envVars, ok := script["env_vars"].([]interface{}); ok && len(envVars) > 0 {
-        envList := make([]attr.Value, len(envVars))
-        for i, env := range envVars {
-            if str, ok := env.(string); ok {
-                envList[i] = types.StringValue(str)
-            }
-        }
-        data.EnvVars = types.ListValueMust(types.StringType, envList)
+        data.EnvVars = stringListFrom(envVars, path.Root("env_vars"), &resp.Diagnostics)
     } else {
         data.EnvVars = types.ListNull(types.StringType)
     }
 
     if platforms, ok := script["supported_platforms"].([]interface{}); ok && len(platforms) > 0 {
-        platList := make([]attr.Value, len(platforms))
-        for i, plat := range platforms {
-            if str, ok := plat.(string); ok {
-                platList[i] = types.StringValue(str)
-            }
-        }
-        data.SupportedPlatforms = types.ListValueMust(types.StringType, platList)
+        data.SupportedPlatforms = stringSetFrom(platforms, path.Root("supported_platforms"), &resp.Diagnostics)
     } else {
-        data.SupportedPlatforms = types.ListNull(types.StringType)
+        data.SupportedPlatforms = types.SetNull(types.StringType)
     }
 
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
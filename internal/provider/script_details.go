@@ -0,0 +1,82 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "sync"
+)
+
+// fetchScriptDetails retrieves the full details, including script_body, of the
+// given scripts. The list endpoint does not return bodies, so every script is
+// fetched individually with at most detailFetchConcurrency parallel requests.
+// Details are cached on the client so several data sources scanning script
+// bodies in one run only fetch each script once. Scripts that could not be
+// fetched are reported in the returned error map.
+func (c *ClientConfig) fetchScriptDetails(ctx context.Context, ids []int64) (map[int64]map[string]interface{}, map[int64]error) {
+    details := make(map[int64]map[string]interface{}, len(ids))
+    failures := make(map[int64]error)
+
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+    sem := make(chan struct{}, detailFetchConcurrency)
+
+    for _, id := range ids {
+        if cached, ok := c.scriptDetails.Load(id); ok {
+            details[id] = cached.(map[string]interface{})
+            continue
+        }
+
+        wg.Add(1)
+        go func(id int64) {
+            defer wg.Done()
+            sem <- struct{}{}
+            defer func() { <-sem }()
+
+            detail, err := c.getObject(ctx, fmt.Sprintf("%s/scripts/%d/", c.BaseURL, id))
+
+            mu.Lock()
+            defer mu.Unlock()
+            if err != nil {
+                failures[id] = err
+                return
+            }
+            details[id] = detail
+            c.scriptDetails.Store(id, detail)
+        }(id)
+    }
+    wg.Wait()
+
+    return details, failures
+}
+
+// forgetScriptDetail drops a cached script detail after the script changed.
+func (c *ClientConfig) forgetScriptDetail(id int64) {
+    c.scriptDetails.Delete(id)
+}
+
+// userDefinedScriptIds returns the IDs of the user defined scripts in a scripts
+// list response. Builtin scripts cannot reference snippets or keystore entries.
+func userDefinedScriptIds(scripts []map[string]interface{}) []int64 {
+    var ids []int64
+    for _, script := range scripts {
+        if scriptType, ok := script["script_type"].(string); ok && scriptType != "userdefined" {
+            continue
+        }
+        if id, ok := script["id"].(float64); ok {
+            ids = append(ids, int64(id))
+        }
+    }
+    return ids
+}
+
+// referenceLines returns the 1-based line numbers of body that contain token.
+func referenceLines(body string, token string) []int64 {
+    var lines []int64
+    for i, line := range strings.Split(body, "\n") {
+        if strings.Contains(line, token) {
+            lines = append(lines, int64(i+1))
+        }
+    }
+    return lines
+}
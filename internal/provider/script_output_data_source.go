@@ -0,0 +1,276 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/url"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ScriptOutputDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &ScriptOutputDataSource{}
+
+const (
+    // defaultScriptOutputTimeout is the script timeout in seconds used when
+    // none is configured, the TRMM default for scripts.
+    defaultScriptOutputTimeout = 90
+
+    // scriptOutputGrace is how long past the script timeout the request may
+    // take, leaving TRMM time to reach the agent and answer.
+    scriptOutputGrace = 30 * time.Second
+)
+
+func NewScriptOutputDataSource() datasource.DataSource {
+    return &ScriptOutputDataSource{}
+}
+
+// ScriptOutputDataSource runs a script on an agent while it is read and
+// exposes the result.
+type ScriptOutputDataSource struct {
+    client *ClientConfig
+}
+
+// ScriptOutputDataSourceModel describes the data source data model.
+type ScriptOutputDataSourceModel struct {
+    Id              types.String  `tfsdk:"id"`
+    AgentId         types.String  `tfsdk:"agent_id"`
+    ScriptId        types.Int64   `tfsdk:"script_id"`
+    Args            types.List    `tfsdk:"args"`
+    EnvVars         types.List    `tfsdk:"env_vars"`
+    Timeout         types.Int64   `tfsdk:"timeout"`
+    RunAsUser       types.Bool    `tfsdk:"run_as_user"`
+    Triggers        types.Map     `tfsdk:"triggers"`
+    SensitiveOutput types.Bool    `tfsdk:"sensitive_output"`
+    Stdout          types.String  `tfsdk:"stdout"`
+    Stderr          types.String  `tfsdk:"stderr"`
+    SensitiveStdout types.String  `tfsdk:"sensitive_stdout"`
+    SensitiveStderr types.String  `tfsdk:"sensitive_stderr"`
+    ExitCode        types.Int64   `tfsdk:"exit_code"`
+    ExecutionTime   types.Float64 `tfsdk:"execution_time"`
+}
+
+func (d *ScriptOutputDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_script_output"
+}
+
+func (d *ScriptOutputDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Runs a script on an agent **every time the data source is read** and returns its output, so live facts from an endpoint can be used " +
+            "in the configuration. Reading has side effects: the script runs on every plan, refresh and apply, and each run is recorded in the agent history. " +
+            "Only use scripts that are safe to run repeatedly.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.StringAttribute{
+                MarkdownDescription: "`<agent_id>/<script_id>`",
+                Computed:            true,
+            },
+            "agent_id": schema.StringAttribute{
+                MarkdownDescription: "Agent the script runs on",
+                Required:            true,
+            },
+            "script_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the script to run",
+                Required:            true,
+            },
+            "args": schema.ListAttribute{
+                MarkdownDescription: "Script arguments",
+                Optional:            true,
+                ElementType:         types.StringType,
+            },
+            "env_vars": schema.ListAttribute{
+                MarkdownDescription: "Environment variables as `KEY=value`",
+                Optional:            true,
+                ElementType:         types.StringType,
+            },
+            "timeout": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Script timeout in seconds, defaults to %d", defaultScriptOutputTimeout),
+                Optional:            true,
+            },
+            "run_as_user": schema.BoolAttribute{
+                MarkdownDescription: "Run the script as the logged in user instead of SYSTEM (Windows only)",
+                Optional:            true,
+            },
+            "triggers": schema.MapAttribute{
+                MarkdownDescription: "Arbitrary values the run depends on. While any of them is unknown during plan, e.g. because it is computed by a resource " +
+                    "being changed, the script only runs during apply, after that change.",
+                Optional:    true,
+                ElementType: types.StringType,
+            },
+            "sensitive_output": schema.BoolAttribute{
+                MarkdownDescription: "When true, the output is returned in `sensitive_stdout` and `sensitive_stderr`, which are hidden in plans and logs, " +
+                    "and `stdout` and `stderr` are null",
+                Optional: true,
+            },
+            "stdout": schema.StringAttribute{
+                MarkdownDescription: "Standard output of the script",
+                Computed:            true,
+            },
+            "stderr": schema.StringAttribute{
+                MarkdownDescription: "Standard error of the script",
+                Computed:            true,
+            },
+            "sensitive_stdout": schema.StringAttribute{
+                MarkdownDescription: "Standard output of the script when `sensitive_output` is true",
+                Computed:            true,
+                Sensitive:           true,
+            },
+            "sensitive_stderr": schema.StringAttribute{
+                MarkdownDescription: "Standard error of the script when `sensitive_output` is true",
+                Computed:            true,
+                Sensitive:           true,
+            },
+            "exit_code": schema.Int64Attribute{
+                MarkdownDescription: "Exit code of the script, null when the agent history does not record it",
+                Computed:            true,
+            },
+            "execution_time": schema.Float64Attribute{
+                MarkdownDescription: "Execution time of the script in seconds, null when the agent history does not record it",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *ScriptOutputDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *ScriptOutputDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+    var timeout types.Int64
+    resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeout"), &timeout)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !timeout.IsNull() && !timeout.IsUnknown() && timeout.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("timeout"),
+            "Invalid Script Timeout",
+            "timeout must be at least 1 second.",
+        )
+    }
+}
+
+func (d *ScriptOutputDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ScriptOutputDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    args, envVars := []string{}, []string{}
+    if !data.Args.IsNull() {
+        resp.Diagnostics.Append(data.Args.ElementsAs(ctx, &args, false)...)
+    }
+    if !data.EnvVars.IsNull() {
+        resp.Diagnostics.Append(data.EnvVars.ElementsAs(ctx, &envVars, false)...)
+    }
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    timeout := int64(defaultScriptOutputTimeout)
+    if !data.Timeout.IsNull() {
+        timeout = data.Timeout.ValueInt64()
+    }
+
+    // The API answers once the script has finished or timed out on the agent
+    runCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second+scriptOutputGrace)
+    defer cancel()
+
+    agentId := data.AgentId.ValueString()
+    statusCode, respBody, err := d.client.sendJSON(runCtx, "POST", fmt.Sprintf("%s/agents/%s/runscript/", d.client.BaseURL, url.PathEscape(agentId)), map[string]interface{}{
+        "output":      "wait",
+        "script":      data.ScriptId.ValueInt64(),
+        "args":        args,
+        "env_vars":    envVars,
+        "timeout":     timeout,
+        "run_as_user": data.RunAsUser.ValueBool(),
+        "emailMode":   "default",
+        "emails":      []string{},
+    })
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to run script %d on agent %s, got error: %s", data.ScriptId.ValueInt64(), agentId, err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to run script %d on agent %s, status code: %d, response: %s", data.ScriptId.ValueInt64(), agentId, statusCode, errorMessage(respBody)))
+        return
+    }
+
+    // The run returns the combined output only; stdout, stderr and the exit
+    // code are recorded separately in the agent history
+    stdout, stderr := types.StringValue(errorMessage(respBody)), types.StringValue("")
+    data.ExitCode = types.Int64Null()
+    data.ExecutionTime = types.Float64Null()
+
+    results, err := d.latestScriptResults(ctx, agentId, data.ScriptId.ValueInt64())
+    if err != nil {
+        resp.Diagnostics.AddWarning(
+            "Script Exit Code Unavailable",
+            fmt.Sprintf("The script ran, but its exit code could not be read from the agent history: %s. stdout holds the combined output.", err),
+        )
+    } else if results != nil {
+        stdout = stringValue(results["stdout"])
+        stderr = stringValue(results["stderr"])
+        data.ExitCode = int64Value(results["retcode"])
+        if executionTime, ok := results["execution_time"].(float64); ok {
+            data.ExecutionTime = types.Float64Value(executionTime)
+        }
+    }
+
+    data.Id = types.StringValue(fmt.Sprintf("%s/%d", agentId, data.ScriptId.ValueInt64()))
+    data.Stdout, data.Stderr = stdout, stderr
+    data.SensitiveStdout, data.SensitiveStderr = types.StringNull(), types.StringNull()
+    if data.SensitiveOutput.ValueBool() {
+        data.SensitiveStdout, data.SensitiveStderr = stdout, stderr
+        data.Stdout, data.Stderr = types.StringNull(), types.StringNull()
+    }
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// latestScriptResults returns the script_results of the newest run of a
+// script in the agent history, or nil when the history has none.
+func (d *ScriptOutputDataSource) latestScriptResults(ctx context.Context, agentId string, scriptId int64) (map[string]interface{}, error) {
+    history, err := d.client.listObjects(ctx, fmt.Sprintf("%s/agents/%s/history/", d.client.BaseURL, url.PathEscape(agentId)))
+    if err != nil {
+        return nil, err
+    }
+
+    var latest map[string]interface{}
+    var latestId float64
+    for _, entry := range history {
+        id, _ := entry["id"].(float64)
+        script, _ := entry["script"].(float64)
+        results, ok := entry["script_results"].(map[string]interface{})
+        if !ok || int64(script) != scriptId || (latest != nil && id <= latestId) {
+            continue
+        }
+        latest, latestId = results, id
+    }
+    return latest, nil
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// scriptOutputServer runs script 5 on agent "abc" and serves an agent
+// history with two runs of it, unless historyStatus is set.
+func scriptOutputServer(t *testing.T, historyStatus int, runs *int32, sent *map[string]interface{}) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/agents/abc/runscript/":
+            atomic.AddInt32(runs, 1)
+            json.NewDecoder(r.Body).Decode(sent)
+            writeJSON(t, w, "LICENSE-1234\nwarning: trial")
+        case r.Method == http.MethodGet && r.URL.Path == "/agents/abc/history/":
+            if historyStatus != 0 {
+                w.WriteHeader(historyStatus)
+                return
+            }
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 10, "type": "script_run", "script": 5, "script_results": map[string]interface{}{"stdout": "LICENSE-0000", "stderr": "", "retcode": 0, "execution_time": 1.5}},
+                {"id": 12, "type": "script_run", "script": 5, "script_results": map[string]interface{}{"stdout": "LICENSE-1234", "stderr": "warning: trial", "retcode": 2, "execution_time": 0.25}},
+                {"id": 13, "type": "script_run", "script": 6, "script_results": map[string]interface{}{"stdout": "other", "stderr": "", "retcode": 0}},
+                {"id": 14, "type": "cmd_run", "command": "ipconfig"},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func scriptOutputConfig(sensitive bool) *ScriptOutputDataSourceModel {
+    return &ScriptOutputDataSourceModel{
+        AgentId:         types.StringValue("abc"),
+        ScriptId:        types.Int64Value(5),
+        Args:            types.ListValueMust(types.StringType, []attr.Value{types.StringValue("-Product"), types.StringValue("Office")}),
+        EnvVars:         types.ListNull(types.StringType),
+        Triggers:        types.MapNull(types.StringType),
+        SensitiveOutput: types.BoolValue(sensitive),
+    }
+}
+
+func TestScriptOutputDataSourceRead(t *testing.T) {
+    var runs int32
+    var sent map[string]interface{}
+    server := scriptOutputServer(t, 0, &runs, &sent)
+
+    resp := readDataSource(t, &ScriptOutputDataSource{client: newTestClient(server)}, scriptOutputConfig(false))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptOutputDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Stdout.ValueString() != "LICENSE-1234" || state.Stderr.ValueString() != "warning: trial" {
+        t.Errorf("expected the output of the newest run, got stdout %q, stderr %q", state.Stdout.ValueString(), state.Stderr.ValueString())
+    }
+    if state.ExitCode.ValueInt64() != 2 || state.ExecutionTime.ValueFloat64() != 0.25 {
+        t.Errorf("unexpected exit code %v or execution time %v", state.ExitCode, state.ExecutionTime)
+    }
+    if !state.SensitiveStdout.IsNull() || state.Id.ValueString() != "abc/5" {
+        t.Errorf("unexpected state: %+v", state)
+    }
+
+    if runs != 1 {
+        t.Errorf("expected 1 run, got %d", runs)
+    }
+    if sent["output"] != "wait" || sent["script"] != float64(5) || sent["timeout"] != float64(defaultScriptOutputTimeout) {
+        t.Errorf("unexpected request body: %v", sent)
+    }
+    if args, _ := sent["args"].([]interface{}); len(args) != 2 || args[1] != "Office" {
+        t.Errorf("expected the args to be sent, got %v", sent["args"])
+    }
+    if envVars, ok := sent["env_vars"].([]interface{}); !ok || len(envVars) != 0 {
+        t.Errorf("expected empty env_vars, got %v", sent["env_vars"])
+    }
+}
+
+func TestScriptOutputDataSourceRead_SensitiveOutput(t *testing.T) {
+    var runs int32
+    var sent map[string]interface{}
+    server := scriptOutputServer(t, 0, &runs, &sent)
+
+    resp := readDataSource(t, &ScriptOutputDataSource{client: newTestClient(server)}, scriptOutputConfig(true))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptOutputDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.Stdout.IsNull() || !state.Stderr.IsNull() {
+        t.Errorf("expected stdout and stderr to be null, got %q and %q", state.Stdout.ValueString(), state.Stderr.ValueString())
+    }
+    if state.SensitiveStdout.ValueString() != "LICENSE-1234" || state.SensitiveStderr.ValueString() != "warning: trial" {
+        t.Errorf("unexpected sensitive output: %q, %q", state.SensitiveStdout.ValueString(), state.SensitiveStderr.ValueString())
+    }
+}
+
+func TestScriptOutputDataSourceRead_HistoryUnavailable(t *testing.T) {
+    var runs int32
+    var sent map[string]interface{}
+    server := scriptOutputServer(t, http.StatusForbidden, &runs, &sent)
+
+    resp := readDataSource(t, &ScriptOutputDataSource{client: newTestClient(server)}, scriptOutputConfig(false))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if warnings := resp.Diagnostics.Warnings(); len(warnings) != 1 || warnings[0].Summary() != "Script Exit Code Unavailable" {
+        t.Errorf("expected an exit code warning, got: %v", resp.Diagnostics)
+    }
+
+    var state ScriptOutputDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Stdout.ValueString() != "LICENSE-1234\nwarning: trial" || !state.ExitCode.IsNull() {
+        t.Errorf("expected the combined output without exit code, got %q, %v", state.Stdout.ValueString(), state.ExitCode)
+    }
+}
+
+func TestScriptOutputDataSourceRead_ReadOnly(t *testing.T) {
+    var runs int32
+    var sent map[string]interface{}
+    server := scriptOutputServer(t, 0, &runs, &sent)
+
+    client := newTestClient(server)
+    client.ReadOnly = true
+
+    resp := readDataSource(t, &ScriptOutputDataSource{client: client}, scriptOutputConfig(false))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error in read-only mode")
+    }
+    if runs != 0 {
+        t.Errorf("expected no script run, got %d", runs)
+    }
+}
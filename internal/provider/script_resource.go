@@ -3,21 +3,38 @@ package provider
 import (
     "bytes"
     "context"
+    "crypto/sha256"
+    "encoding/hex"
     "encoding/json"
     "fmt"
+    "io"
     "net/http"
     "strconv"
+    "strings"
 
     "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
     "github.com/hashicorp/terraform-plugin-framework/path"
     "github.com/hashicorp/terraform-plugin-framework/resource"
     "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
     "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ScriptResource{}
 var _ resource.ResourceWithImportState = &ScriptResource{}
+var _ resource.ResourceWithValidateConfig = &ScriptResource{}
+var _ resource.ResourceWithModifyPlan = &ScriptResource{}
+var _ resource.ResourceWithUpgradeState = &ScriptResource{}
+var _ resource.ResourceWithIdentity = &ScriptResource{}
+
+// defaultMaxScriptBodyBytes is the script_body size limit used when
+// max_body_bytes is not set. It is well above any hand-written script but
+// catches generated or embedded payloads before they hit a proxy limit.
+const defaultMaxScriptBodyBytes = 10 * 1024 * 1024
 
 func NewScriptResource() resource.Resource {
     return &ScriptResource{}
@@ -37,14 +54,21 @@ type ScriptResourceModel struct {
     ScriptType           types.String `tfsdk:"script_type"`
     Category             types.String `tfsdk:"category"`
     ScriptBody           types.String `tfsdk:"script_body"`
+    ScriptBodyURL        types.String `tfsdk:"script_body_url"`
+    ScriptBodySHA256     types.String `tfsdk:"script_body_sha256"`
+    ScriptBodyHash       types.String `tfsdk:"script_body_hash"`
     DefaultTimeout       types.Int64  `tfsdk:"default_timeout"`
     Favorite             types.Bool   `tfsdk:"favorite"`
     Hidden               types.Bool   `tfsdk:"hidden"`
     RunAsUser            types.Bool   `tfsdk:"run_as_user"`
     Args                 types.List   `tfsdk:"args"`
     EnvVars              types.List   `tfsdk:"env_vars"`
-    SupportedPlatforms   types.List   `tfsdk:"supported_platforms"`
+    SensitiveEnvVars     types.List   `tfsdk:"sensitive_env_vars"`
+    SupportedPlatforms   types.Set    `tfsdk:"supported_platforms"`
     Syntax               types.String `tfsdk:"syntax"`
+    MaxBodyBytes         types.Int64  `tfsdk:"max_body_bytes"`
+    ValidateSnippets     types.Bool   `tfsdk:"validate_snippet_references"`
+    Force                types.Bool   `tfsdk:"force"`
 }
 
 func (r *ScriptResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -54,6 +78,8 @@ func (r *ScriptResource) Metadata(ctx context.Context, req resource.MetadataRequ
 func (r *ScriptResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
     resp.Schema = schema.Schema{
         MarkdownDescription: "Script resource for Tactical RMM",
+        // Version 1 changed supported_platforms from a list to a set
+        Version: 1,
 
         Attributes: map[string]schema.Attribute{
             "id": schema.Int64Attribute{
@@ -82,8 +108,22 @@ func (r *ScriptResource) Schema(ctx context.Context, req resource.SchemaRequest,
                 Optional:            true,
             },
             "script_body": schema.StringAttribute{
-                MarkdownDescription: "The script content",
-                Required:            true,
+                MarkdownDescription: "The script content. Exactly one of `script_body` or `script_body_url` must be set; with `script_body_url` it holds the fetched content.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "script_body_url": schema.StringAttribute{
+                MarkdownDescription: "http or https URL the script content is fetched from at plan time, for example a raw file in a shared Git repository. " +
+                    "Redirects are not followed and any response but 200 fails the plan.",
+                Optional: true,
+            },
+            "script_body_sha256": schema.StringAttribute{
+                MarkdownDescription: "Expected SHA-256 of the content of `script_body_url`, hex encoded. The plan fails when the fetched content does not match.",
+                Optional:            true,
+            },
+            "script_body_hash": schema.StringAttribute{
+                MarkdownDescription: "SHA-256 of `script_body`, hex encoded",
+                Computed:            true,
             },
             "default_timeout": schema.Int64Attribute{
                 MarkdownDescription: "Default timeout in seconds",
@@ -115,19 +155,131 @@ func (r *ScriptResource) Schema(ctx context.Context, req resource.SchemaRequest,
                 Optional:            true,
                 ElementType:         types.StringType,
             },
-            "supported_platforms": schema.ListAttribute{
-                MarkdownDescription: "Supported platforms",
+            "sensitive_env_vars": schema.ListAttribute{
+                MarkdownDescription: "Environment variables (`KEY=value`) holding secrets. They are appended to `env_vars` when sent to the API but hidden in plan output. " +
+                    "Keys must not also appear in `env_vars`.",
+                Optional:    true,
+                Sensitive:   true,
+                ElementType: types.StringType,
+            },
+            "supported_platforms": schema.SetAttribute{
+                MarkdownDescription: "Supported platforms: windows, linux, darwin",
                 Optional:            true,
                 ElementType:         types.StringType,
+                Validators: []validator.Set{
+                    supportedPlatformsValidator{},
+                },
             },
             "syntax": schema.StringAttribute{
                 MarkdownDescription: "Script syntax",
                 Optional:            true,
             },
+            "max_body_bytes": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Maximum size of `script_body` in bytes, checked at plan time. Defaults to %d (10 MiB). "+
+                    "Lower it to match the request size limit of the server or a proxy in front of it.", defaultMaxScriptBodyBytes),
+                Optional: true,
+            },
+            "validate_snippet_references": schema.BoolAttribute{
+                MarkdownDescription: "When true, the plan warns about `{{name}}` placeholders in `script_body` that are neither script variables " +
+                    "(`agent.`, `client.`, `site.`, `alert.`, `global.`) nor existing or planned script snippets. Such placeholders are run literally.",
+                Optional: true,
+            },
+            "force": schema.BoolAttribute{
+                MarkdownDescription: "When true, destroying the script first deletes the script checks and automated tasks running it. " +
+                    "Otherwise the destroy fails while any check or task runs the script, listing them. " +
+                    "The value is taken from state, so it must be applied before the destroy.",
+                Optional: true,
+            },
         },
     }
 }
 
+func (r *ScriptResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data ScriptResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.ScriptBody.IsUnknown() && !data.ScriptBodyURL.IsUnknown() && data.ScriptBody.IsNull() == data.ScriptBodyURL.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Script Body",
+            "Exactly one of 'script_body' or 'script_body_url' must be specified.",
+        )
+    }
+    if !data.ScriptBodyURL.IsNull() && !data.ScriptBodyURL.IsUnknown() {
+        if err := validateScriptBodyURL(data.ScriptBodyURL.ValueString()); err != nil {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("script_body_url"),
+                "Invalid Script Body URL",
+                fmt.Sprintf("script_body_url is not a valid URL: %s.", err),
+            )
+        }
+    }
+    if !data.ScriptBodySHA256.IsNull() && !data.ScriptBodySHA256.IsUnknown() {
+        if data.ScriptBodyURL.IsNull() {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("script_body_sha256"),
+                "Invalid Script Body Checksum",
+                "script_body_sha256 requires script_body_url to be set.",
+            )
+        } else if decoded, err := hex.DecodeString(data.ScriptBodySHA256.ValueString()); err != nil || len(decoded) != sha256.Size {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("script_body_sha256"),
+                "Invalid Script Body Checksum",
+                fmt.Sprintf("script_body_sha256 must be a hex encoded SHA-256 of 64 characters, got %q.", data.ScriptBodySHA256.ValueString()),
+            )
+        }
+    }
+
+    maxBodyBytes := int64(defaultMaxScriptBodyBytes)
+    if !data.MaxBodyBytes.IsNull() && !data.MaxBodyBytes.IsUnknown() {
+        maxBodyBytes = data.MaxBodyBytes.ValueInt64()
+        if maxBodyBytes < 1 {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("max_body_bytes"),
+                "Invalid Maximum Body Size",
+                "max_body_bytes must be at least 1.",
+            )
+        }
+    }
+    if !data.MaxBodyBytes.IsUnknown() && !data.ScriptBody.IsNull() && !data.ScriptBody.IsUnknown() && maxBodyBytes > 0 {
+        if size := int64(len(data.ScriptBody.ValueString())); size > maxBodyBytes {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("script_body"),
+                "Script Body Too Large",
+                fmt.Sprintf("script_body is %d bytes, more than the limit of %d bytes set by max_body_bytes. "+
+                    "Shorten the script, or raise max_body_bytes if the server accepts larger requests.", size, maxBodyBytes),
+            )
+        }
+    }
+
+    if data.EnvVars.IsNull() || data.EnvVars.IsUnknown() || data.SensitiveEnvVars.IsUnknown() {
+        return
+    }
+
+    // A key in both lists could not be told apart when the merged list is read back
+    sensitiveKeys := sensitiveEnvVarKeys(ctx, data.SensitiveEnvVars)
+    for i, element := range data.EnvVars.Elements() {
+        env, ok := element.(types.String)
+        if !ok || env.IsNull() || env.IsUnknown() {
+            continue
+        }
+        if key := envVarKey(env.ValueString()); sensitiveKeys[key] {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("env_vars").AtListIndex(i),
+                "Duplicate Environment Variable",
+                fmt.Sprintf("%q is also set in sensitive_env_vars. Set each variable in only one of the two lists.", key),
+            )
+        }
+    }
+}
+
+func (r *ScriptResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = idIdentitySchema("Script")
+}
+
 func (r *ScriptResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
     if req.ProviderData == nil {
         return
@@ -146,12 +298,29 @@ func (r *ScriptResource) Configure(ctx context.Context, req resource.ConfigureRe
 }
 
 func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data ScriptResourceModel
 
     resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
     if resp.Diagnostics.HasError() {
         return
     }
+    r.addSensitiveEnvVarSecrets(ctx, data.SensitiveEnvVars)
+
+    resolveScriptBody(ctx, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    adoptedId := r.resolveNameConflict(ctx, data.Name.ValueString(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
     
     // Store original state of arrays to preserve null vs empty
     argsWasNull := data.Args.IsNull()
@@ -173,10 +342,10 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
     if !data.Category.IsNull() {
         body["category"] = data.Category.ValueString()
     }
-    if !data.DefaultTimeout.IsNull() {
+    if !data.DefaultTimeout.IsNull() && !data.DefaultTimeout.IsUnknown() {
         body["default_timeout"] = data.DefaultTimeout.ValueInt64()
     } else {
-        body["default_timeout"] = 90
+        body["default_timeout"] = r.client.scriptDefaultTimeout(data.Shell.ValueString())
     }
     if !data.Favorite.IsNull() {
         body["favorite"] = data.Favorite.ValueBool()
@@ -197,9 +366,9 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
         resp.Diagnostics.Append(data.Args.ElementsAs(ctx, &args, false)...)
         body["args"] = args
     }
-    if !data.EnvVars.IsNull() {
-        var envVars []string
-        resp.Diagnostics.Append(data.EnvVars.ElementsAs(ctx, &envVars, false)...)
+    if !data.EnvVars.IsNull() || !data.SensitiveEnvVars.IsNull() {
+        envVars, diags := mergedEnvVars(ctx, data.EnvVars, data.SensitiveEnvVars)
+        resp.Diagnostics.Append(diags...)
         body["env_vars"] = envVars
     }
     if !data.SupportedPlatforms.IsNull() {
@@ -214,8 +383,25 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
         return
     }
 
+    // Tactical RMM allows duplicate script names, so when an existing script
+    // may keep the name, its ID is recorded to not mistake it for the created one
+    var preexisting map[int64]bool
+    if !r.client.resolvesConflicts() {
+        preexisting, err = r.client.idsNamed(ctx, r.client.allScriptsURL(), data.Name.ValueString())
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+            return
+        }
+    }
+
+    // An adopted script is updated in place
+    method, requestURL := "POST", fmt.Sprintf("%s/scripts/", r.client.BaseURL)
+    if adoptedId != 0 {
+        method, requestURL = "PUT", fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, adoptedId)
+    }
+
     // Create HTTP request
-    httpReq, err := http.NewRequest("POST", fmt.Sprintf("%s/scripts/", r.client.BaseURL), bytes.NewBuffer(jsonBody))
+    httpReq, err := http.NewRequest(method, requestURL, bytes.NewBuffer(jsonBody))
     if err != nil {
         resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create script, got error: %s", err))
         return
@@ -229,43 +415,30 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
     }
     defer httpResp.Body.Close()
 
-    if httpResp.StatusCode != http.StatusOK {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create script, status code: %d", httpResp.StatusCode))
-        return
-    }
-
-    // Response is just a message, so we need to get the created script
-    // First, list all scripts to find our newly created one
-    listReq, err := http.NewRequest("GET", fmt.Sprintf("%s/scripts/", r.client.BaseURL), nil)
-    if err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
-        return
-    }
-
-    listResp, err := r.client.Do(listReq)
-    if err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+    if httpResp.StatusCode == http.StatusRequestEntityTooLarge {
+        addScriptTooLargeError(&resp.Diagnostics, len(jsonBody))
         return
     }
-    defer listResp.Body.Close()
-
-    var scripts []map[string]interface{}
-    if err := json.NewDecoder(listResp.Body).Decode(&scripts); err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse scripts list, got error: %s", err))
+    if httpResp.StatusCode != http.StatusOK {
+        respBody, _ := io.ReadAll(httpResp.Body)
+        if !addAPIFieldErrors(&resp.Diagnostics, "Unable to create script", httpResp.StatusCode, respBody, scriptFieldPaths) {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create script, status code: %d", httpResp.StatusCode))
+        }
         return
     }
 
-    // Find the script we just created by name
+    // Response is just a message, so we need to find the created script by
+    // name; an adopted script is fetched by ID
     var createdScript map[string]interface{}
-    for _, script := range scripts {
-        if name, ok := script["name"].(string); ok && name == data.Name.ValueString() {
-            createdScript = script
-            break
-        }
+    if adoptedId != 0 {
+        createdScript, err = r.client.getObject(ctx, requestURL)
+    } else {
+        createdScript, err = r.client.findCreatedByFilteredName(ctx, r.client.allScriptsURL(), data.Name.ValueString(), func(script map[string]interface{}) bool {
+            return isBuiltinScript(script) || preexisting[referencedId(script["id"])]
+        })
     }
-
-    if createdScript == nil {
-        resp.Diagnostics.AddError("Client Error", "Unable to find created script")
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find created script, got error: %s", err))
         return
     }
 
@@ -284,7 +457,7 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
     if timeout, ok := createdScript["default_timeout"].(float64); ok {
         data.DefaultTimeout = types.Int64Value(int64(timeout))
     } else if data.DefaultTimeout.IsNull() || data.DefaultTimeout.IsUnknown() {
-        data.DefaultTimeout = types.Int64Value(90)
+        data.DefaultTimeout = types.Int64Value(r.client.scriptDefaultTimeout(data.Shell.ValueString()))
     }
     
     if favorite, ok := createdScript["favorite"].(bool); ok {
@@ -308,13 +481,7 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
     // Handle arrays from response - preserve null state from plan
     if !argsWasNull {
         if args, ok := createdScript["args"].([]interface{}); ok {
-            argsList := make([]attr.Value, len(args))
-            for i, arg := range args {
-                if str, ok := arg.(string); ok {
-                    argsList[i] = types.StringValue(str)
-                }
-            }
-            data.Args = types.ListValueMust(types.StringType, argsList)
+            data.Args = stringListFrom(args, path.Root("args"), &resp.Diagnostics)
         } else {
             // Plan had empty list, preserve it
             data.Args = types.ListValueMust(types.StringType, []attr.Value{})
@@ -324,11 +491,11 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
 
     if !envVarsWasNull {
         if envVars, ok := createdScript["env_vars"].([]interface{}); ok {
-            envList := make([]attr.Value, len(envVars))
-            for i, env := range envVars {
-                if str, ok := env.(string); ok {
-                    envList[i] = types.StringValue(str)
-                }
+            // The API returns the merged list, sensitive entries stay as planned
+            plain, _ := splitEnvVars(stringSlice(envVars), sensitiveEnvVarKeys(ctx, data.SensitiveEnvVars))
+            envList := make([]attr.Value, len(plain))
+            for i, env := range plain {
+                envList[i] = types.StringValue(env)
             }
             data.EnvVars = types.ListValueMust(types.StringType, envList)
         } else {
@@ -340,30 +507,32 @@ func (r *ScriptResource) Create(ctx context.Context, req resource.CreateRequest,
 
     if !platformsWasNull {
         if platforms, ok := createdScript["supported_platforms"].([]interface{}); ok {
-            platList := make([]attr.Value, len(platforms))
-            for i, plat := range platforms {
-                if str, ok := plat.(string); ok {
-                    platList[i] = types.StringValue(str)
-                }
-            }
-            data.SupportedPlatforms = types.ListValueMust(types.StringType, platList)
+            data.SupportedPlatforms = stringSetFrom(platforms, path.Root("supported_platforms"), &resp.Diagnostics)
         } else {
-            // Plan had empty list, preserve it
-            data.SupportedPlatforms = types.ListValueMust(types.StringType, []attr.Value{})
+            // Plan had empty set, preserve it
+            data.SupportedPlatforms = types.SetValueMust(types.StringType, []attr.Value{})
         }
     }
     // If supported_platforms was null in plan, keep it null
 
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 }
 
 func (r *ScriptResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
     var data ScriptResourceModel
 
     resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
     if resp.Diagnostics.HasError() {
         return
     }
+    r.addSensitiveEnvVarSecrets(ctx, data.SensitiveEnvVars)
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 
     // Create HTTP request
     httpReq, err := http.NewRequest("GET", fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, data.Id.ValueInt64()), nil)
@@ -386,6 +555,10 @@ func (r *ScriptResource) Read(ctx context.Context, req resource.ReadRequest, res
     }
 
     if httpResp.StatusCode != http.StatusOK {
+        body, _ := io.ReadAll(httpResp.Body)
+        if r.client.removeOnReadError(ctx, &resp.State, "script", httpResp.StatusCode, body, &resp.Diagnostics) {
+            return
+        }
         resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read script, status code: %d", httpResp.StatusCode))
         return
     }
@@ -416,6 +589,7 @@ func (r *ScriptResource) Read(ctx context.Context, req resource.ReadRequest, res
     if scriptBody, ok := result["script_body"].(string); ok {
         data.ScriptBody = types.StringValue(scriptBody)
     }
+    data.ScriptBodyHash = scriptBodyHash(data.ScriptBody)
     if timeout, ok := result["default_timeout"].(float64); ok {
         data.DefaultTimeout = types.Int64Value(int64(timeout))
     }
@@ -434,35 +608,36 @@ func (r *ScriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 
     // Handle arrays - preserve null if empty
     if args, ok := result["args"].([]interface{}); ok && len(args) > 0 {
-        argsList := make([]attr.Value, len(args))
-        for i, arg := range args {
-            if str, ok := arg.(string); ok {
-                argsList[i] = types.StringValue(str)
-            }
-        }
-        data.Args = types.ListValueMust(types.StringType, argsList)
+        data.Args = stringListFrom(args, path.Root("args"), &resp.Diagnostics)
     }
     // Keep null if the API returns empty or no args
 
-    if envVars, ok := result["env_vars"].([]interface{}); ok && len(envVars) > 0 {
-        envList := make([]attr.Value, len(envVars))
-        for i, env := range envVars {
-            if str, ok := env.(string); ok {
-                envList[i] = types.StringValue(str)
-            }
+    // The API returns env_vars and sensitive_env_vars merged. Entries whose key
+    // is managed as sensitive are split off again so they are never written
+    // back into the plain, displayed list.
+    envVars, _ := result["env_vars"].([]interface{})
+    plain, sensitive := splitEnvVars(stringSlice(envVars), sensitiveEnvVarKeys(ctx, data.SensitiveEnvVars))
+    if len(plain) > 0 {
+        envList := make([]attr.Value, len(plain))
+        for i, env := range plain {
+            envList[i] = types.StringValue(env)
         }
         data.EnvVars = types.ListValueMust(types.StringType, envList)
     }
     // Keep null if the API returns empty or no env_vars
 
-    if platforms, ok := result["supported_platforms"].([]interface{}); ok && len(platforms) > 0 {
-        platList := make([]attr.Value, len(platforms))
-        for i, plat := range platforms {
-            if str, ok := plat.(string); ok {
-                platList[i] = types.StringValue(str)
-            }
+    if !data.SensitiveEnvVars.IsNull() || len(sensitive) > 0 {
+        sensitiveList := make([]attr.Value, len(sensitive))
+        for i, env := range sensitive {
+            sensitiveList[i] = types.StringValue(env)
         }
-        data.SupportedPlatforms = types.ListValueMust(types.StringType, platList)
+        data.SensitiveEnvVars = types.ListValueMust(types.StringType, sensitiveList)
+        // The values may have been changed outside Terraform
+        r.addSensitiveEnvVarSecrets(ctx, data.SensitiveEnvVars)
+    }
+
+    if platforms, ok := result["supported_platforms"].([]interface{}); ok && len(platforms) > 0 {
+        data.SupportedPlatforms = stringSetFrom(platforms, path.Root("supported_platforms"), &resp.Diagnostics)
     }
     // Keep null if the API returns empty or no supported_platforms
 
@@ -470,6 +645,12 @@ func (r *ScriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 }
 
 func (r *ScriptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data ScriptResourceModel
     var state ScriptResourceModel
 
@@ -487,6 +668,12 @@ func (r *ScriptResource) Update(ctx context.Context, req resource.UpdateRequest,
 
     // Use the ID from the current state
     data.Id = state.Id
+    r.addSensitiveEnvVarSecrets(ctx, data.SensitiveEnvVars)
+
+    resolveScriptBody(ctx, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
 
     // Create API request body
     body := map[string]interface{}{
@@ -524,9 +711,9 @@ func (r *ScriptResource) Update(ctx context.Context, req resource.UpdateRequest,
         resp.Diagnostics.Append(data.Args.ElementsAs(ctx, &args, false)...)
         body["args"] = args
     }
-    if !data.EnvVars.IsNull() {
-        var envVars []string
-        resp.Diagnostics.Append(data.EnvVars.ElementsAs(ctx, &envVars, false)...)
+    if !data.EnvVars.IsNull() || !data.SensitiveEnvVars.IsNull() {
+        envVars, diags := mergedEnvVars(ctx, data.EnvVars, data.SensitiveEnvVars)
+        resp.Diagnostics.Append(diags...)
         body["env_vars"] = envVars
     }
     if !data.SupportedPlatforms.IsNull() {
@@ -556,10 +743,18 @@ func (r *ScriptResource) Update(ctx context.Context, req resource.UpdateRequest,
     }
     defer httpResp.Body.Close()
 
+    if httpResp.StatusCode == http.StatusRequestEntityTooLarge {
+        addScriptTooLargeError(&resp.Diagnostics, len(jsonBody))
+        return
+    }
     if httpResp.StatusCode != http.StatusOK {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update script, status code: %d", httpResp.StatusCode))
+        respBody, _ := io.ReadAll(httpResp.Body)
+        if !addAPIFieldErrors(&resp.Diagnostics, "Unable to update script", httpResp.StatusCode, respBody, scriptFieldPaths) {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update script, status code: %d", httpResp.StatusCode))
+        }
         return
     }
+    r.client.forgetScriptDetail(data.Id.ValueInt64())
 
     // Get the updated script to ensure all computed fields are populated
     getReq, err := http.NewRequest("GET", fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, data.Id.ValueInt64()), nil)
@@ -597,7 +792,7 @@ func (r *ScriptResource) Update(ctx context.Context, req resource.UpdateRequest,
     if timeout, ok := result["default_timeout"].(float64); ok {
         data.DefaultTimeout = types.Int64Value(int64(timeout))
     } else if data.DefaultTimeout.IsNull() || data.DefaultTimeout.IsUnknown() {
-        data.DefaultTimeout = types.Int64Value(90)
+        data.DefaultTimeout = types.Int64Value(r.client.scriptDefaultTimeout(data.Shell.ValueString()))
     }
     
     if favorite, ok := result["favorite"].(bool); ok {
@@ -619,9 +814,16 @@ func (r *ScriptResource) Update(ctx context.Context, req resource.UpdateRequest,
     }
 
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 }
 
 func (r *ScriptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data ScriptResourceModel
 
     resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -629,6 +831,28 @@ func (r *ScriptResource) Delete(ctx context.Context, req resource.DeleteRequest,
         return
     }
 
+    // Checks and tasks still running the script are looked up first, so they
+    // can be listed by name. Without access to them the delete is attempted
+    // anyway and the server decides, unless force needs them to be deleted.
+    checks, tasks, err := r.client.scriptRunners(ctx, data.Id.ValueInt64())
+    if err != nil && data.Force.ValueBool() {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find the checks and tasks running the script, got error: %s", err))
+        return
+    }
+    if len(checks)+len(tasks) > 0 {
+        if !data.Force.ValueBool() {
+            resp.Diagnostics.AddError(
+                "Script In Use",
+                fmt.Sprintf("The script is still run by:\n\n%s\n\nRemove these checks and tasks, or set force = true and apply it "+
+                    "before destroying the script to delete them along with it.", scriptRunnerList(checks, tasks)),
+            )
+            return
+        }
+        if !r.deleteRunners(ctx, checks, tasks, &resp.Diagnostics) {
+            return
+        }
+    }
+
     // Create HTTP request
     httpReq, err := http.NewRequest("DELETE", fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, data.Id.ValueInt64()), nil)
     if err != nil {
@@ -645,12 +869,114 @@ func (r *ScriptResource) Delete(ctx context.Context, req resource.DeleteRequest,
     defer httpResp.Body.Close()
 
     if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete script, status code: %d", httpResp.StatusCode))
+        addDeleteError(&resp.Diagnostics, "script", httpResp)
         return
     }
+    r.client.forgetScriptDetail(data.Id.ValueInt64())
+}
+
+// resolveNameConflict applies on_conflict adopt or replace to the
+// user-defined scripts named name and returns the ID of an adopted script, see
+// adoptOrReplace. Built-in community scripts can neither be updated nor
+// deleted, so they never conflict. A script still run by checks or tasks is
+// not replaced, as deleting it would break them.
+func (r *ScriptResource) resolveNameConflict(ctx context.Context, name string, diags *diag.Diagnostics) int64 {
+    if !r.client.resolvesConflicts() {
+        return 0
+    }
+
+    scripts, err := r.client.listObjects(ctx, r.client.allScriptsURL())
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return 0
+    }
+    var existing []map[string]interface{}
+    for _, script := range objectsNamed(scripts, name) {
+        if !isBuiltinScript(script) {
+            existing = append(existing, script)
+        }
+    }
+
+    if r.client.onConflict == onConflictReplace {
+        for _, script := range existing {
+            id := referencedId(script["id"])
+            checks, tasks, err := r.client.scriptRunners(ctx, id)
+            if err == nil && len(checks)+len(tasks) > 0 {
+                diags.AddError(
+                    "Script In Use",
+                    fmt.Sprintf("The existing script %d named %q cannot be replaced, it is still run by:\n\n%s\n\n"+
+                        "Use on_conflict = \"adopt\" to manage it instead.", id, name, scriptRunnerList(checks, tasks)),
+                )
+                return 0
+            }
+        }
+    }
+
+    adoptedId := r.client.adoptOrReplace(ctx, "script", existing, func(id int64) string {
+        return fmt.Sprintf("%s/scripts/%d/", r.client.BaseURL, id)
+    }, diags)
+    for _, script := range existing {
+        r.client.forgetScriptDetail(referencedId(script["id"]))
+    }
+    return adoptedId
+}
+
+// isBuiltinScript reports whether script is one of the community scripts
+// shipped with Tactical RMM, which cannot be created, updated or deleted.
+func isBuiltinScript(script map[string]interface{}) bool {
+    return script["script_type"] == "builtin"
+}
+
+// deleteRunners deletes the checks and tasks running a script. A runner that
+// is already gone is skipped. It returns false when a delete failed.
+func (r *ScriptResource) deleteRunners(ctx context.Context, checks, tasks []map[string]interface{}, diags *diag.Diagnostics) bool {
+    runners := []struct {
+        kind    string
+        path    string
+        objects []map[string]interface{}
+    }{
+        {kind: "task", path: "tasks", objects: tasks},
+        {kind: "check", path: "checks", objects: checks},
+    }
+    for _, runner := range runners {
+        for _, object := range runner.objects {
+            id := referencedId(object["id"])
+            statusCode, respBody, err := r.client.sendJSON(ctx, "DELETE", fmt.Sprintf("%s/%s/%d/", r.client.BaseURL, runner.path, id), nil)
+            if err != nil {
+                diags.AddError("Client Error", fmt.Sprintf("Unable to delete %s %d, got error: %s", runner.kind, id, err))
+                return false
+            }
+            if statusCode != http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotFound {
+                diags.AddError("Client Error", fmt.Sprintf("Unable to delete %s %d, status code: %d, response: %s", runner.kind, id, statusCode, errorMessage(respBody)))
+                return false
+            }
+        }
+    }
+    return true
+}
+
+// scriptRunnerList formats checks and tasks as a bulleted list of their kind,
+// ID and name.
+func scriptRunnerList(checks, tasks []map[string]interface{}) string {
+    var lines []string
+    for _, check := range sortedById(checks) {
+        dep := newDependent("check", check, "readable_desc", "name")
+        lines = append(lines, fmt.Sprintf("- check %d %q", dep.id, dep.name))
+    }
+    for _, task := range sortedById(tasks) {
+        dep := newDependent("task", task, "name")
+        lines = append(lines, fmt.Sprintf("- task %d %q", dep.id, dep.name))
+    }
+    return strings.Join(lines, "\n")
 }
 
 func (r *ScriptResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks generated by terraform query use the identity
+    if id, ok := identityImportID(ctx, req, &resp.Diagnostics); ok {
+        resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+        return
+    }
+
     // Convert string ID to int64
     id, err := strconv.ParseInt(req.ID, 10, 64)
     if err != nil {
@@ -658,5 +984,214 @@ func (r *ScriptResource) ImportState(ctx context.Context, req resource.ImportSta
         return
     }
     
+    // Only the ID is known here. Imported env vars are read into env_vars;
+    // sensitive_env_vars values are registered as secrets once configured.
     resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
+
+func (r *ScriptResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    // Nothing to check on destroy or before the provider is configured
+    if req.Plan.Raw.IsNull() || r.client == nil {
+        return
+    }
+
+    var data ScriptResourceModel
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // A script created without default_timeout gets the default for its shell,
+    // see shell_default_timeouts. It is set in the plan so it is shown before
+    // the apply instead of as a value known after apply.
+    if req.State.Raw.IsNull() && data.DefaultTimeout.IsUnknown() && !data.Shell.IsUnknown() {
+        var configured types.Int64
+        resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default_timeout"), &configured)...)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+        if configured.IsNull() {
+            timeout := types.Int64Value(r.client.scriptDefaultTimeout(data.Shell.ValueString()))
+            resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("default_timeout"), timeout)...)
+        }
+    }
+
+    // The content of script_body_url is fetched at plan time so changes to
+    // it, and drift of the stored body, show up as a script_body diff. An
+    // unknown URL or pin is fetched at apply time instead.
+    if !data.ScriptBodyURL.IsNull() && !data.ScriptBodyURL.IsUnknown() && !data.ScriptBodySHA256.IsUnknown() {
+        data.ScriptBody = fetchPinnedScriptBody(ctx, data, &resp.Diagnostics)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+        resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("script_body"), data.ScriptBody)...)
+    }
+    if !data.ScriptBody.IsUnknown() {
+        resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("script_body_hash"), scriptBodyHash(data.ScriptBody))...)
+    }
+
+    if !data.ValidateSnippets.ValueBool() || data.ScriptBody.IsUnknown() {
+        return
+    }
+
+    references := scriptSnippetReferences(data.ScriptBody.ValueString())
+    if len(references) == 0 {
+        return
+    }
+
+    snippets, err := r.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddWarning(
+            "Unable to Check Snippet References",
+            fmt.Sprintf("Unable to list script snippets, got error: %s", err),
+        )
+        return
+    }
+
+    var unknown []string
+    for _, name := range references {
+        _, planned := r.client.plannedSnippets.Load(name)
+        if !planned && objectNamed(snippets, name) == nil {
+            unknown = append(unknown, snippetToken(name))
+        }
+    }
+
+    if len(unknown) > 0 {
+        resp.Diagnostics.AddAttributeWarning(
+            path.Root("script_body"),
+            "Unknown Snippet References",
+            fmt.Sprintf("script_body references snippets that do not exist: %s. Tactical RMM runs unknown placeholders literally. "+
+                "If a snippet is created in this configuration, use the name attribute of its tacticalrmm_script_snippet resource "+
+                "in script_body so it is planned before the script.", strings.Join(unknown, ", ")),
+        )
+    }
+}
+
+// UpgradeState migrates state written by earlier versions of the provider.
+func (r *ScriptResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+    return map[int64]resource.StateUpgrader{
+        // Version 0 stored supported_platforms as a list
+        0: {
+            StateUpgrader: r.upgradeStateV0,
+        },
+    }
+}
+
+// upgradeStateV0 converts supported_platforms from a list to a set. Both are
+// JSON arrays in the raw state, so only duplicate platforms, which a set
+// cannot hold, have to be removed before the state is decoded with the
+// current schema.
+func (r *ScriptResource) upgradeStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+    var state map[string]interface{}
+    if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+        resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to parse script state, got error: %s", err))
+        return
+    }
+
+    if platforms, ok := state["supported_platforms"].([]interface{}); ok {
+        seen := make(map[interface{}]bool)
+        unique := []interface{}{}
+        for _, platform := range platforms {
+            if !seen[platform] {
+                seen[platform] = true
+                unique = append(unique, platform)
+            }
+        }
+        state["supported_platforms"] = unique
+    }
+
+    upgraded, err := json.Marshal(state)
+    if err != nil {
+        resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to encode script state, got error: %s", err))
+        return
+    }
+
+    var schemaResp resource.SchemaResponse
+    r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+    raw, err := (&tfprotov6.RawState{JSON: upgraded}).Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+    if err != nil {
+        resp.Diagnostics.AddError("Unable to Upgrade State", fmt.Sprintf("Unable to decode script state, got error: %s", err))
+        return
+    }
+
+    resp.State = tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+}
+
+// addScriptTooLargeError reports a create or update request the server or a
+// proxy rejected with 413 Request Entity Too Large.
+func addScriptTooLargeError(diags *diag.Diagnostics, requestBytes int) {
+    diags.AddAttributeError(
+        path.Root("script_body"),
+        "Script Body Too Large",
+        fmt.Sprintf("The server or a proxy in front of it rejected the %d byte request as too large (status code 413). "+
+            "Shorten the script, or raise the request size limit of the server, e.g. client_max_body_size in nginx, "+
+            "and set max_body_bytes to match so the limit is checked at plan time.", requestBytes),
+    )
+}
+
+// envVarKey returns the variable name of a KEY=value environment entry.
+func envVarKey(env string) string {
+    key, _, _ := strings.Cut(env, "=")
+    return key
+}
+
+// sensitiveEnvVarKeys returns the variable names managed by sensitive_env_vars.
+func sensitiveEnvVarKeys(ctx context.Context, sensitive types.List) map[string]bool {
+    keys := make(map[string]bool)
+    if sensitive.IsNull() || sensitive.IsUnknown() {
+        return keys
+    }
+    var envVars []string
+    sensitive.ElementsAs(ctx, &envVars, false)
+    for _, env := range envVars {
+        keys[envVarKey(env)] = true
+    }
+    return keys
+}
+
+// addSensitiveEnvVarSecrets registers the values of sensitive_env_vars as
+// secrets, so they are masked in diagnostics and logs.
+func (r *ScriptResource) addSensitiveEnvVarSecrets(ctx context.Context, sensitive types.List) {
+    if sensitive.IsNull() || sensitive.IsUnknown() {
+        return
+    }
+    var envVars []string
+    sensitive.ElementsAs(ctx, &envVars, false)
+    for _, env := range envVars {
+        _, value, _ := strings.Cut(env, "=")
+        r.client.addSecret(value)
+    }
+}
+
+// splitEnvVars separates the entries of a merged env_vars list whose key is
+// managed as sensitive from the plain ones, preserving their order.
+func splitEnvVars(envVars []string, sensitiveKeys map[string]bool) ([]string, []string) {
+    var plain, sensitive []string
+    for _, env := range envVars {
+        if sensitiveKeys[envVarKey(env)] {
+            sensitive = append(sensitive, env)
+            continue
+        }
+        plain = append(plain, env)
+    }
+    return plain, sensitive
+}
+
+// mergedEnvVars returns env_vars followed by sensitive_env_vars, the list the
+// API stores.
+func mergedEnvVars(ctx context.Context, envVars types.List, sensitiveEnvVars types.List) ([]string, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    merged := []string{}
+    for _, list := range []types.List{envVars, sensitiveEnvVars} {
+        if list.IsNull() {
+            continue
+        }
+        var values []string
+        diags.Append(list.ElementsAs(ctx, &values, false)...)
+        merged = append(merged, values...)
+    }
+    return merged, diags
+}
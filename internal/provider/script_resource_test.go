@@ -0,0 +1,503 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestScriptResourceDelete_ReportsBlockingDependents(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodDelete || r.URL.Path != "/scripts/42/" {
+            http.NotFound(w, r)
+            return
+        }
+        w.WriteHeader(http.StatusBadRequest)
+        writeJSON(t, w, "Script is used by automated tasks: Nightly Cleanup, Disk Report")
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := deleteResource(t, r, &ScriptResourceModel{
+        Id:                 types.Int64Value(42),
+        Name:               types.StringValue("Cleanup"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+
+    if resp.Diagnostics.ErrorsCount() != 1 {
+        t.Fatalf("expected one error, got %v", resp.Diagnostics)
+    }
+    diagnostic := resp.Diagnostics.Errors()[0]
+    if diagnostic.Summary() != "Delete Blocked By Dependents" {
+        t.Errorf("unexpected summary %q", diagnostic.Summary())
+    }
+    if !strings.Contains(diagnostic.Detail(), "Nightly Cleanup, Disk Report") {
+        t.Errorf("expected the blocking dependents in the detail, got %q", diagnostic.Detail())
+    }
+}
+
+// scriptRunnersServer serves a check and a task running script 42 and
+// records every DELETE request.
+func scriptRunnersServer(t *testing.T) (*httptest.Server, *[]string) {
+    t.Helper()
+    var deleted []string
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodDelete:
+            deleted = append(deleted, r.URL.Path)
+            writeJSON(t, w, "ok")
+        case r.URL.Path == "/checks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 21, "check_type": "script", "script": 42, "readable_desc": "Script check: Cleanup", "agent": 3},
+                {"id": 22, "check_type": "script", "script": 7, "readable_desc": "Script check: Other", "agent": 3},
+            })
+        case r.URL.Path == "/tasks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 11, "name": "Nightly Cleanup", "policy": 2, "actions": []map[string]interface{}{{"type": "script", "script": 42}}},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &deleted
+}
+
+func TestScriptResourceDelete_ScriptInUse(t *testing.T) {
+    server, deleted := scriptRunnersServer(t)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := deleteResource(t, r, &ScriptResourceModel{
+        Id:                 types.Int64Value(42),
+        Name:               types.StringValue("Cleanup"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+
+    if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Script In Use" {
+        t.Fatalf("expected a script in use error, got %v", resp.Diagnostics)
+    }
+    detail := resp.Diagnostics.Errors()[0].Detail()
+    if !strings.Contains(detail, `- check 21 "Script check: Cleanup"`) || !strings.Contains(detail, `- task 11 "Nightly Cleanup"`) {
+        t.Errorf("expected the check and task in the detail, got %q", detail)
+    }
+    if strings.Contains(detail, "Other") {
+        t.Errorf("expected only runners of the script in the detail, got %q", detail)
+    }
+    if len(*deleted) != 0 {
+        t.Errorf("expected nothing to be deleted, got %v", *deleted)
+    }
+}
+
+func TestScriptResourceDelete_ForceDeletesRunners(t *testing.T) {
+    server, deleted := scriptRunnersServer(t)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := deleteResource(t, r, &ScriptResourceModel{
+        Id:                 types.Int64Value(42),
+        Name:               types.StringValue("Cleanup"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+        Force:              types.BoolValue(true),
+    })
+
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    expected := []string{"/tasks/11/", "/checks/21/", "/scripts/42/"}
+    if !reflect.DeepEqual(*deleted, expected) {
+        t.Errorf("expected deletes %v, got %v", expected, *deleted)
+    }
+}
+
+func TestErrorMessage_ParsesTRMMErrorShapes(t *testing.T) {
+    cases := map[string]string{
+        `"plain message"`:                          "plain message",
+        `["first", "second"]`:                      "first; second",
+        `{"detail": "Not allowed"}`:                "Not allowed",
+        `{"non_field_errors": ["Has dependents"]}`: "Has dependents",
+        "<html>Bad Request</html>\n":               "<html>Bad Request</html>",
+    }
+    for body, expected := range cases {
+        if got := errorMessage([]byte(body)); got != expected {
+            t.Errorf("errorMessage(%q) = %q, expected %q", body, got, expected)
+        }
+    }
+}
+
+func stringListOf(values ...string) types.List {
+    elements := make([]attr.Value, len(values))
+    for i, value := range values {
+        elements[i] = types.StringValue(value)
+    }
+    return types.ListValueMust(types.StringType, elements)
+}
+
+func stringSetOf(values ...string) types.Set {
+    elements := make([]attr.Value, len(values))
+    for i, value := range values {
+        elements[i] = types.StringValue(value)
+    }
+    return types.SetValueMust(types.StringType, elements)
+}
+
+func TestScriptResourceSchema_SensitiveEnvVarsAreSensitive(t *testing.T) {
+    schemaResp := resourceSchemaFor(t, &ScriptResource{})
+    attribute, ok := schemaResp.Schema.Attributes["sensitive_env_vars"]
+    if !ok {
+        t.Fatal("expected a sensitive_env_vars attribute")
+    }
+    if !attribute.IsSensitive() {
+        t.Error("expected sensitive_env_vars to be marked sensitive")
+    }
+}
+
+func TestScriptResourceCreate_MergesSensitiveEnvVars(t *testing.T) {
+    var sent []string
+    created := false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            var body struct {
+                EnvVars []string `json:"env_vars"`
+            }
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            sent = body.EnvVars
+            created = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/" && !created:
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{{
+                "id":       42,
+                "name":     "Sync Tickets",
+                "env_vars": []string{"LOG_LEVEL=debug", "API_TOKEN=secret"},
+            }})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Sync Tickets"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Sync-Tickets"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            stringListOf("LOG_LEVEL=debug"),
+        SensitiveEnvVars:   stringListOf("API_TOKEN=secret"),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if !reflect.DeepEqual(sent, []string{"LOG_LEVEL=debug", "API_TOKEN=secret"}) {
+        t.Errorf("expected merged env_vars to be sent, got %v", sent)
+    }
+
+    var state ScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.EnvVars.Equal(stringListOf("LOG_LEVEL=debug")) {
+        t.Errorf("expected only plain entries in env_vars, got %v", state.EnvVars)
+    }
+}
+
+func TestScriptResourceCreate_RedactsSensitiveEnvVars(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            var body struct {
+                EnvVars []string `json:"env_vars"`
+            }
+            json.NewDecoder(r.Body).Decode(&body)
+            w.WriteHeader(http.StatusBadRequest)
+            writeJSON(t, w, map[string]interface{}{"non_field_errors": []string{"invalid variables " + strings.Join(body.EnvVars, ", ")}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Sync Tickets"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Sync-Tickets"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            stringListOf("LOG_LEVEL=debug"),
+        SensitiveEnvVars:   stringListOf("API_TOKEN=hunter2-token"),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for the rejected script")
+    }
+    if detail := resp.Diagnostics.Errors()[0].Detail(); strings.Contains(detail, "hunter2-token") || !strings.Contains(detail, redactedValue) {
+        t.Errorf("expected the sensitive value to be redacted, got %q", detail)
+    }
+}
+
+func TestScriptResourceCreate_Hidden(t *testing.T) {
+    shortenCreateLookup(t)
+    created := false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            created = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            // An older hidden script has the same name; hidden scripts are
+            // only listed when asked for
+            scripts := []map[string]interface{}{}
+            if r.URL.Query().Get("showHiddenScripts") == "true" {
+                scripts = append(scripts, map[string]interface{}{"id": 7, "name": "Cleanup", "hidden": true})
+                if created {
+                    scripts = append(scripts, map[string]interface{}{"id": 42, "name": "Cleanup", "hidden": true})
+                }
+            }
+            writeJSON(t, w, scripts)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Cleanup"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue("Clear-RecycleBin -Force"),
+        Hidden:             types.BoolValue(true),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 42 {
+        t.Errorf("expected the created hidden script, got ID %s", state.Id)
+    }
+}
+
+func TestScriptResourceRead_KeepsSensitiveEnvVarsOutOfEnvVars(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/scripts/42/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, map[string]interface{}{
+            "id":       42,
+            "name":     "Sync Tickets",
+            "env_vars": []string{"LOG_LEVEL=debug", "API_TOKEN=rotated"},
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := readResource(t, r, &ScriptResourceModel{
+        Id:                 types.Int64Value(42),
+        Name:               types.StringValue("Sync Tickets"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            stringListOf("LOG_LEVEL=debug"),
+        SensitiveEnvVars:   stringListOf("API_TOKEN=secret"),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.EnvVars.Equal(stringListOf("LOG_LEVEL=debug")) {
+        t.Errorf("expected the sensitive entry to stay out of env_vars, got %v", state.EnvVars)
+    }
+    if !state.SensitiveEnvVars.Equal(stringListOf("API_TOKEN=rotated")) {
+        t.Errorf("expected the remote sensitive value to be detected as drift, got %v", state.SensitiveEnvVars)
+    }
+}
+
+func TestScriptResourceValidateConfig_BodySize(t *testing.T) {
+    ctx := context.Background()
+    r := &ScriptResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    tests := map[string]struct {
+        body         string
+        maxBodyBytes types.Int64
+        errors       int
+    }{
+        "default limit":   {body: "Write-Output 'ok'", maxBodyBytes: types.Int64Null()},
+        "over default":    {body: strings.Repeat("#", defaultMaxScriptBodyBytes+1), maxBodyBytes: types.Int64Null(), errors: 1},
+        "at custom limit": {body: "12345", maxBodyBytes: types.Int64Value(5)},
+        "over custom":     {body: "123456", maxBodyBytes: types.Int64Value(5), errors: 1},
+        "invalid limit":   {body: "1", maxBodyBytes: types.Int64Value(0), errors: 1},
+    }
+
+    for name, test := range tests {
+        t.Run(name, func(t *testing.T) {
+            // Config has no Set method, so the raw value is built through a state
+            state := tfsdk.State{Schema: schemaResp.Schema}
+            if diags := state.Set(ctx, &ScriptResourceModel{
+                Name:               types.StringValue("Big Script"),
+                Shell:              types.StringValue("powershell"),
+                ScriptBody:         types.StringValue(test.body),
+                Args:               types.ListNull(types.StringType),
+                EnvVars:            types.ListNull(types.StringType),
+                SensitiveEnvVars:   types.ListNull(types.StringType),
+                SupportedPlatforms: types.SetNull(types.StringType),
+                MaxBodyBytes:       test.maxBodyBytes,
+            }); diags.HasError() {
+                t.Fatalf("unable to build config: %v", diags)
+            }
+
+            var resp resource.ValidateConfigResponse
+            r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+            if resp.Diagnostics.ErrorsCount() != test.errors {
+                t.Errorf("expected %d errors, got: %v", test.errors, resp.Diagnostics)
+            }
+        })
+    }
+}
+
+func TestScriptResourceCreate_RequestTooLarge(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet && r.URL.Path == "/scripts/" {
+            writeJSON(t, w, []map[string]interface{}{})
+            return
+        }
+        if r.Method == http.MethodPost && r.URL.Path == "/scripts/" {
+            w.WriteHeader(http.StatusRequestEntityTooLarge)
+            w.Write([]byte("<html><body>413 Request Entity Too Large</body></html>"))
+            return
+        }
+        http.NotFound(w, r)
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptResourceModel{
+        Name:               types.StringValue("Big Script"),
+        Shell:              types.StringValue("powershell"),
+        ScriptBody:         types.StringValue(strings.Repeat("#", 2048)),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a 413 response")
+    }
+    err := resp.Diagnostics.Errors()[0]
+    if err.Summary() != "Script Body Too Large" || !strings.Contains(err.Detail(), "max_body_bytes") {
+        t.Errorf("expected a script body size error, got: %v", resp.Diagnostics)
+    }
+}
+
+func TestScriptResourceRead_MalformedListElements(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/scripts/42/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, map[string]interface{}{
+            "id":                  42,
+            "name":                "Sync Tickets",
+            "args":                []interface{}{nil, 5, "-Force", true},
+            "supported_platforms": []interface{}{map[string]interface{}{"os": "windows"}},
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptResource{client: newTestClient(server)}
+    resp := readResource(t, r, &ScriptResourceModel{
+        Id:                 types.Int64Value(42),
+        Name:               types.StringValue("Sync Tickets"),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if got := resp.Diagnostics.WarningsCount(); got != 4 {
+        t.Errorf("expected 4 warnings, got %d: %v", got, resp.Diagnostics)
+    }
+
+    var state ScriptResourceModel
+    resp.State.Get(context.Background(), &state)
+    if !state.Args.Equal(stringListOf("5", "-Force", "true")) {
+        t.Errorf("unexpected args: %v", state.Args)
+    }
+    if !state.SupportedPlatforms.Equal(stringSetOf(`{"os":"windows"}`)) {
+        t.Errorf("unexpected supported_platforms: %v", state.SupportedPlatforms)
+    }
+}
+
+func TestScriptResourceUpgradeState_PlatformsListToSet(t *testing.T) {
+    ctx := context.Background()
+    r := &ScriptResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    // Version 0 state as written by earlier releases, including a duplicate platform
+    rawState := `{
+        "id": 42,
+        "name": "Sync Tickets",
+        "shell": "powershell",
+        "script_body": "Sync-Tickets",
+        "args": ["-Force"],
+        "env_vars": null,
+        "supported_platforms": ["linux", "windows", "linux"]
+    }`
+
+    upgrader, ok := r.UpgradeState(ctx)[0]
+    if !ok {
+        t.Fatal("expected a state upgrader for version 0")
+    }
+    req := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(rawState)}}
+    resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+    upgrader.StateUpgrader(ctx, req, &resp)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptResourceModel
+    if diags := resp.State.Get(ctx, &state); diags.HasError() {
+        t.Fatalf("unable to read upgraded state: %v", diags)
+    }
+    if !state.SupportedPlatforms.Equal(stringSetOf("windows", "linux")) {
+        t.Errorf("unexpected supported_platforms: %v", state.SupportedPlatforms)
+    }
+    if !state.Args.Equal(stringListOf("-Force")) || state.Id.ValueInt64() != 42 {
+        t.Errorf("expected the other attributes to be kept, got %+v", state)
+    }
+}
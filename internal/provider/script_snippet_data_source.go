@@ -85,6 +85,8 @@ func (d *ScriptSnippetDataSource) Configure(ctx context.Context, req datasource.
 }
 
 func (d *ScriptSnippetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
     var data ScriptSnippetDataSourceModel
 
     resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -5,9 +5,13 @@ import (
     "context"
     "encoding/json"
     "fmt"
+    "io"
     "net/http"
     "strconv"
+    "strings"
 
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
     "github.com/hashicorp/terraform-plugin-framework/path"
     "github.com/hashicorp/terraform-plugin-framework/resource"
     "github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -17,6 +21,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ScriptSnippetResource{}
 var _ resource.ResourceWithImportState = &ScriptSnippetResource{}
+var _ resource.ResourceWithModifyPlan = &ScriptSnippetResource{}
+var _ resource.ResourceWithIdentity = &ScriptSnippetResource{}
 
 func NewScriptSnippetResource() resource.Resource {
     return &ScriptSnippetResource{}
@@ -29,11 +35,13 @@ type ScriptSnippetResource struct {
 
 // ScriptSnippetResourceModel describes the resource data model based on ScriptSnippet Django model
 type ScriptSnippetResourceModel struct {
-    Id    types.Int64  `tfsdk:"id"`
-    Name  types.String `tfsdk:"name"`
-    Desc  types.String `tfsdk:"desc"`
-    Code  types.String `tfsdk:"code"`
-    Shell types.String `tfsdk:"shell"`
+    Id                        types.Int64  `tfsdk:"id"`
+    Name                      types.String `tfsdk:"name"`
+    Desc                      types.String `tfsdk:"desc"`
+    Code                      types.String `tfsdk:"code"`
+    Shell                     types.String `tfsdk:"shell"`
+    ReferencedBy              types.List   `tfsdk:"referenced_by"`
+    PreventDeleteIfReferenced types.Bool   `tfsdk:"prevent_delete_if_referenced"`
 }
 
 func (r *ScriptSnippetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -58,7 +66,7 @@ func (r *ScriptSnippetResource) Schema(ctx context.Context, req resource.SchemaR
                 Optional:            true,
             },
             "code": schema.StringAttribute{
-                MarkdownDescription: "Snippet code content",
+                MarkdownDescription: "Snippet code content. It may include other snippets as `{{name}}`; references that form a cycle are rejected at plan time.",
                 Required:            true,
             },
             "shell": schema.StringAttribute{
@@ -66,10 +74,23 @@ func (r *ScriptSnippetResource) Schema(ctx context.Context, req resource.SchemaR
                 Optional:            true,
                 Computed:            true,
             },
+            "referenced_by": schema.ListAttribute{
+                MarkdownDescription: "Names of the user defined scripts whose body references this snippet as `{{name}}`. Populated by fetching every user defined script body.",
+                Computed:            true,
+                ElementType:         types.StringType,
+            },
+            "prevent_delete_if_referenced": schema.BoolAttribute{
+                MarkdownDescription: "When true, deleting the snippet fails while scripts still reference it. Otherwise a warning is emitted.",
+                Optional:            true,
+            },
         },
     }
 }
 
+func (r *ScriptSnippetResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = idIdentitySchema("Script snippet")
+}
+
 func (r *ScriptSnippetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
     if req.ProviderData == nil {
         return
@@ -88,6 +109,12 @@ func (r *ScriptSnippetResource) Configure(ctx context.Context, req resource.Conf
 }
 
 func (r *ScriptSnippetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data ScriptSnippetResourceModel
 
     resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -95,6 +122,44 @@ func (r *ScriptSnippetResource) Create(ctx context.Context, req resource.CreateR
         return
     }
 
+    snippets, err := r.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
+        return
+    }
+
+    // Snippet names are unique and the created snippet is found by name, so an
+    // existing snippet would be silently adopted instead of created, unless
+    // on_conflict asks for exactly that
+    var adoptedId int64
+    priorName := ""
+    existing := objectNamed(snippets, data.Name.ValueString())
+    if existing != nil && r.client.resolvesConflicts() {
+        adoptedId = r.client.adoptOrReplace(ctx, "script snippet", []map[string]interface{}{existing}, func(id int64) string {
+            return fmt.Sprintf("%s/scripts/snippets/%d/", r.client.BaseURL, id)
+        }, &resp.Diagnostics)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+        // The existing snippet's references are superseded by this one's
+        priorName = data.Name.ValueString()
+    } else if existing != nil {
+        existingId, _ := existing["id"].(float64)
+        resp.Diagnostics.AddAttributeError(
+            path.Root("name"),
+            "Duplicate Script Snippet Name",
+            fmt.Sprintf("A script snippet named %q already exists (id %d) and is not managed by this resource. "+
+                "Snippet names must be unique; rename this snippet or import the existing one with "+
+                "'terraform import' before managing it.", data.Name.ValueString(), int64(existingId)),
+        )
+        return
+    }
+
+    checkReferenceCycle(r.client.withPlannedSnippets(snippets), priorName, &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
     // Create API request body
     body := map[string]interface{}{
         "name": data.Name.ValueString(),
@@ -117,8 +182,14 @@ func (r *ScriptSnippetResource) Create(ctx context.Context, req resource.CreateR
         return
     }
 
+    // An adopted snippet is updated in place
+    method, requestURL := "POST", fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL)
+    if adoptedId != 0 {
+        method, requestURL = "PUT", fmt.Sprintf("%s/scripts/snippets/%d/", r.client.BaseURL, adoptedId)
+    }
+
     // Create HTTP request
-    httpReq, err := http.NewRequest("POST", fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL), bytes.NewBuffer(jsonBody))
+    httpReq, err := http.NewRequest(method, requestURL, bytes.NewBuffer(jsonBody))
     if err != nil {
         resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create script snippet, got error: %s", err))
         return
@@ -133,48 +204,27 @@ func (r *ScriptSnippetResource) Create(ctx context.Context, req resource.CreateR
     defer httpResp.Body.Close()
 
     if httpResp.StatusCode != http.StatusOK {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create script snippet, status code: %d", httpResp.StatusCode))
-        return
-    }
-
-    // Response is just a message, so we need to get the created snippet
-    // List all snippets to find our newly created one
-    listReq, err := http.NewRequest("GET", fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL), nil)
-    if err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
-        return
-    }
-
-    listResp, err := r.client.Do(listReq)
-    if err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
-        return
-    }
-    defer listResp.Body.Close()
-
-    var snippets []map[string]interface{}
-    if err := json.NewDecoder(listResp.Body).Decode(&snippets); err != nil {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse script snippets list, got error: %s", err))
+        respBody, _ := io.ReadAll(httpResp.Body)
+        if !addAPIFieldErrors(&resp.Diagnostics, "Unable to create script snippet", httpResp.StatusCode, respBody, scriptSnippetFieldPaths) {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create script snippet, status code: %d", httpResp.StatusCode))
+        }
         return
     }
 
-    // Find the snippet we just created by name
-    var createdSnippet map[string]interface{}
-    for _, snippet := range snippets {
-        if name, ok := snippet["name"].(string); ok && name == data.Name.ValueString() {
-            createdSnippet = snippet
-            break
+    if adoptedId != 0 {
+        data.Id = types.Int64Value(adoptedId)
+    } else {
+        // Response is just a message, so we need to find the created snippet by name
+        createdSnippet, err := r.client.findCreatedByName(ctx, fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL), data.Name.ValueString())
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find created script snippet, got error: %s", err))
+            return
         }
-    }
 
-    if createdSnippet == nil {
-        resp.Diagnostics.AddError("Client Error", "Unable to find created script snippet")
-        return
-    }
-
-    // Update model with response data
-    if id, ok := createdSnippet["id"].(float64); ok {
-        data.Id = types.Int64Value(int64(id))
+        // Update model with response data
+        if id, ok := createdSnippet["id"].(float64); ok {
+            data.Id = types.Int64Value(int64(id))
+        }
     }
 
     // Set defaults if not provided
@@ -182,10 +232,15 @@ func (r *ScriptSnippetResource) Create(ctx context.Context, req resource.CreateR
         data.Shell = types.StringValue("powershell")
     }
 
+    r.setReferencedBy(ctx, &data, &resp.Diagnostics)
+
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 }
 
 func (r *ScriptSnippetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
     var data ScriptSnippetResourceModel
 
     resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -193,6 +248,10 @@ func (r *ScriptSnippetResource) Read(ctx context.Context, req resource.ReadReque
         return
     }
 
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
+
     // Create HTTP request
     httpReq, err := http.NewRequest("GET", fmt.Sprintf("%s/scripts/snippets/%d/", r.client.BaseURL, data.Id.ValueInt64()), nil)
     if err != nil {
@@ -214,6 +273,10 @@ func (r *ScriptSnippetResource) Read(ctx context.Context, req resource.ReadReque
     }
 
     if httpResp.StatusCode != http.StatusOK {
+        body, _ := io.ReadAll(httpResp.Body)
+        if r.client.removeOnReadError(ctx, &resp.State, "script snippet", httpResp.StatusCode, body, &resp.Diagnostics) {
+            return
+        }
         resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read script snippet, status code: %d", httpResp.StatusCode))
         return
     }
@@ -239,10 +302,18 @@ func (r *ScriptSnippetResource) Read(ctx context.Context, req resource.ReadReque
         data.Shell = types.StringValue(shell)
     }
 
+    r.setReferencedBy(ctx, &data, &resp.Diagnostics)
+
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ScriptSnippetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data ScriptSnippetResourceModel
     var state ScriptSnippetResourceModel
 
@@ -261,6 +332,17 @@ func (r *ScriptSnippetResource) Update(ctx context.Context, req resource.UpdateR
     // Use the ID from the current state
     data.Id = state.Id
 
+    snippets, err := r.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
+        return
+    }
+
+    checkReferenceCycle(r.client.withPlannedSnippets(snippets), state.Name.ValueString(), &data, &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
     // Create API request body
     body := map[string]interface{}{
         "name": data.Name.ValueString(),
@@ -297,7 +379,10 @@ func (r *ScriptSnippetResource) Update(ctx context.Context, req resource.UpdateR
     defer httpResp.Body.Close()
 
     if httpResp.StatusCode != http.StatusOK {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update script snippet, status code: %d", httpResp.StatusCode))
+        respBody, _ := io.ReadAll(httpResp.Body)
+        if !addAPIFieldErrors(&resp.Diagnostics, "Unable to update script snippet", httpResp.StatusCode, respBody, scriptSnippetFieldPaths) {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update script snippet, status code: %d", httpResp.StatusCode))
+        }
         return
     }
 
@@ -334,10 +419,19 @@ func (r *ScriptSnippetResource) Update(ctx context.Context, req resource.UpdateR
         data.Shell = types.StringValue("powershell")
     }
 
+    r.setReferencedBy(ctx, &data, &resp.Diagnostics)
+
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
 }
 
 func (r *ScriptSnippetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
     var data ScriptSnippetResourceModel
 
     resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -345,6 +439,22 @@ func (r *ScriptSnippetResource) Delete(ctx context.Context, req resource.DeleteR
         return
     }
 
+    // Scripts still referencing the snippet would run with the literal placeholder
+    referencedBy, err := r.client.scriptsReferencing(ctx, snippetToken(data.Name.ValueString()))
+    if err != nil {
+        resp.Diagnostics.AddWarning(
+            "Unable to Check Snippet References",
+            fmt.Sprintf("Unable to determine which scripts reference snippet '%s', got error: %s", data.Name.ValueString(), err),
+        )
+    } else if len(referencedBy) > 0 {
+        detail := fmt.Sprintf("Snippet '%s' is still referenced by scripts: %s", data.Name.ValueString(), strings.Join(referencedBy, ", "))
+        if data.PreventDeleteIfReferenced.ValueBool() {
+            resp.Diagnostics.AddError("Snippet Still Referenced", detail+". Remove the references or unset prevent_delete_if_referenced to delete it.")
+            return
+        }
+        resp.Diagnostics.AddWarning("Snippet Still Referenced", detail+". These scripts will no longer expand the snippet.")
+    }
+
     // Create HTTP request
     httpReq, err := http.NewRequest("DELETE", fmt.Sprintf("%s/scripts/snippets/%d/", r.client.BaseURL, data.Id.ValueInt64()), nil)
     if err != nil {
@@ -361,12 +471,64 @@ func (r *ScriptSnippetResource) Delete(ctx context.Context, req resource.DeleteR
     defer httpResp.Body.Close()
 
     if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent {
-        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete script snippet, status code: %d", httpResp.StatusCode))
+        addDeleteError(&resp.Diagnostics, "script snippet", httpResp)
+        return
+    }
+}
+
+func (r *ScriptSnippetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    // Nothing to check on destroy or before the provider is configured
+    if req.Plan.Raw.IsNull() || r.client == nil {
+        return
+    }
+
+    var data ScriptSnippetResourceModel
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.Name.IsUnknown() {
+        r.client.plannedSnippets.Store(data.Name.ValueString(), data.Code.ValueString())
+    }
+
+    if data.Name.IsUnknown() || data.Code.IsUnknown() {
         return
     }
+
+    priorName := ""
+    if !req.State.Raw.IsNull() {
+        var state ScriptSnippetResourceModel
+        resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+
+        // Only a changed name or code can introduce a cycle
+        if state.Name.Equal(data.Name) && state.Code.Equal(data.Code) {
+            return
+        }
+        priorName = state.Name.ValueString()
+    }
+
+    snippets, err := r.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", r.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
+        return
+    }
+
+    checkReferenceCycle(r.client.withPlannedSnippets(snippets), priorName, &data, &resp.Diagnostics)
 }
 
 func (r *ScriptSnippetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks generated by terraform query use the identity
+    if id, ok := identityImportID(ctx, req, &resp.Diagnostics); ok {
+        resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+        return
+    }
+
     // Convert string ID to int64
     id, err := strconv.ParseInt(req.ID, 10, 64)
     if err != nil {
@@ -376,3 +538,49 @@ func (r *ScriptSnippetResource) ImportState(ctx context.Context, req resource.Im
     
     resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
+
+// snippetToken returns the placeholder scripts use to include a snippet.
+func snippetToken(name string) string {
+    return "{{" + name + "}}"
+}
+
+// setReferencedBy populates referenced_by with the scripts that include the snippet.
+// A failed scan is reported as a warning so it never blocks managing the snippet.
+func (r *ScriptSnippetResource) setReferencedBy(ctx context.Context, data *ScriptSnippetResourceModel, diags *diag.Diagnostics) {
+    referencedBy, err := r.client.scriptsReferencing(ctx, snippetToken(data.Name.ValueString()))
+    if err != nil {
+        diags.AddWarning(
+            "Unable to Check Snippet References",
+            fmt.Sprintf("Unable to determine which scripts reference snippet '%s', got error: %s", data.Name.ValueString(), err),
+        )
+        if data.ReferencedBy.IsUnknown() {
+            data.ReferencedBy = types.ListNull(types.StringType)
+        }
+        return
+    }
+
+    values := make([]attr.Value, len(referencedBy))
+    for i, name := range referencedBy {
+        values[i] = types.StringValue(name)
+    }
+    list, listDiags := types.ListValue(types.StringType, values)
+    diags.Append(listDiags...)
+    data.ReferencedBy = list
+}
+
+// checkReferenceCycle reports an error on code when the snippet would take
+// part in a reference cycle with the listed snippets, which could never be
+// fully expanded. It runs at plan time and again on apply, when snippets
+// created earlier in the same apply are listed too. Callers add the planned
+// snippets with withPlannedSnippets, so cycles between new snippets are
+// found before any of them is created.
+func checkReferenceCycle(snippets []map[string]interface{}, priorName string, data *ScriptSnippetResourceModel, diags *diag.Diagnostics) {
+    cycle := snippetCycle(snippets, priorName, data.Name.ValueString(), data.Code.ValueString())
+    if cycle != nil {
+        diags.AddAttributeError(
+            path.Root("code"),
+            "Snippet Reference Cycle",
+            fmt.Sprintf("Snippet %q would take part in the reference cycle %s. Remove one of the references to break the cycle.", data.Name.ValueString(), strings.Join(cycle, " -> ")),
+        )
+    }
+}
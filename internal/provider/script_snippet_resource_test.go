@@ -0,0 +1,168 @@
+package provider
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// snippetReferenceServer serves two user defined scripts, one of which includes
+// the {{GetDiskSpace}} snippet, and counts DELETE calls against snippet 7.
+func snippetReferenceServer(t *testing.T) (*httptest.Server, *int32) {
+    t.Helper()
+    var deletes int32
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "name": "Disk Report", "script_type": "userdefined"},
+                {"id": 2, "name": "Cleanup", "script_type": "userdefined"},
+                {"id": 3, "name": "Community Script", "script_type": "builtin"},
+            })
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/1/":
+            writeJSON(t, w, map[string]interface{}{"id": 1, "name": "Disk Report", "script_body": "{{GetDiskSpace}}\nWrite-Output 'done'"})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/2/":
+            writeJSON(t, w, map[string]interface{}{"id": 2, "name": "Cleanup", "script_body": "Remove-Item $env:TEMP"})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/3/":
+            t.Errorf("builtin scripts should not be scanned")
+            http.NotFound(w, r)
+        case r.Method == http.MethodDelete && r.URL.Path == "/scripts/snippets/7/":
+            atomic.AddInt32(&deletes, 1)
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &deletes
+}
+
+func snippetState(name string, preventDelete bool) *ScriptSnippetResourceModel {
+    return &ScriptSnippetResourceModel{
+        Id:                        types.Int64Value(7),
+        Name:                      types.StringValue(name),
+        Code:                      types.StringValue("Get-PSDrive"),
+        Shell:                     types.StringValue("powershell"),
+        ReferencedBy:              types.ListNull(types.StringType),
+        PreventDeleteIfReferenced: types.BoolValue(preventDelete),
+    }
+}
+
+func TestScriptSnippetResourceDelete_Unreferenced(t *testing.T) {
+    server, deletes := snippetReferenceServer(t)
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+
+    resp := deleteResource(t, r, snippetState("UnusedSnippet", true))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if resp.Diagnostics.WarningsCount() != 0 {
+        t.Errorf("expected no warnings, got: %v", resp.Diagnostics)
+    }
+    if got := atomic.LoadInt32(deletes); got != 1 {
+        t.Errorf("expected 1 delete call, got %d", got)
+    }
+}
+
+func TestScriptSnippetResourceDelete_ReferencedWarns(t *testing.T) {
+    server, deletes := snippetReferenceServer(t)
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+
+    resp := deleteResource(t, r, snippetState("GetDiskSpace", false))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 1 || warnings[0].Summary() != "Snippet Still Referenced" {
+        t.Fatalf("expected a snippet reference warning, got: %v", resp.Diagnostics)
+    }
+    if got := atomic.LoadInt32(deletes); got != 1 {
+        t.Errorf("expected 1 delete call, got %d", got)
+    }
+}
+
+func TestScriptSnippetResourceDelete_ReferencedBlocked(t *testing.T) {
+    server, deletes := snippetReferenceServer(t)
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+
+    resp := deleteResource(t, r, snippetState("GetDiskSpace", true))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error when the snippet is still referenced")
+    }
+    if got := atomic.LoadInt32(deletes); got != 0 {
+        t.Errorf("expected no delete calls, got %d", got)
+    }
+}
+
+func TestScriptSnippetResourceCreate_DuplicateName(t *testing.T) {
+    var posts int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 12, "name": "GetDiskSpace", "code": "Get-Volume"},
+            })
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/snippets/":
+            atomic.AddInt32(&posts, 1)
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("GetDiskSpace"),
+        Code:         types.StringValue("Get-PSDrive"),
+        ReferencedBy: types.ListNull(types.StringType),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for an existing snippet name")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Duplicate Script Snippet Name" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+    if got := atomic.LoadInt32(&posts); got != 0 {
+        t.Errorf("expected no create call, got %d", got)
+    }
+}
+
+func TestScriptSnippetResourceUpdate_KeepsOwnName(t *testing.T) {
+    var puts int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 7, "name": "GetDiskSpace", "code": "Get-PSDrive"},
+            })
+        case r.Method == http.MethodPut && r.URL.Path == "/scripts/snippets/7/":
+            atomic.AddInt32(&puts, 1)
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/7/":
+            writeJSON(t, w, map[string]interface{}{"id": 7, "name": "GetDiskSpace", "code": "Get-Volume", "shell": "powershell"})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    planned := snippetState("GetDiskSpace", false)
+    planned.Code = types.StringValue("Get-Volume")
+
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+    resp := updateResource(t, r, snippetState("GetDiskSpace", false), planned)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if got := atomic.LoadInt32(&puts); got != 1 {
+        t.Errorf("expected 1 update call, got %d", got)
+    }
+}
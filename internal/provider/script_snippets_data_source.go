@@ -107,6 +107,8 @@ func (d *ScriptSnippetsDataSource) Configure(ctx context.Context, req datasource
 }
 
 func (d *ScriptSnippetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
     var data ScriptSnippetsDataSourceModel
 
     resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
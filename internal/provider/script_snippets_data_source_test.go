@@ -0,0 +1,55 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestScriptSnippetsDataSourceRead_MalformedSnippets(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": "7", "name": 12, "desc": nil, "code": []string{"Get-PSDrive"}},
+            {"id": 8, "name": "GetDiskSpace", "code": "Get-Volume", "shell": "powershell"},
+            {},
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    snippetObjectType := types.ObjectType{AttrTypes: map[string]attr.Type{
+        "id":    types.Int64Type,
+        "name":  types.StringType,
+        "desc":  types.StringType,
+        "code":  types.StringType,
+        "shell": types.StringType,
+    }}
+
+    d := &ScriptSnippetsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ScriptSnippetsDataSourceModel{
+        Id:       types.Int64Null(),
+        Name:     types.StringNull(),
+        Snippets: types.ListNull(snippetObjectType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptSnippetsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var snippets []ScriptSnippetModel
+    state.Snippets.ElementsAs(context.Background(), &snippets, false)
+    if len(snippets) != 3 {
+        t.Fatalf("expected 3 snippets, got %d", len(snippets))
+    }
+    if !snippets[0].Id.IsNull() || !snippets[0].Name.IsNull() || !snippets[0].Code.IsNull() {
+        t.Errorf("expected malformed attributes to be null, got %+v", snippets[0])
+    }
+    if snippets[1].Name.ValueString() != "GetDiskSpace" || snippets[1].Code.ValueString() != "Get-Volume" {
+        t.Errorf("expected the well-formed snippet to be read, got %+v", snippets[1])
+    }
+}
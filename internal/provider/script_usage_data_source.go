@@ -0,0 +1,352 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ScriptUsageDataSource{}
+
+func NewScriptUsageDataSource() datasource.DataSource {
+    return &ScriptUsageDataSource{}
+}
+
+// ScriptUsageDataSource reports the checks, automated tasks and alert
+// templates that run a script.
+type ScriptUsageDataSource struct {
+    client *ClientConfig
+}
+
+// ScriptUsageDataSourceModel describes the data source data model.
+type ScriptUsageDataSourceModel struct {
+    ScriptId       types.Int64  `tfsdk:"script_id"`
+    Name           types.String `tfsdk:"name"`
+    Checks         types.List   `tfsdk:"checks"`
+    Tasks          types.List   `tfsdk:"tasks"`
+    AlertTemplates types.List   `tfsdk:"alert_templates"`
+    InUse          types.Bool   `tfsdk:"in_use"`
+}
+
+// scriptUserAttrTypes describes a check or task running a script.
+var scriptUserAttrTypes = map[string]attr.Type{
+    "id":             types.Int64Type,
+    "name":           types.StringType,
+    "agent_hostname": types.StringType,
+    "policy_id":      types.Int64Type,
+    "policy_name":    types.StringType,
+}
+
+// scriptAlertTemplateAttrTypes describes an alert template running a script.
+var scriptAlertTemplateAttrTypes = map[string]attr.Type{
+    "id":              types.Int64Type,
+    "name":            types.StringType,
+    "failure_action":  types.BoolType,
+    "resolved_action": types.BoolType,
+}
+
+func (d *ScriptUsageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_script_usage"
+}
+
+func (d *ScriptUsageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    userAttributes := func(kind string) map[string]schema.Attribute {
+        return map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("%s identifier", kind),
+                Computed:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: fmt.Sprintf("%s name or description", kind),
+                Computed:            true,
+            },
+            "agent_hostname": schema.StringAttribute{
+                MarkdownDescription: fmt.Sprintf("Hostname of the agent the %s belongs to, null for policy %ss", kind, kind),
+                Computed:            true,
+            },
+            "policy_id": schema.Int64Attribute{
+                MarkdownDescription: fmt.Sprintf("Identifier of the automation policy the %s belongs to, null for agent %ss", kind, kind),
+                Computed:            true,
+            },
+            "policy_name": schema.StringAttribute{
+                MarkdownDescription: fmt.Sprintf("Name of the automation policy the %s belongs to, null for agent %ss", kind, kind),
+                Computed:            true,
+            },
+        }
+    }
+
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Script usage data source for Tactical RMM. Lists the script checks, automated tasks and alert templates " +
+            "that run a script, with the agent or automation policy they belong to, so every reference can be found before " +
+            "the script is deleted or renamed.",
+
+        Attributes: map[string]schema.Attribute{
+            "script_id": schema.Int64Attribute{
+                MarkdownDescription: "Identifier of the script",
+                Required:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Script name",
+                Computed:            true,
+            },
+            "checks": schema.ListNestedAttribute{
+                MarkdownDescription: "Script checks running the script, ordered by ID",
+                Computed:            true,
+                NestedObject:        schema.NestedAttributeObject{Attributes: userAttributes("check")},
+            },
+            "tasks": schema.ListNestedAttribute{
+                MarkdownDescription: "Automated tasks running the script as one of their actions, ordered by ID",
+                Computed:            true,
+                NestedObject:        schema.NestedAttributeObject{Attributes: userAttributes("task")},
+            },
+            "alert_templates": schema.ListNestedAttribute{
+                MarkdownDescription: "Alert templates running the script as failure or resolved action, ordered by ID",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Alert template identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Alert template name",
+                            Computed:            true,
+                        },
+                        "failure_action": schema.BoolAttribute{
+                            MarkdownDescription: "Whether the script is the failure action of the template",
+                            Computed:            true,
+                        },
+                        "resolved_action": schema.BoolAttribute{
+                            MarkdownDescription: "Whether the script is the resolved action of the template",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+            "in_use": schema.BoolAttribute{
+                MarkdownDescription: "Whether any check, task or alert template runs the script",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *ScriptUsageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *ScriptUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ScriptUsageDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    scriptId := data.ScriptId.ValueInt64()
+    script, err := d.client.getObject(ctx, fmt.Sprintf("%s/scripts/%d/", d.client.BaseURL, scriptId))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read script %d, got error: %s", scriptId, err))
+        return
+    }
+    data.Name = stringValue(script["name"])
+
+    checkUsers, taskUsers, err := d.client.scriptRunners(ctx, scriptId)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find the checks and tasks running script %d, got error: %s", scriptId, err))
+        return
+    }
+    templates, err := d.client.listObjects(ctx, fmt.Sprintf("%s/alerts/templates/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list alert templates, got error: %s", err))
+        return
+    }
+
+    // Checks and tasks reference their agent and policy by ID, so the names
+    // are looked up, but only when something runs the script
+    users := append(append([]map[string]interface{}{}, checkUsers...), taskUsers...)
+    var agentNames, policyNames map[int64]string
+    if parentsNeeded(users, "agent") {
+        agents, err := d.client.listObjects(ctx, fmt.Sprintf("%s/agents/", d.client.BaseURL))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list agents, got error: %s", err))
+            return
+        }
+        agentNames = namesById(agents, "hostname")
+    }
+    if parentsNeeded(users, "policy") {
+        policies, err := d.client.listObjects(ctx, fmt.Sprintf("%s/automation/policies/", d.client.BaseURL))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list automation policies, got error: %s", err))
+            return
+        }
+        policyNames = namesById(policies, "name")
+    }
+
+    scriptUser := func(object map[string]interface{}, nameKeys ...string) attr.Value {
+        dep := newDependent("", object, nameKeys...)
+        agentHostname, policyName := types.StringNull(), types.StringNull()
+        if referencedId(object["agent"]) != 0 {
+            agentHostname = parentName(object["agent"], agentNames, "hostname")
+        }
+        policyId := types.Int64Null()
+        if id := referencedId(object["policy"]); id != 0 {
+            policyId = types.Int64Value(id)
+            policyName = parentName(object["policy"], policyNames, "name")
+        }
+        value, diags := types.ObjectValue(scriptUserAttrTypes, map[string]attr.Value{
+            "id":             types.Int64Value(dep.id),
+            "name":           types.StringValue(dep.name),
+            "agent_hostname": agentHostname,
+            "policy_id":      policyId,
+            "policy_name":    policyName,
+        })
+        resp.Diagnostics.Append(diags...)
+        return value
+    }
+
+    checkValues := []attr.Value{}
+    for _, check := range sortedById(checkUsers) {
+        checkValues = append(checkValues, scriptUser(check, "readable_desc", "name"))
+    }
+    taskValues := []attr.Value{}
+    for _, task := range sortedById(taskUsers) {
+        taskValues = append(taskValues, scriptUser(task, "name"))
+    }
+    templateValues := []attr.Value{}
+    for _, template := range sortedById(templates) {
+        failure := referencedId(template["action"]) == scriptId
+        resolved := referencedId(template["resolved_action"]) == scriptId
+        if !failure && !resolved {
+            continue
+        }
+        value, diags := types.ObjectValue(scriptAlertTemplateAttrTypes, map[string]attr.Value{
+            "id":              types.Int64Value(referencedId(template["id"])),
+            "name":            stringValue(template["name"]),
+            "failure_action":  types.BoolValue(failure),
+            "resolved_action": types.BoolValue(resolved),
+        })
+        resp.Diagnostics.Append(diags...)
+        templateValues = append(templateValues, value)
+    }
+
+    var diags diag.Diagnostics
+    data.Checks, diags = types.ListValue(types.ObjectType{AttrTypes: scriptUserAttrTypes}, checkValues)
+    resp.Diagnostics.Append(diags...)
+    data.Tasks, diags = types.ListValue(types.ObjectType{AttrTypes: scriptUserAttrTypes}, taskValues)
+    resp.Diagnostics.Append(diags...)
+    data.AlertTemplates, diags = types.ListValue(types.ObjectType{AttrTypes: scriptAlertTemplateAttrTypes}, templateValues)
+    resp.Diagnostics.Append(diags...)
+    data.InUse = types.BoolValue(len(checkValues)+len(taskValues)+len(templateValues) > 0)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// scriptRunners returns the script checks and the automated tasks running
+// the script.
+func (c *ClientConfig) scriptRunners(ctx context.Context, scriptId int64) ([]map[string]interface{}, []map[string]interface{}, error) {
+    checks, err := c.listObjects(ctx, fmt.Sprintf("%s/checks/", c.BaseURL))
+    if err != nil {
+        return nil, nil, fmt.Errorf("unable to list checks: %w", err)
+    }
+    tasks, err := c.listObjects(ctx, fmt.Sprintf("%s/tasks/", c.BaseURL))
+    if err != nil {
+        return nil, nil, fmt.Errorf("unable to list tasks: %w", err)
+    }
+
+    var checkRunners, taskRunners []map[string]interface{}
+    for _, check := range checks {
+        if checkRunsScript(check, scriptId) {
+            checkRunners = append(checkRunners, check)
+        }
+    }
+    for _, task := range tasks {
+        if taskRunsScript(task, scriptId) {
+            taskRunners = append(taskRunners, task)
+        }
+    }
+    return checkRunners, taskRunners, nil
+}
+
+// checkRunsScript reports whether check is a script check running the script.
+func checkRunsScript(check map[string]interface{}, scriptId int64) bool {
+    return check["check_type"] == "script" && referencedId(check["script"]) == scriptId
+}
+
+// taskRunsScript reports whether one of the actions of task runs the script.
+func taskRunsScript(task map[string]interface{}, scriptId int64) bool {
+    actions, _ := task["actions"].([]interface{})
+    for _, action := range actions {
+        action, _ := action.(map[string]interface{})
+        if action["type"] == "script" && referencedId(action["script"]) == scriptId {
+            return true
+        }
+    }
+    return false
+}
+
+// parentsNeeded reports whether any of objects references a parent through
+// key by ID only, so its name has to be looked up.
+func parentsNeeded(objects []map[string]interface{}, key string) bool {
+    for _, object := range objects {
+        if _, nested := object[key].(map[string]interface{}); !nested && referencedId(object[key]) != 0 {
+            return true
+        }
+    }
+    return false
+}
+
+// namesById maps the ID of every listed object to its nameKey.
+func namesById(objects []map[string]interface{}, nameKey string) map[int64]string {
+    names := make(map[int64]string)
+    for _, object := range objects {
+        if name, ok := object[nameKey].(string); ok {
+            names[referencedId(object["id"])] = name
+        }
+    }
+    return names
+}
+
+// parentName returns the name of a referenced parent, taken from the nested
+// object when TRMM serializes it, otherwise looked up in names. Unknown
+// parents have a null name.
+func parentName(value interface{}, names map[int64]string, nameKey string) types.String {
+    if nested, ok := value.(map[string]interface{}); ok {
+        return stringValue(nested[nameKey])
+    }
+    if name, ok := names[referencedId(value)]; ok {
+        return types.StringValue(name)
+    }
+    return types.StringNull()
+}
+
+// sortedById returns objects ordered by their ID.
+func sortedById(objects []map[string]interface{}) []map[string]interface{} {
+    sorted := append([]map[string]interface{}(nil), objects...)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        return referencedId(sorted[i]["id"]) < referencedId(sorted[j]["id"])
+    })
+    return sorted
+}
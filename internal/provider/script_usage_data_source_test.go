@@ -0,0 +1,138 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// scriptUsageServer serves script 5, which is run by an agent check, a policy
+// task and both actions of an alert template, and script 6, which nothing
+// uses. Agent and policy list calls are counted.
+func scriptUsageServer(t *testing.T) (*httptest.Server, *int32) {
+    t.Helper()
+    var parentLists int32
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/scripts/5/":
+            writeJSON(t, w, map[string]interface{}{"id": 5, "name": "Disk Cleanup"})
+        case "/scripts/6/":
+            writeJSON(t, w, map[string]interface{}{"id": 6, "name": "Unused"})
+        case "/checks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 22, "check_type": "script", "script": 5, "readable_desc": "Script check: Disk Cleanup", "agent": 3, "policy": nil},
+                {"id": 21, "check_type": "diskspace", "readable_desc": "Disk space check: C:", "agent": 3, "policy": nil},
+            })
+        case "/tasks/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 11, "name": "Nightly Cleanup", "agent": nil, "policy": 2, "actions": []map[string]interface{}{
+                    {"type": "cmd", "command": "echo start"},
+                    {"type": "script", "script": map[string]interface{}{"id": 5, "name": "Disk Cleanup"}},
+                }},
+                {"id": 12, "name": "Other Task", "agent": 3, "policy": nil, "actions": []map[string]interface{}{
+                    {"type": "script", "script": 8},
+                }},
+            })
+        case "/alerts/templates/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 32, "name": "Workstations", "action": 8, "resolved_action": nil},
+                {"id": 31, "name": "Servers", "action": 5, "resolved_action": map[string]interface{}{"id": 5, "name": "Disk Cleanup"}},
+            })
+        case "/agents/":
+            atomic.AddInt32(&parentLists, 1)
+            writeJSON(t, w, []map[string]interface{}{{"id": 3, "agent_id": "agent-3", "hostname": "web01"}})
+        case "/automation/policies/":
+            atomic.AddInt32(&parentLists, 1)
+            writeJSON(t, w, []map[string]interface{}{{"id": 2, "name": "Servers Baseline"}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &parentLists
+}
+
+func scriptUsageModel(scriptId int64) *ScriptUsageDataSourceModel {
+    return &ScriptUsageDataSourceModel{
+        ScriptId:       types.Int64Value(scriptId),
+        Name:           types.StringNull(),
+        Checks:         types.ListNull(types.ObjectType{AttrTypes: scriptUserAttrTypes}),
+        Tasks:          types.ListNull(types.ObjectType{AttrTypes: scriptUserAttrTypes}),
+        AlertTemplates: types.ListNull(types.ObjectType{AttrTypes: scriptAlertTemplateAttrTypes}),
+        InUse:          types.BoolNull(),
+    }
+}
+
+func TestScriptUsageDataSourceRead(t *testing.T) {
+    server, _ := scriptUsageServer(t)
+    d := &ScriptUsageDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, scriptUsageModel(5))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var data ScriptUsageDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if data.Name.ValueString() != "Disk Cleanup" || !data.InUse.ValueBool() {
+        t.Errorf("unexpected name or in_use: %s, %s", data.Name, data.InUse)
+    }
+    if len(data.Checks.Elements()) != 1 || len(data.Tasks.Elements()) != 1 || len(data.AlertTemplates.Elements()) != 1 {
+        t.Fatalf("expected one check, task and alert template, got %s, %s and %s", data.Checks, data.Tasks, data.AlertTemplates)
+    }
+
+    check := data.Checks.Elements()[0].(types.Object).Attributes()
+    if check["id"].(types.Int64).ValueInt64() != 22 || check["agent_hostname"].(types.String).ValueString() != "web01" ||
+        !check["policy_id"].(types.Int64).IsNull() || !check["policy_name"].(types.String).IsNull() {
+        t.Errorf("unexpected check: %v", check)
+    }
+    task := data.Tasks.Elements()[0].(types.Object).Attributes()
+    if task["id"].(types.Int64).ValueInt64() != 11 || !task["agent_hostname"].(types.String).IsNull() ||
+        task["policy_id"].(types.Int64).ValueInt64() != 2 || task["policy_name"].(types.String).ValueString() != "Servers Baseline" {
+        t.Errorf("unexpected task: %v", task)
+    }
+    template := data.AlertTemplates.Elements()[0].(types.Object).Attributes()
+    if template["id"].(types.Int64).ValueInt64() != 31 || !template["failure_action"].(types.Bool).ValueBool() ||
+        !template["resolved_action"].(types.Bool).ValueBool() {
+        t.Errorf("unexpected alert template: %v", template)
+    }
+}
+
+func TestScriptUsageDataSourceRead_Unused(t *testing.T) {
+    server, parentLists := scriptUsageServer(t)
+    d := &ScriptUsageDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, scriptUsageModel(6))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var data ScriptUsageDataSourceModel
+    resp.State.Get(context.Background(), &data)
+    if data.InUse.ValueBool() {
+        t.Error("expected in_use to be false")
+    }
+    // Empty lists rather than null, so length checks work in configurations
+    if data.Checks.IsNull() || len(data.Checks.Elements())+len(data.Tasks.Elements())+len(data.AlertTemplates.Elements()) != 0 {
+        t.Errorf("expected empty lists, got %s, %s and %s", data.Checks, data.Tasks, data.AlertTemplates)
+    }
+    if got := atomic.LoadInt32(parentLists); got != 0 {
+        t.Errorf("expected no agent or policy lookups, got %d", got)
+    }
+}
+
+func TestScriptUsageDataSourceRead_MissingScript(t *testing.T) {
+    server, _ := scriptUsageServer(t)
+    d := &ScriptUsageDataSource{client: newTestClient(server)}
+
+    resp := readDataSource(t, d, scriptUsageModel(404))
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a script that does not exist")
+    }
+}
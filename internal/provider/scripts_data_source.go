@@ -5,15 +5,20 @@ import (
     "encoding/json"
     "fmt"
     "net/http"
+    "sort"
+    "strings"
 
     "github.com/hashicorp/terraform-plugin-framework/attr"
     "github.com/hashicorp/terraform-plugin-framework/datasource"
     "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
     "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ datasource.DataSource = &ScriptsDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &ScriptsDataSource{}
 
 func NewScriptsDataSource() datasource.DataSource {
     return &ScriptsDataSource{}
@@ -33,6 +38,7 @@ type ScriptsDataSourceModel struct {
     Category           types.String `tfsdk:"category"`
     Hidden             types.Bool   `tfsdk:"hidden"`
     IncludeScriptBody  types.Bool   `tfsdk:"include_script_body"`
+    Fields             types.List   `tfsdk:"fields"`
     Scripts            types.List   `tfsdk:"scripts"`
 }
 
@@ -53,10 +59,30 @@ type ScriptModel struct {
     RunAsUser            types.Bool   `tfsdk:"run_as_user"`
     Args                 types.List   `tfsdk:"args"`
     EnvVars              types.List   `tfsdk:"env_vars"`
-    SupportedPlatforms   types.List   `tfsdk:"supported_platforms"`
+    SupportedPlatforms   types.Set    `tfsdk:"supported_platforms"`
     Syntax               types.String `tfsdk:"syntax"`
 }
 
+// scriptAttrTypes are the attribute types of an entry in the scripts list.
+var scriptAttrTypes = map[string]attr.Type{
+    "id":                  types.Int64Type,
+    "name":                types.StringType,
+    "description":         types.StringType,
+    "shell":               types.StringType,
+    "script_type":         types.StringType,
+    "category":            types.StringType,
+    "filename":            types.StringType,
+    "script_body":         types.StringType,
+    "default_timeout":     types.Int64Type,
+    "favorite":            types.BoolType,
+    "hidden":              types.BoolType,
+    "run_as_user":         types.BoolType,
+    "args":                types.ListType{ElemType: types.StringType},
+    "env_vars":            types.ListType{ElemType: types.StringType},
+    "supported_platforms": types.SetType{ElemType: types.StringType},
+    "syntax":              types.StringType,
+}
+
 func (d *ScriptsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
     resp.TypeName = req.ProviderTypeName + "_scripts"
 }
@@ -94,6 +120,12 @@ func (d *ScriptsDataSource) Schema(ctx context.Context, req datasource.SchemaReq
                 MarkdownDescription: "When true, fetches the full script body for each script. This requires additional API calls per script.",
                 Optional:            true,
             },
+            "fields": schema.ListAttribute{
+                MarkdownDescription: "Attributes to populate for each script, e.g. `[\"name\", \"category\"]`. `id` is always populated; all other attributes are left null, " +
+                    "which keeps the state small for large script libraries. `script_body` may only be listed together with include_script_body. Defaults to all attributes.",
+                Optional:    true,
+                ElementType: types.StringType,
+            },
             "scripts": schema.ListNestedAttribute{
                 MarkdownDescription: "List of scripts matching the filter criteria, or all scripts if no filter is specified.",
                 Computed:            true,
@@ -157,7 +189,7 @@ func (d *ScriptsDataSource) Schema(ctx context.Context, req datasource.SchemaReq
                             Computed:            true,
                             ElementType:         types.StringType,
                         },
-                        "supported_platforms": schema.ListAttribute{
+                        "supported_platforms": schema.SetAttribute{
                             MarkdownDescription: "Supported platforms",
                             Computed:            true,
                             ElementType:         types.StringType,
@@ -173,6 +205,58 @@ func (d *ScriptsDataSource) Schema(ctx context.Context, req datasource.SchemaReq
     }
 }
 
+func (d *ScriptsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+    var data ScriptsDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if data.Fields.IsNull() || data.Fields.IsUnknown() {
+        return
+    }
+
+    selectsBody := false
+    for i, element := range data.Fields.Elements() {
+        field, ok := element.(types.String)
+        if !ok || field.IsNull() || field.IsUnknown() {
+            continue
+        }
+        if _, ok := scriptAttrTypes[field.ValueString()]; !ok {
+            resp.Diagnostics.AddAttributeError(
+                path.Root("fields").AtListIndex(i),
+                "Invalid Script Field",
+                fmt.Sprintf("%q is not a script attribute. Valid fields are: %s.", field.ValueString(), strings.Join(scriptFieldNames(), ", ")),
+            )
+        }
+        if field.ValueString() == "script_body" {
+            selectsBody = true
+        }
+    }
+
+    // Script bodies cost one request per script, so they are neither fetched
+    // only to be dropped nor silently left null when selected
+    if data.IncludeScriptBody.IsUnknown() {
+        return
+    }
+    includeScriptBody := data.IncludeScriptBody.ValueBool()
+    if includeScriptBody && !selectsBody {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("fields"),
+            "Conflicting Script Fields",
+            "include_script_body is true but fields does not list script_body. Add script_body to fields or remove include_script_body.",
+        )
+    }
+    if selectsBody && !includeScriptBody {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("fields"),
+            "Conflicting Script Fields",
+            "fields lists script_body, which is only fetched when include_script_body is true.",
+        )
+    }
+}
+
 func (d *ScriptsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
     if req.ProviderData == nil {
         return
@@ -191,6 +275,8 @@ func (d *ScriptsDataSource) Configure(ctx context.Context, req datasource.Config
 }
 
 func (d *ScriptsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
     var data ScriptsDataSourceModel
 
     resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -285,6 +371,33 @@ func (d *ScriptsDataSource) Read(ctx context.Context, req datasource.ReadRequest
     // Determine if we need to fetch script bodies
     includeScriptBody := !data.IncludeScriptBody.IsNull() && data.IncludeScriptBody.ValueBool()
 
+    // Determine which attributes to populate, all of them unless fields is set
+    var selectedFields map[string]bool
+    if !data.Fields.IsNull() {
+        var fields []string
+        resp.Diagnostics.Append(data.Fields.ElementsAs(ctx, &fields, false)...)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+        selectedFields = map[string]bool{"id": true}
+        for _, field := range fields {
+            selectedFields[field] = true
+        }
+    }
+
+    // Fetch script bodies up front, a bounded number of requests at a time
+    var scriptDetails map[int64]map[string]interface{}
+    var detailFailures map[int64]error
+    if includeScriptBody {
+        var ids []int64
+        for _, script := range filteredScripts {
+            if id, ok := script["id"].(float64); ok {
+                ids = append(ids, int64(id))
+            }
+        }
+        scriptDetails, detailFailures = d.client.fetchScriptDetails(ctx, ids)
+    }
+
     // Convert to ScriptModel list
     scriptsList := make([]ScriptModel, len(filteredScripts))
     for i, script := range filteredScripts {
@@ -337,53 +450,31 @@ func (d *ScriptsDataSource) Read(ctx context.Context, req datasource.ReadRequest
 
         // Handle arrays
         if args, ok := script["args"].([]interface{}); ok && len(args) > 0 {
-            argsList := make([]attr.Value, len(args))
-            for j, arg := range args {
-                if str, ok := arg.(string); ok {
-                    argsList[j] = types.StringValue(str)
-                }
-            }
-            model.Args = types.ListValueMust(types.StringType, argsList)
+            model.Args = stringListFrom(args, path.Root("scripts").AtListIndex(i).AtName("args"), &resp.Diagnostics)
         } else {
             model.Args = types.ListNull(types.StringType)
         }
 
         if envVars, ok := script["env_vars"].([]interface{}); ok && len(envVars) > 0 {
-            envList := make([]attr.Value, len(envVars))
-            for j, env := range envVars {
-                if str, ok := env.(string); ok {
-                    envList[j] = types.StringValue(str)
-                }
-            }
-            model.EnvVars = types.ListValueMust(types.StringType, envList)
+            model.EnvVars = stringListFrom(envVars, path.Root("scripts").AtListIndex(i).AtName("env_vars"), &resp.Diagnostics)
         } else {
             model.EnvVars = types.ListNull(types.StringType)
         }
 
         if platforms, ok := script["supported_platforms"].([]interface{}); ok && len(platforms) > 0 {
-            platList := make([]attr.Value, len(platforms))
-            for j, plat := range platforms {
-                if str, ok := plat.(string); ok {
-                    platList[j] = types.StringValue(str)
-                }
-            }
-            model.SupportedPlatforms = types.ListValueMust(types.StringType, platList)
+            model.SupportedPlatforms = stringSetFrom(platforms, path.Root("scripts").AtListIndex(i).AtName("supported_platforms"), &resp.Diagnostics)
         } else {
-            model.SupportedPlatforms = types.ListNull(types.StringType)
+            model.SupportedPlatforms = types.SetNull(types.StringType)
         }
         
         // Fetch script body if requested
         if includeScriptBody && !model.Id.IsNull() {
-            scriptDetail, err := d.fetchScriptDetail(model.Id.ValueInt64())
-            if err != nil {
-                // Log warning but continue - don't fail the entire operation
-                resp.Diagnostics.AddWarning(
-                    "Script Detail Fetch Warning",
-                    fmt.Sprintf("Unable to fetch script body for script ID %d: %s", model.Id.ValueInt64(), err),
-                )
+            if err, failed := detailFailures[model.Id.ValueInt64()]; failed {
+                // The script is still listed, only without its body
+                addItemFetchWarning(&resp.Diagnostics, fmt.Sprintf("the body of script ID %d", model.Id.ValueInt64()), err)
                 model.ScriptBody = types.StringNull()
             } else {
-                if scriptBody, ok := scriptDetail["script_body"].(string); ok {
+                if scriptBody, ok := scriptDetails[model.Id.ValueInt64()]["script_body"].(string); ok {
                     model.ScriptBody = types.StringValue(scriptBody)
                 } else {
                     model.ScriptBody = types.StringNull()
@@ -398,31 +489,16 @@ func (d *ScriptsDataSource) Read(ctx context.Context, req datasource.ReadRequest
     }
 
     // Convert to list value
-    scriptObjectType := types.ObjectType{
-        AttrTypes: map[string]attr.Type{
-            "id":                   types.Int64Type,
-            "name":                 types.StringType,
-            "description":          types.StringType,
-            "shell":                types.StringType,
-            "script_type":          types.StringType,
-            "category":             types.StringType,
-            "filename":             types.StringType,
-            "script_body":          types.StringType,
-            "default_timeout":      types.Int64Type,
-            "favorite":             types.BoolType,
-            "hidden":               types.BoolType,
-            "run_as_user":          types.BoolType,
-            "args":                 types.ListType{ElemType: types.StringType},
-            "env_vars":             types.ListType{ElemType: types.StringType},
-            "supported_platforms":  types.ListType{ElemType: types.StringType},
-            "syntax":               types.StringType,
-        },
-    }
+    scriptObjectType := types.ObjectType{AttrTypes: scriptAttrTypes}
 
     scriptsListValue := make([]attr.Value, len(scriptsList))
     for i, script := range scriptsList {
         objValue, diags := types.ObjectValueFrom(ctx, scriptObjectType.AttrTypes, script)
         resp.Diagnostics.Append(diags...)
+        if selectedFields != nil && !diags.HasError() {
+            objValue, diags = selectScriptFields(objValue, selectedFields)
+            resp.Diagnostics.Append(diags...)
+        }
         scriptsListValue[i] = objValue
     }
 
@@ -433,27 +509,38 @@ func (d *ScriptsDataSource) Read(ctx context.Context, req datasource.ReadRequest
     resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-// fetchScriptDetail retrieves the full script details including script_body
-func (d *ScriptsDataSource) fetchScriptDetail(scriptId int64) (map[string]interface{}, error) {
-    httpReq, err := http.NewRequest("GET", fmt.Sprintf("%s/scripts/%d/", d.client.BaseURL, scriptId), nil)
-    if err != nil {
-        return nil, fmt.Errorf("unable to create request: %w", err)
-    }
-
-    httpResp, err := d.client.Do(httpReq)
-    if err != nil {
-        return nil, fmt.Errorf("unable to fetch script detail: %w", err)
-    }
-    defer httpResp.Body.Close()
-
-    if httpResp.StatusCode != http.StatusOK {
-        return nil, fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
+// scriptFieldNames returns the attribute names valid in fields, sorted.
+func scriptFieldNames() []string {
+    names := make([]string, 0, len(scriptAttrTypes))
+    for name := range scriptAttrTypes {
+        names = append(names, name)
     }
+    sort.Strings(names)
+    return names
+}
 
-    var result map[string]interface{}
-    if err := json.NewDecoder(httpResp.Body).Decode(&result); err != nil {
-        return nil, fmt.Errorf("unable to parse response: %w", err)
+// selectScriptFields returns a copy of a scripts list entry in which every
+// attribute not in fields is null.
+func selectScriptFields(script types.Object, fields map[string]bool) (types.Object, diag.Diagnostics) {
+    values := make(map[string]attr.Value, len(scriptAttrTypes))
+    for name, value := range script.Attributes() {
+        if fields[name] {
+            values[name] = value
+            continue
+        }
+        switch scriptAttrTypes[name] {
+        case types.Int64Type:
+            values[name] = types.Int64Null()
+        case types.BoolType:
+            values[name] = types.BoolNull()
+        case types.StringType:
+            values[name] = types.StringNull()
+        case types.SetType{ElemType: types.StringType}:
+            values[name] = types.SetNull(types.StringType)
+        default:
+            // The list attributes all hold strings
+            values[name] = types.ListNull(types.StringType)
+        }
     }
-
-    return result, nil
+    return types.ObjectValue(scriptAttrTypes, values)
 }
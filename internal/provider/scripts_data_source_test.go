@@ -0,0 +1,148 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestScriptsDataSourceRead_FieldsLeavesOtherAttributesNull(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/scripts/" {
+            t.Errorf("unexpected request to %s", r.URL.Path)
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, []map[string]interface{}{{
+            "id":                  7,
+            "name":                "Disk Cleanup",
+            "description":         "Frees disk space",
+            "shell":               "powershell",
+            "script_type":         "userdefined",
+            "category":            "Maintenance",
+            "default_timeout":     90,
+            "args":                []string{"-Force"},
+            "supported_platforms": []string{"windows"},
+        }})
+    }))
+    t.Cleanup(server.Close)
+
+    d := &ScriptsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ScriptsDataSourceModel{
+        Id:                types.Int64Null(),
+        Name:              types.StringNull(),
+        ScriptType:        types.StringNull(),
+        Shell:             types.StringNull(),
+        Category:          types.StringNull(),
+        Hidden:            types.BoolNull(),
+        IncludeScriptBody: types.BoolNull(),
+        Fields:            stringListOf("name", "category"),
+        Scripts:           types.ListNull(types.ObjectType{AttrTypes: scriptAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state ScriptsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var scripts []ScriptModel
+    state.Scripts.ElementsAs(context.Background(), &scripts, false)
+    if len(scripts) != 1 {
+        t.Fatalf("expected 1 script, got %d", len(scripts))
+    }
+    script := scripts[0]
+    if script.Id.ValueInt64() != 7 || script.Name.ValueString() != "Disk Cleanup" || script.Category.ValueString() != "Maintenance" {
+        t.Errorf("expected id, name and category to be populated, got %+v", script)
+    }
+    if !script.Description.IsNull() || !script.Shell.IsNull() || !script.DefaultTimeout.IsNull() || !script.Args.IsNull() || !script.SupportedPlatforms.IsNull() {
+        t.Errorf("expected unselected attributes to be null, got %+v", script)
+    }
+}
+
+func TestScriptsDataSourceRead_MalformedListElements(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": 7, "name": "Disk Cleanup", "args": []interface{}{nil}, "env_vars": []interface{}{"A=1", 2}},
+            {"id": 8, "name": "Reboot", "supported_platforms": []interface{}{false, "windows"}},
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    d := &ScriptsDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &ScriptsDataSourceModel{
+        Id:                types.Int64Null(),
+        Name:              types.StringNull(),
+        ScriptType:        types.StringNull(),
+        Shell:             types.StringNull(),
+        Category:          types.StringNull(),
+        Hidden:            types.BoolNull(),
+        IncludeScriptBody: types.BoolNull(),
+        Fields:            types.ListNull(types.StringType),
+        Scripts:           types.ListNull(types.ObjectType{AttrTypes: scriptAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 3 {
+        t.Fatalf("expected 3 warnings, got: %v", resp.Diagnostics)
+    }
+    if summary := warnings[0].Summary(); summary != "Unexpected List Element" {
+        t.Errorf("unexpected warning summary: %s", summary)
+    }
+
+    var state ScriptsDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var scripts []ScriptModel
+    state.Scripts.ElementsAs(context.Background(), &scripts, false)
+    if len(scripts) != 2 {
+        t.Fatalf("expected 2 scripts, got %d", len(scripts))
+    }
+    if !scripts[0].Args.Equal(stringListOf()) || !scripts[0].EnvVars.Equal(stringListOf("A=1", "2")) {
+        t.Errorf("unexpected lists for the first script: %+v", scripts[0])
+    }
+    if !scripts[1].SupportedPlatforms.Equal(stringSetOf("windows", "false")) {
+        t.Errorf("unexpected supported_platforms: %v", scripts[1].SupportedPlatforms)
+    }
+}
+
+func TestSelectScriptFields_KeepsAllSelected(t *testing.T) {
+    script, diags := types.ObjectValueFrom(context.Background(), scriptAttrTypes, ScriptModel{
+        Id:                 types.Int64Value(1),
+        Name:               types.StringValue("Reboot"),
+        Description:        types.StringNull(),
+        Shell:              types.StringValue("cmd"),
+        ScriptType:         types.StringValue("builtin"),
+        Category:           types.StringNull(),
+        Filename:           types.StringNull(),
+        ScriptBody:         types.StringNull(),
+        DefaultTimeout:     types.Int64Value(30),
+        Favorite:           types.BoolValue(true),
+        Hidden:             types.BoolValue(false),
+        RunAsUser:          types.BoolValue(false),
+        Args:               stringListOf("/r"),
+        EnvVars:            types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+        Syntax:             types.StringNull(),
+    })
+    if diags.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", diags)
+    }
+
+    all := make(map[string]bool)
+    for _, name := range scriptFieldNames() {
+        all[name] = true
+    }
+    selected, diags := selectScriptFields(script, all)
+    if diags.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", diags)
+    }
+    if !selected.Equal(script) {
+        t.Errorf("expected selecting every field to keep the script unchanged, got %v", selected)
+    }
+}
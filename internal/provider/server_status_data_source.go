@@ -0,0 +1,233 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ServerStatusDataSource{}
+
+func NewServerStatusDataSource() datasource.DataSource {
+    return &ServerStatusDataSource{}
+}
+
+// ServerStatusDataSource reads the health of the Tactical RMM backend from
+// its monitoring endpoint.
+type ServerStatusDataSource struct {
+    client *ClientConfig
+}
+
+// ServerStatusDataSourceModel describes the data source data model.
+type ServerStatusDataSourceModel struct {
+    MonitoringToken      types.String `tfsdk:"monitoring_token"`
+    Healthy              types.Bool   `tfsdk:"healthy"`
+    Version              types.String `tfsdk:"version"`
+    LatestAgentVersion   types.String `tfsdk:"latest_agent_version"`
+    AgentCount           types.Int64  `tfsdk:"agent_count"`
+    ClientCount          types.Int64  `tfsdk:"client_count"`
+    SiteCount            types.Int64  `tfsdk:"site_count"`
+    DiskUsagePercent     types.Int64  `tfsdk:"disk_usage_percent"`
+    MemUsagePercent      types.Int64  `tfsdk:"mem_usage_percent"`
+    DaysUntilCertExpires types.Int64  `tfsdk:"days_until_cert_expires"`
+    CertExpired          types.Bool   `tfsdk:"cert_expired"`
+    CeleryQueueLength    types.Int64  `tfsdk:"celery_queue_length"`
+    CeleryQueueHealthy   types.Bool   `tfsdk:"celery_queue_healthy"`
+    RedisPing            types.Bool   `tfsdk:"redis_ping"`
+    NatsStdPing          types.Bool   `tfsdk:"nats_std_ping"`
+    NatsWsPing           types.Bool   `tfsdk:"nats_ws_ping"`
+    MeshPing             types.Bool   `tfsdk:"mesh_ping"`
+    ServicesRunning      types.Map    `tfsdk:"services_running"`
+}
+
+func (d *ServerStatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_server_status"
+}
+
+func (d *ServerStatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Server status data source for Tactical RMM. Reads the monitoring endpoint `/core/status/`, which reports the health of the backend: " +
+            "certificate expiry, the celery queue, connectivity to Redis, NATS and MeshCentral, and the services running on the server. " +
+            "The endpoint is authenticated with the monitoring token (`MON_TOKEN` in `local_settings.py`), not the API key. " +
+            "Values a server does not report, e.g. the pings on older releases or the services on Docker installations, are null.",
+
+        Attributes: map[string]schema.Attribute{
+            "monitoring_token": schema.StringAttribute{
+                MarkdownDescription: "Monitoring token configured as `MON_TOKEN` on the server",
+                Required:            true,
+                Sensitive:           true,
+            },
+            "healthy": schema.BoolAttribute{
+                MarkdownDescription: "Whether every reported check is healthy: the certificate has not expired, the celery queue is healthy, no ping failed and all services are running",
+                Computed:            true,
+            },
+            "version": schema.StringAttribute{
+                MarkdownDescription: "Tactical RMM version of the server",
+                Computed:            true,
+            },
+            "latest_agent_version": schema.StringAttribute{
+                MarkdownDescription: "Agent version the server distributes",
+                Computed:            true,
+            },
+            "agent_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of agents",
+                Computed:            true,
+            },
+            "client_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of clients",
+                Computed:            true,
+            },
+            "site_count": schema.Int64Attribute{
+                MarkdownDescription: "Number of sites",
+                Computed:            true,
+            },
+            "disk_usage_percent": schema.Int64Attribute{
+                MarkdownDescription: "Disk usage of the server's root filesystem in percent",
+                Computed:            true,
+            },
+            "mem_usage_percent": schema.Int64Attribute{
+                MarkdownDescription: "Memory usage of the server in percent",
+                Computed:            true,
+            },
+            "days_until_cert_expires": schema.Int64Attribute{
+                MarkdownDescription: "Days until the TLS certificate expires, negative once it has expired",
+                Computed:            true,
+            },
+            "cert_expired": schema.BoolAttribute{
+                MarkdownDescription: "Whether the TLS certificate has expired",
+                Computed:            true,
+            },
+            "celery_queue_length": schema.Int64Attribute{
+                MarkdownDescription: "Number of tasks waiting in the celery queue",
+                Computed:            true,
+            },
+            "celery_queue_healthy": schema.BoolAttribute{
+                MarkdownDescription: "Whether the server considers the celery queue length healthy",
+                Computed:            true,
+            },
+            "redis_ping": schema.BoolAttribute{
+                MarkdownDescription: "Whether the server reached Redis",
+                Computed:            true,
+            },
+            "nats_std_ping": schema.BoolAttribute{
+                MarkdownDescription: "Whether the server reached NATS over its standard port",
+                Computed:            true,
+            },
+            "nats_ws_ping": schema.BoolAttribute{
+                MarkdownDescription: "Whether the server reached NATS over websockets",
+                Computed:            true,
+            },
+            "mesh_ping": schema.BoolAttribute{
+                MarkdownDescription: "Whether the server reached MeshCentral",
+                Computed:            true,
+            },
+            "services_running": schema.MapAttribute{
+                MarkdownDescription: "Whether each service of the server is running, keyed by service name, e.g. nginx, postgres, celery. Null on Docker installations, which do not report services.",
+                Computed:            true,
+                ElementType:         types.BoolType,
+            },
+        },
+    }
+}
+
+func (d *ServerStatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *ServerStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data ServerStatusDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    d.client.addSecret(data.MonitoringToken.ValueString())
+
+    // The monitoring endpoint only accepts a POST with the token in the body
+    body := map[string]interface{}{"auth": data.MonitoringToken.ValueString()}
+    statusCode, respBody, err := d.client.sendJSON(ctx, "POST", fmt.Sprintf("%s/core/status/", d.client.BaseURL), body)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read server status, got error: %s", err))
+        return
+    }
+    if statusCode == http.StatusUnauthorized {
+        resp.Diagnostics.AddError(
+            "Invalid Monitoring Token",
+            fmt.Sprintf("The server rejected the monitoring token: %s\n\nSet monitoring_token to the MON_TOKEN configured on the server.", errorMessage(respBody)),
+        )
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read server status, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    var status map[string]interface{}
+    if err := json.Unmarshal(respBody, &status); err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse response, got error: %s", err))
+        return
+    }
+
+    data.Version = stringValue(status["version"])
+    data.LatestAgentVersion = stringValue(status["latest_agent_version"])
+    data.AgentCount = int64Value(status["agent_count"])
+    data.ClientCount = int64Value(status["client_count"])
+    data.SiteCount = int64Value(status["site_count"])
+    data.DiskUsagePercent = int64Value(status["disk_usage_percent"])
+    data.MemUsagePercent = int64Value(status["mem_usage_percent"])
+    data.DaysUntilCertExpires = int64Value(status["days_until_cert_expires"])
+    data.CertExpired = boolValue(status["cert_expired"])
+    data.CeleryQueueLength = int64Value(status["celery_queue_len"])
+    data.CeleryQueueHealthy = types.BoolNull()
+    if health, ok := status["celery_queue_health"].(string); ok {
+        data.CeleryQueueHealthy = types.BoolValue(health == "healthy")
+    }
+
+    data.RedisPing = boolValue(status["redis_ping"])
+    data.NatsStdPing = boolValue(status["nats_std_ping"])
+    data.NatsWsPing = boolValue(status["nats_ws_ping"])
+    data.MeshPing = boolValue(status["mesh_ping"])
+
+    // Docker installations report a message instead of the services
+    data.ServicesRunning = types.MapNull(types.BoolType)
+    healthy := !data.CertExpired.ValueBool() && (data.CeleryQueueHealthy.IsNull() || data.CeleryQueueHealthy.ValueBool())
+    if services, ok := status["services_running"].(map[string]interface{}); ok {
+        elements := make(map[string]attr.Value, len(services))
+        for name, running := range services {
+            running, _ := running.(bool)
+            elements[name] = types.BoolValue(running)
+            healthy = healthy && running
+        }
+        data.ServicesRunning = types.MapValueMust(types.BoolType, elements)
+    }
+    // Older servers do not ping, which does not count as unhealthy
+    for _, ping := range []types.Bool{data.RedisPing, data.NatsStdPing, data.NatsWsPing, data.MeshPing} {
+        healthy = healthy && (ping.IsNull() || ping.ValueBool())
+    }
+    data.Healthy = types.BoolValue(healthy)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// serverStatusServer serves status from the monitoring endpoint to requests
+// authenticated with token.
+func serverStatusServer(t *testing.T, token string, status map[string]interface{}) *httptest.Server {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodPost || r.URL.Path != "/core/status/" {
+            http.NotFound(w, r)
+            return
+        }
+        var body map[string]interface{}
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["auth"] != token {
+            w.WriteHeader(http.StatusUnauthorized)
+            w.Write([]byte("Not authenticated\n"))
+            return
+        }
+        writeJSON(t, w, status)
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func serverStatusModel(token string) *ServerStatusDataSourceModel {
+    return &ServerStatusDataSourceModel{
+        MonitoringToken: types.StringValue(token),
+        ServicesRunning: types.MapNull(types.BoolType),
+    }
+}
+
+func TestServerStatusDataSourceRead(t *testing.T) {
+    status := map[string]interface{}{
+        "version": "v0.20.1", "latest_agent_version": "2.9.1", "agent_count": 120, "client_count": 8, "site_count": 19,
+        "disk_usage_percent": 41, "mem_usage_percent": 63, "days_until_cert_expires": 37, "cert_expired": false,
+        "redis_ping": true, "celery_queue_len": 2, "celery_queue_health": "healthy",
+        "nats_std_ping": true, "nats_ws_ping": true, "mesh_ping": true,
+        "services_running": map[string]interface{}{"nginx": true, "postgres": true, "celery": true},
+    }
+
+    tests := map[string]struct {
+        modify  func(status map[string]interface{})
+        healthy bool
+    }{
+        "healthy": {
+            modify:  func(status map[string]interface{}) {},
+            healthy: true,
+        },
+        "celery backlog": {
+            modify: func(status map[string]interface{}) {
+                status["celery_queue_len"], status["celery_queue_health"] = 85, "unhealthy"
+            },
+        },
+        "nats down": {
+            modify: func(status map[string]interface{}) {
+                status["nats_ws_ping"] = false
+            },
+        },
+        "service stopped": {
+            modify: func(status map[string]interface{}) {
+                status["services_running"] = map[string]interface{}{"nginx": true, "postgres": false}
+            },
+        },
+        "docker without pings": {
+            modify: func(status map[string]interface{}) {
+                status["services_running"] = "not available in docker"
+                for _, key := range []string{"redis_ping", "nats_std_ping", "nats_ws_ping", "mesh_ping", "celery_queue_len", "celery_queue_health"} {
+                    delete(status, key)
+                }
+            },
+            healthy: true,
+        },
+    }
+
+    for name, test := range tests {
+        reported := make(map[string]interface{}, len(status))
+        for key, value := range status {
+            reported[key] = value
+        }
+        test.modify(reported)
+
+        server := serverStatusServer(t, "mon-secret", reported)
+        resp := readDataSource(t, &ServerStatusDataSource{client: newTestClient(server)}, serverStatusModel("mon-secret"))
+        if resp.Diagnostics.HasError() {
+            t.Fatalf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+        }
+
+        var state ServerStatusDataSourceModel
+        resp.State.Get(context.Background(), &state)
+        if state.Healthy.ValueBool() != test.healthy {
+            t.Errorf("%s: expected healthy %t, got %v", name, test.healthy, state.Healthy)
+        }
+        if state.Version.ValueString() != "v0.20.1" || state.AgentCount.ValueInt64() != 120 || state.DaysUntilCertExpires.ValueInt64() != 37 {
+            t.Errorf("%s: unexpected status: %+v", name, state)
+        }
+
+        if name == "healthy" {
+            running := map[string]bool{}
+            state.ServicesRunning.ElementsAs(context.Background(), &running, false)
+            if !state.CeleryQueueHealthy.ValueBool() || state.CeleryQueueLength.ValueInt64() != 2 || !state.NatsStdPing.ValueBool() || len(running) != 3 || !running["postgres"] {
+                t.Errorf("%s: unexpected status: %+v", name, state)
+            }
+        }
+        if name == "docker without pings" && (!state.ServicesRunning.IsNull() || !state.RedisPing.IsNull() || !state.CeleryQueueHealthy.IsNull()) {
+            t.Errorf("%s: expected the values not reported to be null: %+v", name, state)
+        }
+    }
+}
+
+func TestServerStatusDataSourceRead_InvalidToken(t *testing.T) {
+    server := serverStatusServer(t, "mon-secret", map[string]interface{}{})
+    resp := readDataSource(t, &ServerStatusDataSource{client: newTestClient(server)}, serverStatusModel("wrong"))
+    if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Invalid Monitoring Token" {
+        t.Errorf("expected an invalid monitoring token error, got: %v", resp.Diagnostics)
+    }
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultScriptTimeout is the default_timeout of scripts created without one
+// when shell_default_timeouts has no entry for their shell.
+const defaultScriptTimeout int64 = 90
+
+// scriptShells are the shells Tactical RMM runs scripts with.
+var scriptShells = []string{"powershell", "cmd", "python", "shell", "nushell", "deno"}
+
+// parseShellDefaultTimeouts converts the shell_default_timeouts
+// configuration. Unknown shells are rejected so a typo does not silently fall
+// back to the global default.
+func parseShellDefaultTimeouts(ctx context.Context, value types.Map) (map[string]int64, diag.Diagnostics) {
+    var diags diag.Diagnostics
+    if value.IsNull() || value.IsUnknown() {
+        return nil, diags
+    }
+
+    var configured map[string]int64
+    diags.Append(value.ElementsAs(ctx, &configured, false)...)
+    if diags.HasError() {
+        return nil, diags
+    }
+
+    known := make(map[string]bool, len(scriptShells))
+    for _, shell := range scriptShells {
+        known[shell] = true
+    }
+
+    shells := make([]string, 0, len(configured))
+    for shell := range configured {
+        shells = append(shells, shell)
+    }
+    sort.Strings(shells)
+
+    timeouts := make(map[string]int64, len(configured))
+    for _, shell := range shells {
+        timeout := configured[shell]
+        switch {
+        case !known[shell]:
+            diags.AddAttributeError(
+                path.Root("shell_default_timeouts").AtMapKey(shell),
+                "Invalid Shell Default Timeout",
+                fmt.Sprintf("%q is not a script shell, expected one of: %s.", shell, strings.Join(scriptShells, ", ")),
+            )
+        case timeout < 1:
+            diags.AddAttributeError(
+                path.Root("shell_default_timeouts").AtMapKey(shell),
+                "Invalid Shell Default Timeout",
+                fmt.Sprintf("The default timeout for %s must be at least 1 second, got %d.", shell, timeout),
+            )
+        default:
+            timeouts[shell] = timeout
+        }
+    }
+
+    return timeouts, diags
+}
+
+// scriptDefaultTimeout returns the default_timeout for a script of the given
+// shell that is created without one: the shell's shell_default_timeouts entry,
+// or defaultScriptTimeout.
+func (c *ClientConfig) scriptDefaultTimeout(shell string) int64 {
+    if c != nil {
+        if timeout, ok := c.shellDefaultTimeouts[shell]; ok {
+            return timeout
+        }
+    }
+    return defaultScriptTimeout
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+    "github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParseShellDefaultTimeouts(t *testing.T) {
+    value := types.MapValueMust(types.Int64Type, map[string]attr.Value{
+        "python":     types.Int64Value(600),
+        "cmd":        types.Int64Value(0),
+        "powershel":  types.Int64Value(120),
+        "powershell": types.Int64Value(120),
+    })
+
+    timeouts, diags := parseShellDefaultTimeouts(context.Background(), value)
+    if diags.ErrorsCount() != 2 {
+        t.Errorf("expected the zero cmd timeout and the unknown shell to be rejected, got %v", diags)
+    }
+    if len(timeouts) != 2 || timeouts["python"] != 600 || timeouts["powershell"] != 120 {
+        t.Errorf("unexpected timeouts: %v", timeouts)
+    }
+}
+
+func TestClientConfigScriptDefaultTimeout(t *testing.T) {
+    client := &ClientConfig{shellDefaultTimeouts: map[string]int64{"python": 600}}
+
+    for shell, want := range map[string]int64{
+        "python":     600,
+        "powershell": defaultScriptTimeout,
+    } {
+        if got := client.scriptDefaultTimeout(shell); got != want {
+            t.Errorf("%s: expected %d, got %d", shell, want, got)
+        }
+    }
+
+    var unconfigured *ClientConfig
+    if got := unconfigured.scriptDefaultTimeout("python"); got != defaultScriptTimeout {
+        t.Errorf("expected %d without a configured provider, got %d", defaultScriptTimeout, got)
+    }
+}
+
+// shellTimeoutScript is a python script planned without default_timeout.
+func shellTimeoutScript() *ScriptResourceModel {
+    return &ScriptResourceModel{
+        Id:                 types.Int64Unknown(),
+        Name:               types.StringValue("Inventory Export"),
+        Shell:              types.StringValue("python"),
+        ScriptBody:         types.StringValue("print('export')"),
+        DefaultTimeout:     types.Int64Unknown(),
+        Args:               types.ListNull(types.StringType),
+        EnvVars:            types.ListNull(types.StringType),
+        SensitiveEnvVars:   types.ListNull(types.StringType),
+        SupportedPlatforms: types.SetNull(types.StringType),
+    }
+}
+
+func TestScriptResourceModifyPlan_ShellDefaultTimeout(t *testing.T) {
+    ctx := context.Background()
+    r := &ScriptResource{client: &ClientConfig{shellDefaultTimeouts: map[string]int64{"python": 600}}}
+    schemaResp := resourceSchemaFor(t, r)
+
+    planned := tfsdk.Plan{Schema: schemaResp.Schema}
+    if diags := planned.Set(ctx, shellTimeoutScript()); diags.HasError() {
+        t.Fatalf("unable to build plan: %v", diags)
+    }
+    configured := shellTimeoutScript()
+    configured.Id = types.Int64Null()
+    configured.DefaultTimeout = types.Int64Null()
+    config := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := config.Set(ctx, configured); diags.HasError() {
+        t.Fatalf("unable to build config: %v", diags)
+    }
+    state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+    resp := resource.ModifyPlanResponse{Plan: planned}
+    r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+        Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw},
+        Plan:   planned,
+        State:  state,
+    }, &resp)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var timeout types.Int64
+    resp.Plan.GetAttribute(ctx, path.Root("default_timeout"), &timeout)
+    if timeout.ValueInt64() != 600 {
+        t.Errorf("expected the python default of 600 in the plan, got %v", timeout)
+    }
+}
+
+func TestScriptResourceCreate_ShellDefaultTimeout(t *testing.T) {
+    var sent interface{}
+    created := false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/":
+            var body map[string]interface{}
+            if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+                t.Errorf("unable to decode request: %s", err)
+            }
+            sent = body["default_timeout"]
+            created = true
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/" && !created:
+            writeJSON(t, w, []map[string]interface{}{})
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 42, "name": "Inventory Export", "default_timeout": sent}})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    client := newTestClient(server)
+    client.shellDefaultTimeouts = map[string]int64{"python": 600}
+
+    r := &ScriptResource{client: client}
+    resp := createResource(t, r, shellTimeoutScript())
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if sent != float64(600) {
+        t.Errorf("expected the python default of 600 to be sent, got %v", sent)
+    }
+}
@@ -0,0 +1,220 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SitesDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &SitesDataSource{}
+
+// siteAttrTypes describes one entry of the sites list.
+var siteAttrTypes = map[string]attr.Type{
+    "id":          types.Int64Type,
+    "name":        types.StringType,
+    "client_id":   types.Int64Type,
+    "client_name": types.StringType,
+}
+
+func NewSitesDataSource() datasource.DataSource {
+    return &SitesDataSource{}
+}
+
+// SitesDataSource lists sites, optionally only those of one client.
+type SitesDataSource struct {
+    client *ClientConfig
+}
+
+// SitesDataSourceModel describes the data source data model.
+type SitesDataSourceModel struct {
+    ClientId   types.Int64  `tfsdk:"client_id"`
+    ClientName types.String `tfsdk:"client_name"`
+    Sites      types.List   `tfsdk:"sites"`
+}
+
+func (d *SitesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_sites"
+}
+
+func (d *SitesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Sites data source for Tactical RMM. Lists all sites, or only the sites of one client selected by `client_id` or `client_name`. " +
+            "Filtering by a client that does not exist is an error rather than an empty list.",
+
+        Attributes: map[string]schema.Attribute{
+            "client_id": schema.Int64Attribute{
+                MarkdownDescription: "Optional: Only list the sites of this client. Conflicts with `client_name`.",
+                Optional:            true,
+            },
+            "client_name": schema.StringAttribute{
+                MarkdownDescription: "Optional: Only list the sites of the client with this name (exact match). Conflicts with `client_id`.",
+                Optional:            true,
+            },
+            "sites": schema.ListNestedAttribute{
+                MarkdownDescription: "Sites matching the filter, sorted by client and site name",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Site identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Site name",
+                            Computed:            true,
+                        },
+                        "client_id": schema.Int64Attribute{
+                            MarkdownDescription: "Identifier of the client the site belongs to",
+                            Computed:            true,
+                        },
+                        "client_name": schema.StringAttribute{
+                            MarkdownDescription: "Name of the client the site belongs to",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *SitesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+    var data SitesDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    if !data.ClientId.IsNull() && !data.ClientName.IsNull() {
+        resp.Diagnostics.AddError(
+            "Invalid Site Filter",
+            "Only one of 'client_id' or 'client_name' can be specified.",
+        )
+    }
+}
+
+func (d *SitesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *SitesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data SitesDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // A filter on a missing client is an error, an empty list would silently
+    // turn a for_each over the sites into a no-op
+    filterClient := !data.ClientId.IsNull() || !data.ClientName.IsNull()
+    var clientId int64
+    if filterClient {
+        clients, err := d.client.listObjects(ctx, fmt.Sprintf("%s/clients/", d.client.BaseURL))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list clients, got error: %s", err))
+            return
+        }
+
+        client := findClient(clients, data.ClientId, data.ClientName)
+        if client == nil {
+            resp.Diagnostics.AddError("Client Not Found", fmt.Sprintf("No client matches %s.", describeClientFilter(data.ClientId, data.ClientName)))
+            return
+        }
+        id, _ := client["id"].(float64)
+        clientId = int64(id)
+    }
+
+    sites, err := d.client.listObjects(ctx, fmt.Sprintf("%s/clients/sites/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list sites, got error: %s", err))
+        return
+    }
+
+    var matched []map[string]interface{}
+    for _, site := range sites {
+        if filterClient {
+            if id, ok := site["client"].(float64); !ok || int64(id) != clientId {
+                continue
+            }
+        }
+        matched = append(matched, site)
+    }
+
+    sort.SliceStable(matched, func(i, j int) bool {
+        clientI, _ := matched[i]["client_name"].(string)
+        clientJ, _ := matched[j]["client_name"].(string)
+        if clientI != clientJ {
+            return clientI < clientJ
+        }
+        nameI, _ := matched[i]["name"].(string)
+        nameJ, _ := matched[j]["name"].(string)
+        return nameI < nameJ
+    })
+
+    siteValues := make([]attr.Value, 0, len(matched))
+    for _, site := range matched {
+        siteValue, diags := types.ObjectValue(siteAttrTypes, map[string]attr.Value{
+            "id":          int64Value(site["id"]),
+            "name":        stringValue(site["name"]),
+            "client_id":   int64Value(site["client"]),
+            "client_name": stringValue(site["client_name"]),
+        })
+        resp.Diagnostics.Append(diags...)
+        siteValues = append(siteValues, siteValue)
+    }
+
+    sitesValue, diags := types.ListValue(types.ObjectType{AttrTypes: siteAttrTypes}, siteValues)
+    resp.Diagnostics.Append(diags...)
+    data.Sites = sitesValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findClient returns the client with the given ID, or with the given name when
+// the ID is null. It returns nil when no client matches.
+func findClient(clients []map[string]interface{}, id types.Int64, name types.String) map[string]interface{} {
+    for _, client := range clients {
+        if !id.IsNull() {
+            if clientId, ok := client["id"].(float64); ok && int64(clientId) == id.ValueInt64() {
+                return client
+            }
+            continue
+        }
+        if clientName, ok := client["name"].(string); ok && clientName == name.ValueString() {
+            return client
+        }
+    }
+    return nil
+}
+
+// describeClientFilter formats a client ID or name filter for error messages.
+func describeClientFilter(id types.Int64, name types.String) string {
+    if !id.IsNull() {
+        return fmt.Sprintf("client_id %d", id.ValueInt64())
+    }
+    return fmt.Sprintf("client_name %q", name.ValueString())
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func sitesServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/clients/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "name": "Acme"},
+                {"id": 2, "name": "Globex"},
+            })
+        case "/clients/sites/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 10, "name": "Warehouse", "client": 1, "client_name": "Acme"},
+                {"id": 11, "name": "HQ", "client": 2, "client_name": "Globex"},
+                {"id": 12, "name": "Branch", "client": 1, "client_name": "Acme"},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func TestSitesDataSourceRead_ByClientName(t *testing.T) {
+    d := &SitesDataSource{client: newTestClient(sitesServer(t))}
+
+    resp := readDataSource(t, d, &SitesDataSourceModel{
+        ClientId:   types.Int64Null(),
+        ClientName: types.StringValue("Acme"),
+        Sites:      types.ListNull(types.ObjectType{AttrTypes: siteAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state SitesDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var sites []struct {
+        Id         types.Int64  `tfsdk:"id"`
+        Name       types.String `tfsdk:"name"`
+        ClientId   types.Int64  `tfsdk:"client_id"`
+        ClientName types.String `tfsdk:"client_name"`
+    }
+    state.Sites.ElementsAs(context.Background(), &sites, false)
+    if len(sites) != 2 {
+        t.Fatalf("expected 2 sites, got %d", len(sites))
+    }
+    if sites[0].Name.ValueString() != "Branch" || sites[1].Name.ValueString() != "Warehouse" {
+        t.Errorf("unexpected sites: %v", sites)
+    }
+    if sites[0].ClientId.ValueInt64() != 1 {
+        t.Errorf("expected client_id 1, got %d", sites[0].ClientId.ValueInt64())
+    }
+}
+
+func TestSitesDataSourceRead_UnknownClientName(t *testing.T) {
+    d := &SitesDataSource{client: newTestClient(sitesServer(t))}
+
+    resp := readDataSource(t, d, &SitesDataSourceModel{
+        ClientId:   types.Int64Null(),
+        ClientName: types.StringValue("Initech"),
+        Sites:      types.ListNull(types.ObjectType{AttrTypes: siteAttrTypes}),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a client that does not exist")
+    }
+    if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Client Not Found" {
+        t.Errorf("unexpected error summary: %s", summary)
+    }
+}
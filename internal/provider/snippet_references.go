@@ -0,0 +1,140 @@
+package provider
+
+import (
+    "regexp"
+    "sort"
+    "strings"
+)
+
+// snippetTokenPattern matches {{name}} placeholders. Keystore references use
+// the same syntax with a global. prefix and are not snippets.
+var snippetTokenPattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// snippetReferences returns the names referenced as {{name}} in code, in order
+// of first appearance.
+func snippetReferences(code string) []string {
+    seen := make(map[string]bool)
+    var names []string
+    for _, match := range snippetTokenPattern.FindAllStringSubmatch(code, -1) {
+        name := match[1]
+        if strings.HasPrefix(name, "global.") || seen[name] {
+            continue
+        }
+        seen[name] = true
+        names = append(names, name)
+    }
+    return names
+}
+
+// scriptVariablePrefixes are the prefixes of the {{...}} placeholders TRMM
+// replaces with agent, client, site, alert or keystore values.
+var scriptVariablePrefixes = []string{"agent.", "client.", "site.", "alert.", "global."}
+
+// scriptSnippetReferences returns the snippet names referenced in a script
+// body, leaving out script variables.
+func scriptSnippetReferences(body string) []string {
+    var names []string
+    for _, name := range snippetReferences(body) {
+        variable := false
+        for _, prefix := range scriptVariablePrefixes {
+            if strings.HasPrefix(name, prefix) {
+                variable = true
+                break
+            }
+        }
+        if !variable {
+            names = append(names, name)
+        }
+    }
+    return names
+}
+
+// snippetGraph maps each snippet name to the snippets its code references.
+// References to names that are not snippets are left out.
+func snippetGraph(codes map[string]string) map[string][]string {
+    graph := make(map[string][]string, len(codes))
+    for name, code := range codes {
+        for _, ref := range snippetReferences(code) {
+            if _, ok := codes[ref]; ok {
+                graph[name] = append(graph[name], ref)
+            }
+        }
+    }
+    return graph
+}
+
+// findSnippetCycle returns a reference cycle through start, beginning and
+// ending with start, or nil if start is not part of one.
+func findSnippetCycle(graph map[string][]string, start string) []string {
+    visited := make(map[string]bool)
+    var path []string
+
+    var visit func(name string) bool
+    visit = func(name string) bool {
+        path = append(path, name)
+        refs := append([]string(nil), graph[name]...)
+        sort.Strings(refs)
+        for _, ref := range refs {
+            if ref == start {
+                path = append(path, ref)
+                return true
+            }
+            if visited[ref] {
+                continue
+            }
+            visited[ref] = true
+            if visit(ref) {
+                return true
+            }
+        }
+        path = path[:len(path)-1]
+        return false
+    }
+
+    if visit(start) {
+        return path
+    }
+    return nil
+}
+
+// withPlannedSnippets returns the listed snippets together with the snippets
+// planned in this run. The planned code of a snippet replaces the listed
+// one, as it is what the apply will leave on the server.
+func (c *ClientConfig) withPlannedSnippets(snippets []map[string]interface{}) []map[string]interface{} {
+    planned := make(map[string]string)
+    c.plannedSnippets.Range(func(key, value interface{}) bool {
+        name, _ := key.(string)
+        planned[name], _ = value.(string)
+        return true
+    })
+
+    merged := make([]map[string]interface{}, 0, len(snippets)+len(planned))
+    for _, snippet := range snippets {
+        if name, _ := snippet["name"].(string); planned[name] == "" {
+            merged = append(merged, snippet)
+        }
+    }
+    for name, code := range planned {
+        if code != "" {
+            merged = append(merged, map[string]interface{}{"name": name, "code": code})
+        }
+    }
+    return merged
+}
+
+// snippetCycle checks whether giving the snippet previously named priorName
+// the given name and code would create a reference cycle with the other
+// snippets. priorName is empty for a new snippet.
+func snippetCycle(snippets []map[string]interface{}, priorName string, name string, code string) []string {
+    codes := make(map[string]string, len(snippets)+1)
+    for _, snippet := range snippets {
+        snippetName, ok := snippet["name"].(string)
+        if !ok || snippetName == priorName {
+            continue
+        }
+        codes[snippetName], _ = snippet["code"].(string)
+    }
+    codes[name] = code
+
+    return findSnippetCycle(snippetGraph(codes), name)
+}
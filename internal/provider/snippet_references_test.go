@@ -0,0 +1,178 @@
+package provider
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSnippetReferences_SkipsKeystoreReferences(t *testing.T) {
+    refs := snippetReferences("{{ Logging }}\n$token = '{{global.api_token}}'\n{{Retry}} {{Logging}}")
+    if !reflect.DeepEqual(refs, []string{"Logging", "Retry"}) {
+        t.Errorf("unexpected references %v", refs)
+    }
+}
+
+func TestSnippetCycle_ValidChain(t *testing.T) {
+    snippets := []map[string]interface{}{
+        {"name": "Logging", "code": "{{Format}}"},
+        {"name": "Format", "code": "Get-Date"},
+    }
+    if cycle := snippetCycle(snippets, "", "Report", "{{Logging}}\n{{Format}}\n{{NotASnippet}}"); cycle != nil {
+        t.Errorf("expected no cycle, got %v", cycle)
+    }
+}
+
+func TestSnippetCycle_DetectsCycle(t *testing.T) {
+    snippets := []map[string]interface{}{
+        {"name": "Logging", "code": "{{Format}}"},
+        {"name": "Format", "code": "{{Report}}"},
+        {"name": "Report", "code": "Write-Output 'old'"},
+    }
+    cycle := snippetCycle(snippets, "Report", "Report", "{{Logging}}")
+    if !reflect.DeepEqual(cycle, []string{"Report", "Logging", "Format", "Report"}) {
+        t.Errorf("unexpected cycle %v", cycle)
+    }
+}
+
+func TestSnippetCycle_DetectsSelfReference(t *testing.T) {
+    cycle := snippetCycle(nil, "", "Logging", "{{Logging}}")
+    if !reflect.DeepEqual(cycle, []string{"Logging", "Logging"}) {
+        t.Errorf("unexpected cycle %v", cycle)
+    }
+}
+
+func TestScriptSnippetResourceCreate_RejectsReferenceCycle(t *testing.T) {
+    var posts int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 12, "name": "Logging", "code": "{{Retry}}"},
+            })
+        case r.Method == http.MethodPost && r.URL.Path == "/scripts/snippets/":
+            atomic.AddInt32(&posts, 1)
+            writeJSON(t, w, "ok")
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+    resp := createResource(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("Retry"),
+        Code:         types.StringValue("{{Logging}}"),
+        ReferencedBy: types.ListNull(types.StringType),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a reference cycle")
+    }
+    diagnostic := resp.Diagnostics.Errors()[0]
+    if diagnostic.Summary() != "Snippet Reference Cycle" {
+        t.Errorf("unexpected error summary: %s", diagnostic.Summary())
+    }
+    if !strings.Contains(diagnostic.Detail(), "Retry -> Logging -> Retry") {
+        t.Errorf("expected the cycle in the detail, got %q", diagnostic.Detail())
+    }
+    if got := atomic.LoadInt32(&posts); got != 0 {
+        t.Errorf("expected no create call, got %d", got)
+    }
+}
+
+func TestScriptSnippetResourceModifyPlan_RejectsCycleBetweenNewSnippets(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/" {
+            writeJSON(t, w, []map[string]interface{}{{"id": 12, "name": "Logging", "code": "Write-Log"}})
+            return
+        }
+        http.NotFound(w, r)
+    }))
+    t.Cleanup(server.Close)
+    r := &ScriptSnippetResource{client: newTestClient(server)}
+
+    // Neither snippet exists yet, the first one planned sees no cycle
+    resp := planCreate(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("Retry"),
+        Code:         types.StringValue("{{Logging}}\n{{Backoff}}"),
+        ReferencedBy: types.ListUnknown(types.StringType),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    resp = planCreate(t, r, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("Backoff"),
+        Code:         types.StringValue("{{Retry}}"),
+        ReferencedBy: types.ListUnknown(types.StringType),
+    })
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for a cycle between planned snippets")
+    }
+    diagnostic := resp.Diagnostics.Errors()[0]
+    if diagnostic.Summary() != "Snippet Reference Cycle" || !strings.Contains(diagnostic.Detail(), "Backoff -> Retry -> Backoff") {
+        t.Errorf("expected the cycle in the error, got %v", resp.Diagnostics)
+    }
+}
+
+func TestScriptSnippetReferences_SkipsScriptVariables(t *testing.T) {
+    body := "{{agent.hostname}} {{client.name}} {{site.name}} {{alert.severity}} {{global.token}} {{Logging}} {{ Retry }} {{Logging}}"
+    got := scriptSnippetReferences(body)
+    if len(got) != 2 || got[0] != "Logging" || got[1] != "Retry" {
+        t.Errorf("expected [Logging Retry], got %v", got)
+    }
+}
+
+func TestScriptResourceModifyPlan_WarnsAboutUnknownSnippets(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet && r.URL.Path == "/scripts/snippets/" {
+            writeJSON(t, w, []map[string]interface{}{{"id": 12, "name": "Logging", "code": "Write-Log"}})
+            return
+        }
+        http.NotFound(w, r)
+    }))
+    t.Cleanup(server.Close)
+    client := newTestClient(server)
+
+    // A snippet planned earlier in the same run is not reported
+    snippetResp := planCreate(t, &ScriptSnippetResource{client: client}, &ScriptSnippetResourceModel{
+        Name:         types.StringValue("Retry"),
+        Code:         types.StringValue("Start-Sleep 5"),
+        ReferencedBy: types.ListUnknown(types.StringType),
+    })
+    if snippetResp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", snippetResp.Diagnostics)
+    }
+
+    script := func(validate bool) *ScriptResourceModel {
+        return &ScriptResourceModel{
+            Name:               types.StringValue("Nightly"),
+            Shell:              types.StringValue("powershell"),
+            ScriptBody:         types.StringValue("{{Logging}}\n{{Retry}}\n{{Cleanup}}\nWrite-Output {{agent.hostname}}"),
+            Args:               types.ListNull(types.StringType),
+            EnvVars:            types.ListNull(types.StringType),
+            SensitiveEnvVars:   types.ListNull(types.StringType),
+            SupportedPlatforms: types.SetNull(types.StringType),
+            ValidateSnippets:   types.BoolValue(validate),
+        }
+    }
+
+    resp := planCreate(t, &ScriptResource{client: client}, script(true))
+    warnings := resp.Diagnostics.Warnings()
+    if resp.Diagnostics.HasError() || len(warnings) != 1 || warnings[0].Summary() != "Unknown Snippet References" {
+        t.Fatalf("expected an unknown snippet warning, got: %v", resp.Diagnostics)
+    }
+    if detail := warnings[0].Detail(); !strings.Contains(detail, "{{Cleanup}}") || strings.Contains(detail, "{{Logging}}") || strings.Contains(detail, "{{Retry}}") {
+        t.Errorf("expected only {{Cleanup}} to be reported, got %q", detail)
+    }
+
+    resp = planCreate(t, &ScriptResource{client: client}, script(false))
+    if resp.Diagnostics.WarningsCount() != 0 {
+        t.Errorf("expected no check without validate_snippet_references, got: %v", resp.Diagnostics)
+    }
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SnippetUsageDataSource{}
+
+func NewSnippetUsageDataSource() datasource.DataSource {
+    return &SnippetUsageDataSource{}
+}
+
+// SnippetUsageDataSource reports which scripts reference a snippet.
+type SnippetUsageDataSource struct {
+    client *ClientConfig
+}
+
+// SnippetUsageDataSourceModel describes the data source data model.
+type SnippetUsageDataSourceModel struct {
+    Id      types.Int64  `tfsdk:"id"`
+    Name    types.String `tfsdk:"name"`
+    Token   types.String `tfsdk:"token"`
+    Scripts types.List   `tfsdk:"scripts"`
+}
+
+// scriptReferenceAttrTypes describes a script referencing a snippet or keystore entry.
+var scriptReferenceAttrTypes = map[string]attr.Type{
+    "id":    types.Int64Type,
+    "name":  types.StringType,
+    "lines": types.ListType{ElemType: types.Int64Type},
+}
+
+func (d *SnippetUsageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_snippet_usage"
+}
+
+func (d *SnippetUsageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Snippet usage data source for Tactical RMM. Lists the user defined scripts whose body references a snippet as `{{name}}`. " +
+            "Script bodies are not part of the scripts list, so one additional API call is made per user defined script.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: "Script snippet identifier. Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Snippet name. Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "token": schema.StringAttribute{
+                MarkdownDescription: "Reference searched for in script bodies, e.g. `{{GetDiskSpace}}`",
+                Computed:            true,
+            },
+            "scripts": schema.ListNestedAttribute{
+                MarkdownDescription: "Scripts referencing the snippet",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "Script identifier",
+                            Computed:            true,
+                        },
+                        "name": schema.StringAttribute{
+                            MarkdownDescription: "Script name",
+                            Computed:            true,
+                        },
+                        "lines": schema.ListAttribute{
+                            MarkdownDescription: "Line numbers (starting at 1) of the script body that reference the snippet",
+                            Computed:            true,
+                            ElementType:         types.Int64Type,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *SnippetUsageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *SnippetUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data SnippetUsageDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Validate that either ID or name is provided
+    if data.Id.IsNull() && data.Name.IsNull() {
+        resp.Diagnostics.AddError(
+            "Missing Script Snippet Identifier",
+            "Either 'id' or 'name' must be specified to look up snippet usage.",
+        )
+        return
+    }
+
+    // Resolve the snippet so both id and name are known
+    var snippet map[string]interface{}
+    if !data.Id.IsNull() {
+        found, err := d.client.getObject(ctx, fmt.Sprintf("%s/scripts/snippets/%d/", d.client.BaseURL, data.Id.ValueInt64()))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read script snippet, got error: %s", err))
+            return
+        }
+        snippet = found
+    } else {
+        snippets, err := d.client.listObjects(ctx, fmt.Sprintf("%s/scripts/snippets/", d.client.BaseURL))
+        if err != nil {
+            resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list script snippets, got error: %s", err))
+            return
+        }
+        for _, s := range snippets {
+            if name, ok := s["name"].(string); ok && name == data.Name.ValueString() {
+                snippet = s
+                break
+            }
+        }
+        if snippet == nil {
+            resp.Diagnostics.AddError("Script Snippet Not Found", fmt.Sprintf("Script snippet with name '%s' not found", data.Name.ValueString()))
+            return
+        }
+    }
+
+    if id, ok := snippet["id"].(float64); ok {
+        data.Id = types.Int64Value(int64(id))
+    }
+    if name, ok := snippet["name"].(string); ok {
+        data.Name = types.StringValue(name)
+    }
+    token := snippetToken(data.Name.ValueString())
+    data.Token = types.StringValue(token)
+
+    scripts, err := d.client.listObjects(ctx, d.client.allScriptsURL())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list scripts, got error: %s", err))
+        return
+    }
+
+    ids := userDefinedScriptIds(scripts)
+    details, failures := d.client.fetchScriptDetails(ctx, ids)
+
+    references := []attr.Value{}
+    for _, id := range ids {
+        if err, failed := failures[id]; failed {
+            addItemFetchWarning(&resp.Diagnostics, fmt.Sprintf("script ID %d", id), err)
+            continue
+        }
+
+        body, _ := details[id]["script_body"].(string)
+        lines := referenceLines(body, token)
+        if len(lines) == 0 {
+            continue
+        }
+
+        name, _ := details[id]["name"].(string)
+        linesValue, diags := types.ListValueFrom(ctx, types.Int64Type, lines)
+        resp.Diagnostics.Append(diags...)
+        reference, diags := types.ObjectValue(scriptReferenceAttrTypes, map[string]attr.Value{
+            "id":    types.Int64Value(id),
+            "name":  types.StringValue(name),
+            "lines": linesValue,
+        })
+        resp.Diagnostics.Append(diags...)
+        references = append(references, reference)
+    }
+
+    scriptsValue, diags := types.ListValue(types.ObjectType{AttrTypes: scriptReferenceAttrTypes}, references)
+    resp.Diagnostics.Append(diags...)
+    data.Scripts = scriptsValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// scriptBodiesServer serves a scripts list and script details built from
+// bodies (keyed by script ID), and tracks the peak number of concurrent
+// detail requests.
+func scriptBodiesServer(t *testing.T, bodies map[int]string) (*httptest.Server, *int32) {
+    t.Helper()
+    var inFlight, peak int32
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.URL.Path == "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 7, "name": "GetDiskSpace"}})
+        case r.URL.Path == "/scripts/snippets/7/":
+            writeJSON(t, w, map[string]interface{}{"id": 7, "name": "GetDiskSpace"})
+        case r.URL.Path == "/scripts/":
+            scripts := []map[string]interface{}{
+                {"id": 999, "name": "Builtin", "script_type": "builtin"},
+            }
+            for id := range bodies {
+                scripts = append(scripts, map[string]interface{}{"id": id, "name": fmt.Sprintf("Script %d", id), "script_type": "userdefined"})
+            }
+            writeJSON(t, w, scripts)
+        case strings.HasPrefix(r.URL.Path, "/scripts/"):
+            current := atomic.AddInt32(&inFlight, 1)
+            defer atomic.AddInt32(&inFlight, -1)
+            for {
+                previous := atomic.LoadInt32(&peak)
+                if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+                    break
+                }
+            }
+            time.Sleep(5 * time.Millisecond)
+
+            var id int
+            fmt.Sscanf(r.URL.Path, "/scripts/%d/", &id)
+            body, ok := bodies[id]
+            if !ok {
+                http.NotFound(w, r)
+                return
+            }
+            writeJSON(t, w, map[string]interface{}{"id": id, "name": fmt.Sprintf("Script %d", id), "script_body": body})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    return server, &peak
+}
+
+func TestSnippetUsageDataSourceRead_ReportsReferencingLines(t *testing.T) {
+    server, _ := scriptBodiesServer(t, map[int]string{
+        1: "Write-Output 'start'\n{{GetDiskSpace}}\nWrite-Output 'again'\n$x = {{GetDiskSpace}}",
+        2: "Write-Output 'no snippets here'",
+        3: "{{GetDiskSpaceV2}}",
+    })
+
+    d := &SnippetUsageDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &SnippetUsageDataSourceModel{
+        Id:      types.Int64Null(),
+        Name:    types.StringValue("GetDiskSpace"),
+        Token:   types.StringNull(),
+        Scripts: types.ListNull(types.ObjectType{AttrTypes: scriptReferenceAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state SnippetUsageDataSourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 7 {
+        t.Errorf("expected snippet id 7, got %d", state.Id.ValueInt64())
+    }
+
+    type reference struct {
+        Id    int64   `tfsdk:"id"`
+        Name  string  `tfsdk:"name"`
+        Lines []int64 `tfsdk:"lines"`
+    }
+    var references []reference
+    state.Scripts.ElementsAs(context.Background(), &references, false)
+
+    expected := []reference{{Id: 1, Name: "Script 1", Lines: []int64{2, 4}}}
+    if !reflect.DeepEqual(references, expected) {
+        t.Errorf("expected %v, got %v", expected, references)
+    }
+}
+
+func TestSnippetUsageDataSourceRead_SkipsUnreadableScripts(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/scripts/snippets/":
+            writeJSON(t, w, []map[string]interface{}{{"id": 7, "name": "GetDiskSpace"}})
+        case "/scripts/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "name": "Broken", "script_type": "userdefined"},
+                {"id": 2, "name": "Disk Report", "script_type": "userdefined"},
+            })
+        case "/scripts/1/":
+            http.Error(w, "corrupt script", http.StatusInternalServerError)
+        case "/scripts/2/":
+            writeJSON(t, w, map[string]interface{}{"id": 2, "name": "Disk Report", "script_body": "{{GetDiskSpace}}"})
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+
+    d := &SnippetUsageDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &SnippetUsageDataSourceModel{
+        Id:      types.Int64Null(),
+        Name:    types.StringValue("GetDiskSpace"),
+        Token:   types.StringNull(),
+        Scripts: types.ListNull(types.ObjectType{AttrTypes: scriptReferenceAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    warnings := resp.Diagnostics.Warnings()
+    if len(warnings) != 1 || warnings[0].Summary() != "Incomplete Results" || !strings.Contains(warnings[0].Detail(), "script ID 1") {
+        t.Errorf("expected a warning for script 1, got: %v", resp.Diagnostics)
+    }
+
+    var state SnippetUsageDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    type reference struct {
+        Id    int64   `tfsdk:"id"`
+        Name  string  `tfsdk:"name"`
+        Lines []int64 `tfsdk:"lines"`
+    }
+    var references []reference
+    state.Scripts.ElementsAs(context.Background(), &references, false)
+
+    expected := []reference{{Id: 2, Name: "Disk Report", Lines: []int64{1}}}
+    if !reflect.DeepEqual(references, expected) {
+        t.Errorf("expected %v, got %v", expected, references)
+    }
+}
+
+func TestFetchScriptDetails_LimitsConcurrency(t *testing.T) {
+    bodies := make(map[int]string)
+    var ids []int64
+    for id := 1; id <= 20; id++ {
+        bodies[id] = "Write-Output 'hello'"
+        ids = append(ids, int64(id))
+    }
+    server, peak := scriptBodiesServer(t, bodies)
+
+    details, failures := newTestClient(server).fetchScriptDetails(context.Background(), append(ids, 404))
+    if len(details) != 20 {
+        t.Errorf("expected 20 script details, got %d", len(details))
+    }
+    if _, ok := failures[404]; !ok || len(failures) != 1 {
+        t.Errorf("expected only script 404 to fail, got %v", failures)
+    }
+    if got := atomic.LoadInt32(peak); got > detailFetchConcurrency {
+        t.Errorf("expected at most %d concurrent requests, got %d", detailFetchConcurrency, got)
+    }
+}
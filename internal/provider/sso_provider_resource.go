@@ -0,0 +1,429 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/diag"
+    "github.com/hashicorp/terraform-plugin-framework/path"
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+    "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SSOProviderResource{}
+var _ resource.ResourceWithImportState = &SSOProviderResource{}
+var _ resource.ResourceWithIdentity = &SSOProviderResource{}
+var _ resource.ResourceWithValidateConfig = &SSOProviderResource{}
+
+func NewSSOProviderResource() resource.Resource {
+    return &SSOProviderResource{}
+}
+
+// SSOProviderResource manages an OpenID Connect single sign-on provider.
+type SSOProviderResource struct {
+    client *ClientConfig
+}
+
+// SSOProviderResourceModel describes the resource data model based on the
+// allauth SocialApp Django model TRMM stores SSO providers in.
+type SSOProviderResourceModel struct {
+    Id           types.Int64  `tfsdk:"id"`
+    Name         types.String `tfsdk:"name"`
+    ProviderId   types.String `tfsdk:"provider_id"`
+    IssuerURL    types.String `tfsdk:"issuer_url"`
+    ClientId     types.String `tfsdk:"client_id"`
+    ClientSecret types.String `tfsdk:"client_secret"`
+    RoleId       types.Int64  `tfsdk:"role_id"`
+}
+
+func (r *SSOProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_sso_provider"
+}
+
+func (r *SSOProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "OpenID Connect single sign-on provider for Tactical RMM. Provider names are unique, so each name is managed by at most one resource.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: "SSO provider identifier",
+                Computed:            true,
+                PlanModifiers: []planmodifier.Int64{
+                    int64planmodifier.UseStateForUnknown(),
+                },
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "Provider name shown on the login page (unique)",
+                Required:            true,
+            },
+            "provider_id": schema.StringAttribute{
+                MarkdownDescription: "Identifier TRMM derives from the name, used in the callback URL",
+                Computed:            true,
+                PlanModifiers: []planmodifier.String{
+                    stringplanmodifier.UseStateForUnknown(),
+                },
+            },
+            "issuer_url": schema.StringAttribute{
+                MarkdownDescription: "OIDC issuer URL, e.g. `https://login.microsoftonline.com/<tenant>/v2.0`. The discovery document path is appended automatically.",
+                Required:            true,
+                Validators: []validator.String{
+                    issuerURLValidator{},
+                },
+            },
+            "client_id": schema.StringAttribute{
+                MarkdownDescription: "Client ID of the application registered with the identity provider",
+                Required:            true,
+            },
+            "client_secret": schema.StringAttribute{
+                MarkdownDescription: "Client secret of the application registered with the identity provider",
+                Required:            true,
+                Sensitive:           true,
+            },
+            "role_id": schema.Int64Attribute{
+                MarkdownDescription: "ID of the role assigned to users created on their first sign-in through this provider. Without it new users have no role until one is assigned in TRMM.",
+                Optional:            true,
+            },
+        },
+    }
+}
+
+func (r *SSOProviderResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+    resp.IdentitySchema = idIdentitySchema("SSO provider")
+}
+
+func (r *SSOProviderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Resource Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    r.client = client
+}
+
+func (r *SSOProviderResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+    var data SSOProviderResourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Empty values pass Required, but the login flow cannot work without them
+    for attribute, value := range map[string]types.String{
+        "name":          data.Name,
+        "client_id":     data.ClientId,
+        "client_secret": data.ClientSecret,
+    } {
+        if !value.IsNull() && !value.IsUnknown() && strings.TrimSpace(value.ValueString()) == "" {
+            resp.Diagnostics.AddAttributeError(
+                path.Root(attribute),
+                "Missing SSO Provider Setting",
+                fmt.Sprintf("%s must not be empty.", attribute),
+            )
+        }
+    }
+
+    if !data.RoleId.IsNull() && !data.RoleId.IsUnknown() && data.RoleId.ValueInt64() < 1 {
+        resp.Diagnostics.AddAttributeError(
+            path.Root("role_id"),
+            "Invalid Role",
+            fmt.Sprintf("role_id must be a role ID of at least 1, got: %d.", data.RoleId.ValueInt64()),
+        )
+    }
+}
+
+func (r *SSOProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data SSOProviderResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+    r.client.addSecret(data.ClientSecret.ValueString())
+
+    if !r.client.requireFeature(ctx, featureSSO, &resp.Diagnostics) {
+        return
+    }
+
+    listURL := fmt.Sprintf("%s/accounts/ssoproviders/", r.client.BaseURL)
+    providers, err := r.client.listObjects(ctx, listURL)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list SSO providers, got error: %s", err))
+        return
+    }
+
+    // The created provider is found by name, so an existing one would be
+    // silently adopted instead of created, unless on_conflict asks for exactly
+    // that
+    method, requestURL := "POST", listURL
+    existing := objectNamed(providers, data.Name.ValueString())
+    if existing != nil && r.client.resolvesConflicts() {
+        adoptedId := r.client.adoptOrReplace(ctx, "SSO provider", []map[string]interface{}{existing}, func(id int64) string {
+            return fmt.Sprintf("%s/accounts/ssoproviders/%d/", r.client.BaseURL, id)
+        }, &resp.Diagnostics)
+        if resp.Diagnostics.HasError() {
+            return
+        }
+        if adoptedId != 0 {
+            method, requestURL = "PUT", fmt.Sprintf("%s/accounts/ssoproviders/%d/", r.client.BaseURL, adoptedId)
+        }
+    } else if existing != nil {
+        existingId, _ := existing["id"].(float64)
+        resp.Diagnostics.AddAttributeError(
+            path.Root("name"),
+            "Duplicate SSO Provider Name",
+            fmt.Sprintf("An SSO provider named %q already exists (id %d) and is not managed by this resource. "+
+                "Import it with 'terraform import' to manage it.", data.Name.ValueString(), int64(existingId)),
+        )
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, method, requestURL, data.requestBody())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create SSO provider, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create SSO provider, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    // Response is just "ok", so we need to find the created provider by name
+    created, err := r.client.findCreatedByName(ctx, listURL, data.Name.ValueString())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find created SSO provider, got error: %s", err))
+        return
+    }
+
+    data.setFromAPI(created)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
+}
+
+func (r *SSOProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data SSOProviderResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // The identity is set first, so it is also present when the resource is
+    // removed from state below
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
+
+    r.client.addSecret(data.ClientSecret.ValueString())
+
+    if !r.client.requireFeature(ctx, featureSSO, &resp.Diagnostics) {
+        return
+    }
+
+    provider, found := r.findProvider(ctx, data.Id.ValueInt64(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+    if !found {
+        resp.State.RemoveResource(ctx)
+        return
+    }
+
+    data.setFromAPI(provider)
+    r.client.addSecret(data.ClientSecret.ValueString())
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SSOProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data SSOProviderResourceModel
+    var state SSOProviderResourceModel
+
+    resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    data.Id = state.Id
+    r.client.addSecret(data.ClientSecret.ValueString())
+
+    if !r.client.requireFeature(ctx, featureSSO, &resp.Diagnostics) {
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "PUT", fmt.Sprintf("%s/accounts/ssoproviders/%d/", r.client.BaseURL, data.Id.ValueInt64()), data.requestBody())
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update SSO provider, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update SSO provider, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+
+    // The provider ID follows the name, so it is read back after a rename
+    provider, found := r.findProvider(ctx, data.Id.ValueInt64(), &resp.Diagnostics)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+    if !found {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("SSO provider %d not found after update", data.Id.ValueInt64()))
+        return
+    }
+
+    data.setFromAPI(provider)
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+    setIdentity(ctx, resp.Identity, data.Id, &resp.Diagnostics)
+}
+
+func (r *SSOProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+    defer r.client.redactDiagnostics(&resp.Diagnostics)
+
+    if r.client.denyWrite(&resp.Diagnostics) {
+        return
+    }
+
+    var data SSOProviderResourceModel
+
+    resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    statusCode, respBody, err := r.client.sendJSON(ctx, "DELETE", fmt.Sprintf("%s/accounts/ssoproviders/%d/", r.client.BaseURL, data.Id.ValueInt64()), nil)
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete SSO provider, got error: %s", err))
+        return
+    }
+    if statusCode != http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotFound {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete SSO provider, status code: %d, response: %s", statusCode, errorMessage(respBody)))
+        return
+    }
+}
+
+func (r *SSOProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+    // Import blocks may use the identity instead of an ID
+    if id, ok := identityImportID(ctx, req, &resp.Diagnostics); ok {
+        resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+        return
+    }
+
+    id, err := strconv.ParseInt(req.ID, 10, 64)
+    if err != nil {
+        resp.Diagnostics.AddError("Invalid ID", fmt.Sprintf("Unable to parse ID: %s", err))
+        return
+    }
+
+    resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// findProvider looks up an SSO provider by ID. There is no detail GET
+// endpoint, so the full list is fetched.
+func (r *SSOProviderResource) findProvider(ctx context.Context, id int64, diags *diag.Diagnostics) (map[string]interface{}, bool) {
+    providers, err := r.client.listObjects(ctx, fmt.Sprintf("%s/accounts/ssoproviders/", r.client.BaseURL))
+    if err != nil {
+        diags.AddError("Client Error", fmt.Sprintf("Unable to list SSO providers, got error: %s", err))
+        return nil, false
+    }
+
+    for _, provider := range providers {
+        if providerId, ok := provider["id"].(float64); ok && int64(providerId) == id {
+            return provider, true
+        }
+    }
+    return nil, false
+}
+
+// requestBody returns the create and update request body. A null role is sent
+// so removing role_id also clears the role on the server.
+func (m *SSOProviderResourceModel) requestBody() map[string]interface{} {
+    var role interface{}
+    if !m.RoleId.IsNull() {
+        role = m.RoleId.ValueInt64()
+    }
+
+    return map[string]interface{}{
+        "name":       m.Name.ValueString(),
+        "server_url": m.IssuerURL.ValueString(),
+        "client_id":  m.ClientId.ValueString(),
+        "secret":     m.ClientSecret.ValueString(),
+        "role":       role,
+    }
+}
+
+// setFromAPI updates the model from a listed SSO provider. The secret is only
+// taken over when the server returns it; otherwise the configured one is kept.
+func (m *SSOProviderResourceModel) setFromAPI(provider map[string]interface{}) {
+    if id, ok := provider["id"].(float64); ok {
+        m.Id = types.Int64Value(int64(id))
+    }
+    if name, ok := provider["name"].(string); ok {
+        m.Name = types.StringValue(name)
+    }
+    m.ProviderId = stringValue(provider["provider_id"])
+    if clientId, ok := provider["client_id"].(string); ok {
+        m.ClientId = types.StringValue(clientId)
+    }
+    if secret, ok := provider["secret"].(string); ok && secret != "" {
+        m.ClientSecret = types.StringValue(secret)
+    }
+
+    // The issuer and role are either returned as is or inside the provider
+    // settings
+    settings, _ := provider["settings"].(map[string]interface{})
+    serverURL, ok := provider["server_url"].(string)
+    if !ok {
+        serverURL, ok = settings["server_url"].(string)
+    }
+    if ok {
+        m.IssuerURL = types.StringValue(serverURL)
+    }
+
+    role, ok := provider["role"]
+    if !ok {
+        role, ok = settings["role"]
+    }
+    if ok {
+        if roleId, isNumber := role.(float64); isNumber {
+            m.RoleId = types.Int64Value(int64(roleId))
+        } else {
+            m.RoleId = types.Int64Null()
+        }
+    }
+}
@@ -0,0 +1,322 @@
+package provider
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/resource"
+    "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/tfsdk"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ssoProviderServer is an in-memory /accounts/ssoproviders/ endpoint. The
+// list omits secrets unless echoSecret is set.
+type ssoProviderServer struct {
+    mu         sync.Mutex
+    providers  []map[string]interface{}
+    nextId     int
+    echoSecret bool
+    requests   []map[string]interface{}
+}
+
+func newSSOProviderServer(t *testing.T, s *ssoProviderServer) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        s.mu.Lock()
+        defer s.mu.Unlock()
+
+        var body map[string]interface{}
+        if r.Body != nil {
+            json.NewDecoder(r.Body).Decode(&body)
+        }
+
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/accounts/ssoproviders/":
+            list := []map[string]interface{}{}
+            for _, provider := range s.providers {
+                listed := map[string]interface{}{}
+                for key, value := range provider {
+                    if key != "secret" || s.echoSecret {
+                        listed[key] = value
+                    }
+                }
+                list = append(list, listed)
+            }
+            writeJSON(t, w, list)
+        case r.Method == http.MethodPost && r.URL.Path == "/accounts/ssoproviders/":
+            s.requests = append(s.requests, body)
+            s.nextId++
+            body["id"] = s.nextId
+            body["provider_id"] = strings.ToLower(body["name"].(string))
+            s.providers = append(s.providers, body)
+            writeJSON(t, w, "ok")
+        case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/accounts/ssoproviders/"):
+            s.requests = append(s.requests, body)
+            for _, provider := range s.providers {
+                if fmt.Sprintf("/accounts/ssoproviders/%v/", provider["id"]) == r.URL.Path {
+                    for key, value := range body {
+                        provider[key] = value
+                    }
+                    provider["provider_id"] = strings.ToLower(body["name"].(string))
+                    writeJSON(t, w, "ok")
+                    return
+                }
+            }
+            http.NotFound(w, r)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func ssoProviderModel(name string, secret string) *SSOProviderResourceModel {
+    return &SSOProviderResourceModel{
+        Id:           types.Int64Unknown(),
+        Name:         types.StringValue(name),
+        ProviderId:   types.StringUnknown(),
+        IssuerURL:    types.StringValue("https://login.example.com/tenant/v2.0"),
+        ClientId:     types.StringValue("trmm-client"),
+        ClientSecret: types.StringValue(secret),
+        RoleId:       types.Int64Null(),
+    }
+}
+
+func TestSSOProviderResourceCreate(t *testing.T) {
+    fake := &ssoProviderServer{}
+    server := newSSOProviderServer(t, fake)
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    resp := createResource(t, r, ssoProviderModel("Entra", "s3cret-value"))
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state SSOProviderResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.Id.ValueInt64() != 1 || state.ProviderId.ValueString() != "entra" {
+        t.Errorf("unexpected state: %+v", state)
+    }
+    if state.ClientSecret.ValueString() != "s3cret-value" {
+        t.Errorf("expected the configured secret to be kept, got %q", state.ClientSecret.ValueString())
+    }
+
+    sent := fake.requests[0]
+    if sent["secret"] != "s3cret-value" || sent["server_url"] != "https://login.example.com/tenant/v2.0" || sent["client_id"] != "trmm-client" {
+        t.Errorf("unexpected request body: %v", sent)
+    }
+}
+
+func TestSSOProviderResourceCreate_Role(t *testing.T) {
+    fake := &ssoProviderServer{}
+    server := newSSOProviderServer(t, fake)
+
+    planned := ssoProviderModel("Entra", "s3cret-value")
+    planned.RoleId = types.Int64Value(5)
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    resp := createResource(t, r, planned)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    if role := fake.requests[0]["role"]; role != float64(5) {
+        t.Errorf("expected role 5 to be sent, got %v", role)
+    }
+
+    var state SSOProviderResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.RoleId.ValueInt64() != 5 {
+        t.Errorf("expected role_id 5 in state, got %v", state.RoleId)
+    }
+}
+
+func TestSSOProviderResource_FeatureNotAvailable(t *testing.T) {
+    var probes int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet && r.URL.Path == "/accounts/ssoproviders/" {
+            atomic.AddInt32(&probes, 1)
+        }
+        if r.Method != http.MethodGet {
+            t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+        }
+        http.NotFound(w, r)
+    }))
+    t.Cleanup(server.Close)
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    createResp := createResource(t, r, ssoProviderModel("Entra", "s3cret-value"))
+    if !createResp.Diagnostics.HasError() || createResp.Diagnostics.Errors()[0].Summary() != "Feature Not Available" {
+        t.Fatalf("expected a feature not available error, got: %v", createResp.Diagnostics)
+    }
+    if detail := createResp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "not available on this server") {
+        t.Errorf("unexpected error detail: %s", detail)
+    }
+
+    prior := ssoProviderModel("Entra", "s3cret-value")
+    prior.Id = types.Int64Value(1)
+    prior.ProviderId = types.StringValue("entra")
+    readResp := readResource(t, r, prior)
+    if !readResp.Diagnostics.HasError() || readResp.Diagnostics.Errors()[0].Summary() != "Feature Not Available" {
+        t.Fatalf("expected a feature not available error, got: %v", readResp.Diagnostics)
+    }
+
+    if got := atomic.LoadInt32(&probes); got != 1 {
+        t.Errorf("expected the feature to be probed once, got %d requests", got)
+    }
+}
+
+func TestSSOProviderResourceCreate_DuplicateName(t *testing.T) {
+    fake := &ssoProviderServer{providers: []map[string]interface{}{{"id": 4, "name": "Entra"}}}
+    server := newSSOProviderServer(t, fake)
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    resp := createResource(t, r, ssoProviderModel("Entra", "s3cret-value"))
+    if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Duplicate SSO Provider Name" {
+        t.Fatalf("expected a duplicate name error, got: %v", resp.Diagnostics)
+    }
+    if len(fake.requests) != 0 {
+        t.Errorf("expected no create request, got %v", fake.requests)
+    }
+}
+
+func TestSSOProviderResourceRead(t *testing.T) {
+    fake := &ssoProviderServer{providers: []map[string]interface{}{{
+        "id":          3,
+        "name":        "Keycloak",
+        "provider_id": "keycloak",
+        "client_id":   "changed-client",
+        "secret":      "never-listed",
+        "settings":    map[string]interface{}{"server_url": "https://sso.example.com/realms/it", "role": 2},
+    }}}
+    server := newSSOProviderServer(t, fake)
+
+    prior := ssoProviderModel("Keycloak", "configured-secret")
+    prior.Id = types.Int64Value(3)
+    prior.ProviderId = types.StringValue("keycloak")
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    resp := readResource(t, r, prior)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state SSOProviderResourceModel
+    resp.State.Get(context.Background(), &state)
+    if state.ClientId.ValueString() != "changed-client" {
+        t.Errorf("expected client_id drift to be read, got %q", state.ClientId.ValueString())
+    }
+    if state.IssuerURL.ValueString() != "https://sso.example.com/realms/it" {
+        t.Errorf("expected the issuer from the settings, got %q", state.IssuerURL.ValueString())
+    }
+    if state.ClientSecret.ValueString() != "configured-secret" {
+        t.Errorf("expected the secret to be kept when it is not returned, got %q", state.ClientSecret.ValueString())
+    }
+    if state.RoleId.ValueInt64() != 2 {
+        t.Errorf("expected the role from the settings, got %v", state.RoleId)
+    }
+}
+
+func TestSSOProviderResourceRead_Removed(t *testing.T) {
+    server := newSSOProviderServer(t, &ssoProviderServer{})
+
+    prior := ssoProviderModel("Keycloak", "configured-secret")
+    prior.Id = types.Int64Value(3)
+    prior.ProviderId = types.StringValue("keycloak")
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    resp := readResource(t, r, prior)
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+    if !resp.State.Raw.IsNull() {
+        t.Error("expected the resource to be removed from state")
+    }
+}
+
+func TestSSOProviderResourceUpdate_RedactsSecret(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var body map[string]interface{}
+        json.NewDecoder(r.Body).Decode(&body)
+        w.WriteHeader(http.StatusBadRequest)
+        writeJSON(t, w, map[string]interface{}{"detail": fmt.Sprintf("secret %v is too short", body["secret"])})
+    }))
+    t.Cleanup(server.Close)
+
+    prior := ssoProviderModel("Entra", "old-secret")
+    prior.Id = types.Int64Value(1)
+    prior.ProviderId = types.StringValue("entra")
+    planned := ssoProviderModel("Entra", "new-secret")
+    planned.Id = types.Int64Value(1)
+    planned.ProviderId = types.StringValue("entra")
+
+    r := &SSOProviderResource{client: newTestClient(server)}
+    resp := updateResource(t, r, prior, planned)
+    if !resp.Diagnostics.HasError() {
+        t.Fatal("expected an error for the rejected update")
+    }
+    if detail := resp.Diagnostics.Errors()[0].Detail(); strings.Contains(detail, "new-secret") || !strings.Contains(detail, redactedValue) {
+        t.Errorf("expected the secret to be redacted, got %q", detail)
+    }
+}
+
+func TestSSOProviderResourceSchema_SecretIsSensitive(t *testing.T) {
+    schemaResp := resourceSchemaFor(t, &SSOProviderResource{})
+    secret, ok := schemaResp.Schema.Attributes["client_secret"].(schema.StringAttribute)
+    if !ok || !secret.Sensitive {
+        t.Error("expected client_secret to be a sensitive string")
+    }
+}
+
+func TestSSOProviderResourceValidateConfig(t *testing.T) {
+    ctx := context.Background()
+    r := &SSOProviderResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    model := ssoProviderModel("Entra", " ")
+    model.Id = types.Int64Null()
+    model.ProviderId = types.StringNull()
+
+    // Config has no Set method, so the raw value is built through a state
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build config: %v", diags)
+    }
+
+    var resp resource.ValidateConfigResponse
+    r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+    if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Missing SSO Provider Setting" {
+        t.Errorf("expected an error for the empty client_secret, got: %v", resp.Diagnostics)
+    }
+}
+
+func TestSSOProviderResourceValidateConfig_Role(t *testing.T) {
+    ctx := context.Background()
+    r := &SSOProviderResource{}
+    schemaResp := resourceSchemaFor(t, r)
+
+    model := ssoProviderModel("Entra", "s3cret-value")
+    model.Id = types.Int64Null()
+    model.ProviderId = types.StringNull()
+    model.RoleId = types.Int64Value(0)
+
+    state := tfsdk.State{Schema: schemaResp.Schema}
+    if diags := state.Set(ctx, model); diags.HasError() {
+        t.Fatalf("unable to build config: %v", diags)
+    }
+
+    var resp resource.ValidateConfigResponse
+    r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+    if resp.Diagnostics.ErrorsCount() != 1 || resp.Diagnostics.Errors()[0].Summary() != "Invalid Role" {
+        t.Errorf("expected an error for role_id 0, got: %v", resp.Diagnostics)
+    }
+}
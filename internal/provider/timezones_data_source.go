@@ -0,0 +1,120 @@
+package provider
+
+import (
+    "context"
+    _ "embed"
+    "fmt"
+    "sort"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TimezonesDataSource{}
+
+// bundledTimezones is the IANA time zone list TRMM offers, including the
+// legacy aliases pytz keeps. It is used for servers whose settings do not
+// include the list.
+//
+//go:embed timezones.txt
+var bundledTimezones string
+
+func NewTimezonesDataSource() datasource.DataSource {
+    return &TimezonesDataSource{}
+}
+
+// TimezonesDataSource lists the time zones TRMM accepts for agents, clients
+// and the server default.
+type TimezonesDataSource struct {
+    client *ClientConfig
+}
+
+// TimezonesDataSourceModel describes the data source data model.
+type TimezonesDataSourceModel struct {
+    Timezones       types.List   `tfsdk:"timezones"`
+    DefaultTimezone types.String `tfsdk:"default_timezone"`
+    Source          types.String `tfsdk:"source"`
+}
+
+func (d *TimezonesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_timezones"
+}
+
+func (d *TimezonesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Timezones data source for Tactical RMM. Lists the time zone names the server accepts, so modules can validate or offer them. " +
+            "The list comes from the server settings, or from a bundled IANA list when the server does not return one.",
+
+        Attributes: map[string]schema.Attribute{
+            "timezones": schema.ListAttribute{
+                MarkdownDescription: "Accepted time zone names, sorted",
+                ElementType:         types.StringType,
+                Computed:            true,
+            },
+            "default_timezone": schema.StringAttribute{
+                MarkdownDescription: "Default time zone of the server, null if none is set",
+                Computed:            true,
+            },
+            "source": schema.StringAttribute{
+                MarkdownDescription: "Where the list comes from: `server` or `bundled`",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *TimezonesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *TimezonesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data TimezonesDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    settings, err := d.client.getObject(ctx, fmt.Sprintf("%s/core/settings/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read core settings, got error: %s", err))
+        return
+    }
+
+    timezones := stringSlice(settings["all_timezones"])
+    data.Source = types.StringValue("server")
+    if len(timezones) == 0 {
+        timezones = strings.Fields(bundledTimezones)
+        data.Source = types.StringValue("bundled")
+    }
+    sort.Strings(timezones)
+
+    data.DefaultTimezone = types.StringNull()
+    if name, ok := settings["default_time_zone"].(string); ok && name != "" {
+        data.DefaultTimezone = types.StringValue(name)
+    }
+
+    timezoneList, diags := types.ListValueFrom(ctx, types.StringType, timezones)
+    resp.Diagnostics.Append(diags...)
+    data.Timezones = timezoneList
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
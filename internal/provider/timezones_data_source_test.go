@@ -0,0 +1,75 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func readTimezones(t *testing.T, settings map[string]interface{}) (TimezonesDataSourceModel, []string) {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/core/settings/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, settings)
+    }))
+    t.Cleanup(server.Close)
+
+    d := &TimezonesDataSource{client: newTestClient(server)}
+    resp := readDataSource(t, d, &TimezonesDataSourceModel{Timezones: types.ListNull(types.StringType)})
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state TimezonesDataSourceModel
+    resp.Diagnostics.Append(resp.State.Get(context.Background(), &state)...)
+    var timezones []string
+    resp.Diagnostics.Append(state.Timezones.ElementsAs(context.Background(), &timezones, false)...)
+    return state, timezones
+}
+
+func TestTimezonesDataSource_Bundled(t *testing.T) {
+    state, timezones := readTimezones(t, map[string]interface{}{"default_time_zone": "America/Los_Angeles"})
+
+    if state.Source.ValueString() != "bundled" {
+        t.Errorf("expected the bundled list, got source %s", state.Source)
+    }
+    if state.DefaultTimezone.ValueString() != "America/Los_Angeles" {
+        t.Errorf("unexpected default time zone: %s", state.DefaultTimezone)
+    }
+    if len(timezones) < 400 {
+        t.Fatalf("expected the full IANA list, got %d time zones", len(timezones))
+    }
+
+    known := make(map[string]bool, len(timezones))
+    for _, name := range timezones {
+        known[name] = true
+    }
+    for _, name := range []string{"UTC", "America/New_York", "America/Chicago", "Europe/London", "Europe/Berlin", "Asia/Tokyo", "Australia/Sydney", "US/Eastern"} {
+        if !known[name] {
+            t.Errorf("expected %s in the bundled list", name)
+        }
+    }
+}
+
+func TestTimezonesDataSource_PrefersServerList(t *testing.T) {
+    state, timezones := readTimezones(t, map[string]interface{}{
+        "all_timezones":     []string{"UTC", "Europe/Berlin", "America/New_York"},
+        "default_time_zone": "",
+    })
+
+    if state.Source.ValueString() != "server" {
+        t.Errorf("expected the server list, got source %s", state.Source)
+    }
+    if !state.DefaultTimezone.IsNull() {
+        t.Errorf("expected no default time zone, got %s", state.DefaultTimezone)
+    }
+    if len(timezones) != 3 || timezones[0] != "America/New_York" || timezones[2] != "UTC" {
+        t.Errorf("expected the sorted server list, got %v", timezones)
+    }
+}
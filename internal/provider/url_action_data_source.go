@@ -0,0 +1,178 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &URLActionDataSource{}
+
+func NewURLActionDataSource() datasource.DataSource {
+    return &URLActionDataSource{}
+}
+
+// URLActionDataSource looks up a single URL action.
+type URLActionDataSource struct {
+    client *ClientConfig
+}
+
+// URLActionDataSourceModel describes the data source data model.
+type URLActionDataSourceModel struct {
+    Id         types.Int64  `tfsdk:"id"`
+    Name       types.String `tfsdk:"name"`
+    Desc       types.String `tfsdk:"desc"`
+    Pattern    types.String `tfsdk:"pattern"`
+    ActionType types.String `tfsdk:"action_type"`
+    RestMethod types.String `tfsdk:"rest_method"`
+}
+
+func (d *URLActionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_url_action"
+}
+
+func (d *URLActionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "URL action data source for Tactical RMM. Looks up one URL action by ID or exact name, " +
+            "e.g. to pass its ID to `tacticalrmm_run_agent_url_action`.",
+
+        Attributes: map[string]schema.Attribute{
+            "id": schema.Int64Attribute{
+                MarkdownDescription: "URL action identifier. Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "name": schema.StringAttribute{
+                MarkdownDescription: "URL action name (exact match). Either `id` or `name` must be specified.",
+                Optional:            true,
+                Computed:            true,
+            },
+            "desc": schema.StringAttribute{
+                MarkdownDescription: "URL action description",
+                Computed:            true,
+            },
+            "pattern": schema.StringAttribute{
+                MarkdownDescription: "URL pattern, which may contain `{{agent.*}}`, `{{client.*}}` and `{{site.*}}` variables",
+                Computed:            true,
+            },
+            "action_type": schema.StringAttribute{
+                MarkdownDescription: "Action type: `web` opens the URL in the browser, `rest` sends a request from the server",
+                Computed:            true,
+            },
+            "rest_method": schema.StringAttribute{
+                MarkdownDescription: "HTTP method of `rest` actions, null for `web` actions",
+                Computed:            true,
+            },
+        },
+    }
+}
+
+func (d *URLActionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *URLActionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data URLActionDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    // Validate that either ID or name is provided
+    if data.Id.IsNull() && data.Name.IsNull() {
+        resp.Diagnostics.AddError(
+            "Missing URL Action Identifier",
+            "Either 'id' or 'name' must be specified to look up a URL action.",
+        )
+        return
+    }
+
+    actions, err := d.client.listObjects(ctx, fmt.Sprintf("%s/core/urlaction/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list URL actions, got error: %s", err))
+        return
+    }
+
+    // Find the action by ID, or by name which must be unique
+    var matches []map[string]interface{}
+    for _, a := range actions {
+        if !data.Id.IsNull() {
+            if referencedId(a["id"]) == data.Id.ValueInt64() {
+                matches = append(matches, a)
+            }
+        } else if name, ok := a["name"].(string); ok && name == data.Name.ValueString() {
+            matches = append(matches, a)
+        }
+    }
+
+    if len(matches) == 0 {
+        if !data.Id.IsNull() {
+            resp.Diagnostics.AddError("URL Action Not Found", fmt.Sprintf("URL action with ID %d not found", data.Id.ValueInt64()))
+        } else {
+            resp.Diagnostics.AddError(
+                "URL Action Not Found",
+                fmt.Sprintf("URL action with name '%s' not found. Available URL actions: %s.", data.Name.ValueString(), urlActionCandidates(actions)),
+            )
+        }
+        return
+    }
+
+    if len(matches) > 1 {
+        resp.Diagnostics.AddError(
+            "Ambiguous URL Action Name",
+            fmt.Sprintf("Found %d URL actions named '%s': %s; use 'id' to select one.", len(matches), data.Name.ValueString(), urlActionCandidates(matches)),
+        )
+        return
+    }
+
+    action := matches[0]
+
+    // Update model with response data
+    data.Id = int64Value(action["id"])
+    data.Name = stringValue(action["name"])
+    data.Desc = stringValue(action["desc"])
+    data.Pattern = stringValue(action["pattern"])
+    data.ActionType = stringValue(action["action_type"])
+    data.RestMethod = types.StringNull()
+    if data.ActionType.ValueString() == "rest" {
+        data.RestMethod = stringValue(action["rest_method"])
+    }
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// urlActionCandidates formats URL actions as "'name' (ID n)" sorted by ID, or
+// "none" for an empty list.
+func urlActionCandidates(actions []map[string]interface{}) string {
+    if len(actions) == 0 {
+        return "none"
+    }
+
+    candidates := make([]string, 0, len(actions))
+    for _, action := range sortedById(actions) {
+        name, _ := action["name"].(string)
+        candidates = append(candidates, fmt.Sprintf("'%s' (ID %d)", name, referencedId(action["id"])))
+    }
+    return strings.Join(candidates, ", ")
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// urlActionsServer serves a web and a REST URL action and two actions sharing
+// the name "Duplicate".
+func urlActionsServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != http.MethodGet || r.URL.Path != "/core/urlaction/" {
+            http.NotFound(w, r)
+            return
+        }
+        writeJSON(t, w, []map[string]interface{}{
+            {"id": 1, "name": "Open Remote", "desc": "Remote session", "pattern": "https://remote.example.com/{{agent.agent_id}}", "action_type": "web", "rest_method": "post"},
+            {"id": 2, "name": "Create Ticket", "desc": "", "pattern": "https://tickets.example.com/api", "action_type": "rest", "rest_method": "post"},
+            {"id": 4, "name": "Duplicate", "pattern": "https://b.example.com", "action_type": "web"},
+            {"id": 3, "name": "Duplicate", "pattern": "https://a.example.com", "action_type": "web"},
+        })
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+func urlActionModel(id int64, name string) *URLActionDataSourceModel {
+    data := &URLActionDataSourceModel{
+        Id:         types.Int64Null(),
+        Name:       types.StringNull(),
+        Desc:       types.StringNull(),
+        Pattern:    types.StringNull(),
+        ActionType: types.StringNull(),
+        RestMethod: types.StringNull(),
+    }
+    if id != 0 {
+        data.Id = types.Int64Value(id)
+    }
+    if name != "" {
+        data.Name = types.StringValue(name)
+    }
+    return data
+}
+
+func TestURLActionDataSourceRead(t *testing.T) {
+    tests := map[string]struct {
+        config     *URLActionDataSourceModel
+        id         int64
+        restMethod string
+    }{
+        "web by name":  {config: urlActionModel(0, "Open Remote"), id: 1},
+        "rest by id":   {config: urlActionModel(2, ""), id: 2, restMethod: "post"},
+        "duplicate id": {config: urlActionModel(3, ""), id: 3},
+    }
+
+    for name, test := range tests {
+        t.Run(name, func(t *testing.T) {
+            d := &URLActionDataSource{client: newTestClient(urlActionsServer(t))}
+            resp := readDataSource(t, d, test.config)
+            if resp.Diagnostics.HasError() {
+                t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+            }
+
+            var data URLActionDataSourceModel
+            resp.State.Get(context.Background(), &data)
+            if data.Id.ValueInt64() != test.id || data.Pattern.IsNull() || data.ActionType.IsNull() {
+                t.Errorf("unexpected URL action: %+v", data)
+            }
+            // The method of web actions is not used, so it is not exposed
+            if data.RestMethod.ValueString() != test.restMethod {
+                t.Errorf("expected rest_method %q, got %s", test.restMethod, data.RestMethod)
+            }
+        })
+    }
+}
+
+func TestURLActionDataSourceRead_Errors(t *testing.T) {
+    tests := map[string]struct {
+        config   *URLActionDataSourceModel
+        summary  string
+        contains string
+    }{
+        "no identifier": {config: urlActionModel(0, ""), summary: "Missing URL Action Identifier"},
+        "missing id":    {config: urlActionModel(9, ""), summary: "URL Action Not Found", contains: "ID 9"},
+        "missing name": {config: urlActionModel(0, "Open remote"), summary: "URL Action Not Found",
+            contains: "Available URL actions: 'Open Remote' (ID 1), 'Create Ticket' (ID 2), 'Duplicate' (ID 3), 'Duplicate' (ID 4)."},
+        "ambiguous name": {config: urlActionModel(0, "Duplicate"), summary: "Ambiguous URL Action Name",
+            contains: "Found 2 URL actions named 'Duplicate': 'Duplicate' (ID 3), 'Duplicate' (ID 4)"},
+    }
+
+    for name, test := range tests {
+        t.Run(name, func(t *testing.T) {
+            d := &URLActionDataSource{client: newTestClient(urlActionsServer(t))}
+            resp := readDataSource(t, d, test.config)
+            if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != test.summary {
+                t.Fatalf("expected a %q error, got %v", test.summary, resp.Diagnostics)
+            }
+            if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, test.contains) {
+                t.Errorf("expected %q in the detail, got %q", test.contains, detail)
+            }
+        })
+    }
+}
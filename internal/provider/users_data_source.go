@@ -0,0 +1,190 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "sort"
+
+    "github.com/hashicorp/terraform-plugin-framework/attr"
+    "github.com/hashicorp/terraform-plugin-framework/datasource"
+    "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &UsersDataSource{}
+
+// userAttrTypes describes one entry of the users list. Only these fields are
+// copied from the API; password hashes, TOTP and API key material are never
+// read into state.
+var userAttrTypes = map[string]attr.Type{
+    "id":         types.Int64Type,
+    "username":   types.StringType,
+    "email":      types.StringType,
+    "is_active":  types.BoolType,
+    "role":       types.StringType,
+    "role_id":    types.Int64Type,
+    "last_login": types.StringType,
+}
+
+func NewUsersDataSource() datasource.DataSource {
+    return &UsersDataSource{}
+}
+
+// UsersDataSource lists dashboard users with their roles for access reviews.
+type UsersDataSource struct {
+    client *ClientConfig
+}
+
+// UsersDataSourceModel describes the data source data model.
+type UsersDataSourceModel struct {
+    IsActive types.Bool   `tfsdk:"is_active"`
+    Role     types.String `tfsdk:"role"`
+    Users    types.List   `tfsdk:"users"`
+}
+
+func (d *UsersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+    resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *UsersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+    resp.Schema = schema.Schema{
+        MarkdownDescription: "Users data source for Tactical RMM. Lists dashboard users with their role and last login for access reviews. " +
+            "Passwords, two-factor secrets and API keys are never returned.",
+
+        Attributes: map[string]schema.Attribute{
+            "is_active": schema.BoolAttribute{
+                MarkdownDescription: "Optional: Only list active (`true`) or disabled (`false`) users.",
+                Optional:            true,
+            },
+            "role": schema.StringAttribute{
+                MarkdownDescription: "Optional: Only list users with the role of this name (exact match).",
+                Optional:            true,
+            },
+            "users": schema.ListNestedAttribute{
+                MarkdownDescription: "Matching users, sorted by username",
+                Computed:            true,
+                NestedObject: schema.NestedAttributeObject{
+                    Attributes: map[string]schema.Attribute{
+                        "id": schema.Int64Attribute{
+                            MarkdownDescription: "User identifier",
+                            Computed:            true,
+                        },
+                        "username": schema.StringAttribute{
+                            MarkdownDescription: "Username",
+                            Computed:            true,
+                        },
+                        "email": schema.StringAttribute{
+                            MarkdownDescription: "Email address",
+                            Computed:            true,
+                        },
+                        "is_active": schema.BoolAttribute{
+                            MarkdownDescription: "Whether the user can log in",
+                            Computed:            true,
+                        },
+                        "role": schema.StringAttribute{
+                            MarkdownDescription: "Name of the user's role, null if the user has none",
+                            Computed:            true,
+                        },
+                        "role_id": schema.Int64Attribute{
+                            MarkdownDescription: "Identifier of the user's role, null if the user has none",
+                            Computed:            true,
+                        },
+                        "last_login": schema.StringAttribute{
+                            MarkdownDescription: "Time of the last login as returned by Tactical RMM, null if the user never logged in",
+                            Computed:            true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func (d *UsersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+    if req.ProviderData == nil {
+        return
+    }
+
+    client, ok := req.ProviderData.(*ClientConfig)
+    if !ok {
+        resp.Diagnostics.AddError(
+            "Unexpected Data Source Configure Type",
+            fmt.Sprintf("Expected *ClientConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+        )
+        return
+    }
+
+    d.client = client
+}
+
+func (d *UsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+    defer d.client.redactDiagnostics(&resp.Diagnostics)
+
+    var data UsersDataSourceModel
+
+    resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+    if resp.Diagnostics.HasError() {
+        return
+    }
+
+    users, err := d.client.listObjects(ctx, fmt.Sprintf("%s/accounts/users/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list users, got error: %s", err))
+        return
+    }
+
+    roles, err := d.client.listObjects(ctx, fmt.Sprintf("%s/accounts/roles/", d.client.BaseURL))
+    if err != nil {
+        resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list roles, got error: %s", err))
+        return
+    }
+
+    roleNames := make(map[int64]string, len(roles))
+    for _, role := range roles {
+        id, _ := role["id"].(float64)
+        name, _ := role["name"].(string)
+        roleNames[int64(id)] = name
+    }
+
+    sort.SliceStable(users, func(i, j int) bool {
+        first, _ := users[i]["username"].(string)
+        second, _ := users[j]["username"].(string)
+        return first < second
+    })
+
+    userValues := []attr.Value{}
+    for _, user := range users {
+        roleId := int64Value(user["role"])
+        role := types.StringNull()
+        if !roleId.IsNull() {
+            role = types.StringValue(roleNames[roleId.ValueInt64()])
+        }
+
+        isActive := boolValue(user["is_active"])
+        if !data.IsActive.IsNull() && !isActive.Equal(data.IsActive) {
+            continue
+        }
+        if !data.Role.IsNull() && !role.Equal(data.Role) {
+            continue
+        }
+
+        userValue, diags := types.ObjectValue(userAttrTypes, map[string]attr.Value{
+            "id":         int64Value(user["id"]),
+            "username":   stringValue(user["username"]),
+            "email":      stringValue(user["email"]),
+            "is_active":  isActive,
+            "role":       role,
+            "role_id":    roleId,
+            "last_login": stringValue(user["last_login"]),
+        })
+        resp.Diagnostics.Append(diags...)
+        userValues = append(userValues, userValue)
+    }
+
+    usersValue, diags := types.ListValue(types.ObjectType{AttrTypes: userAttrTypes}, userValues)
+    resp.Diagnostics.Append(diags...)
+    data.Users = usersValue
+
+    resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func usersServer(t *testing.T) *httptest.Server {
+    t.Helper()
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/accounts/users/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 3, "username": "tech1", "email": "tech1@example.com", "is_active": true, "role": 2, "last_login": "2026-10-01T08:00:00Z", "password": "pbkdf2_sha256$secret-hash", "totp_key": "TOTPSECRET"},
+                {"id": 1, "username": "admin", "email": "admin@example.com", "is_active": true, "role": 1, "last_login": nil, "password": "pbkdf2_sha256$secret-hash"},
+                {"id": 4, "username": "former", "email": "former@example.com", "is_active": false, "role": 2, "last_login": "2025-01-10T12:00:00Z", "password": "pbkdf2_sha256$secret-hash"},
+                {"id": 5, "username": "api", "email": "", "is_active": true, "role": nil, "last_login": nil, "api_key": "APIKEYSECRET"},
+            })
+        case "/accounts/roles/":
+            writeJSON(t, w, []map[string]interface{}{
+                {"id": 1, "name": "Admins"},
+                {"id": 2, "name": "Technicians"},
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    t.Cleanup(server.Close)
+    return server
+}
+
+type userEntry struct {
+    Id        types.Int64  `tfsdk:"id"`
+    Username  types.String `tfsdk:"username"`
+    Email     types.String `tfsdk:"email"`
+    IsActive  types.Bool   `tfsdk:"is_active"`
+    Role      types.String `tfsdk:"role"`
+    RoleId    types.Int64  `tfsdk:"role_id"`
+    LastLogin types.String `tfsdk:"last_login"`
+}
+
+func readUsers(t *testing.T, isActive types.Bool, role types.String) []userEntry {
+    t.Helper()
+    d := &UsersDataSource{client: newTestClient(usersServer(t))}
+    resp := readDataSource(t, d, &UsersDataSourceModel{
+        IsActive: isActive,
+        Role:     role,
+        Users:    types.ListNull(types.ObjectType{AttrTypes: userAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    var state UsersDataSourceModel
+    resp.State.Get(context.Background(), &state)
+
+    var users []userEntry
+    state.Users.ElementsAs(context.Background(), &users, false)
+    return users
+}
+
+func TestUsersDataSourceRead_All(t *testing.T) {
+    users := readUsers(t, types.BoolNull(), types.StringNull())
+    if len(users) != 4 {
+        t.Fatalf("expected 4 users, got %d", len(users))
+    }
+    if users[0].Username.ValueString() != "admin" || users[0].Role.ValueString() != "Admins" || !users[0].LastLogin.IsNull() {
+        t.Errorf("unexpected first user %+v", users[0])
+    }
+    if users[1].Username.ValueString() != "api" || !users[1].Role.IsNull() || !users[1].RoleId.IsNull() {
+        t.Errorf("expected a user without role, got %+v", users[1])
+    }
+}
+
+func TestUsersDataSourceRead_Filters(t *testing.T) {
+    users := readUsers(t, types.BoolValue(true), types.StringValue("Technicians"))
+    if len(users) != 1 || users[0].Username.ValueString() != "tech1" {
+        t.Fatalf("expected only tech1, got %+v", users)
+    }
+    if users[0].RoleId.ValueInt64() != 2 || users[0].LastLogin.ValueString() != "2026-10-01T08:00:00Z" {
+        t.Errorf("unexpected user %+v", users[0])
+    }
+
+    inactive := readUsers(t, types.BoolValue(false), types.StringNull())
+    if len(inactive) != 1 || inactive[0].Username.ValueString() != "former" {
+        t.Errorf("expected only former, got %+v", inactive)
+    }
+}
+
+func TestUsersDataSourceRead_ExcludesSecrets(t *testing.T) {
+    d := &UsersDataSource{client: newTestClient(usersServer(t))}
+    resp := readDataSource(t, d, &UsersDataSourceModel{
+        IsActive: types.BoolNull(),
+        Role:     types.StringNull(),
+        Users:    types.ListNull(types.ObjectType{AttrTypes: userAttrTypes}),
+    })
+    if resp.Diagnostics.HasError() {
+        t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+    }
+
+    state := resp.State.Raw.String()
+    for _, secret := range []string{"secret-hash", "TOTPSECRET", "APIKEYSECRET"} {
+        if strings.Contains(state, secret) {
+            t.Errorf("state contains %q: %s", secret, state)
+        }
+    }
+}
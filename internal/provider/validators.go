@@ -0,0 +1,132 @@
+package provider
+
+import (
+    "context"
+    "fmt"
+    "net/url"
+    "strings"
+
+    "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+    "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// knownPlatforms are the agent platforms Tactical RMM accepts in a script's
+// supported_platforms (the AgentPlat choices on the server).
+var knownPlatforms = []string{"windows", "linux", "darwin"}
+
+var _ validator.Set = supportedPlatformsValidator{}
+
+// supportedPlatformsValidator checks that every element of a set is a known
+// agent platform.
+type supportedPlatformsValidator struct{}
+
+func (v supportedPlatformsValidator) Description(ctx context.Context) string {
+    return fmt.Sprintf("each value must be one of: %s", strings.Join(knownPlatforms, ", "))
+}
+
+func (v supportedPlatformsValidator) MarkdownDescription(ctx context.Context) string {
+    return fmt.Sprintf("each value must be one of: `%s`", strings.Join(knownPlatforms, "`, `"))
+}
+
+func (v supportedPlatformsValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+    if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+        return
+    }
+
+    for _, element := range req.ConfigValue.Elements() {
+        platform, ok := element.(types.String)
+        if !ok || platform.IsNull() || platform.IsUnknown() {
+            continue
+        }
+
+        known := false
+        for _, candidate := range knownPlatforms {
+            if platform.ValueString() == candidate {
+                known = true
+                break
+            }
+        }
+
+        if !known {
+            resp.Diagnostics.AddAttributeError(
+                req.Path.AtSetValue(platform),
+                "Invalid Supported Platform",
+                fmt.Sprintf("Unknown platform %q, %s.", platform.ValueString(), v.Description(ctx)),
+            )
+        }
+    }
+}
+
+// oidcDiscoveryPath is appended to the issuer URL by the OIDC client, see
+// issuerURLValidator.
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+var _ validator.String = issuerURLValidator{}
+
+// issuerURLValidator checks that a string is an OIDC issuer URL: an absolute
+// http or https URL without query, fragment or discovery document path.
+type issuerURLValidator struct{}
+
+func (v issuerURLValidator) Description(ctx context.Context) string {
+    return "value must be an absolute http or https URL without query, fragment or " + oidcDiscoveryPath
+}
+
+func (v issuerURLValidator) MarkdownDescription(ctx context.Context) string {
+    return "value must be an absolute `http` or `https` URL without query, fragment or `" + oidcDiscoveryPath + "`"
+}
+
+func (v issuerURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+    if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+        return
+    }
+
+    value := req.ConfigValue.ValueString()
+    parsed, err := url.Parse(value)
+    if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+        resp.Diagnostics.AddAttributeError(req.Path, "Invalid Issuer URL", fmt.Sprintf("%q is not an absolute http or https URL.", value))
+        return
+    }
+    if parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+        resp.Diagnostics.AddAttributeError(req.Path, "Invalid Issuer URL", fmt.Sprintf("%q must not contain credentials, a query or a fragment.", value))
+        return
+    }
+    if strings.HasSuffix(strings.TrimSuffix(parsed.Path, "/"), oidcDiscoveryPath) {
+        resp.Diagnostics.AddAttributeError(
+            req.Path,
+            "Invalid Issuer URL",
+            fmt.Sprintf("%q points at the discovery document. Use the issuer URL without %s, it is appended automatically.", value, oidcDiscoveryPath),
+        )
+        return
+    }
+    if parsed.Scheme == "http" {
+        resp.Diagnostics.AddAttributeWarning(
+            req.Path,
+            "Insecure Issuer URL",
+            fmt.Sprintf("%q does not use https, so tokens and the client secret are exchanged in plain text.", value),
+        )
+    }
+}
+
+var _ validator.String = jsonValueValidator{}
+
+// jsonValueValidator checks that a string is a single JSON document.
+type jsonValueValidator struct{}
+
+func (v jsonValueValidator) Description(ctx context.Context) string {
+    return "value must be a valid JSON document"
+}
+
+func (v jsonValueValidator) MarkdownDescription(ctx context.Context) string {
+    return v.Description(ctx)
+}
+
+func (v jsonValueValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+    if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+        return
+    }
+
+    // The value is usually sensitive, so only the parse error is shown
+    if _, err := canonicalJSON(req.ConfigValue.ValueString()); err != nil {
+        resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSON Value", fmt.Sprintf("The value is not valid JSON: %s.", err))
+    }
+}